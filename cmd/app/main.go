@@ -19,6 +19,8 @@ import (
 	"github.com/cago-frame/cago/server/cron"
 	"github.com/cago-frame/cago/server/mux"
 	"github.com/scriptscat/scriptlist/internal/api"
+	"github.com/scriptscat/scriptlist/internal/pkg/botdetect"
+	"github.com/scriptscat/scriptlist/internal/pkg/clickhouse"
 	"github.com/scriptscat/scriptlist/internal/repository/issue_repo"
 	"github.com/scriptscat/scriptlist/internal/repository/notification_repo"
 	"github.com/scriptscat/scriptlist/internal/repository/report_repo"
@@ -42,9 +44,15 @@ func main() {
 	script_repo.RegisterScriptCode(script_repo.NewScriptCodeRepo())
 
 	script_repo.RegisterScriptDomain(script_repo.NewScriptDomainRepo())
+	script_repo.RegisterScriptLocale(script_repo.NewScriptLocaleRepo())
+	script_repo.RegisterScriptMatch(script_repo.NewScriptMatchRepo())
+	script_repo.RegisterScriptSuggest(script_repo.NewScriptSuggestRepo())
+	script_repo.RegisterScriptSearchLog(script_repo.NewScriptSearchLogRepo())
 	script_repo.RegisterScriptCategory(script_repo.NewScriptCategoryRepo())
 	script_repo.RegisterScriptCategoryList(script_repo.NewScriptCategoryListRepo())
 	script_repo.RegisterMigrate(script_repo.NewMigrateRepo())
+	// 搜索后端, 可选elasticsearch或mysql
+	script_repo.RegisterSearchEngine(script_repo.NewSearchEngine(cfg.String(ctx, "search.engine")))
 	script_repo.RegisterLibDefinition(script_repo.NewLibDefinitionRepo())
 	script_repo.RegisterScriptWatch(script_repo.NewScriptWatchRepo())
 
@@ -59,9 +67,17 @@ func main() {
 	// 收藏夹
 	script_repo.RegisterScriptFavorite(script_repo.NewScriptFavorite())
 	script_repo.RegisterScriptFavoriteFolder(script_repo.NewScriptFavoriteFolder())
+	script_repo.RegisterScriptFraudReview(script_repo.NewScriptFraudReview())
 
 	statistics_repo.RegisterScriptStatistics(statistics_repo.NewScriptStatistics())
 	statistics_repo.RegisterStatisticsInfo(statistics_repo.NewStatisticsInfo())
+	statistics_repo.RegisterCoInstall(statistics_repo.NewCoInstall())
+	statistics_repo.RegisterStatisticsCollect(statistics_repo.NewStatisticsCollect())
+	statistics_repo.RegisterStatisticsDaily(statistics_repo.NewStatisticsDaily())
+	statistics_repo.RegisterBot(statistics_repo.NewBot())
+	statistics_repo.RegisterFraud(statistics_repo.NewFraud())
+	statistics_repo.RegisterRealtime(statistics_repo.NewRealtime())
+	statistics_repo.RegisterScriptError(statistics_repo.NewScriptError())
 
 	issue_repo.RegisterScriptIssue(issue_repo.NewScriptIssue())
 	issue_repo.RegisterScriptIssueComment(issue_repo.NewScriptIssueComment())
@@ -80,6 +96,11 @@ func main() {
 	resource_repo.RegisterResource(resource_repo.NewResource())
 
 	notification_repo.RegisterNotification(notification_repo.NewNotificationRepo())
+	notification_repo.RegisterWebhook(notification_repo.NewWebhookRepo())
+	notification_repo.RegisterWebhookLog(notification_repo.NewWebhookLogRepo())
+	notification_repo.RegisterMailBuffer(notification_repo.NewMailBufferRepo())
+	notification_repo.RegisterMention(notification_repo.NewMentionRepo())
+	notification_repo.RegisterRealtime(notification_repo.NewRealtime())
 
 	audit_repo.RegisterAuditLog(audit_repo.NewAuditLogRepo())
 
@@ -90,8 +111,13 @@ func main() {
 		Registry(cache.Cache()).
 		Registry(cago.FuncComponent(elasticsearch.Elasticsearch)).
 		Registry(cago.FuncComponent(broker.Broker)).
+		Registry(cago.FuncComponent(clickhouse.Clickhouse)).
+		Registry(cago.FuncComponent(botdetect.Botdetect)).
 		Registry(cago.FuncComponent(func(ctx context.Context, cfg *configs.Config) error {
-			return migrations.RunMigrations(db.Default())
+			if err := migrations.RunMigrations(db.Default()); err != nil {
+				return err
+			}
+			return migrations.RunClickhouseMigrations(ctx, clickhouse.Default())
 		})).
 		Registry(cago.FuncComponent(consumer.Consumer)).
 		Registry(cron.Cron()).
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"time"
+
+	"github.com/cago-frame/cago"
+	"github.com/cago-frame/cago/configs"
+	"github.com/cago-frame/cago/database/db"
+	"github.com/cago-frame/cago/database/redis"
+	"github.com/cago-frame/cago/pkg/component"
+	"github.com/cago-frame/cago/pkg/logger"
+	"github.com/scriptscat/scriptlist/internal/repository/script_repo"
+	"github.com/scriptscat/scriptlist/internal/repository/statistics_repo"
+	"github.com/scriptscat/scriptlist/internal/service/statistics_svc"
+	"go.uber.org/zap"
+)
+
+// 一次性将redis中已有的每日统计数据补录到数据库, 用于每日落库任务上线之前的数据
+// redis中的uv数据保留60天, 默认补录最近60天到昨天
+func main() {
+	now := time.Now()
+	yesterday := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()).AddDate(0, 0, -1)
+	startStr := flag.String("start", yesterday.AddDate(0, 0, -59).Format("2006-01-02"), "开始日期")
+	endStr := flag.String("end", yesterday.Format("2006-01-02"), "结束日期")
+	flag.Parse()
+	start, err := time.ParseInLocation("2006-01-02", *startStr, now.Location())
+	if err != nil {
+		log.Fatalf("parse start err: %v", err)
+	}
+	end, err := time.ParseInLocation("2006-01-02", *endStr, now.Location())
+	if err != nil {
+		log.Fatalf("parse end err: %v", err)
+	}
+	if end.After(yesterday) {
+		// 当天的数据还在变化, 由每日落库任务处理
+		end = yesterday
+	}
+
+	ctx := context.Background()
+	cfg, err := configs.NewConfig("scriptlist")
+	if err != nil {
+		log.Fatalf("load config err: %v", err)
+	}
+	script_repo.RegisterMigrate(script_repo.NewMigrateRepo())
+	statistics_repo.RegisterScriptStatistics(statistics_repo.NewScriptStatistics())
+	statistics_repo.RegisterStatisticsDaily(statistics_repo.NewStatisticsDaily())
+
+	err = cago.New(ctx, cfg).
+		Registry(component.Core()).
+		Registry(db.Database()).
+		Registry(cago.FuncComponent(redis.Redis)).
+		RegistryCancel(cago.FuncComponentCancel(func(ctx context.Context, cancel context.CancelFunc, cfg *configs.Config) error {
+			// 补录完成后退出
+			defer cancel()
+			for date := start; !date.After(end); date = date.AddDate(0, 0, 1) {
+				if err := statistics_svc.Statistics().SaveDaily(ctx, date); err != nil {
+					logger.Ctx(ctx).Error("补录每日统计数据失败", zap.Error(err),
+						zap.String("date", date.Format("2006-01-02")))
+				}
+			}
+			return nil
+		})).
+		Start()
+	if err != nil {
+		log.Fatalf("start err: %v", err)
+	}
+}
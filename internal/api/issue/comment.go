@@ -4,18 +4,20 @@ import (
 	"github.com/cago-frame/cago/pkg/utils/httputils"
 	"github.com/cago-frame/cago/server/mux"
 	"github.com/scriptscat/scriptlist/internal/model/entity/issue_entity"
+	"github.com/scriptscat/scriptlist/internal/model/entity/notification_entity"
 	"github.com/scriptscat/scriptlist/internal/model/entity/user_entity"
 )
 
 type Comment struct {
 	user_entity.UserInfo `json:",inline"`
-	ID                   int64                    `json:"id"`
-	IssueID              int64                    `json:"issue_id"`
-	Content              string                   `json:"content"`
-	Type                 issue_entity.CommentType `json:"type"`
-	Status               int32                    `json:"status"`
-	Createtime           int64                    `json:"createtime"`
-	Updatetime           int64                    `json:"updatetime"`
+	ID                   int64                              `json:"id"`
+	IssueID              int64                              `json:"issue_id"`
+	Content              string                             `json:"content"`
+	Type                 issue_entity.CommentType           `json:"type"`
+	Mentions             []*notification_entity.MentionLink `json:"mentions,omitempty"` // 内容中@的用户
+	Status               int32                              `json:"status"`
+	Createtime           int64                              `json:"createtime"`
+	Updatetime           int64                              `json:"updatetime"`
 }
 
 // ListCommentRequest 获取反馈评论列表
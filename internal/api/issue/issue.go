@@ -3,6 +3,7 @@ package issue
 import (
 	"github.com/cago-frame/cago/pkg/utils/httputils"
 	"github.com/cago-frame/cago/server/mux"
+	"github.com/scriptscat/scriptlist/internal/model/entity/notification_entity"
 	"github.com/scriptscat/scriptlist/internal/model/entity/user_entity"
 )
 
@@ -52,8 +53,9 @@ type GetIssueRequest struct {
 }
 
 type GetIssueResponse struct {
-	*Issue  `json:",inline"`
-	Content string `json:"content"`
+	*Issue   `json:",inline"`
+	Content  string                             `json:"content"`
+	Mentions []*notification_entity.MentionLink `json:"mentions,omitempty"` // 内容中@的用户
 }
 
 // GetWatchRequest 获取issue关注状态
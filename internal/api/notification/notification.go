@@ -60,3 +60,9 @@ type BatchMarkReadRequest struct {
 
 type BatchMarkReadResponse struct {
 }
+
+// StreamRequest 通过SSE推送新通知和未读数
+type StreamRequest struct {
+	mux.Meta `path:"/notifications/stream" method:"GET"`
+	LastID   int64 `form:"last_id"` // 断线前收到的最后一条通知id, 优先使用Last-Event-ID请求头
+}
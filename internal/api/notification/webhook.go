@@ -0,0 +1,76 @@
+package notification
+
+import (
+	"github.com/cago-frame/cago/pkg/utils/httputils"
+	"github.com/cago-frame/cago/server/mux"
+	"github.com/scriptscat/scriptlist/internal/model/entity/notification_entity"
+)
+
+// Webhook 通知webhook配置
+type Webhook struct {
+	ID         int64                      `json:"id"`
+	URL        string                     `json:"url"`
+	Secret     string                     `json:"secret"`     // 签名密钥, 只在创建和重新生成时返回
+	Types      []notification_entity.Type `json:"types"`      // 订阅的通知类型, 为空时订阅全部
+	Status     int32                      `json:"status"`     // 1:启用 2:停用
+	Createtime int64                      `json:"createtime"` // 未配置时为0
+	Updatetime int64                      `json:"updatetime"`
+}
+
+// WebhookLog webhook投递记录
+type WebhookLog struct {
+	ID         int64                    `json:"id"`
+	Delivery   string                   `json:"delivery"`
+	Event      string                   `json:"event"`
+	Type       notification_entity.Type `json:"type"`
+	URL        string                   `json:"url"`
+	Payload    string                   `json:"payload"`
+	Attempt    int                      `json:"attempt"`
+	StatusCode int                      `json:"status_code"`
+	Response   string                   `json:"response"`
+	Error      string                   `json:"error"`
+	Duration   int64                    `json:"duration"` // 耗时, 毫秒
+	Status     int32                    `json:"status"`   // 1:成功 2:失败
+	Createtime int64                    `json:"createtime"`
+}
+
+// GetWebhookRequest 获取webhook配置
+type GetWebhookRequest struct {
+	mux.Meta `path:"/notifications/webhook" method:"GET"`
+}
+
+type GetWebhookResponse struct {
+	*Webhook
+}
+
+// UpdateWebhookRequest 更新webhook配置
+type UpdateWebhookRequest struct {
+	mux.Meta    `path:"/notifications/webhook" method:"PUT"`
+	URL         string                     `json:"url" binding:"required,max=512" label:"webhook地址"`
+	Types       []notification_entity.Type `json:"types" binding:"omitempty"`
+	Status      int32                      `json:"status" binding:"required,oneof=1 2"`
+	ResetSecret bool                       `json:"reset_secret"` // 重新生成签名密钥
+}
+
+type UpdateWebhookResponse struct {
+	*Webhook
+}
+
+// ListWebhookLogRequest webhook投递记录
+type ListWebhookLogRequest struct {
+	mux.Meta              `path:"/notifications/webhook/logs" method:"GET"`
+	httputils.PageRequest `form:",inline"`
+}
+
+type ListWebhookLogResponse struct {
+	httputils.PageResponse[*WebhookLog] `json:",inline"`
+}
+
+// PingWebhookRequest 发送测试请求
+type PingWebhookRequest struct {
+	mux.Meta `path:"/notifications/webhook/ping" method:"POST"`
+}
+
+type PingWebhookResponse struct {
+	*WebhookLog
+}
@@ -3,19 +3,21 @@ package report
 import (
 	"github.com/cago-frame/cago/pkg/utils/httputils"
 	"github.com/cago-frame/cago/server/mux"
+	"github.com/scriptscat/scriptlist/internal/model/entity/notification_entity"
 	"github.com/scriptscat/scriptlist/internal/model/entity/report_entity"
 	"github.com/scriptscat/scriptlist/internal/model/entity/user_entity"
 )
 
 type Comment struct {
 	user_entity.UserInfo `json:",inline"`
-	ID                   int64                     `json:"id"`
-	ReportID             int64                     `json:"report_id"`
-	Content              string                    `json:"content"`
-	Type                 report_entity.CommentType `json:"type"`
-	Status               int32                     `json:"status"`
-	Createtime           int64                     `json:"createtime"`
-	Updatetime           int64                     `json:"updatetime"`
+	ID                   int64                              `json:"id"`
+	ReportID             int64                              `json:"report_id"`
+	Content              string                             `json:"content"`
+	Type                 report_entity.CommentType          `json:"type"`
+	Mentions             []*notification_entity.MentionLink `json:"mentions,omitempty"` // 内容中@的用户
+	Status               int32                              `json:"status"`
+	Createtime           int64                              `json:"createtime"`
+	Updatetime           int64                              `json:"updatetime"`
 }
 
 // ListCommentRequest 获取举报评论列表
@@ -118,6 +118,8 @@ func Router(ctx context.Context, root *mux.Router) error {
 	// 通知中心
 	notificationCtr := notification_ctr.NewNotification()
 	notificationCtr.Router(r)
+	notificationWebhookCtr := notification_ctr.NewWebhook()
+	notificationWebhookCtr.Router(r)
 	// 资源
 	{
 		controller := resource_ctr.NewResource()
@@ -3,20 +3,22 @@ package script
 import (
 	"github.com/cago-frame/cago/pkg/utils/httputils"
 	"github.com/cago-frame/cago/server/mux"
+	"github.com/scriptscat/scriptlist/internal/model/entity/notification_entity"
 	"github.com/scriptscat/scriptlist/internal/model/entity/user_entity"
 )
 
 type Score struct {
 	user_entity.UserInfo
-	ID                      int64  `json:"id"`
-	ScriptID                int64  `json:"script_id"`
-	Score                   int64  `json:"score"`
-	Message                 string `json:"message"`
-	AuthorMessage           string `json:"author_message"`
-	AuthorMessageCreatetime int64  `json:"author_message_createtime"`
-	Createtime              int64  `json:"createtime"`
-	Updatetime              int64  `json:"updatetime"`
-	State                   int64  `json:"state"`
+	ID                      int64                              `json:"id"`
+	ScriptID                int64                              `json:"script_id"`
+	Score                   int64                              `json:"score"`
+	Message                 string                             `json:"message"`
+	AuthorMessage           string                             `json:"author_message"`
+	AuthorMessageCreatetime int64                              `json:"author_message_createtime"`
+	AuthorMessageMentions   []*notification_entity.MentionLink `json:"author_message_mentions,omitempty"` // 回复中@的用户
+	Createtime              int64                              `json:"createtime"`
+	Updatetime              int64                              `json:"updatetime"`
+	State                   int64                              `json:"state"`
 }
 
 // PutScoreRequest 脚本评分
@@ -34,6 +34,7 @@ type Script struct {
 	TotalInstall         int64                          `json:"total_install"`
 	Createtime           int64                          `json:"createtime"`
 	Updatetime           int64                          `json:"updatetime"`
+	Highlight            map[string][]string            `json:"highlight,omitempty"` // 关键字搜索时的高亮片段
 }
 
 // CategoryListItem 拥有的分类列表
@@ -63,20 +64,85 @@ type Code struct {
 	Definition           string                         `json:"definition,omitempty"`
 }
 
+// LocaleRequest 多语言参数, lang参数优先于Accept-Language请求头
+type LocaleRequest struct {
+	Lang           string `form:"lang" binding:"max=32"`
+	AcceptLanguage string `header:"Accept-Language"`
+}
+
+// Languages 按优先级返回请求的语言列表
+func (l *LocaleRequest) Languages() []string {
+	languages := script_entity.ParseAcceptLanguage(l.AcceptLanguage)
+	if lang := script_entity.NormalizeLanguage(l.Lang); lang != "" {
+		languages = append([]string{lang}, languages...)
+	}
+	return languages
+}
+
 // ListRequest 获取脚本列表
 type ListRequest struct {
 	mux.Meta              `path:"/scripts" method:"GET"`
 	httputils.PageRequest `form:",inline"`
+	LocaleRequest         `form:",inline"`
 	Keyword               string `form:"keyword"`
 	Domain                string `form:"domain"`
 	UserID                int64  `form:"user_id"`                                         // 用户ID
 	Category              int64  `form:"category"`                                        // 分类ID
 	ScriptType            int    `form:"script_type,default=0" binding:"oneof=0 1 2 3 4"` // 0:全部 1: 脚本 2: 库 3: 后台脚本 4: 定时脚本
-	Sort                  string `form:"sort,default=today_download" binding:"oneof=today_update today_download total_download score createtime updatetime"`
+	Sort                  string `form:"sort,default=today_download" binding:"oneof=today_update today_download total_download score trending createtime updatetime"`
 }
 
 type ListResponse struct {
 	httputils.PageResponse[*Script] `json:",inline"`
+	Facets                          *SearchFacets `json:"facets,omitempty"`    // 关键字搜索时返回分面统计
+	SearchID                        string        `json:"search_id,omitempty"` // 关键字搜索的第一页返回, 访问结果时回传用于统计点击率
+}
+
+// SearchFacets 搜索结果的分面统计
+type SearchFacets struct {
+	Category []*FacetItem `json:"category"`
+	Tag      []*FacetItem `json:"tag"`
+	Type     []*FacetItem `json:"type"`
+	Domain   []*FacetItem `json:"domain"`
+}
+
+type FacetItem struct {
+	Key   string `json:"key"`
+	Name  string `json:"name,omitempty"` // 分类和标签的名称
+	Count int64  `json:"count"`
+}
+
+// MatchRequest 根据网址查找会在该页面运行的脚本
+type MatchRequest struct {
+	mux.Meta              `path:"/scripts/match" method:"GET"`
+	httputils.PageRequest `form:",inline"`
+	LocaleRequest         `form:",inline"`
+	URL                   string `form:"url" binding:"required,max=2048" label:"网址"`
+}
+
+type MatchResponse struct {
+	httputils.PageResponse[*Script] `json:",inline"`
+}
+
+// SuggestRequest 搜索建议
+type SuggestRequest struct {
+	mux.Meta `path:"/scripts/suggest" method:"GET"`
+	Q        string `form:"q" binding:"required,max=64" label:"关键字"`
+	Size     int    `form:"size,default=5" binding:"min=1,max=20"` // 每一类返回的数量
+}
+
+// SuggestItem 搜索建议项, Count为排序使用的热度
+type SuggestItem struct {
+	ID    int64  `json:"id,omitempty"`
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
+}
+
+type SuggestResponse struct {
+	Scripts []*SuggestItem `json:"scripts"` // 脚本名称, 按总下载量排序
+	Tags    []*SuggestItem `json:"tags"`    // 标签, 按使用的脚本数量排序
+	Authors []*SuggestItem `json:"authors"` // 作者, 按公开脚本的总下载量排序
+	Domains []*SuggestItem `json:"domains"` // 域名, 按脚本数量排序
 }
 
 // CreateRequest 创建脚本
@@ -144,18 +210,55 @@ type DeleteCodeRequest struct {
 type DeleteCodeResponse struct {
 }
 
-// MigrateEsRequest 全量迁移数据到es
+// MigrateEsRequest 全量迁移数据到es, 存在未完成的任务时从检查点继续
 type MigrateEsRequest struct {
 	mux.Meta `path:"/scripts/migrate/es" method:"POST"`
+	// Mode inplace: 直接写入当前索引 alias: 写入新索引, 完成后原子地切换别名
+	Mode script_entity.MigrateMode `form:"mode,default=inplace" binding:"oneof=inplace alias"`
+	// Restart 忽略检查点重新开始, 存在未完成的其它模式的任务时必须指定
+	Restart bool `form:"restart"`
 }
 
 type MigrateEsResponse struct {
+	*script_entity.MigrateProgress
+}
+
+// MigrateEsProgressRequest 获取迁移数据到es的进度
+type MigrateEsProgressRequest struct {
+	mux.Meta `path:"/scripts/migrate/es" method:"GET"`
+}
+
+type MigrateEsProgressResponse struct {
+	*script_entity.MigrateProgress
+	Locked bool `json:"locked"` // 是否有机器正在执行
+}
+
+// CancelMigrateEsRequest 取消迁移数据到es, 可以之后从检查点继续
+type CancelMigrateEsRequest struct {
+	mux.Meta `path:"/scripts/migrate/es" method:"DELETE"`
+}
+
+type CancelMigrateEsResponse struct {
 }
 
 // InfoRequest 获取脚本信息
 type InfoRequest struct {
-	mux.Meta `path:"/scripts/:id" method:"GET"`
-	ID       int64 `uri:"id" binding:"required"`
+	mux.Meta      `path:"/scripts/:id" method:"GET"`
+	ID            int64 `uri:"id" binding:"required"`
+	LocaleRequest `form:",inline"`
+}
+
+// RelatedRequest 相关脚本推荐
+type RelatedRequest struct {
+	mux.Meta      `path:"/scripts/:id/related" method:"GET"`
+	ID            int64 `uri:"id" binding:"required"`
+	Size          int   `form:"size,default=6" binding:"min=1,max=20"` // 每一类返回的数量
+	LocaleRequest `form:",inline"`
+}
+
+type RelatedResponse struct {
+	Installed []*Script `json:"installed"` // 安装了该脚本的用户也安装了
+	Similar   []*Script `json:"similar"`   // 内容相似的脚本
 }
 
 type InfoResponse struct {
@@ -385,8 +488,31 @@ type LastScoreResponse struct {
 // RecordVisitRequest 记录脚本访问统计
 type RecordVisitRequest struct {
 	mux.Meta `path:"/scripts/:id/visit" method:"POST"`
-	ID       int64 `uri:"id" binding:"required"`
+	ID       int64  `uri:"id" binding:"required"`
+	SearchID string `form:"search_id" binding:"max=64"` // 从搜索结果进入时的搜索id
 }
 
 type RecordVisitResponse struct {
 }
+
+// SearchAnalyticsRequest 搜索词统计, 仅管理员
+type SearchAnalyticsRequest struct {
+	mux.Meta `path:"/scripts/search/analytics" method:"GET"`
+	Days     int `form:"days,default=7" binding:"min=1,max=90"`
+	Size     int `form:"size,default=20" binding:"min=1,max=100"`
+}
+
+// SearchQueryStat 搜索词统计
+type SearchQueryStat struct {
+	Query       string  `json:"query"`
+	Searches    int64   `json:"searches"`     // 搜索次数
+	ZeroResults int64   `json:"zero_results"` // 没有结果的次数
+	Clicked     int64   `json:"clicked"`      // 有点击结果的次数
+	CTR         float64 `json:"ctr"`          // 点击率
+}
+
+type SearchAnalyticsResponse struct {
+	Summary    *SearchQueryStat   `json:"summary"`
+	TopQueries []*SearchQueryStat `json:"top_queries"` // 搜索次数最多的词
+	ZeroResult []*SearchQueryStat `json:"zero_result"` // 没有结果的词
+}
@@ -0,0 +1,90 @@
+package statistics
+
+import (
+	"github.com/cago-frame/cago/server/mux"
+)
+
+// CollectRequest 统计数据上报
+type CollectRequest struct {
+	mux.Meta      `path:"/statistics/:key/collect" method:"POST"`
+	Key           string `uri:"key" binding:"required,max=128"`
+	SessionID     string `json:"session_id" binding:"required,max=64" label:"会话id"`
+	VisitorID     string `json:"visitor_id" binding:"required,max=64" label:"访客id"`
+	OperationPage string `json:"operation_page" binding:"required,max=1024" label:"操作页面"`
+	InstallPage   string `json:"install_page" binding:"max=1024" label:"安装页面"`
+	Duration      int32  `json:"duration" binding:"min=0" label:"停留时长"`
+	VisitTime     int64  `json:"visit_time" label:"访问时间"`
+	ExitTime      int64  `json:"exit_time" label:"退出时间"`
+	Version       string `json:"version" binding:"max=64" label:"版本"`
+}
+
+type CollectResponse struct {
+}
+
+// CollectRange 统计时间范围, 默认最近7天
+type CollectRange struct {
+	Start int64 `form:"start"`
+	End   int64 `form:"end"`
+}
+
+// CollectTop 访问排行
+type CollectTop struct {
+	Key string `json:"key"`
+	PV  int64  `json:"pv"`
+	UV  int64  `json:"uv"`
+}
+
+// CollectHostRequest 脚本运行域名统计
+type CollectHostRequest struct {
+	mux.Meta     `path:"/script/:id/statistics/collect/host" method:"GET"`
+	ID           int64 `uri:"id" binding:"required"`
+	CollectRange `form:",inline"`
+	Limit        int `form:"limit,default=20" binding:"min=1,max=100"`
+}
+
+type CollectHostResponse struct {
+	List []*CollectTop `json:"list"`
+}
+
+// CollectPageRequest 脚本运行页面统计
+type CollectPageRequest struct {
+	mux.Meta     `path:"/script/:id/statistics/collect/page" method:"GET"`
+	ID           int64 `uri:"id" binding:"required"`
+	CollectRange `form:",inline"`
+	Limit        int `form:"limit,default=20" binding:"min=1,max=100"`
+}
+
+type CollectPageResponse struct {
+	List []*CollectTop `json:"list"`
+}
+
+// CollectDurationRequest 停留时长趋势
+type CollectDurationRequest struct {
+	mux.Meta     `path:"/script/:id/statistics/collect/duration" method:"GET"`
+	ID           int64 `uri:"id" binding:"required"`
+	CollectRange `form:",inline"`
+}
+
+type CollectDurationResponse struct {
+	Sessions    *Chart  `json:"sessions"`
+	AvgDuration *FChart `json:"avg_duration"`
+}
+
+// FChart 浮点数图表
+type FChart struct {
+	X []string  `json:"x"`
+	Y []float64 `json:"y"`
+}
+
+// CollectVisitorRequest 访客趋势
+type CollectVisitorRequest struct {
+	mux.Meta     `path:"/script/:id/statistics/collect/visitor" method:"GET"`
+	ID           int64 `uri:"id" binding:"required"`
+	CollectRange `form:",inline"`
+}
+
+type CollectVisitorResponse struct {
+	Visitors    *Chart `json:"visitors"`
+	NewVisitors *Chart `json:"new_visitors"`
+	PV          *Chart `json:"pv"`
+}
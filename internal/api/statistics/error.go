@@ -0,0 +1,70 @@
+package statistics
+
+import (
+	"github.com/cago-frame/cago/pkg/utils/httputils"
+	"github.com/cago-frame/cago/server/mux"
+)
+
+// ErrorReportRequest 脚本运行错误上报, 由脚本在任意页面调用, 使用统计key识别脚本
+type ErrorReportRequest struct {
+	mux.Meta `path:"/statistics/:key/error" method:"POST"`
+	Key      string `uri:"key" binding:"required,max=128"`
+	Message  string `json:"message" binding:"required,max=1024" label:"错误信息"`
+	Stack    string `json:"stack" binding:"max=8192" label:"错误堆栈"`
+	Version  string `json:"version" binding:"max=64" label:"版本"`
+}
+
+type ErrorReportResponse struct {
+}
+
+// ErrorGroup 按指纹聚合的错误
+type ErrorGroup struct {
+	ID      int64  `json:"id"`
+	Message string `json:"message"`
+	// Host 最近一次发生错误的页面域名
+	Host string `json:"host"`
+	// Version 最近一次发生错误的版本
+	Version string `json:"version"`
+	// Count 按采样率还原后的数量
+	Count int64 `json:"count"`
+	// IssueID 转为反馈后的反馈id
+	IssueID   int64 `json:"issue_id"`
+	FirstTime int64 `json:"first_time"`
+	LastTime  int64 `json:"last_time"`
+}
+
+// ErrorGroupListRequest 脚本运行错误列表
+type ErrorGroupListRequest struct {
+	mux.Meta              `path:"/script/:id/statistics/errors" method:"GET"`
+	ID                    int64 `uri:"id" binding:"required"`
+	httputils.PageRequest `form:",inline"`
+}
+
+type ErrorGroupListResponse struct {
+	httputils.PageResponse[*ErrorGroup] `json:",inline"`
+}
+
+// ErrorGroupRequest 脚本运行错误详情
+type ErrorGroupRequest struct {
+	mux.Meta `path:"/script/:id/statistics/errors/:group_id" method:"GET"`
+	ID       int64 `uri:"id" binding:"required"`
+	GroupID  int64 `uri:"group_id" binding:"required"`
+}
+
+type ErrorGroupResponse struct {
+	*ErrorGroup `json:",inline"`
+	Stack       string `json:"stack"`
+	// Versions 各版本中发生的数量
+	Versions []*PieChart `json:"versions"`
+}
+
+// ErrorGroupIssueRequest 将错误转为脚本反馈
+type ErrorGroupIssueRequest struct {
+	mux.Meta `path:"/script/:id/statistics/errors/:group_id/issue" method:"POST"`
+	ID       int64 `uri:"id" binding:"required"`
+	GroupID  int64 `uri:"group_id" binding:"required"`
+}
+
+type ErrorGroupIssueResponse struct {
+	IssueID int64 `json:"issue_id"`
+}
@@ -0,0 +1,26 @@
+package statistics
+
+import (
+	"github.com/cago-frame/cago/server/mux"
+)
+
+// ScriptExportRequest 导出脚本每日统计数据
+type ScriptExportRequest struct {
+	mux.Meta `path:"/script/:id/statistics/export" method:"GET"`
+	ID       int64 `uri:"id" binding:"required"`
+	// 时间范围, 格式为2006-01-02, 默认为最近30天
+	Start string `form:"start" binding:"omitempty,datetime=2006-01-02"`
+	End   string `form:"end" binding:"omitempty,datetime=2006-01-02"`
+	// 导出格式: csv, ndjson
+	Format string `form:"format,default=csv" binding:"oneof=csv ndjson"`
+}
+
+// ExportRow 导出的一行数据, Dimension为total时是当天的pv和uv, 其余为访客分布与安装来源, 只有pv
+type ExportRow struct {
+	Date      string `json:"date"`
+	Op        string `json:"op"`
+	Dimension string `json:"dimension"`
+	Value     string `json:"value"`
+	Pv        int64  `json:"pv"`
+	Uv        int64  `json:"uv"`
+}
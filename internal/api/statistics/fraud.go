@@ -0,0 +1,55 @@
+package statistics
+
+import (
+	"github.com/cago-frame/cago/pkg/utils/httputils"
+	"github.com/cago-frame/cago/server/mux"
+	"github.com/scriptscat/scriptlist/internal/model/entity/script_entity"
+)
+
+// FraudReview 疑似刷量的脚本
+type FraudReview struct {
+	ID         int64                           `json:"id"`
+	ScriptID   int64                           `json:"script_id"`
+	ScriptName string                          `json:"script_name"`
+	Status     script_entity.FraudReviewStatus `json:"status"`
+	// Reason 标记原因: spike 下载量突增, ip_range 集中在同一ip段, token 同一token重复下载, ua 不可能的ua组合
+	Reason     []script_entity.FraudReason `json:"reason"`
+	Date       string                      `json:"date"`
+	Total      int64                       `json:"total"`
+	Baseline   float64                     `json:"baseline"`
+	IPNet      string                      `json:"ip_net"`
+	IPNetNum   int64                       `json:"ip_net_num"`
+	TokenNum   int64                       `json:"token_num"`
+	Impossible int64                       `json:"impossible"`
+	// Download, Trending 冻结的排行数据
+	Download   int64   `json:"download"`
+	Trending   float64 `json:"trending"`
+	ReviewUID  int64   `json:"review_uid"`
+	ReviewNote string  `json:"review_note"`
+	ReviewTime int64   `json:"review_time"`
+	Createtime int64   `json:"createtime"`
+}
+
+// FraudReviewListRequest 疑似刷量的脚本审核队列, 仅管理员
+type FraudReviewListRequest struct {
+	mux.Meta              `path:"/statistics/fraud-reviews" method:"GET"`
+	httputils.PageRequest `form:",inline"`
+	// Status 0 全部, 1 待审核, 2 确认刷量, 3 误报
+	Status script_entity.FraudReviewStatus `form:"status,default=1" binding:"oneof=0 1 2 3"`
+}
+
+type FraudReviewListResponse struct {
+	httputils.PageResponse[*FraudReview] `json:",inline"`
+}
+
+// FraudReviewRequest 审核疑似刷量的脚本, 仅管理员
+type FraudReviewRequest struct {
+	mux.Meta `path:"/statistics/fraud-reviews/:id" method:"PUT"`
+	ID       int64 `uri:"id" binding:"required"`
+	// Status 2 确认刷量, 从总下载量中扣除异常当日的下载量并清零当日下载量; 3 误报, 恢复排行数据
+	Status script_entity.FraudReviewStatus `json:"status" binding:"oneof=2 3"`
+	Note   string                          `json:"note" binding:"max=255"`
+}
+
+type FraudReviewResponse struct {
+}
@@ -8,6 +8,11 @@ import (
 type ScriptRequest struct {
 	mux.Meta `path:"/script/:id/statistics" method:"GET"`
 	ID       int64 `uri:"id" binding:"required"`
+	// 图表的时间范围, 格式为2006-01-02, 默认为最近30天
+	Start string `form:"start" binding:"omitempty,datetime=2006-01-02"`
+	End   string `form:"end" binding:"omitempty,datetime=2006-01-02"`
+	// 图表的聚合粒度: day, week, month
+	Bucket string `form:"bucket,default=day" binding:"oneof=day week month"`
 }
 
 type Overview struct {
@@ -33,6 +38,35 @@ type ScriptResponse struct {
 	UpdateUv   *Overview `json:"update_uv"`
 	UvChart    *DUChart  `json:"uv_chart"`
 	PvChart    *DUChart  `json:"pv_chart"`
+	// Visitor 时间范围内下载和更新用户的分布
+	Visitor *VisitorPie `json:"visitor"`
+	// Source 时间范围内的安装来源
+	Source *InstallSource `json:"source"`
+}
+
+// InstallSource 安装来源
+type InstallSource struct {
+	// Source 来源: site 本站, folder 收藏夹订阅, external 外部网站, direct 直接访问, 其它为链接中自定义的source参数
+	Source []*PieChart `json:"source"`
+	// Referer 外部来源网站排行
+	Referer []*PieChart     `json:"referer"`
+	Folder  []*FolderSource `json:"folder"`
+}
+
+// FolderSource 收藏夹订阅安装, 私密收藏夹不返回名称
+type FolderSource struct {
+	ID    int64  `json:"id"`
+	Name  string `json:"name"`
+	Value int64  `json:"value"`
+}
+
+// VisitorPie 访客的浏览器, 系统, 设备和脚本管理器分布
+type VisitorPie struct {
+	Browser        []*PieChart `json:"browser"`
+	OS             []*PieChart `json:"os"`
+	Device         []*PieChart `json:"device"`
+	Manager        []*PieChart `json:"manager"`
+	ManagerVersion []*PieChart `json:"manager_version"`
 }
 
 // ScriptRealtimeRequest 脚本实时统计数据
@@ -46,8 +80,30 @@ type ScriptRealtimeResponse struct {
 	Update   *Chart `json:"update"`
 }
 
+// ScriptRealtimeStreamRequest 通过SSE推送脚本实时统计数据
+type ScriptRealtimeStreamRequest struct {
+	mux.Meta `path:"/script/:id/statistics/realtime/stream" method:"GET"`
+	ID       int64 `uri:"id" binding:"required"`
+}
+
 // PieChart 饼图
 type PieChart struct {
 	Key   string `json:"key"`
 	Value int64  `json:"value"`
 }
+
+// FilteredRequest 被识别为机器人而没有计入统计的请求, 仅管理员
+type FilteredRequest struct {
+	mux.Meta `path:"/statistics/filtered" method:"GET"`
+	// ScriptID 为0时返回全站的数量
+	ScriptID int64 `form:"script_id"`
+	Days     int   `form:"days,default=7" binding:"min=1,max=90"`
+}
+
+type FilteredResponse struct {
+	View     *Chart `json:"view"`
+	Download *Chart `json:"download"`
+	Update   *Chart `json:"update"`
+	// Reason 过滤原因分布: ua 已知的爬虫, datacenter 机房ip, frequency 无cookie高频请求
+	Reason []*PieChart `json:"reason"`
+}
@@ -0,0 +1,62 @@
+package statistics
+
+import (
+	"github.com/cago-frame/cago/server/mux"
+)
+
+// UserStatisticsRequest 用户拥有或者通过访问权限管理的所有脚本的统计数据
+type UserStatisticsRequest struct {
+	mux.Meta `path:"/users/statistics" method:"GET"`
+	// 时间范围, 格式为2006-01-02, 默认为最近30天
+	Start string `form:"start" binding:"omitempty,datetime=2006-01-02"`
+	End   string `form:"end" binding:"omitempty,datetime=2006-01-02"`
+	// 图表的聚合粒度: day, week, month
+	Bucket string `form:"bucket,default=day" binding:"oneof=day week month"`
+}
+
+// Compare 时间范围内与上一个相同长度时间范围的对比
+type Compare struct {
+	Current  int64 `json:"current"`
+	Previous int64 `json:"previous"`
+}
+
+// RatingCompare 时间范围内新增的评分, Average为新增评分的平均分
+type RatingCompare struct {
+	Count           int64   `json:"count"`
+	Average         float64 `json:"average"`
+	PreviousCount   int64   `json:"previous_count"`
+	PreviousAverage float64 `json:"previous_average"`
+}
+
+// ScriptSummary 单个脚本在时间范围内的数据
+type ScriptSummary struct {
+	ScriptID int64  `json:"script_id"`
+	Name     string `json:"name"`
+	Download int64  `json:"download"`
+	Previous int64  `json:"previous"` // 上一个时间范围的下载量
+	Growth   int64  `json:"growth"`
+	Issue    int64  `json:"issue"`  // 新增反馈
+	Report   int64  `json:"report"` // 新增举报
+	// RatingCount, RatingAverage 新增的评分数量与平均分
+	RatingCount   int64   `json:"rating_count"`
+	RatingAverage float64 `json:"rating_average"`
+}
+
+type UserStatisticsResponse struct {
+	ScriptNum int            `json:"script_num"`
+	Download  *Compare       `json:"download"`
+	Update    *Compare       `json:"update"`
+	View      *Compare       `json:"view"`
+	Issue     *Compare       `json:"issue"`
+	Report    *Compare       `json:"report"`
+	Rating    *RatingCompare `json:"rating"`
+	PvChart   *UserChart     `json:"pv_chart"`
+	// Top 下载量增长最多的脚本
+	Top []*ScriptSummary `json:"top"`
+}
+
+type UserChart struct {
+	Download *Chart `json:"download"`
+	Update   *Chart `json:"update"`
+	View     *Chart `json:"view"`
+}
@@ -0,0 +1,28 @@
+package statistics
+
+import (
+	"github.com/cago-frame/cago/server/mux"
+)
+
+// ScriptVersionRequest 脚本各版本的每日活跃安装数, 来自脚本管理器的更新检查
+type ScriptVersionRequest struct {
+	mux.Meta `path:"/script/:id/statistics/version" method:"GET"`
+	ID       int64 `uri:"id" binding:"required"`
+	// 时间范围, 格式为2006-01-02, 默认为最近30天
+	Start string `form:"start" binding:"omitempty,datetime=2006-01-02"`
+	End   string `form:"end" binding:"omitempty,datetime=2006-01-02"`
+}
+
+// VersionSeries 某个版本每天的活跃安装数
+type VersionSeries struct {
+	Version string  `json:"version"`
+	Y       []int64 `json:"y"`
+}
+
+type ScriptVersionResponse struct {
+	X []string `json:"x"`
+	// 活跃安装数最多的版本, 其余的合并为other
+	Series []*VersionSeries `json:"series"`
+	// 时间范围内最后一天的版本分布
+	Latest []*PieChart `json:"latest"`
+}
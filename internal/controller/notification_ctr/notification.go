@@ -24,6 +24,7 @@ func (n *Notification) Router(r *mux.Router) {
 			n.GetUnreadCount,
 			n.MarkRead,
 			n.BatchMarkRead,
+			n.Stream,
 		},
 	}})
 }
@@ -52,3 +53,8 @@ func (n *Notification) BatchMarkRead(ctx *gin.Context, req *api.BatchMarkReadReq
 	}
 	return &api.BatchMarkReadResponse{}, nil
 }
+
+// Stream 通过SSE推送新通知和未读数
+func (n *Notification) Stream(ctx *gin.Context, req *api.StreamRequest) error {
+	return notification_svc.Notification().Stream(ctx, req)
+}
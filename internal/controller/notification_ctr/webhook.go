@@ -0,0 +1,64 @@
+package notification_ctr
+
+import (
+	"strconv"
+
+	"github.com/cago-frame/cago/database/redis"
+	"github.com/cago-frame/cago/pkg/limit"
+	"github.com/cago-frame/cago/pkg/utils/muxutils"
+	"github.com/cago-frame/cago/server/mux"
+	"github.com/gin-gonic/gin"
+	api "github.com/scriptscat/scriptlist/internal/api/notification"
+	"github.com/scriptscat/scriptlist/internal/service/auth_svc"
+	"github.com/scriptscat/scriptlist/internal/service/notification_svc"
+)
+
+type Webhook struct {
+	limit *limit.PeriodLimit
+}
+
+func NewWebhook() *Webhook {
+	return &Webhook{
+		limit: limit.NewPeriodLimit(
+			60, 5, redis.Default(), "limit:notification:webhook:ping",
+		),
+	}
+}
+
+func (w *Webhook) Router(r *mux.Router) {
+	muxutils.BindTree(r, []*muxutils.RouterTree{{
+		Middleware: []gin.HandlerFunc{auth_svc.Auth().RequireLogin(true)},
+		Handler: []interface{}{
+			w.GetWebhook,
+			w.UpdateWebhook,
+			w.ListWebhookLog,
+			w.PingWebhook,
+		},
+	}})
+}
+
+// GetWebhook 获取webhook配置
+func (w *Webhook) GetWebhook(ctx *gin.Context, req *api.GetWebhookRequest) (*api.GetWebhookResponse, error) {
+	return notification_svc.Webhook().GetWebhook(ctx, req)
+}
+
+// UpdateWebhook 更新webhook配置
+func (w *Webhook) UpdateWebhook(ctx *gin.Context, req *api.UpdateWebhookRequest) (*api.UpdateWebhookResponse, error) {
+	return notification_svc.Webhook().UpdateWebhook(ctx, req)
+}
+
+// ListWebhookLog webhook投递记录
+func (w *Webhook) ListWebhookLog(ctx *gin.Context, req *api.ListWebhookLogRequest) (*api.ListWebhookLogResponse, error) {
+	return notification_svc.Webhook().ListWebhookLog(ctx, req)
+}
+
+// PingWebhook 发送测试请求
+func (w *Webhook) PingWebhook(ctx *gin.Context, req *api.PingWebhookRequest) (*api.PingWebhookResponse, error) {
+	resp, err := w.limit.FuncTake(ctx, strconv.FormatInt(auth_svc.Auth().Get(ctx).UID, 10), func() (interface{}, error) {
+		return notification_svc.Webhook().PingWebhook(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*api.PingWebhookResponse), nil
+}
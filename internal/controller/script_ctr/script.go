@@ -23,6 +23,7 @@ import (
 	api "github.com/scriptscat/scriptlist/internal/api/script"
 	"github.com/scriptscat/scriptlist/internal/model"
 	"github.com/scriptscat/scriptlist/internal/model/entity/script_entity"
+	"github.com/scriptscat/scriptlist/internal/model/entity/statistics_entity"
 	"github.com/scriptscat/scriptlist/internal/pkg/code"
 	"github.com/scriptscat/scriptlist/internal/repository/statistics_repo"
 	"github.com/scriptscat/scriptlist/internal/service/auth_svc"
@@ -33,7 +34,8 @@ import (
 )
 
 type Script struct {
-	limit limit.Limit
+	limit        limit.Limit
+	suggestLimit limit.Limit
 }
 
 func NewScript() *Script {
@@ -43,6 +45,9 @@ func NewScript() *Script {
 		), limit.NewPeriodLimit(
 			3600, 8, redis.Default(), "limit:create:script:hour",
 		)),
+		suggestLimit: limit.NewPeriodLimit(
+			60, 60, redis.Default(), "limit:suggest:script",
+		),
 	}
 }
 
@@ -71,6 +76,8 @@ func (s *Script) Router(root *mux.Router, r *mux.Router) {
 			},
 			Handler: []interface{}{
 				s.List,
+				s.Match,
+				s.Suggest,
 				s.LastScore,
 			},
 		},
@@ -82,6 +89,7 @@ func (s *Script) Router(root *mux.Router, r *mux.Router) {
 			},
 			Handler: []interface{}{
 				s.Info,
+				s.Related,
 				s.Code,
 				s.VersionList,
 				s.VersionCode,
@@ -96,6 +104,9 @@ func (s *Script) Router(root *mux.Router, r *mux.Router) {
 			Handler: []interface{}{
 				s.Create,
 				s.MigrateEs,
+				s.MigrateEsProgress,
+				s.CancelMigrateEs,
+				s.SearchAnalytics,
 				// 需要检查脚本状态
 				&muxutils.RouterTree{
 					Middleware: []gin.HandlerFunc{script_svc.Script().RequireScript()},
@@ -152,6 +163,28 @@ func (s *Script) List(ctx context.Context, req *api.ListRequest) (*api.ListRespo
 	return script_svc.Script().List(ctx, req)
 }
 
+// Match 根据网址查找会在该页面运行的脚本
+func (s *Script) Match(ctx context.Context, req *api.MatchRequest) (*api.MatchResponse, error) {
+	return script_svc.Script().Match(ctx, req)
+}
+
+// Suggest 搜索建议
+func (s *Script) Suggest(ctx *gin.Context, req *api.SuggestRequest) (*api.SuggestResponse, error) {
+	// 根据ip限流
+	resp, err := s.suggestLimit.FuncTake(ctx, ctx.ClientIP(), func() (interface{}, error) {
+		return script_svc.Script().Suggest(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*api.SuggestResponse), nil
+}
+
+// Related 相关脚本推荐
+func (s *Script) Related(ctx context.Context, req *api.RelatedRequest) (*api.RelatedResponse, error) {
+	return script_svc.Script().Related(ctx, req)
+}
+
 // Create 创建脚本/库
 func (s *Script) Create(ctx context.Context, req *api.CreateRequest) (*api.CreateResponse, error) {
 	resp, err := s.limit.FuncTake(ctx, strconv.FormatInt(auth_svc.Auth().Get(ctx).UID, 10), func() (interface{}, error) {
@@ -179,8 +212,31 @@ func (s *Script) MigrateEs(ctx context.Context, req *api.MigrateEsRequest) (*api
 	if auth_svc.Auth().Get(ctx).AdminLevel != model.Admin {
 		return nil, httputils.NewError(http.StatusForbidden, -1, "无权限")
 	}
-	go script_svc.Script().MigrateEs()
-	return &api.MigrateEsResponse{}, nil
+	return script_svc.Script().MigrateEs(ctx, req)
+}
+
+// SearchAnalytics 搜索词统计
+func (s *Script) SearchAnalytics(ctx context.Context, req *api.SearchAnalyticsRequest) (*api.SearchAnalyticsResponse, error) {
+	if auth_svc.Auth().Get(ctx).AdminLevel != model.Admin {
+		return nil, httputils.NewError(http.StatusForbidden, -1, "无权限")
+	}
+	return script_svc.Script().SearchAnalytics(ctx, req)
+}
+
+// MigrateEsProgress 获取迁移数据到es的进度
+func (s *Script) MigrateEsProgress(ctx context.Context, req *api.MigrateEsProgressRequest) (*api.MigrateEsProgressResponse, error) {
+	if auth_svc.Auth().Get(ctx).AdminLevel != model.Admin {
+		return nil, httputils.NewError(http.StatusForbidden, -1, "无权限")
+	}
+	return script_svc.Script().MigrateEsProgress(ctx, req)
+}
+
+// CancelMigrateEs 取消迁移数据到es
+func (s *Script) CancelMigrateEs(ctx context.Context, req *api.CancelMigrateEsRequest) (*api.CancelMigrateEsResponse, error) {
+	if auth_svc.Auth().Get(ctx).AdminLevel != model.Admin {
+		return nil, httputils.NewError(http.StatusForbidden, -1, "无权限")
+	}
+	return script_svc.Script().CancelMigrateEs(ctx, req)
 }
 
 func (s *Script) Download(pre bool) gin.HandlerFunc {
@@ -269,8 +325,14 @@ func (s *Script) downloadScript(ctx *gin.Context, id int64, version string, pre
 		IP:              ctx.ClientIP(),
 		UA:              ua,
 		StatisticsToken: statistics_svc.Statistics().GetStatisticsToken(ctx),
+		NoCookie:        !statistics_svc.Statistics().HasStatisticsToken(ctx),
 		Download:        statistics_repo.DownloadScriptStatistics,
 		Time:            time.Now(),
+		Referer:         ctx.GetHeader("Referer"),
+		Source:          ctx.Query("source"),
+	}
+	if record.Source == string(statistics_entity.InstallSourceFolder) {
+		record.FolderID, _ = strconv.ParseInt(ctx.Query("folder"), 10, 64)
 	}
 	user := auth_svc.Auth().Get(ctx)
 	if user != nil {
@@ -322,7 +384,9 @@ func (s *Script) downloadSubscribe(ctx *gin.Context, id int64) {
 	code += "// @version " + strconv.FormatInt(folder.Updatetime, 10) + "\n"
 	code += "// @author " + folder.Username + "\n"
 	for _, v := range list {
-		code += "// @scriptUrl " + configs.Url() + "/scripts/code/" + strconv.FormatInt(v.ID, 10) + "/" + v.Name + ".user.js\n"
+		// 带上来源参数, 通过订阅安装的归因到收藏夹
+		code += "// @scriptUrl " + configs.Url() + "/scripts/code/" + strconv.FormatInt(v.ID, 10) + "/" + v.Name +
+			".user.js?source=folder&folder=" + strconv.FormatInt(folder.ID, 10) + "\n"
 	}
 	code += "// ==/UserSubscribe==\n"
 
@@ -366,8 +430,10 @@ func (s *Script) getScriptMeta(ctx *gin.Context, version string, pre bool) {
 		IP:              ctx.ClientIP(),
 		UA:              ua,
 		StatisticsToken: statistics_svc.Statistics().GetStatisticsToken(ctx),
+		NoCookie:        !statistics_svc.Statistics().HasStatisticsToken(ctx),
 		Download:        statistics_repo.UpdateScriptStatistics,
 		Time:            time.Now(),
+		Version:         statistics_svc.Statistics().ClientVersion(ctx),
 	}
 	user := auth_svc.Auth().Get(ctx)
 	if user != nil {
@@ -2,32 +2,82 @@ package statistics_ctr
 
 import (
 	"context"
+	"net/http"
 
+	"github.com/cago-frame/cago/database/redis"
+	"github.com/cago-frame/cago/pkg/limit"
+	"github.com/cago-frame/cago/pkg/utils/httputils"
 	"github.com/cago-frame/cago/pkg/utils/muxutils"
 	"github.com/cago-frame/cago/server/mux"
+	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	api "github.com/scriptscat/scriptlist/internal/api/statistics"
+	"github.com/scriptscat/scriptlist/internal/model"
 	"github.com/scriptscat/scriptlist/internal/service/auth_svc"
 	"github.com/scriptscat/scriptlist/internal/service/script_svc"
 	"github.com/scriptscat/scriptlist/internal/service/statistics_svc"
 )
 
 type Statistics struct {
+	collectLimit limit.Limit
+	errorLimit   limit.Limit
 }
 
 func NewStatistics() *Statistics {
-	return &Statistics{}
+	return &Statistics{
+		collectLimit: limit.NewPeriodLimit(
+			60, 120, redis.Default(), "limit:statistics:collect",
+		),
+		errorLimit: limit.NewPeriodLimit(
+			60, 30, redis.Default(), "limit:statistics:error",
+		),
+	}
 }
 
 func (s *Statistics) Router(r *mux.Router) {
-	muxutils.BindTree(r, []*muxutils.RouterTree{muxutils.
-		Use(
-			auth_svc.Auth().RequireLogin(true),
-			script_svc.Script().RequireScript(script_svc.WithRequireScriptAccess("statistics", "manage")),
-		).Append(
-		s.Script,
-		s.ScriptRealtime,
-	)})
+	// 统计上报, 由脚本在任意页面调用, 允许跨域
+	cfg := cors.DefaultConfig()
+	cfg.AllowAllOrigins = true
+	r.OPTIONS("/statistics/:key/collect", cors.New(cfg))
+	r.OPTIONS("/statistics/:key/error", cors.New(cfg))
+	r.Group("/", cors.New(cfg)).Bind(
+		s.Collect,
+		s.ErrorReport,
+	)
+	muxutils.BindTree(r, []*muxutils.RouterTree{{
+		Middleware: []gin.HandlerFunc{auth_svc.Auth().RequireLogin(true)},
+		Handler: []interface{}{
+			s.Filtered,
+			s.FraudReviewList,
+			s.FraudReview,
+			s.UserStatistics,
+			&muxutils.RouterTree{
+				Middleware: []gin.HandlerFunc{
+					script_svc.Script().RequireScript(script_svc.WithRequireScriptAccess("statistics", "manage")),
+				},
+				Handler: []interface{}{
+					s.Script,
+					s.ScriptRealtime,
+					s.ScriptRealtimeStream,
+					s.ScriptVersion,
+					s.ErrorGroupList,
+					s.ErrorGroup,
+					s.ErrorGroupIssue,
+					s.CollectHost,
+					s.CollectPage,
+					s.CollectDuration,
+					s.CollectVisitor,
+				},
+			},
+			&muxutils.RouterTree{
+				// 导出与统计页面使用相同的权限
+				Middleware: []gin.HandlerFunc{s.Middleware()},
+				Handler: []interface{}{
+					s.ScriptExport,
+				},
+			},
+		},
+	}})
 }
 
 // Script 脚本统计数据
@@ -40,6 +90,109 @@ func (s *Statistics) ScriptRealtime(ctx context.Context, req *api.ScriptRealtime
 	return statistics_svc.Statistics().ScriptRealtime(ctx, req)
 }
 
+// ScriptRealtimeStream 通过SSE推送脚本实时统计数据
+func (s *Statistics) ScriptRealtimeStream(ctx *gin.Context, req *api.ScriptRealtimeStreamRequest) error {
+	return statistics_svc.Statistics().ScriptRealtimeStream(ctx, req)
+}
+
+// ScriptVersion 脚本各版本的每日活跃安装数
+func (s *Statistics) ScriptVersion(ctx context.Context, req *api.ScriptVersionRequest) (*api.ScriptVersionResponse, error) {
+	return statistics_svc.Statistics().ScriptVersion(ctx, req)
+}
+
+// ScriptExport 导出脚本每日统计数据
+func (s *Statistics) ScriptExport(ctx *gin.Context, req *api.ScriptExportRequest) error {
+	return statistics_svc.Statistics().ScriptExport(ctx, req)
+}
+
+// Filtered 被识别为机器人而没有计入统计的请求
+func (s *Statistics) Filtered(ctx context.Context, req *api.FilteredRequest) (*api.FilteredResponse, error) {
+	if auth_svc.Auth().Get(ctx).AdminLevel != model.Admin {
+		return nil, httputils.NewError(http.StatusForbidden, -1, "无权限")
+	}
+	return statistics_svc.Statistics().Filtered(ctx, req)
+}
+
+// FraudReviewList 疑似刷量的脚本审核队列
+func (s *Statistics) FraudReviewList(ctx context.Context, req *api.FraudReviewListRequest) (*api.FraudReviewListResponse, error) {
+	if auth_svc.Auth().Get(ctx).AdminLevel != model.Admin {
+		return nil, httputils.NewError(http.StatusForbidden, -1, "无权限")
+	}
+	return statistics_svc.Statistics().FraudReviewList(ctx, req)
+}
+
+// FraudReview 审核疑似刷量的脚本
+func (s *Statistics) FraudReview(ctx context.Context, req *api.FraudReviewRequest) (*api.FraudReviewResponse, error) {
+	if auth_svc.Auth().Get(ctx).AdminLevel != model.Admin {
+		return nil, httputils.NewError(http.StatusForbidden, -1, "无权限")
+	}
+	return statistics_svc.Statistics().FraudReview(ctx, req)
+}
+
+// UserStatistics 用户拥有或者管理的所有脚本的统计数据
+func (s *Statistics) UserStatistics(ctx context.Context, req *api.UserStatisticsRequest) (*api.UserStatisticsResponse, error) {
+	return statistics_svc.Statistics().UserStatistics(ctx, req)
+}
+
+// Collect 统计数据上报
+func (s *Statistics) Collect(ctx *gin.Context, req *api.CollectRequest) (*api.CollectResponse, error) {
+	// 根据ip限流
+	resp, err := s.collectLimit.FuncTake(ctx, ctx.ClientIP(), func() (interface{}, error) {
+		return statistics_svc.Statistics().Collect(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*api.CollectResponse), nil
+}
+
+// ErrorReport 脚本运行错误上报
+func (s *Statistics) ErrorReport(ctx *gin.Context, req *api.ErrorReportRequest) (*api.ErrorReportResponse, error) {
+	// 根据ip限流
+	resp, err := s.errorLimit.FuncTake(ctx, ctx.ClientIP(), func() (interface{}, error) {
+		return statistics_svc.Statistics().ErrorReport(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*api.ErrorReportResponse), nil
+}
+
+// ErrorGroupList 脚本运行错误列表
+func (s *Statistics) ErrorGroupList(ctx context.Context, req *api.ErrorGroupListRequest) (*api.ErrorGroupListResponse, error) {
+	return statistics_svc.Statistics().ErrorGroupList(ctx, req)
+}
+
+// ErrorGroup 脚本运行错误详情
+func (s *Statistics) ErrorGroup(ctx context.Context, req *api.ErrorGroupRequest) (*api.ErrorGroupResponse, error) {
+	return statistics_svc.Statistics().ErrorGroup(ctx, req)
+}
+
+// ErrorGroupIssue 将错误转为脚本反馈
+func (s *Statistics) ErrorGroupIssue(ctx context.Context, req *api.ErrorGroupIssueRequest) (*api.ErrorGroupIssueResponse, error) {
+	return statistics_svc.Statistics().ErrorGroupIssue(ctx, req)
+}
+
+// CollectHost 脚本运行域名统计
+func (s *Statistics) CollectHost(ctx context.Context, req *api.CollectHostRequest) (*api.CollectHostResponse, error) {
+	return statistics_svc.Statistics().CollectHost(ctx, req)
+}
+
+// CollectPage 脚本运行页面统计
+func (s *Statistics) CollectPage(ctx context.Context, req *api.CollectPageRequest) (*api.CollectPageResponse, error) {
+	return statistics_svc.Statistics().CollectPage(ctx, req)
+}
+
+// CollectDuration 停留时长趋势
+func (s *Statistics) CollectDuration(ctx context.Context, req *api.CollectDurationRequest) (*api.CollectDurationResponse, error) {
+	return statistics_svc.Statistics().CollectDuration(ctx, req)
+}
+
+// CollectVisitor 访客趋势
+func (s *Statistics) CollectVisitor(ctx context.Context, req *api.CollectVisitorRequest) (*api.CollectVisitorResponse, error) {
+	return statistics_svc.Statistics().CollectVisitor(ctx, req)
+}
+
 func (s *Statistics) Middleware() gin.HandlerFunc {
 	return statistics_svc.Statistics().Middleware()
 }
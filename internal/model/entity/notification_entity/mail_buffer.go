@@ -0,0 +1,15 @@
+package notification_entity
+
+// NotificationMailBuffer 等待汇总发送的邮件通知
+type NotificationMailBuffer struct {
+	ID         int64  `gorm:"column:id;type:bigint(20);not null;primary_key;autoIncrement"`
+	UserID     int64  `gorm:"column:user_id;type:bigint(20);not null;index:idx_user_id"`
+	FromUserID int64  `gorm:"column:from_user_id;type:bigint(20);default:0"`
+	Type       Type   `gorm:"column:type;type:int(11);not null"`
+	ScriptID   int64  `gorm:"column:script_id;type:bigint(20);default:0"`
+	ScriptName string `gorm:"column:script_name;type:varchar(255);default:''"`
+	// Title 渲染后的通知标题, 包含脚本名称且已经转义, 长度可能超过255
+	Title      string `gorm:"column:title;type:text;not null"`
+	Link       string `gorm:"column:link;type:varchar(512);default:''"`
+	Createtime int64  `gorm:"column:createtime;type:bigint(20)"`
+}
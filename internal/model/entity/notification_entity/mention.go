@@ -0,0 +1,66 @@
+package notification_entity
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// MentionTarget 提及所在的内容类型
+type MentionTarget int
+
+const (
+	MentionTargetIssue         MentionTarget = iota + 1 // 反馈
+	MentionTargetIssueComment                           // 反馈评论
+	MentionTargetReportComment                          // 举报评论
+	MentionTargetScoreReply                             // 评分回复
+)
+
+// MentionLimit 单条内容最多提及的用户数
+const MentionLimit = 20
+
+// @前不能是字母数字, 避免匹配邮箱地址
+var mentionRegexp = regexp.MustCompile(`(?:^|[^\p{L}\p{N}_@.])@([\p{L}\p{N}_\-]{1,32})`)
+
+// ParseMentions 解析内容中@的用户名, 去重并保持出现顺序
+func ParseMentions(content string) []string {
+	ret := make([]string, 0)
+	exist := make(map[string]struct{})
+	for _, m := range mentionRegexp.FindAllStringSubmatch(content, -1) {
+		if _, ok := exist[m[1]]; ok {
+			continue
+		}
+		exist[m[1]] = struct{}{}
+		ret = append(ret, m[1])
+		if len(ret) >= MentionLimit {
+			break
+		}
+	}
+	return ret
+}
+
+// Mention 内容中@的用户
+type Mention struct {
+	ID         int64         `gorm:"column:id;type:bigint(20);not null;primary_key;autoIncrement"`
+	TargetType MentionTarget `gorm:"column:target_type;type:tinyint(4);not null;index:idx_target,priority:1"`
+	TargetID   int64         `gorm:"column:target_id;type:bigint(20);not null;index:idx_target,priority:2"`
+	ScriptID   int64         `gorm:"column:script_id;type:bigint(20);not null"`
+	UserID     int64         `gorm:"column:user_id;type:bigint(20);not null;index:idx_user_id"` // 被提及的用户
+	Username   string        `gorm:"column:username;type:varchar(255);not null"`
+	FromUserID int64         `gorm:"column:from_user_id;type:bigint(20);not null"`
+	Createtime int64         `gorm:"column:createtime;type:bigint(20)"`
+}
+
+// MentionLink 提及的用户, 用于将内容中的@用户名渲染为个人主页链接
+type MentionLink struct {
+	UserID   int64  `json:"user_id"`
+	Username string `json:"username"`
+	Link     string `json:"link"`
+}
+
+func (m *Mention) Link() *MentionLink {
+	return &MentionLink{
+		UserID:   m.UserID,
+		Username: m.Username,
+		Link:     fmt.Sprintf("/users/%d", m.UserID),
+	}
+}
@@ -0,0 +1,32 @@
+package notification_entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMentions(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{"开头", "@user1 你好", []string{"user1"}},
+		{"多个用户并去重", "感谢 @user1 和 @王五, 还有@user1", []string{"user1", "王五"}},
+		{"标点结尾", "请看看(@user-2).", []string{"user-2"}},
+		{"邮箱地址", "联系 test@example.com", []string{}},
+		{"连续的@", "@@user1", []string{}},
+		{"无提及", "没有提及", []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ParseMentions(tt.content))
+		})
+	}
+	content := ""
+	for i := 0; i < MentionLimit+5; i++ {
+		content += " @u" + string(rune('a'+i))
+	}
+	assert.Len(t, ParseMentions(content), MentionLimit)
+}
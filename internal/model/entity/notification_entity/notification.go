@@ -22,6 +22,7 @@ const (
 	ReportCreateTemplate                       // 举报创建
 	ReportCommentTemplate                      // 举报评论
 	ScriptDeleteTemplate                       // 脚本删除
+	MentionTemplate                            // @提及
 )
 
 // 已读状态
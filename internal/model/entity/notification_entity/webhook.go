@@ -0,0 +1,166 @@
+package notification_entity
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/cago-frame/cago/pkg/consts"
+	"github.com/cago-frame/cago/pkg/i18n"
+	"github.com/cago-frame/cago/pkg/utils"
+	"github.com/scriptscat/scriptlist/internal/pkg/code"
+)
+
+// webhook事件
+const (
+	WebhookEventNotification = "notification" // 通知
+	WebhookEventPing         = "ping"         // 测试
+)
+
+// 投递状态
+const (
+	WebhookDeliverySuccess int32 = 1 // 成功
+	WebhookDeliveryFailed  int32 = 2 // 失败
+)
+
+// NotificationWebhook 用户配置的通知webhook, 每个用户一个
+type NotificationWebhook struct {
+	ID         int64  `gorm:"column:id;type:bigint(20);not null;primary_key;autoIncrement"`
+	UserID     int64  `gorm:"column:user_id;type:bigint(20);not null;uniqueIndex:idx_user_id"`
+	URL        string `gorm:"column:url;type:varchar(512);not null"`
+	Secret     string `gorm:"column:secret;type:varchar(64);not null"`   // 签名密钥
+	Types      string `gorm:"column:types;type:varchar(255);default:''"` // 订阅的通知类型, 逗号分隔, 为空时订阅全部
+	Status     int32  `gorm:"column:status;type:tinyint(4);default:1;not null"`
+	Createtime int64  `gorm:"column:createtime;type:bigint(20)"`
+	Updatetime int64  `gorm:"column:updatetime;type:bigint(20)"`
+}
+
+// CheckURL 检查webhook地址, 只允许http和https
+func CheckURL(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return i18n.NewError(ctx, code.NotificationWebhookURLInvalid)
+	}
+	// 直接填写ip时提前拦截内网地址, 域名在投递时检查
+	if ip := net.ParseIP(u.Hostname()); ip != nil && !IsPublicIP(ip) {
+		return i18n.NewError(ctx, code.NotificationWebhookURLInvalid)
+	}
+	return nil
+}
+
+// 标准库没有覆盖的保留地址, 也不允许投递
+var reservedNets = func() []*net.IPNet {
+	list := []string{
+		"0.0.0.0/8",       // 本网络
+		"100.64.0.0/10",   // 运营商级NAT
+		"192.0.0.0/24",    // IETF协议分配
+		"192.0.2.0/24",    // 文档地址
+		"198.18.0.0/15",   // 基准测试
+		"198.51.100.0/24", // 文档地址
+		"203.0.113.0/24",  // 文档地址
+		"240.0.0.0/4",     // 保留地址与广播地址
+		"64:ff9b::/96",    // NAT64, 可以映射到内网的ipv4地址
+		"64:ff9b:1::/48",  // 本地NAT64
+		"2001:db8::/32",   // 文档地址
+		"2002::/16",       // 6to4, 可以映射到内网的ipv4地址
+	}
+	ret := make([]*net.IPNet, 0, len(list))
+	for _, v := range list {
+		_, n, err := net.ParseCIDR(v)
+		if err != nil {
+			panic(err)
+		}
+		ret = append(ret, n)
+	}
+	return ret
+}()
+
+// IsPublicIP 是否为公网地址
+func IsPublicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+	for _, n := range reservedNets {
+		if n.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// CheckOperate 检查webhook是否可用
+func (w *NotificationWebhook) CheckOperate(ctx context.Context) error {
+	if w == nil {
+		return i18n.NewErrorWithStatus(ctx, http.StatusNotFound, code.NotificationWebhookNotFound)
+	}
+	return nil
+}
+
+// ResetSecret 重新生成签名密钥
+func (w *NotificationWebhook) ResetSecret() {
+	w.Secret = utils.RandString(32, utils.Mix)
+}
+
+// IsActive 是否启用
+func (w *NotificationWebhook) IsActive() bool {
+	return w.Status == consts.ACTIVE
+}
+
+// GetTypes 订阅的通知类型
+func (w *NotificationWebhook) GetTypes() []Type {
+	ret := make([]Type, 0)
+	for _, v := range strings.Split(w.Types, ",") {
+		t, err := strconv.Atoi(v)
+		if err != nil {
+			continue
+		}
+		ret = append(ret, Type(t))
+	}
+	return ret
+}
+
+// SetTypes 设置订阅的通知类型
+func (w *NotificationWebhook) SetTypes(types []Type) {
+	list := make([]string, 0, len(types))
+	for _, t := range types {
+		list = append(list, strconv.Itoa(int(t)))
+	}
+	w.Types = strings.Join(list, ",")
+}
+
+// Subscribed 是否订阅了该类型的通知
+func (w *NotificationWebhook) Subscribed(t Type) bool {
+	types := w.GetTypes()
+	if len(types) == 0 {
+		return true
+	}
+	for _, v := range types {
+		if v == t {
+			return true
+		}
+	}
+	return false
+}
+
+// NotificationWebhookLog webhook投递记录, 每次尝试一条
+type NotificationWebhookLog struct {
+	ID         int64  `gorm:"column:id;type:bigint(20);not null;primary_key;autoIncrement"`
+	WebhookID  int64  `gorm:"column:webhook_id;type:bigint(20);not null"`
+	UserID     int64  `gorm:"column:user_id;type:bigint(20);not null;index:idx_user_id"`
+	Delivery   string `gorm:"column:delivery;type:varchar(64);not null"` // 投递id, 重试时不变
+	Event      string `gorm:"column:event;type:varchar(32);not null"`
+	Type       Type   `gorm:"column:type;type:int(11);default:0"`
+	URL        string `gorm:"column:url;type:varchar(512);not null"`
+	Payload    string `gorm:"column:payload;type:text"`
+	Attempt    int    `gorm:"column:attempt;type:int(11);default:1"`
+	StatusCode int    `gorm:"column:status_code;type:int(11);default:0"`
+	Response   string `gorm:"column:response;type:varchar(1024);default:''"`
+	Error      string `gorm:"column:error;type:varchar(1024);default:''"`
+	Duration   int64  `gorm:"column:duration;type:bigint(20);default:0"` // 耗时, 毫秒
+	Status     int32  `gorm:"column:status;type:tinyint(4);not null"`
+	Createtime int64  `gorm:"column:createtime;type:bigint(20);index:idx_createtime"`
+}
@@ -0,0 +1,50 @@
+package notification_entity
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotificationWebhook_Subscribed(t *testing.T) {
+	w := &NotificationWebhook{}
+	assert.True(t, w.Subscribed(IssueCreateTemplate))
+	w.SetTypes([]Type{IssueCreateTemplate, ScriptScoreTemplate})
+	assert.Equal(t, "101,103", w.Types)
+	assert.Equal(t, []Type{IssueCreateTemplate, ScriptScoreTemplate}, w.GetTypes())
+	assert.True(t, w.Subscribed(ScriptScoreTemplate))
+	assert.False(t, w.Subscribed(ReportCreateTemplate))
+}
+
+func TestCheckURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		wantErr bool
+	}{
+		{"https", "https://example.com/hook", false},
+		{"http带端口", "http://example.com:8080/hook", false},
+		{"公网ip", "http://8.8.8.8/hook", false},
+		{"其它协议", "ftp://example.com/hook", true},
+		{"缺少域名", "https:///hook", true},
+		{"本地地址", "http://127.0.0.1:8080/hook", true},
+		{"内网地址", "http://192.168.1.1/hook", true},
+		{"ipv6本地地址", "http://[::1]/hook", true},
+		{"运营商级NAT", "http://100.64.1.1/hook", true},
+		{"本网络地址", "http://0.1.2.3/hook", true},
+		{"基准测试地址", "http://198.18.0.1/hook", true},
+		{"IETF协议地址", "http://192.0.0.1/hook", true},
+		{"NAT64地址", "http://[64:ff9b::a00:1]/hook", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckURL(context.Background(), tt.url)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package script_entity
+
+type MigrateStatus string
+
+const (
+	MigrateStatusRunning   MigrateStatus = "running"   // 进行中, 如果进程重启会被自动恢复
+	MigrateStatusCancelled MigrateStatus = "cancelled" // 已取消, 可以从检查点恢复
+	MigrateStatusFailed    MigrateStatus = "failed"    // 失败, 可以从检查点恢复
+	MigrateStatusFinished  MigrateStatus = "finished"  // 已完成
+)
+
+type MigrateMode string
+
+const (
+	MigrateModeInplace MigrateMode = "inplace" // 直接写入当前索引
+	MigrateModeAlias   MigrateMode = "alias"   // 写入新索引, 完成后原子地切换别名
+)
+
+// MigrateProgress 重建搜索索引的进度
+type MigrateProgress struct {
+	Status     MigrateStatus `json:"status"`
+	Mode       MigrateMode   `json:"mode"`
+	Index      string        `json:"index"`   // alias模式下写入的新索引
+	LastID     int64         `json:"last_id"` // 检查点, 已处理的最大脚本id
+	Total      int64         `json:"total"`
+	Processed  int64         `json:"processed"`
+	Failed     int64         `json:"failed"`
+	LastError  string        `json:"last_error"`
+	Starttime  int64         `json:"starttime"`
+	Updatetime int64         `json:"updatetime"`
+}
+
+// Resumable 是否可以从检查点恢复
+func (m *MigrateProgress) Resumable() bool {
+	return m != nil && (m.Status == MigrateStatusRunning ||
+		m.Status == MigrateStatusCancelled || m.Status == MigrateStatusFailed)
+}
@@ -0,0 +1,70 @@
+package script_entity
+
+import "strings"
+
+type FraudReviewStatus int
+
+const (
+	FraudReviewPending   FraudReviewStatus = 1 // 待审核, 冻结排行数据
+	FraudReviewConfirmed FraudReviewStatus = 2 // 确认刷量, 扣除异常当日的下载量
+	FraudReviewDismissed FraudReviewStatus = 3 // 误报
+)
+
+// FraudReason 被标记为刷量的原因
+type FraudReason string
+
+const (
+	FraudReasonSpike   FraudReason = "spike"    // 下载量相对于基线突增
+	FraudReasonIPRange FraudReason = "ip_range" // 下载集中在同一个ip段
+	FraudReasonToken   FraudReason = "token"    // 同一个统计token在不同ip下重复下载
+	FraudReasonUA      FraudReason = "ua"       // 不可能存在的ua组合占比过高
+)
+
+// ScriptFraudReview 疑似刷量的脚本审核队列
+// 待审核时, 脚本在排行中今日下载与今日更新记为0, 总下载量与热度冻结为标记时的值
+type ScriptFraudReview struct {
+	ID       int64             `gorm:"column:id;type:bigint(20);not null;primary_key"`
+	ScriptID int64             `gorm:"column:script_id;type:bigint(20);not null;index:script_status"`
+	Status   FraudReviewStatus `gorm:"column:status;type:tinyint(2);not null;index:script_status;index:status"`
+	Reason   string            `gorm:"column:reason;type:varchar(255)"` // 多个原因以逗号分隔
+	Date     string            `gorm:"column:date;type:varchar(10)"`    // 异常的日期
+	// 标记时的检测数据
+	Total      int64   `gorm:"column:total;type:bigint(20)"`   // 当日下载量
+	Baseline   float64 `gorm:"column:baseline;type:double"`    // 前7天的平均下载量
+	IPNet      string  `gorm:"column:ip_net;type:varchar(64)"` // 下载最多的ip段
+	IPNetNum   int64   `gorm:"column:ip_net_num;type:bigint(20)"`
+	TokenNum   int64   `gorm:"column:token_num;type:bigint(20)"`  // 同一token最多的下载次数
+	Impossible int64   `gorm:"column:impossible;type:bigint(20)"` // 不可能的ua组合数量
+	// 标记时的排行数据
+	Download int64   `gorm:"column:download;type:bigint(20)"`
+	Trending float64 `gorm:"column:trending;type:double"`
+	// 审核信息
+	ReviewUID  int64  `gorm:"column:review_uid;type:bigint(20)"`
+	ReviewNote string `gorm:"column:review_note;type:varchar(255)"`
+	ReviewTime int64  `gorm:"column:review_time;type:bigint(20)"`
+	Createtime int64  `gorm:"column:createtime;type:bigint(20)"`
+	Updatetime int64  `gorm:"column:updatetime;type:bigint(20)"`
+}
+
+func (s *ScriptFraudReview) Reasons() []FraudReason {
+	ret := make([]FraudReason, 0)
+	for _, v := range strings.Split(s.Reason, ",") {
+		if v != "" {
+			ret = append(ret, FraudReason(v))
+		}
+	}
+	return ret
+}
+
+func (s *ScriptFraudReview) SetReasons(reasons []FraudReason) {
+	list := make([]string, 0, len(reasons))
+	for _, v := range reasons {
+		list = append(list, string(v))
+	}
+	s.Reason = strings.Join(list, ",")
+}
+
+// IsPending 是否待审核
+func (s *ScriptFraudReview) IsPending() bool {
+	return s != nil && s.Status == FraudReviewPending
+}
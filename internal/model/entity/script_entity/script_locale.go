@@ -0,0 +1,128 @@
+package script_entity
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ScriptLocale 脚本的多语言名称与描述, 来自元数据中的 @name:xx 与 @description:xx
+type ScriptLocale struct {
+	ID          int64  `gorm:"column:id;type:bigint(20);not null;primary_key"`
+	ScriptID    int64  `gorm:"column:script_id;type:bigint(20);not null;index:script_language,unique"`
+	Language    string `gorm:"column:language;type:varchar(32);not null;index:script_language,unique"` // 统一为小写, 例如: en, zh-cn
+	Name        string `gorm:"column:name;type:varchar(255)"`
+	Description string `gorm:"column:description;type:text"`
+	Createtime  int64  `gorm:"column:createtime;type:bigint(20)"`
+	Updatetime  int64  `gorm:"column:updatetime;type:bigint(20)"`
+}
+
+// languageMaxLen 语言标签的最大长度, 与language字段的长度一致
+const languageMaxLen = 32
+
+// NormalizeLanguage 统一语言标签格式, zh_CN -> zh-cn
+func NormalizeLanguage(lang string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(lang)), "_", "-")
+}
+
+// ParseScriptLocale 从解析后的元数据中提取多语言名称与描述
+func ParseScriptLocale(metaJson map[string][]string) []*ScriptLocale {
+	locales := make(map[string]*ScriptLocale)
+	for key, values := range metaJson {
+		field, lang, ok := strings.Cut(key, ":")
+		if !ok || len(values) == 0 {
+			continue
+		}
+		lang = NormalizeLanguage(lang)
+		if lang == "" {
+			continue
+		}
+		// 语言标签来自脚本元数据, 需要限制长度避免超过字段长度
+		if r := []rune(lang); len(r) > languageMaxLen {
+			lang = string(r[:languageMaxLen])
+		}
+		if field != "name" && field != "description" {
+			continue
+		}
+		locale, ok := locales[lang]
+		if !ok {
+			locale = &ScriptLocale{Language: lang}
+			locales[lang] = locale
+		}
+		if field == "name" {
+			locale.Name = values[0]
+		} else {
+			locale.Description = values[0]
+		}
+	}
+	ret := make([]*ScriptLocale, 0, len(locales))
+	for _, v := range locales {
+		ret = append(ret, v)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Language < ret[j].Language
+	})
+	return ret
+}
+
+// ParseAcceptLanguage 解析Accept-Language请求头, 按权重从高到低返回语言列表
+func ParseAcceptLanguage(header string) []string {
+	type item struct {
+		lang string
+		q    float64
+	}
+	items := make([]item, 0)
+	for _, part := range strings.Split(header, ",") {
+		lang, params, _ := strings.Cut(part, ";")
+		lang = NormalizeLanguage(lang)
+		if lang == "" || lang == "*" {
+			continue
+		}
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			k, v, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if !ok || k != "q" {
+				continue
+			}
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				q = f
+			}
+		}
+		if q <= 0 {
+			continue
+		}
+		items = append(items, item{lang: lang, q: q})
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].q > items[j].q
+	})
+	ret := make([]string, 0, len(items))
+	for _, v := range items {
+		ret = append(ret, v.lang)
+	}
+	return ret
+}
+
+// MatchScriptLocale 根据语言偏好选择最合适的多语言数据, 没有匹配时返回nil
+// 优先完全匹配, 其次匹配主语言, 例如en-us可以匹配en, zh可以匹配zh-cn
+func MatchScriptLocale(locales []*ScriptLocale, languages []string) *ScriptLocale {
+	if len(locales) == 0 {
+		return nil
+	}
+	for _, lang := range languages {
+		lang = NormalizeLanguage(lang)
+		for _, v := range locales {
+			if v.Language == lang {
+				return v
+			}
+		}
+		primary, _, _ := strings.Cut(lang, "-")
+		for _, v := range locales {
+			p, _, _ := strings.Cut(v.Language, "-")
+			if p == primary {
+				return v
+			}
+		}
+	}
+	return nil
+}
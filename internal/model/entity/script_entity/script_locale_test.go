@@ -0,0 +1,51 @@
+package script_entity
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseScriptLocale(t *testing.T) {
+	metaJson := parseMetaToJson(`// ==UserScript==
+// @name         测试脚本
+// @name:en      Test Script
+// @name:zh_TW   測試腳本
+// @description  这是一个测试脚本
+// @description:en A test script
+// @description:ja テストスクリプト
+// @version      1.0.0
+// @match:foo    bar
+// ==/UserScript==`)
+	locales := ParseScriptLocale(metaJson)
+	assert.Len(t, locales, 3)
+	assert.Equal(t, &ScriptLocale{Language: "en", Name: "Test Script", Description: "A test script"}, locales[0])
+	assert.Equal(t, &ScriptLocale{Language: "ja", Description: "テストスクリプト"}, locales[1])
+	assert.Equal(t, &ScriptLocale{Language: "zh-tw", Name: "測試腳本"}, locales[2])
+
+	// 超长的语言标签截断到字段长度
+	locales = ParseScriptLocale(map[string][]string{
+		"name:" + strings.Repeat("a", 40): {"Test Script"},
+	})
+	assert.Len(t, locales, 1)
+	assert.Equal(t, strings.Repeat("a", languageMaxLen), locales[0].Language)
+}
+
+func TestParseAcceptLanguage(t *testing.T) {
+	assert.Equal(t, []string{"en-us", "en", "zh-cn"}, ParseAcceptLanguage("zh-CN;q=0.8, en-US,en;q=0.9"))
+	assert.Equal(t, []string{"ja"}, ParseAcceptLanguage("ja, *;q=0.5, fr;q=0"))
+	assert.Empty(t, ParseAcceptLanguage(""))
+}
+
+func TestMatchScriptLocale(t *testing.T) {
+	locales := []*ScriptLocale{
+		{Language: "en", Name: "Test Script"},
+		{Language: "zh-tw", Name: "測試腳本"},
+	}
+	assert.Nil(t, MatchScriptLocale(nil, []string{"en"}))
+	assert.Nil(t, MatchScriptLocale(locales, []string{"ja"}))
+	assert.Equal(t, "en", MatchScriptLocale(locales, []string{"ja", "en-US"}).Language)
+	assert.Equal(t, "zh-tw", MatchScriptLocale(locales, []string{"zh_TW", "en"}).Language)
+	assert.Equal(t, "zh-tw", MatchScriptLocale(locales, []string{"zh"}).Language)
+}
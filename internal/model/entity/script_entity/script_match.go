@@ -0,0 +1,231 @@
+package script_entity
+
+import (
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+type ScriptMatchType int
+
+const (
+	ScriptMatchTypeMatch        ScriptMatchType = iota + 1 // @match
+	ScriptMatchTypeInclude                                 // @include
+	ScriptMatchTypeExclude                                 // @exclude
+	ScriptMatchTypeExcludeMatch                            // @exclude-match
+)
+
+// ScriptMatchMetaKey 元数据key与匹配规则类型的对应关系
+var ScriptMatchMetaKey = map[string]ScriptMatchType{
+	"match":         ScriptMatchTypeMatch,
+	"include":       ScriptMatchTypeInclude,
+	"exclude":       ScriptMatchTypeExclude,
+	"exclude-match": ScriptMatchTypeExcludeMatch,
+}
+
+// ScriptMatch 脚本完整的匹配规则
+type ScriptMatch struct {
+	ID           int64           `gorm:"column:id;type:bigint(20);not null;primary_key"`
+	ScriptID     int64           `gorm:"column:script_id;type:bigint(20);not null;index:script_id"`
+	ScriptCodeID int64           `gorm:"column:script_code_id;type:bigint(20)"`
+	Type         ScriptMatchType `gorm:"column:type;type:tinyint(2);not null"`
+	Pattern      string          `gorm:"column:pattern;type:varchar(1024);not null"`
+	Host         string          `gorm:"column:host;type:varchar(255);index:host"` // 规则能确定的主机, 用于缩小查询范围, 无法确定时为*
+	Status       int             `gorm:"column:status;type:tinyint(2);default:1"`
+	Createtime   int64           `gorm:"column:createtime;type:bigint(20)"`
+}
+
+// IsExclude 是否为排除规则
+func (m *ScriptMatch) IsExclude() bool {
+	return m.Type == ScriptMatchTypeExclude || m.Type == ScriptMatchTypeExcludeMatch
+}
+
+// Match 判断url是否命中规则
+func (m *ScriptMatch) Match(u *url.URL) bool {
+	switch m.Type {
+	case ScriptMatchTypeMatch, ScriptMatchTypeExcludeMatch:
+		return matchPattern(m.Pattern, u)
+	default:
+		return matchGlob(m.Pattern, u.String())
+	}
+}
+
+// ParseScriptMatch 从解析后的元数据中提取匹配规则
+func ParseScriptMatch(metaJson map[string][]string) []*ScriptMatch {
+	ret := make([]*ScriptMatch, 0)
+	exist := make(map[string]struct{})
+	for key, t := range ScriptMatchMetaKey {
+		for _, pattern := range metaJson[key] {
+			pattern = strings.TrimSpace(pattern)
+			if pattern == "" || len(pattern) > 1024 {
+				continue
+			}
+			if _, ok := exist[key+pattern]; ok {
+				continue
+			}
+			exist[key+pattern] = struct{}{}
+			ret = append(ret, &ScriptMatch{
+				Type:    t,
+				Pattern: pattern,
+				Host:    patternHost(t, pattern),
+			})
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].Type != ret[j].Type {
+			return ret[i].Type < ret[j].Type
+		}
+		return ret[i].Pattern < ret[j].Pattern
+	})
+	return ret
+}
+
+// MatchScriptURL 判断url是否命中脚本的规则, 命中任意一条包含规则且没有命中排除规则
+func MatchScriptURL(rules []*ScriptMatch, u *url.URL) bool {
+	matched := false
+	for _, v := range rules {
+		if v.IsExclude() {
+			if v.Match(u) {
+				return false
+			}
+		} else if !matched && v.Match(u) {
+			matched = true
+		}
+	}
+	return matched
+}
+
+// MatchCandidateHosts 返回可能匹配该主机的规则主机, 包括各级父域名与*
+// 例如: a.example.com -> a.example.com, example.com, com, *
+func MatchCandidateHosts(host string) []string {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	ret := make([]string, 0)
+	for host != "" {
+		ret = append(ret, host)
+		_, host, _ = strings.Cut(host, ".")
+	}
+	return append(ret, "*")
+}
+
+// patternHost 解析规则中能确定的主机, *.example.com 记为 example.com
+func patternHost(t ScriptMatchType, pattern string) string {
+	if isRegexPattern(t, pattern) {
+		return "*"
+	}
+	_, rest, ok := strings.Cut(pattern, "://")
+	if !ok {
+		return "*"
+	}
+	host, _, _ := strings.Cut(rest, "/")
+	if i := strings.LastIndex(host, "@"); i != -1 {
+		host = host[i+1:]
+	}
+	if h, _, ok := strings.Cut(host, ":"); ok {
+		host = h
+	}
+	host = strings.TrimPrefix(strings.ToLower(host), "*.")
+	// 超过字段长度的不是合法的主机名, 无法用于缩小范围
+	if host == "" || len(host) > 255 || strings.ContainsRune(host, '*') || strings.HasSuffix(host, ".tld") {
+		return "*"
+	}
+	return host
+}
+
+func isRegexPattern(t ScriptMatchType, pattern string) bool {
+	if t != ScriptMatchTypeInclude && t != ScriptMatchTypeExclude {
+		return false
+	}
+	return len(pattern) > 2 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/")
+}
+
+// matchPattern 按照 match pattern 规则匹配: <scheme>://<host><path>
+// 不符合规范的规则按照glob处理
+func matchPattern(pattern string, u *url.URL) bool {
+	if pattern == "<all_urls>" {
+		switch u.Scheme {
+		case "http", "https", "file", "ftp":
+			return true
+		}
+		return false
+	}
+	scheme, rest, ok := strings.Cut(pattern, "://")
+	if !ok {
+		return matchGlob(pattern, u.String())
+	}
+	switch scheme {
+	case "*":
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return false
+		}
+	default:
+		if !strings.EqualFold(scheme, u.Scheme) {
+			return false
+		}
+	}
+	host, path, ok := strings.Cut(rest, "/")
+	// 没有路径时视为匹配整个站点
+	path = "/" + path
+	if !ok {
+		path = "/*"
+	}
+	if h, _, ok := strings.Cut(host, ":"); ok {
+		host = h
+	}
+	if !matchHost(strings.ToLower(host), strings.ToLower(u.Hostname())) {
+		return false
+	}
+	p := u.EscapedPath()
+	if p == "" {
+		p = "/"
+	}
+	if u.RawQuery != "" {
+		p += "?" + u.RawQuery
+	}
+	re, err := globToRegexp(path)
+	if err != nil {
+		return false
+	}
+	return re.MatchString(p)
+}
+
+func matchHost(pattern, host string) bool {
+	if pattern == "*" {
+		return true
+	}
+	if strings.ContainsRune(strings.TrimPrefix(pattern, "*."), '*') || strings.HasSuffix(pattern, ".tld") {
+		re, err := globToRegexp(pattern)
+		if err != nil {
+			return false
+		}
+		return re.MatchString(host)
+	}
+	if strings.HasPrefix(pattern, "*.") {
+		pattern = pattern[2:]
+		return host == pattern || strings.HasSuffix(host, "."+pattern)
+	}
+	return pattern == host
+}
+
+// matchGlob 按照 @include/@exclude 规则匹配, /.../ 形式为正则表达式, 其它为glob
+func matchGlob(pattern string, s string) bool {
+	var re *regexp.Regexp
+	var err error
+	if isRegexPattern(ScriptMatchTypeInclude, pattern) {
+		re, err = regexp.Compile(pattern[1 : len(pattern)-1])
+	} else {
+		re, err = globToRegexp(pattern)
+	}
+	if err != nil {
+		return false
+	}
+	return re.MatchString(s)
+}
+
+// globToRegexp 将glob转换为正则, *匹配任意字符, .tld匹配任意顶级域名
+func globToRegexp(glob string) (*regexp.Regexp, error) {
+	expr := regexp.QuoteMeta(glob)
+	expr = strings.ReplaceAll(expr, `\*`, ".*")
+	expr = strings.ReplaceAll(expr, `\.tld`, `\.[a-z]{2,}(\.[a-z]{2,})?`)
+	return regexp.Compile("(?i)^" + expr + "$")
+}
@@ -0,0 +1,86 @@
+package script_entity
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseScriptMatch(t *testing.T) {
+	rules := ParseScriptMatch(map[string][]string{
+		"match":         {"*://*.example.com/*", "https://www.example.com/path/*", "*://*.example.com/*"},
+		"include":       {"/^https?://foo\\.com/.*$/", "http*://bar.com/*", "*://www.google.tld/*"},
+		"exclude":       {"*://*.example.com/logout*"},
+		"exclude-match": {"https://www.example.com/path/private"},
+	})
+	assert.Equal(t, []*ScriptMatch{
+		{Type: ScriptMatchTypeMatch, Pattern: "*://*.example.com/*", Host: "example.com"},
+		{Type: ScriptMatchTypeMatch, Pattern: "https://www.example.com/path/*", Host: "www.example.com"},
+		{Type: ScriptMatchTypeInclude, Pattern: "*://www.google.tld/*", Host: "*"},
+		{Type: ScriptMatchTypeInclude, Pattern: "/^https?://foo\\.com/.*$/", Host: "*"},
+		{Type: ScriptMatchTypeInclude, Pattern: "http*://bar.com/*", Host: "bar.com"},
+		{Type: ScriptMatchTypeExclude, Pattern: "*://*.example.com/logout*", Host: "example.com"},
+		{Type: ScriptMatchTypeExcludeMatch, Pattern: "https://www.example.com/path/private", Host: "www.example.com"},
+	}, rules)
+
+	// 超长的主机无法写入host字段, 视为无法确定主机
+	long := strings.Repeat("a", 300) + ".com"
+	rules = ParseScriptMatch(map[string][]string{"match": {"*://" + long + "/*"}})
+	require.Len(t, rules, 1)
+	assert.Equal(t, "*", rules[0].Host)
+}
+
+func TestScriptMatch_Match(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    *ScriptMatch
+		url     string
+		matched bool
+	}{
+		{"任意协议与子域名", &ScriptMatch{Type: ScriptMatchTypeMatch, Pattern: "*://*.example.com/*"}, "https://a.b.example.com/x?y=1", true},
+		{"子域名规则匹配根域名", &ScriptMatch{Type: ScriptMatchTypeMatch, Pattern: "*://*.example.com/*"}, "http://example.com/", true},
+		{"子域名规则不匹配其它域名", &ScriptMatch{Type: ScriptMatchTypeMatch, Pattern: "*://*.example.com/*"}, "http://badexample.com/", false},
+		{"协议不匹配", &ScriptMatch{Type: ScriptMatchTypeMatch, Pattern: "https://example.com/*"}, "http://example.com/", false},
+		{"星号协议不匹配file", &ScriptMatch{Type: ScriptMatchTypeMatch, Pattern: "*://*/*"}, "file:///etc/hosts", false},
+		{"路径匹配", &ScriptMatch{Type: ScriptMatchTypeMatch, Pattern: "https://example.com/path/*"}, "https://example.com/path/a/b", true},
+		{"路径不匹配", &ScriptMatch{Type: ScriptMatchTypeMatch, Pattern: "https://example.com/path/*"}, "https://example.com/other", false},
+		{"所有网址", &ScriptMatch{Type: ScriptMatchTypeMatch, Pattern: "<all_urls>"}, "https://example.com/", true},
+		{"include glob", &ScriptMatch{Type: ScriptMatchTypeInclude, Pattern: "http*://bar.com/*"}, "https://bar.com/a", true},
+		{"include tld", &ScriptMatch{Type: ScriptMatchTypeInclude, Pattern: "*://www.google.tld/*"}, "https://www.google.co.jp/search", true},
+		{"include 正则", &ScriptMatch{Type: ScriptMatchTypeInclude, Pattern: "/^https?://foo\\.com/.*$/"}, "https://foo.com/a", true},
+		{"include 正则不匹配", &ScriptMatch{Type: ScriptMatchTypeInclude, Pattern: "/^https?://foo\\.com/.*$/"}, "https://foo.org/a", false},
+		{"include 全部", &ScriptMatch{Type: ScriptMatchTypeInclude, Pattern: "*"}, "https://foo.org/a", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := url.Parse(tt.url)
+			require.NoError(t, err)
+			assert.Equal(t, tt.matched, tt.rule.Match(u))
+		})
+	}
+}
+
+func TestMatchScriptURL(t *testing.T) {
+	rules := ParseScriptMatch(map[string][]string{
+		"match":         {"*://*.example.com/*"},
+		"exclude":       {"*://*.example.com/logout*"},
+		"exclude-match": {"https://www.example.com/private/*"},
+	})
+	for url_, matched := range map[string]bool{
+		"https://www.example.com/":          true,
+		"https://www.example.com/logout":    false,
+		"https://www.example.com/private/a": false,
+		"https://www.other.com/":            false,
+	} {
+		u, err := url.Parse(url_)
+		require.NoError(t, err)
+		assert.Equal(t, matched, MatchScriptURL(rules, u), url_)
+	}
+}
+
+func TestMatchCandidateHosts(t *testing.T) {
+	assert.Equal(t, []string{"a.example.com", "example.com", "com", "*"}, MatchCandidateHosts("A.Example.com."))
+}
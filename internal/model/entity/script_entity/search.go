@@ -4,62 +4,117 @@ import (
 	"bytes"
 	"encoding/json"
 	"io"
+	"math"
+	"strings"
 
 	"github.com/cago-frame/cago/configs"
+	"github.com/cago-frame/cago/pkg/consts"
 )
 
-/*
-// 索引模板
-PUT _index_template/scriptlist.script
-{
-  "template": {
-    "mappings": {
-      "properties": {
-        "content": {
-          "type": "text",
-          "analyzer": "ik_max_word",
-          "search_analyzer": "ik_smart"
-        },
-        "description": {
-          "type": "text",
-          "analyzer": "ik_max_word",
-          "search_analyzer": "ik_smart"
-        },
-        "name": {
-          "type": "text",
-          "analyzer": "ik_max_word",
-          "search_analyzer": "ik_smart"
+// ScriptSearchMappingVersion 索引映射的版本, 修改映射后需要增加版本号, 旧版本的索引需要以alias模式重建
+const ScriptSearchMappingVersion = 2
+
+// ScriptSearchMappings 索引的映射, 重建索引时使用该映射创建新索引
+// 索引模板 PUT _index_template/scriptlist.script 的 template.mappings 也应该与其保持一致
+const ScriptSearchMappings = `{
+  "_meta": {
+    "version": 2
+  },
+  "properties": {
+    "content": {
+      "type": "text",
+      "analyzer": "ik_max_word",
+      "search_analyzer": "ik_smart"
+    },
+    "description": {
+      "type": "text",
+      "analyzer": "ik_max_word",
+      "search_analyzer": "ik_smart"
+    },
+    "name": {
+      "type": "text",
+      "analyzer": "ik_max_word",
+      "search_analyzer": "ik_smart"
+    },
+    "locale_name": {
+      "type": "text",
+      "analyzer": "ik_max_word",
+      "search_analyzer": "ik_smart"
+    },
+    "locale_description": {
+      "type": "text",
+      "analyzer": "ik_max_word",
+      "search_analyzer": "ik_smart"
+    },
+    "domain": {
+      "type": "keyword"
+    },
+    "suggest": {
+      "type": "completion",
+      "analyzer": "simple",
+      "contexts": [
+        {
+          "name": "visible",
+          "type": "category"
         }
-      }
+      ]
     }
-  },
-  "index_patterns": [
-    "dev.script"
-  ]
-}
-*/
+  }
+}`
 
 // ScriptSearch es中储存的数据
+// 重建索引使用alias模式后, CollectionName为指向实际索引的别名
 type ScriptSearch struct {
-	ID            int64         `json:"id"`
-	UserID        int64         `json:"user_id"`
-	Name          string        `json:"name"`
-	Description   string        `json:"description"`
-	Content       string        `json:"content"`
-	Version       string        `json:"version"`
-	Changelog     string        `json:"changelog"`
-	TotalDownload int64         `json:"total_download"`
-	TodayDownload int64         `json:"today_download"`
-	Score         float64       `json:"score"`
-	Category      []int64       `json:"category"`
-	Domain        []string      `json:"domain"`
-	Public        Public        `json:"public"`
-	Unwell        UnwellContent `json:"unwell"`
-	Archive       ScriptArchive `json:"archive"`
-	Type          Type          `json:"type"`
-	Status        int64         `json:"status"`
-	Createtime    int64         `json:"createtime"`
-	Updatetime    int64         `json:"updatetime"`
+	ID                int64          `json:"id"`
+	UserID            int64          `json:"user_id"`
+	Name              string         `json:"name"`
+	Description       string         `json:"description"`
+	LocaleName        []string       `json:"locale_name"`        // 多语言名称, 使得可以通过其它语言搜索到脚本
+	LocaleDescription []string       `json:"locale_description"` // 多语言描述
+	Content           string         `json:"content"`
+	Version           string         `json:"version"`
+	Changelog         string         `json:"changelog"`
+	TotalDownload     int64          `json:"total_download"`
+	TodayDownload     int64          `json:"today_download"`
+	TodayUpdate       int64          `json:"today_update"`
+	Score             float64        `json:"score"`
+	Trending          float64        `json:"trending"` // 时间衰减的热度
+	Category          []int64        `json:"category"`
+	Tag               []int64        `json:"tag"`
+	Domain            []string       `json:"domain"`
+	Public            Public         `json:"public"`
+	Unwell            UnwellContent  `json:"unwell"`
+	Archive           ScriptArchive  `json:"archive"`
+	Type              Type           `json:"type"`
+	Status            int64          `json:"status"`
+	Createtime        int64          `json:"createtime"`
+	Updatetime        int64          `json:"updatetime"`
+	Suggest           *ScriptSuggest `json:"suggest,omitempty"` // 搜索建议, 需要使用新的模板重建索引
+}
+
+// ScriptSuggest es completion suggester储存的数据
+type ScriptSuggest struct {
+	Input    []string            `json:"input"`
+	Weight   int64               `json:"weight"`
+	Contexts map[string][]string `json:"contexts"`
+}
+
+// NewScriptSuggest 使用脚本名称生成搜索建议, 以总下载量为权重, 只有公开的脚本可以被建议
+func NewScriptSuggest(s *ScriptSearch) *ScriptSuggest {
+	input := append([]string{s.Name}, s.LocaleName...)
+	weight := s.TotalDownload
+	if weight > math.MaxInt32 {
+		weight = math.MaxInt32
+	}
+	visible := "false"
+	if s.Status == consts.ACTIVE && s.Public == PublicScript && s.Unwell == Well {
+		visible = "true"
+	}
+	return &ScriptSuggest{
+		Input:    input,
+		Weight:   weight,
+		Contexts: map[string][]string{"visible": {visible}},
+	}
 }
 
 func (s *ScriptSearch) CollectionName() string {
@@ -76,3 +131,56 @@ func (s *ScriptSearch) Reader() (io.Reader, error) {
 	}
 	return bytes.NewReader(b), nil
 }
+
+// ScriptFulltext 内嵌搜索后端使用的mysql全文索引表, 使用ngram分词支持中文
+type ScriptFulltext struct {
+	ScriptID          int64         `gorm:"column:script_id;type:bigint(20);not null;primary_key;autoIncrement:false"`
+	UserID            int64         `gorm:"column:user_id;type:bigint(20);index:user_id"`
+	Name              string        `gorm:"column:name;type:varchar(255);index:search,class:FULLTEXT,option:WITH PARSER ngram;index:name_prefix"` // name_prefix用于搜索建议
+	Description       string        `gorm:"column:description;type:text;index:search,class:FULLTEXT,option:WITH PARSER ngram"`
+	Content           string        `gorm:"column:content;type:longtext;index:search,class:FULLTEXT,option:WITH PARSER ngram"`
+	LocaleName        string        `gorm:"column:locale_name;type:text;index:search,class:FULLTEXT,option:WITH PARSER ngram"`
+	LocaleDescription string        `gorm:"column:locale_description;type:text;index:search,class:FULLTEXT,option:WITH PARSER ngram"`
+	Version           string        `gorm:"column:version;type:varchar(255)"`
+	TotalDownload     int64         `gorm:"column:total_download;type:bigint(20)"`
+	TodayDownload     int64         `gorm:"column:today_download;type:bigint(20)"`
+	TodayUpdate       int64         `gorm:"column:today_update;type:bigint(20)"`
+	Score             float64       `gorm:"column:score;type:double"`
+	Trending          float64       `gorm:"column:trending;type:double"`
+	Public            Public        `gorm:"column:public;type:tinyint(2)"`
+	Unwell            UnwellContent `gorm:"column:unwell;type:tinyint(2)"`
+	Archive           ScriptArchive `gorm:"column:archive;type:tinyint(2)"`
+	Type              Type          `gorm:"column:type;type:tinyint(2)"`
+	Status            int64         `gorm:"column:status;type:tinyint(2)"`
+	Createtime        int64         `gorm:"column:createtime;type:bigint(20)"`
+	Updatetime        int64         `gorm:"column:updatetime;type:bigint(20)"`
+}
+
+// ScriptFulltextFields 全文索引的字段
+const ScriptFulltextFields = "name, description, content, locale_name, locale_description"
+
+// NewScriptFulltext 由es储存的数据转换为全文索引数据
+func NewScriptFulltext(s *ScriptSearch) *ScriptFulltext {
+	return &ScriptFulltext{
+		ScriptID:          s.ID,
+		UserID:            s.UserID,
+		Name:              s.Name,
+		Description:       s.Description,
+		Content:           s.Content,
+		LocaleName:        strings.Join(s.LocaleName, "\n"),
+		LocaleDescription: strings.Join(s.LocaleDescription, "\n"),
+		Version:           s.Version,
+		TotalDownload:     s.TotalDownload,
+		TodayDownload:     s.TodayDownload,
+		TodayUpdate:       s.TodayUpdate,
+		Score:             s.Score,
+		Trending:          s.Trending,
+		Public:            s.Public,
+		Unwell:            s.Unwell,
+		Archive:           s.Archive,
+		Type:              s.Type,
+		Status:            s.Status,
+		Createtime:        s.Createtime,
+		Updatetime:        s.Updatetime,
+	}
+}
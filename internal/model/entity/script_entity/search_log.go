@@ -0,0 +1,31 @@
+package script_entity
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// ScriptSearchLog 关键字搜索日志, 用于统计搜索词与点击率
+type ScriptSearchLog struct {
+	ID            int64  `gorm:"column:id;type:bigint(20);not null;primary_key"`
+	SearchID      string `gorm:"column:search_id;type:varchar(64);not null;index:search_id,unique"`
+	Query         string `gorm:"column:query;type:varchar(128);not null;index:query_time"` // 标准化后的关键字
+	Filters       string `gorm:"column:filters;type:varchar(1024)"`                        // 筛选条件, json格式
+	Sort          string `gorm:"column:sort;type:varchar(32)"`
+	Total         int64  `gorm:"column:total;type:bigint(20)"` // 结果数量
+	Clicks        int64  `gorm:"column:clicks;type:bigint(20);default:0"`
+	ClickScriptID int64  `gorm:"column:click_script_id;type:bigint(20);default:0"` // 第一次点击的脚本
+	UserID        int64  `gorm:"column:user_id;type:bigint(20)"`
+	Createtime    int64  `gorm:"column:createtime;type:bigint(20);index:query_time;index:createtime"`
+	Clicktime     int64  `gorm:"column:clicktime;type:bigint(20)"`
+}
+
+// NormalizeSearchQuery 标准化搜索关键字, 转为小写并合并空白, 超出长度的部分会被截断
+func NormalizeSearchQuery(q string) string {
+	q = strings.ToLower(strings.Join(strings.Fields(q), " "))
+	for len(q) > 128 {
+		_, size := utf8.DecodeLastRuneInString(q)
+		q = q[:len(q)-size]
+	}
+	return q
+}
@@ -0,0 +1,49 @@
+package script_entity
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/cago-frame/cago/pkg/consts"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewScriptSuggest(t *testing.T) {
+	suggest := NewScriptSuggest(&ScriptSearch{
+		Name:          "脚本",
+		LocaleName:    []string{"Script"},
+		TotalDownload: math.MaxInt64,
+		Status:        consts.ACTIVE,
+		Public:        PublicScript,
+		Unwell:        Well,
+	})
+	assert.Equal(t, &ScriptSuggest{
+		Input:    []string{"脚本", "Script"},
+		Weight:   math.MaxInt32,
+		Contexts: map[string][]string{"visible": {"true"}},
+	}, suggest)
+
+	suggest = NewScriptSuggest(&ScriptSearch{Name: "脚本", Status: consts.ACTIVE, Public: UnPublicScript, Unwell: Well})
+	assert.Equal(t, []string{"false"}, suggest.Contexts["visible"])
+}
+
+func TestNormalizeSearchQuery(t *testing.T) {
+	assert.Equal(t, "hello world", NormalizeSearchQuery("  Hello \t World\n"))
+	assert.Equal(t, "脚本", NormalizeSearchQuery("脚本"))
+	q := NormalizeSearchQuery(strings.Repeat("脚", 50))
+	assert.LessOrEqual(t, len(q), 128)
+	assert.True(t, utf8.ValidString(q))
+}
+
+func TestScriptSearchMappings(t *testing.T) {
+	m := struct {
+		Meta struct {
+			Version int `json:"version"`
+		} `json:"_meta"`
+	}{}
+	assert.NoError(t, json.Unmarshal([]byte(ScriptSearchMappings), &m))
+	assert.Equal(t, ScriptSearchMappingVersion, m.Meta.Version)
+}
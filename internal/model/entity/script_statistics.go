@@ -1,10 +1,67 @@
 package entity
 
+import (
+	"math"
+	"time"
+)
+
 type ScriptStatistics struct {
-	ID         int64 `gorm:"column:id;type:bigint(20);not null;primary_key"`
-	ScriptID   int64 `gorm:"column:script_id;type:bigint(20);index:script,unique"`
-	Download   int64 `gorm:"column:download;type:bigint(20);default:0"`
-	Update     int64 `gorm:"column:update;type:bigint(20);default:0"`
-	Score      int64 `gorm:"column:score;type:bigint(20);default:0"`
-	ScoreCount int64 `gorm:"column:score_count;type:bigint(20);default:0"`
+	ID         int64   `gorm:"column:id;type:bigint(20);not null;primary_key"`
+	ScriptID   int64   `gorm:"column:script_id;type:bigint(20);index:script,unique"`
+	Download   int64   `gorm:"column:download;type:bigint(20);default:0"`
+	Update     int64   `gorm:"column:update;type:bigint(20);default:0"`
+	Score      int64   `gorm:"column:score;type:bigint(20);default:0"`
+	ScoreCount int64   `gorm:"column:score_count;type:bigint(20);default:0"`
+	Trending   float64 `gorm:"column:trending;type:double;default:0;index:trending"` // 热度, 由定时任务计算
+}
+
+const (
+	TrendingDays           = 30   // 计算热度使用的每日统计天数
+	trendingHalfLife       = 3.0  // 每日下载与更新的半衰期(天)
+	trendingUpdateHalfLife = 30.0 // 脚本更新时间的半衰期(天)
+	trendingUpdateWeight   = 0.2  // 更新检查相对于下载的权重
+	trendingPriorCount     = 10   // 贝叶斯平均的先验评分数量
+	trendingPriorScore     = 30   // 先验的平均评分, 评分范围为10-50
+	trendingMinDelta       = 0.05 // 热度相对变化超过该比例才更新
+)
+
+// Trending 计算时间衰减的热度
+// 每日的下载与更新按天数指数衰减后求和并取对数, 再乘以贝叶斯平均后的评分与脚本更新时间的衰减
+func Trending(now time.Time, dates []*ScriptDateStatistics, statistics *ScriptStatistics, updatetime int64) float64 {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	activity := 0.0
+	for _, v := range dates {
+		t, err := time.ParseInLocation("2006-01-02", v.Date, now.Location())
+		if err != nil {
+			continue
+		}
+		days := today.Sub(t).Hours() / 24
+		if days < 0 || days >= TrendingDays {
+			continue
+		}
+		activity += (float64(v.Download) + trendingUpdateWeight*float64(v.Update)) *
+			math.Exp2(-days/trendingHalfLife)
+	}
+	score, scoreCount := int64(0), int64(0)
+	if statistics != nil {
+		score, scoreCount = statistics.Score, statistics.ScoreCount
+	}
+	rating := (trendingPriorCount*trendingPriorScore + float64(score)) /
+		(trendingPriorCount + float64(scoreCount)) / trendingPriorScore
+	recency := 0.5
+	if updatetime > 0 {
+		days := math.Max(now.Sub(time.Unix(updatetime, 0)).Hours()/24, 0)
+		recency += 0.5 * math.Exp2(-days/trendingUpdateHalfLife)
+	}
+	return math.Log1p(activity) * rating * recency
+}
+
+// TrendingChanged 热度是否有明显变化, 热度每小时都会随时间衰减,
+// 只有相对变化超过一定比例时才更新, 避免每次都重建所有脚本的搜索索引
+func TrendingChanged(old, trending float64) bool {
+	delta := math.Abs(trending - old)
+	if delta < 1e-6 {
+		return false
+	}
+	return delta > math.Max(math.Abs(old), math.Abs(trending))*trendingMinDelta
 }
@@ -0,0 +1,33 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrending(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.Local)
+	recent := []*ScriptDateStatistics{{Date: "2026-03-10", Download: 100}}
+	old := []*ScriptDateStatistics{{Date: "2026-03-01", Download: 100}}
+	assert.Greater(t, Trending(now, recent, nil, now.Unix()), Trending(now, old, nil, now.Unix()))
+	// 超出计算天数的统计不计入
+	assert.Zero(t, Trending(now, []*ScriptDateStatistics{{Date: "2026-01-01", Download: 100}}, nil, now.Unix()))
+	// 评分数量少时接近先验
+	high := &ScriptStatistics{Score: 50 * 100, ScoreCount: 100}
+	few := &ScriptStatistics{Score: 50, ScoreCount: 1}
+	assert.Greater(t, Trending(now, recent, high, now.Unix()), Trending(now, recent, few, now.Unix()))
+	assert.Greater(t, Trending(now, recent, few, now.Unix()), Trending(now, recent, nil, now.Unix()))
+	// 最近更新的脚本热度更高
+	assert.Greater(t, Trending(now, recent, nil, now.Unix()), Trending(now, recent, nil, now.AddDate(0, -6, 0).Unix()))
+}
+
+func TestTrendingChanged(t *testing.T) {
+	assert.False(t, TrendingChanged(0, 0))
+	assert.True(t, TrendingChanged(0, 1))
+	assert.True(t, TrendingChanged(1, 0))
+	// 小幅的衰减不更新
+	assert.False(t, TrendingChanged(10, 9.8))
+	assert.True(t, TrendingChanged(10, 9))
+}
@@ -0,0 +1,87 @@
+package statistics_entity
+
+import (
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type InstallSource string
+
+const (
+	// InstallSourceSite 从本站页面安装
+	InstallSourceSite InstallSource = "site"
+	// InstallSourceFolder 通过收藏夹订阅安装
+	InstallSourceFolder InstallSource = "folder"
+	// InstallSourceExternal 从外部网站跳转安装
+	InstallSourceExternal InstallSource = "external"
+	// InstallSourceDirect 直接访问链接安装
+	InstallSourceDirect InstallSource = "direct"
+)
+
+// 安装来源统计的维度
+const (
+	SourceDimensionSource  = "source"
+	SourceDimensionReferer = "referer"
+	SourceDimensionFolder  = "folder"
+)
+
+// refererHostMaxLen 来源域名的最大长度, 与统计字段的长度一致
+const refererHostMaxLen = 128
+
+// 作者自定义的来源标记, 例如 ?source=github
+var customSourceRegex = regexp.MustCompile(`^[a-zA-Z0-9_.-]{1,32}$`)
+
+// Attribution 安装来源归因
+type Attribution struct {
+	// Source 来源, 为InstallSource或者作者自定义的来源标记
+	Source string
+	// RefererHost 外部来源网站的域名
+	RefererHost string
+	// FolderID 通过收藏夹订阅安装时的收藏夹id
+	FolderID int64
+}
+
+// ParseAttribution 根据source参数与Referer判断安装来源, source参数优先, siteHost为本站域名
+func ParseAttribution(source string, folderId int64, referer, siteHost string) *Attribution {
+	ret := &Attribution{}
+	if u, err := url.Parse(referer); err == nil && u.Hostname() != "" {
+		host := strings.ToLower(u.Hostname())
+		if host != strings.ToLower(siteHost) {
+			// 域名最长可以有253个字符, 超过字段长度时截取
+			if len(host) > refererHostMaxLen {
+				host = host[:refererHostMaxLen]
+			}
+			ret.RefererHost = host
+		}
+	}
+	switch {
+	case source == string(InstallSourceFolder) && folderId > 0:
+		ret.Source = string(InstallSourceFolder)
+		ret.FolderID = folderId
+	case source != "" && source != string(InstallSourceFolder) && customSourceRegex.MatchString(source):
+		ret.Source = strings.ToLower(source)
+	case ret.RefererHost != "":
+		ret.Source = string(InstallSourceExternal)
+	case referer != "":
+		ret.Source = string(InstallSourceSite)
+	default:
+		ret.Source = string(InstallSourceDirect)
+	}
+	return ret
+}
+
+// Dimensions 各统计维度的值
+func (a *Attribution) Dimensions() map[string]string {
+	ret := map[string]string{
+		SourceDimensionSource: a.Source,
+	}
+	if a.RefererHost != "" {
+		ret[SourceDimensionReferer] = a.RefererHost
+	}
+	if a.FolderID > 0 {
+		ret[SourceDimensionFolder] = strconv.FormatInt(a.FolderID, 10)
+	}
+	return ret
+}
@@ -0,0 +1,36 @@
+package statistics_entity
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAttribution(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		folderId int64
+		referer  string
+		want     *Attribution
+	}{
+		{"本站页面", "", 0, "https://scriptcat.org/zh-CN/script-show-page/1", &Attribution{Source: "site"}},
+		{"收藏夹订阅", "folder", 10, "", &Attribution{Source: "folder", FolderID: 10}},
+		{"收藏夹缺少id", "folder", 0, "", &Attribution{Source: "direct"}},
+		{"外部网站", "", 0, "https://GitHub.com/user/repo", &Attribution{Source: "external", RefererHost: "github.com"}},
+		{"自定义来源", "GitHub", 0, "https://github.com/user/repo", &Attribution{Source: "github", RefererHost: "github.com"}},
+		{"无效的自定义来源", "<script>", 0, "", &Attribution{Source: "direct"}},
+		{"直接访问", "", 0, "", &Attribution{Source: "direct"}},
+		{"超长的来源域名", "", 0, "https://" + strings.Repeat("a", 240) + ".com/", &Attribution{Source: "external", RefererHost: strings.Repeat("a", 128)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ParseAttribution(tt.source, tt.folderId, tt.referer, "scriptcat.org"))
+		})
+	}
+	assert.Equal(t, map[string]string{"source": "folder", "folder": "10"},
+		(&Attribution{Source: "folder", FolderID: 10}).Dimensions())
+	assert.Equal(t, map[string]string{"source": "external", "referer": "github.com"},
+		(&Attribution{Source: "external", RefererHost: "github.com"}).Dimensions())
+}
@@ -0,0 +1,67 @@
+package statistics_entity
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"regexp"
+	"strings"
+)
+
+// ScriptErrorGroup 脚本运行错误, 按错误堆栈的指纹聚合, Count为按采样率还原后的数量
+type ScriptErrorGroup struct {
+	ID          int64  `gorm:"column:id;type:bigint(20);not null;primary_key"`
+	ScriptID    int64  `gorm:"column:script_id;type:bigint(20);not null;index:script_fingerprint,unique"`
+	Fingerprint string `gorm:"column:fingerprint;type:char(40);not null;index:script_fingerprint,unique"`
+	Message     string `gorm:"column:message;type:varchar(1024);not null"`
+	Stack       string `gorm:"column:stack;type:text"`
+	Host        string `gorm:"column:host;type:varchar(255);not null;default:''"` // 最近一次发生错误的页面域名
+	Version     string `gorm:"column:version;type:varchar(64);not null;default:''"`
+	Count       int64  `gorm:"column:count;type:bigint(20);not null;default:0"`
+	IssueID     int64  `gorm:"column:issue_id;type:bigint(20);not null;default:0"` // 转为反馈后的反馈id
+	FirstTime   int64  `gorm:"column:first_time;type:bigint(20);not null"`
+	LastTime    int64  `gorm:"column:last_time;type:bigint(20);not null;index:last_time"`
+	Createtime  int64  `gorm:"column:createtime;type:bigint(20);not null"`
+	Updatetime  int64  `gorm:"column:updatetime;type:bigint(20)"`
+}
+
+// ScriptErrorVersion 错误在各版本中发生的数量
+type ScriptErrorVersion struct {
+	ID         int64  `gorm:"column:id;type:bigint(20);not null;primary_key"`
+	GroupID    int64  `gorm:"column:group_id;type:bigint(20);not null;index:group_version,unique"`
+	Version    string `gorm:"column:version;type:varchar(64);not null;index:group_version,unique"`
+	Count      int64  `gorm:"column:count;type:bigint(20);not null;default:0"`
+	LastTime   int64  `gorm:"column:last_time;type:bigint(20);not null"`
+	Createtime int64  `gorm:"column:createtime;type:bigint(20);not null"`
+}
+
+// 计算指纹时使用的堆栈帧数量
+const errorFingerprintFrames = 5
+
+var (
+	// 堆栈中的行号与列号, 不同版本的代码行号会变化
+	errorLineRegex = regexp.MustCompile(`:\d+(:\d+)?`)
+	// 链接中的参数
+	errorQueryRegex = regexp.MustCompile(`\?[^\s)]*`)
+	// 错误信息中的数字, 例如下标与id
+	errorNumberRegex = regexp.MustCompile(`\d+`)
+)
+
+// ErrorFingerprint 根据错误信息和堆栈计算指纹, 忽略行号, 链接参数和错误信息中的数字, 使不同版本与页面的同一错误聚合在一起
+func ErrorFingerprint(message, stack string) string {
+	message, _, _ = strings.Cut(strings.TrimSpace(message), "\n")
+	parts := []string{errorNumberRegex.ReplaceAllString(message, "0")}
+	for _, line := range strings.Split(stack, "\n") {
+		line = strings.TrimSpace(line)
+		// 只取堆栈帧, 第一行通常是错误信息
+		if !strings.HasPrefix(line, "at ") && !strings.Contains(line, "@") {
+			continue
+		}
+		line = errorQueryRegex.ReplaceAllString(line, "")
+		parts = append(parts, errorLineRegex.ReplaceAllString(line, ""))
+		if len(parts) > errorFingerprintFrames {
+			break
+		}
+	}
+	sum := sha1.Sum([]byte(strings.Join(parts, "\n")))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,26 @@
+package statistics_entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorFingerprint(t *testing.T) {
+	stack := "TypeError: Cannot read properties of undefined (reading '1')\n" +
+		"    at main (userscript.html?name=test.user.js&id=1:12:5)\n" +
+		"    at userscript.html?name=test.user.js&id=1:30:1"
+	fp := ErrorFingerprint("Cannot read properties of undefined (reading '1')", stack)
+	assert.Len(t, fp, 40)
+	// 行号与参数不同的同一错误
+	assert.Equal(t, fp, ErrorFingerprint("Cannot read properties of undefined (reading '2')",
+		"TypeError: Cannot read properties of undefined (reading '2')\n"+
+			"    at main (userscript.html?name=test.user.js&id=2:15:9)\n"+
+			"    at userscript.html?name=test.user.js&id=2:33:1"))
+	// firefox的堆栈格式
+	assert.Equal(t, ErrorFingerprint("a is null", "main@moz-extension://x/userscript.html:12:5\n@moz-extension://x/userscript.html:30:1"),
+		ErrorFingerprint("a is null", "main@moz-extension://x/userscript.html:18:5\n@moz-extension://x/userscript.html:40:1"))
+	// 调用位置不同
+	assert.NotEqual(t, fp, ErrorFingerprint("Cannot read properties of undefined (reading '1')",
+		"TypeError: x\n    at other (userscript.html:12:5)"))
+}
@@ -0,0 +1,45 @@
+package statistics_entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatisticsVisitor_ParseUA(t *testing.T) {
+	tests := []struct {
+		name    string
+		ua      string
+		device  DeviceType
+		browser BrowserType
+	}{
+		{"windows chrome", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", DeviceTypePC, BrowserTypeChrome},
+		{"windows edge", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.0.0", DeviceTypePC, BrowserTypeEdge},
+		{"linux firefox", "Mozilla/5.0 (X11; Linux x86_64; rv:121.0) Gecko/20100101 Firefox/121.0", DeviceTypePC, BrowserTypeFirefox},
+		{"mac safari", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Safari/605.1.15", DeviceTypePC, BrowserTypeSafari},
+		{"android opera", "Mozilla/5.0 (Linux; Android 10; K) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36 OPR/79.0.0.0", DeviceTypeMobile, BrowserTypeOpera},
+		{"iphone safari", "Mozilla/5.0 (iPhone; CPU iPhone OS 17_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Mobile/15E148 Safari/604.1", DeviceTypeMobile, BrowserTypeSafari},
+		{"空ua", "", DeviceTypeUnknown, BrowserTypeUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &StatisticsVisitor{UA: tt.ua}
+			v.ParseUA()
+			assert.Equal(t, int64(tt.device), v.DeviceType)
+			assert.Equal(t, string(tt.browser), v.BrowserType)
+		})
+	}
+}
+
+func TestWhitelist_Allow(t *testing.T) {
+	var empty *Whitelist
+	assert.True(t, empty.Allow("example.com"))
+	assert.True(t, (&Whitelist{}).Allow("example.com"))
+	w := &Whitelist{Whitelist: []string{"example.com", "*.scriptcat.org"}}
+	assert.True(t, w.Allow("example.com"))
+	assert.True(t, w.Allow("www.Example.com"))
+	assert.True(t, w.Allow("docs.scriptcat.org"))
+	assert.True(t, w.Allow("scriptcat.org"))
+	assert.False(t, w.Allow("badexample.com"))
+	assert.False(t, w.Allow("example.com.evil.com"))
+}
@@ -0,0 +1,35 @@
+package statistics_entity
+
+// StatisticsDaily 脚本每日统计数据, 由定时任务从redis落库, 用于查询长期的历史数据
+type StatisticsDaily struct {
+	ID         int64  `gorm:"column:id;type:bigint(20);not null;primary_key"`
+	ScriptID   int64  `gorm:"column:script_id;type:bigint(20);not null;index:script_op_date,unique"`
+	Op         string `gorm:"column:op;type:varchar(16);not null;index:script_op_date,unique"`
+	Date       string `gorm:"column:date;type:varchar(10);not null;index:script_op_date,unique"`
+	Pv         int64  `gorm:"column:pv;type:bigint(20);not null;default:0"`
+	Uv         int64  `gorm:"column:uv;type:bigint(20);not null;default:0"`
+	Createtime int64  `gorm:"column:createtime;type:bigint(20);not null"`
+}
+
+// StatisticsUaDaily 脚本每日访客ua分布, dimension为browser, os, device, manager, manager_version
+type StatisticsUaDaily struct {
+	ID         int64  `gorm:"column:id;type:bigint(20);not null;primary_key"`
+	ScriptID   int64  `gorm:"column:script_id;type:bigint(20);not null;index:script_op_date_ua,unique"`
+	Op         string `gorm:"column:op;type:varchar(16);not null;index:script_op_date_ua,unique"`
+	Date       string `gorm:"column:date;type:varchar(10);not null;index:script_op_date_ua,unique"`
+	Dimension  string `gorm:"column:dimension;type:varchar(16);not null;index:script_op_date_ua,unique"`
+	Value      string `gorm:"column:value;type:varchar(64);not null;index:script_op_date_ua,unique"`
+	Num        int64  `gorm:"column:num;type:bigint(20);not null;default:0"`
+	Createtime int64  `gorm:"column:createtime;type:bigint(20);not null"`
+}
+
+// StatisticsSourceDaily 脚本每日安装来源, dimension为source, referer, folder
+type StatisticsSourceDaily struct {
+	ID         int64  `gorm:"column:id;type:bigint(20);not null;primary_key"`
+	ScriptID   int64  `gorm:"column:script_id;type:bigint(20);not null;index:script_date_source,unique"`
+	Date       string `gorm:"column:date;type:varchar(10);not null;index:script_date_source,unique"`
+	Dimension  string `gorm:"column:dimension;type:varchar(16);not null;index:script_date_source,unique"`
+	Value      string `gorm:"column:value;type:varchar(128);not null;index:script_date_source,unique"`
+	Num        int64  `gorm:"column:num;type:bigint(20);not null;default:0"`
+	Createtime int64  `gorm:"column:createtime;type:bigint(20);not null"`
+}
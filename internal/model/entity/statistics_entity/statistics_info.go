@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 )
 
 type Whitelist struct {
@@ -25,6 +26,21 @@ func (w *Whitelist) Value() (driver.Value, error) {
 	return ret, err
 }
 
+// Allow 域名是否在白名单内, 白名单为空时允许所有域名, 白名单中的域名同时匹配其子域名
+func (w *Whitelist) Allow(host string) bool {
+	if w == nil || len(w.Whitelist) == 0 {
+		return true
+	}
+	host = strings.ToLower(host)
+	for _, v := range w.Whitelist {
+		v = strings.TrimPrefix(strings.ToLower(v), "*.")
+		if host == v || strings.HasSuffix(host, "."+v) {
+			return true
+		}
+	}
+	return false
+}
+
 type StatisticsInfo struct {
 	ID            int64      `gorm:"column:id;type:bigint(20);not null;primary_key"`
 	ScriptID      int64      `gorm:"column:script_id;type:bigint(20);not null;index:script_id,unique"`
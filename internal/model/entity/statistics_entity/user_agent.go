@@ -0,0 +1,185 @@
+package statistics_entity
+
+import (
+	"regexp"
+	"strings"
+)
+
+type DeviceType int
+
+const (
+	DeviceTypeUnknown DeviceType = iota + 1
+	DeviceTypePC
+	DeviceTypeMobile
+)
+
+func (d DeviceType) String() string {
+	switch d {
+	case DeviceTypePC:
+		return "pc"
+	case DeviceTypeMobile:
+		return "mobile"
+	}
+	return "unknown"
+}
+
+type BrowserType string
+
+const (
+	BrowserTypeUnknown BrowserType = "unknown"
+	BrowserTypeChrome  BrowserType = "chrome"
+	BrowserTypeEdge    BrowserType = "edge"
+	BrowserTypeFirefox BrowserType = "firefox"
+	BrowserTypeSafari  BrowserType = "safari"
+	BrowserTypeOpera   BrowserType = "opera"
+)
+
+type OSType string
+
+const (
+	OSTypeUnknown  OSType = "unknown"
+	OSTypeWindows  OSType = "windows"
+	OSTypeMacOS    OSType = "macos"
+	OSTypeIOS      OSType = "ios"
+	OSTypeAndroid  OSType = "android"
+	OSTypeLinux    OSType = "linux"
+	OSTypeChromeOS OSType = "chromeos"
+)
+
+// UA统计的维度
+const (
+	UADimensionBrowser        = "browser"
+	UADimensionOS             = "os"
+	UADimensionDevice         = "device"
+	UADimensionManager        = "manager"
+	UADimensionManagerVersion = "manager_version"
+	// UADimensionVersion 客户端安装的脚本版本, 仅更新检查记录
+	UADimensionVersion = "version"
+)
+
+// 脚本管理器的ua标识, key为小写
+var scriptManagers = map[string]string{
+	"tampermonkey":  "Tampermonkey",
+	"violentmonkey": "Violentmonkey",
+	"scriptcat":     "ScriptCat",
+	"greasemonkey":  "Greasemonkey",
+	"firemonkey":    "FireMonkey",
+	"userscripts":   "Userscripts",
+	"stay":          "Stay",
+	"adguard":       "AdGuard",
+}
+
+// managerVersionMaxLen 脚本管理器版本号的最大长度, 与管理器名称组合后需要小于统计字段的长度
+const managerVersionMaxLen = 32
+
+var scriptManagerRegex = regexp.MustCompile(`(?i)\b(tampermonkey|violentmonkey|scriptcat|greasemonkey|firemonkey|userscripts|stay|adguard)(?:[/ ]v?(\d+(?:\.\d+)*))?`)
+
+// UserAgent ua解析结果
+type UserAgent struct {
+	Browser BrowserType
+	OS      OSType
+	Device  DeviceType
+	// Manager 脚本管理器, ua中没有标识时为空
+	Manager        string
+	ManagerVersion string
+}
+
+// ParseUserAgent 解析ua中的浏览器, 系统, 设备和脚本管理器
+func ParseUserAgent(s string) *UserAgent {
+	ua := strings.ToLower(s)
+	ret := &UserAgent{
+		Browser: BrowserTypeUnknown,
+		OS:      OSTypeUnknown,
+		Device:  DeviceTypeUnknown,
+	}
+	// 顺序有意义, ios和android的ua中也包含mac os和linux
+	switch {
+	case ua == "":
+	case strings.Contains(ua, "iphone") || strings.Contains(ua, "ipad") || strings.Contains(ua, "ipod"):
+		ret.OS = OSTypeIOS
+	case strings.Contains(ua, "android"):
+		ret.OS = OSTypeAndroid
+	case strings.Contains(ua, "windows"):
+		ret.OS = OSTypeWindows
+	case strings.Contains(ua, "cros"):
+		ret.OS = OSTypeChromeOS
+	case strings.Contains(ua, "macintosh") || strings.Contains(ua, "mac os"):
+		ret.OS = OSTypeMacOS
+	case strings.Contains(ua, "linux"):
+		ret.OS = OSTypeLinux
+	}
+	switch {
+	case strings.Contains(ua, "mobile") || ret.OS == OSTypeIOS || ret.OS == OSTypeAndroid:
+		ret.Device = DeviceTypeMobile
+	case ret.OS != OSTypeUnknown:
+		ret.Device = DeviceTypePC
+	}
+	// 顺序有意义, edge和opera的ua中也包含chrome, chrome的ua中也包含safari
+	switch {
+	case strings.Contains(ua, "edg/") || strings.Contains(ua, "edga/") || strings.Contains(ua, "edgios/"):
+		ret.Browser = BrowserTypeEdge
+	case strings.Contains(ua, "opr/") || strings.Contains(ua, "opera"):
+		ret.Browser = BrowserTypeOpera
+	case strings.Contains(ua, "firefox/") || strings.Contains(ua, "fxios/"):
+		ret.Browser = BrowserTypeFirefox
+	case strings.Contains(ua, "chrome/") || strings.Contains(ua, "crios/"):
+		ret.Browser = BrowserTypeChrome
+	case strings.Contains(ua, "safari/"):
+		ret.Browser = BrowserTypeSafari
+	}
+	if m := scriptManagerRegex.FindStringSubmatch(s); m != nil {
+		ret.Manager = scriptManagers[strings.ToLower(m[1])]
+		ret.ManagerVersion = m[2]
+		// 版本号来自客户端, 需要限制长度避免超过字段长度
+		if len(ret.ManagerVersion) > managerVersionMaxLen {
+			ret.ManagerVersion = ret.ManagerVersion[:managerVersionMaxLen]
+		}
+	}
+	return ret
+}
+
+// Dimensions 各统计维度的值, 没有识别到脚本管理器时记为unknown
+func (u *UserAgent) Dimensions() map[string]string {
+	manager, managerVersion := "unknown", "unknown"
+	if u.Manager != "" {
+		manager = u.Manager
+		managerVersion = u.Manager
+		if u.ManagerVersion != "" {
+			managerVersion += " " + u.ManagerVersion
+		}
+	}
+	return map[string]string{
+		UADimensionBrowser:        string(u.Browser),
+		UADimensionOS:             string(u.OS),
+		UADimensionDevice:         u.Device.String(),
+		UADimensionManager:        manager,
+		UADimensionManagerVersion: managerVersion,
+	}
+}
+
+// 只能运行在特定浏览器上的脚本管理器
+var managerBrowsers = map[string][]BrowserType{
+	"Greasemonkey": {BrowserTypeFirefox},
+	"FireMonkey":   {BrowserTypeFirefox},
+	"Userscripts":  {BrowserTypeSafari},
+	"Stay":         {BrowserTypeSafari},
+}
+
+// Impossible 是否为现实中不存在的ua组合, 例如windows上的safari, chrome上的Greasemonkey
+func (u *UserAgent) Impossible() bool {
+	if u.Browser == BrowserTypeSafari {
+		switch u.OS {
+		case OSTypeWindows, OSTypeAndroid, OSTypeLinux, OSTypeChromeOS:
+			return true
+		}
+	}
+	if browsers, ok := managerBrowsers[u.Manager]; ok && u.Browser != BrowserTypeUnknown {
+		for _, v := range browsers {
+			if v == u.Browser {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
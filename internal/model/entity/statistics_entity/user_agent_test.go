@@ -0,0 +1,60 @@
+package statistics_entity
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseUserAgent(t *testing.T) {
+	tests := []struct {
+		name string
+		ua   string
+		want *UserAgent
+	}{
+		{"windows chrome tampermonkey", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Tampermonkey/5.1.1",
+			&UserAgent{Browser: BrowserTypeChrome, OS: OSTypeWindows, Device: DeviceTypePC, Manager: "Tampermonkey", ManagerVersion: "5.1.1"}},
+		{"mac firefox violentmonkey", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10.15; rv:121.0) Gecko/20100101 Firefox/121.0 Violentmonkey/2.18.0",
+			&UserAgent{Browser: BrowserTypeFirefox, OS: OSTypeMacOS, Device: DeviceTypePC, Manager: "Violentmonkey", ManagerVersion: "2.18.0"}},
+		{"linux edge scriptcat", "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.0.0 ScriptCat/v0.16.5",
+			&UserAgent{Browser: BrowserTypeEdge, OS: OSTypeLinux, Device: DeviceTypePC, Manager: "ScriptCat", ManagerVersion: "0.16.5"}},
+		{"android chrome", "Mozilla/5.0 (Linux; Android 10; K) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36",
+			&UserAgent{Browser: BrowserTypeChrome, OS: OSTypeAndroid, Device: DeviceTypeMobile}},
+		{"ipad safari userscripts", "Mozilla/5.0 (iPad; CPU OS 17_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Safari/604.1 Userscripts",
+			&UserAgent{Browser: BrowserTypeSafari, OS: OSTypeIOS, Device: DeviceTypeMobile, Manager: "Userscripts"}},
+		{"chromeos", "Mozilla/5.0 (X11; CrOS x86_64 14541.0.0) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			&UserAgent{Browser: BrowserTypeChrome, OS: OSTypeChromeOS, Device: DeviceTypePC}},
+		{"空ua", "", &UserAgent{Browser: BrowserTypeUnknown, OS: OSTypeUnknown, Device: DeviceTypeUnknown}},
+		{"超长的版本号", "Tampermonkey/" + strings.Repeat("1.", 100) + "1",
+			&UserAgent{Browser: BrowserTypeUnknown, OS: OSTypeUnknown, Device: DeviceTypeUnknown,
+				Manager: "Tampermonkey", ManagerVersion: strings.Repeat("1.", 16)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ParseUserAgent(tt.ua))
+		})
+	}
+}
+
+func TestUserAgent_Dimensions(t *testing.T) {
+	ua := &UserAgent{Browser: BrowserTypeChrome, OS: OSTypeWindows, Device: DeviceTypePC, Manager: "Tampermonkey", ManagerVersion: "5.1.1"}
+	assert.Equal(t, map[string]string{
+		UADimensionBrowser:        "chrome",
+		UADimensionOS:             "windows",
+		UADimensionDevice:         "pc",
+		UADimensionManager:        "Tampermonkey",
+		UADimensionManagerVersion: "Tampermonkey 5.1.1",
+	}, ua.Dimensions())
+	ua = &UserAgent{Browser: BrowserTypeUnknown, OS: OSTypeUnknown, Device: DeviceTypeUnknown}
+	assert.Equal(t, "unknown", ua.Dimensions()[UADimensionManager])
+	assert.Equal(t, "unknown", ua.Dimensions()[UADimensionManagerVersion])
+}
+
+func TestUserAgent_Impossible(t *testing.T) {
+	assert.False(t, ParseUserAgent("Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Tampermonkey/5.1.1").Impossible())
+	assert.False(t, ParseUserAgent("Mozilla/5.0 (iPad; CPU OS 17_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Safari/604.1 Userscripts").Impossible())
+	assert.True(t, (&UserAgent{Browser: BrowserTypeSafari, OS: OSTypeWindows, Device: DeviceTypePC}).Impossible())
+	assert.True(t, (&UserAgent{Browser: BrowserTypeChrome, OS: OSTypeWindows, Device: DeviceTypePC, Manager: "Greasemonkey"}).Impossible())
+	assert.False(t, (&UserAgent{Browser: BrowserTypeUnknown, OS: OSTypeUnknown, Device: DeviceTypeUnknown, Manager: "Userscripts"}).Impossible())
+}
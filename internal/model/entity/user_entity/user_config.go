@@ -28,8 +28,17 @@ type Notify struct {
 	ScriptReport int `json:"script_report"`
 	// 脚本举报评论
 	ScriptReportComment int `json:"script_report_comment"`
+	// 邮件发送方式 0: 立即发送 1: 每小时汇总 2: 每日汇总
+	Digest int `json:"digest"`
 }
 
+// 邮件发送方式
+const (
+	DigestImmediate = iota // 立即发送
+	DigestHourly           // 每小时汇总
+	DigestDaily            // 每日汇总
+)
+
 func (n *Notify) Scan(value interface{}) error {
 	bytes, ok := value.([]byte)
 	if !ok {
@@ -48,6 +57,16 @@ func (n *Notify) IsEnabled(v int) bool {
 	return v != 2
 }
 
+// DigestMode 邮件发送方式, 无效的值视为立即发送
+func (n *Notify) DigestMode() int {
+	switch n.Digest {
+	case DigestHourly, DigestDaily:
+		return n.Digest
+	default:
+		return DigestImmediate
+	}
+}
+
 type UserConfig struct {
 	ID  int64 `gorm:"column:id;type:bigint(20);not null;primary_key"`
 	Uid int64 `gorm:"column:uid;type:bigint(20);index:user_id"`
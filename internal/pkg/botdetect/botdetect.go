@@ -0,0 +1,129 @@
+package botdetect
+
+import (
+	"bufio"
+	"context"
+	_ "embed"
+	"io"
+	"net"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/cago-frame/cago/configs"
+)
+
+// Reason 判断为机器人的原因
+type Reason string
+
+const (
+	ReasonNone Reason = ""
+	// ReasonUA ua为已知的爬虫, 预览或者监控工具
+	ReasonUA Reason = "ua"
+	// ReasonDatacenter 请求来自机房ip
+	ReasonDatacenter Reason = "datacenter"
+	// ReasonFrequency 不带cookie的高频请求
+	ReasonFrequency Reason = "frequency"
+)
+
+//go:embed datacenter.txt
+var defaultDatacenter string
+
+// 已知的爬虫, 链接预览, 监控与http库的ua, 只匹配明确的标识, 避免误伤设备名称中包含bot的普通用户, 例如CUBOT手机
+var botUARegex = regexp.MustCompile(`(?i)(` +
+	// 带版本号的通用爬虫标识, 例如 Googlebot/2.1, Baiduspider/2.0
+	`[a-z]bot/\d|spider/\d|crawler/\d|` +
+	`googlebot|bingbot|baiduspider|yandexbot|duckduckbot|sogou web spider|360spider|bytespider|petalbot|` +
+	`applebot|ahrefsbot|semrushbot|mj12bot|dotbot|gptbot|claudebot|ccbot|amazonbot|yahoo! slurp|scrapy|` +
+	`curl/|wget/|python-requests|python-urllib|aiohttp|httpx|go-http-client|java/|okhttp|axios/|node-fetch|undici|` +
+	`libwww-perl|headlesschrome|phantomjs|puppeteer|playwright|` +
+	`facebookexternalhit|twitterbot|slackbot|discordbot|telegrambot|linkedinbot|embedly|^whatsapp/|` +
+	`skypeuripreview|google-pagerenderer|` +
+	`uptimerobot|pingdom|statuscake|site24x7|newrelicpinger|datadog agent)`)
+
+// Detector 机器人识别
+type Detector struct {
+	datacenter []*net.IPNet
+}
+
+var (
+	defaultDetector *Detector
+	defaultOnce     sync.Once
+)
+
+// Default 没有初始化时使用内置的机房ip列表
+func Default() *Detector {
+	defaultOnce.Do(func() {
+		if defaultDetector == nil {
+			defaultDetector = New(strings.NewReader(defaultDatacenter))
+		}
+	})
+	return defaultDetector
+}
+
+// Botdetect 初始化机器人识别组件, 配置了statistics.datacenter_file时使用配置的机房ip列表
+func Botdetect(ctx context.Context, cfg *configs.Config) error {
+	file := cfg.String(ctx, "statistics.datacenter_file")
+	if file == "" {
+		defaultDetector = New(strings.NewReader(defaultDatacenter))
+		return nil
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	defaultDetector = New(f)
+	return nil
+}
+
+// New 从机房ip列表创建, 每行一个CIDR, #开头为注释
+func New(datacenter io.Reader) *Detector {
+	d := &Detector{}
+	scanner := bufio.NewScanner(datacenter)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		_, ipNet, err := net.ParseCIDR(line)
+		if err != nil {
+			continue
+		}
+		d.datacenter = append(d.datacenter, ipNet)
+	}
+	return d
+}
+
+// IsBotUA 是否为已知的机器人ua, 空ua也认为是机器人
+func (d *Detector) IsBotUA(ua string) bool {
+	return strings.TrimSpace(ua) == "" || botUARegex.MatchString(ua)
+}
+
+// IsDatacenter ip是否属于机房
+func (d *Detector) IsDatacenter(ip string) bool {
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return false
+	}
+	for _, v := range d.datacenter {
+		if v.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// Classify 根据ua与ip判断, 高频请求需要结合请求记录另外判断
+func (d *Detector) Classify(ua, ip string) Reason {
+	if d.IsBotUA(ua) {
+		return ReasonUA
+	}
+	if d.IsDatacenter(ip) {
+		return ReasonDatacenter
+	}
+	return ReasonNone
+}
@@ -0,0 +1,48 @@
+package botdetect
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetector_Classify(t *testing.T) {
+	d := New(strings.NewReader("# comment\n\n10.0.0.0/8\n2001:db8::/32\ninvalid\n"))
+	chrome := "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+	tests := []struct {
+		name string
+		ua   string
+		ip   string
+		want Reason
+	}{
+		{"正常用户", chrome, "1.1.1.1", ReasonNone},
+		{"空ua", "", "1.1.1.1", ReasonUA},
+		{"搜索引擎", "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)", "1.1.1.1", ReasonUA},
+		{"链接预览", "facebookexternalhit/1.1 (+http://www.facebook.com/externalhit_uatext.php)", "1.1.1.1", ReasonUA},
+		{"监控", "Mozilla/5.0+(compatible; UptimeRobot/2.0; http://www.uptimerobot.com/)", "1.1.1.1", ReasonUA},
+		{"http库", "python-requests/2.31.0", "1.1.1.1", ReasonUA},
+		{"curl", "curl/8.4.0", "1.1.1.1", ReasonUA},
+		{"带版本号的爬虫", "Mozilla/5.0 (compatible; Examplebot/1.0; +https://example.com)", "1.1.1.1", ReasonUA},
+		{"whatsapp链接预览", "WhatsApp/2.23.20.0 A", "1.1.1.1", ReasonUA},
+		{"cubot手机", "Mozilla/5.0 (Linux; Android 10; CUBOT X30) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36", "1.1.1.1", ReasonNone},
+		{"cubot手机build", "Mozilla/5.0 (Linux; Android 11; CUBOT KINGKONG 5 Pro Build/RP1A.200720.011; wv) AppleWebKit/537.36 (KHTML, like Gecko) Version/4.0 Chrome/120.0.0.0 Mobile Safari/537.36", "1.1.1.1", ReasonNone},
+		{"whatsapp内置浏览器", "Mozilla/5.0 (Linux; Android 13; SM-S918B) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36 WhatsApp/2.23.20.0", "1.1.1.1", ReasonNone},
+		{"safari", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.0 Safari/605.1.15", "1.1.1.1", ReasonNone},
+		{"设备名包含monitor", "Mozilla/5.0 (Linux; Android 12; HealthMonitor M1) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36", "1.1.1.1", ReasonNone},
+		{"脚本管理器更新检查", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 ScriptCat/0.16.0", "1.1.1.1", ReasonNone},
+		{"机房ip", chrome, "10.1.2.3", ReasonDatacenter},
+		{"机房ipv6", chrome, "2001:db8::1", ReasonDatacenter},
+		{"无效ip", chrome, "unknown", ReasonNone},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, d.Classify(tt.ua, tt.ip))
+		})
+	}
+}
+
+func TestDefault(t *testing.T) {
+	assert.True(t, Default().IsDatacenter("104.131.1.1"))
+	assert.False(t, Default().IsDatacenter("1.1.1.1"))
+}
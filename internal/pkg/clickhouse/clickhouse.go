@@ -0,0 +1,204 @@
+package clickhouse
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/cago-frame/cago/configs"
+	"github.com/cago-frame/cago/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// Config clickhouse配置, 使用http接口(默认端口8123)
+type Config struct {
+	Address  string `yaml:"address"`
+	Database string `yaml:"database"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+	// DSN 旧版本的配置, 只支持http(s)://user:password@host:port/database的形式
+	DSN string `yaml:"dsn"`
+}
+
+// parseDSN 兼容旧版本的dsn配置, 原生协议的dsn无法使用http接口访问, 返回错误
+func (c *Config) parseDSN() error {
+	u, err := url.Parse(c.DSN)
+	if err != nil {
+		return fmt.Errorf("clickhouse: invalid dsn: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("clickhouse: dsn scheme %q is not supported, "+
+			"please configure clickhouse.address with the http interface (default port 8123)", u.Scheme)
+	}
+	c.Address = u.Scheme + "://" + u.Host
+	if db := strings.Trim(u.Path, "/"); db != "" {
+		c.Database = db
+	}
+	if u.User != nil {
+		c.Username = u.User.Username()
+		c.Password, _ = u.User.Password()
+	}
+	return nil
+}
+
+// Client 通过http接口访问clickhouse, 查询与写入都使用JSONEachRow格式
+type Client struct {
+	config *Config
+	client *http.Client
+}
+
+var defaultClient *Client
+
+// ErrNotConfigured 没有配置clickhouse
+var ErrNotConfigured = errors.New("clickhouse not configured")
+
+// Clickhouse 初始化clickhouse组件, 没有配置地址或者旧版本的dsn无法使用时不启用
+func Clickhouse(ctx context.Context, cfg *configs.Config) error {
+	config := &Config{}
+	if err := cfg.Scan(ctx, "clickhouse", config); err != nil {
+		return err
+	}
+	if config.Address == "" && config.DSN != "" {
+		// 旧版本的配置使用原生协议, 不影响启动, 只是不启用统计收集
+		if err := config.parseDSN(); err != nil {
+			logger.Default().Warn("clickhouse dsn is not supported, statistics collect is disabled", zap.Error(err))
+			return nil
+		}
+	}
+	if config.Address == "" {
+		logger.Default().Warn("clickhouse address is empty, statistics collect is disabled")
+		return nil
+	}
+	defaultClient = NewClient(config)
+	return nil
+}
+
+func Default() *Client {
+	return defaultClient
+}
+
+func SetDefault(client *Client) {
+	defaultClient = client
+}
+
+func NewClient(config *Config) *Client {
+	if config.Database == "" {
+		config.Database = "default"
+	}
+	return &Client{
+		config: config,
+		client: &http.Client{Timeout: time.Second * 30},
+	}
+}
+
+// Exec 执行语句, 参数使用clickhouse的{name:Type}语法
+func (c *Client) Exec(ctx context.Context, query string, params map[string]interface{}) error {
+	resp, err := c.do(ctx, nil, params, strings.NewReader(query))
+	if err != nil {
+		return err
+	}
+	_ = resp.Close()
+	return nil
+}
+
+// Insert 批量写入数据, rows为结构体切片, 使用json tag作为列名
+// 使用clickhouse的异步写入, 由服务端合并小批量的写入, 返回时数据已经落盘
+func (c *Client) Insert(ctx context.Context, table string, rows interface{}) error {
+	v := reflect.ValueOf(rows)
+	if v.Kind() != reflect.Slice {
+		return errors.New("clickhouse: rows must be a slice")
+	}
+	if v.Len() == 0 {
+		return nil
+	}
+	buf := bytes.NewBufferString("INSERT INTO " + table + " FORMAT JSONEachRow\n")
+	encoder := json.NewEncoder(buf)
+	for i := 0; i < v.Len(); i++ {
+		if err := encoder.Encode(v.Index(i).Interface()); err != nil {
+			return err
+		}
+	}
+	resp, err := c.do(ctx, map[string]string{
+		"async_insert":          "1",
+		"wait_for_async_insert": "1",
+	}, nil, buf)
+	if err != nil {
+		return err
+	}
+	_ = resp.Close()
+	return nil
+}
+
+// Query 查询数据, dest为结构体切片的指针, 使用json tag映射列名
+func (c *Client) Query(ctx context.Context, dest interface{}, query string, params map[string]interface{}) error {
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Slice {
+		return errors.New("clickhouse: dest must be a pointer to slice")
+	}
+	slice := v.Elem()
+	resp, err := c.do(ctx, nil, params, strings.NewReader(query+" FORMAT JSONEachRow"))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Close()
+	}()
+	scanner := bufio.NewScanner(resp)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		item := reflect.New(slice.Type().Elem())
+		if err := json.Unmarshal(line, item.Interface()); err != nil {
+			return err
+		}
+		slice.Set(reflect.Append(slice, item.Elem()))
+	}
+	return scanner.Err()
+}
+
+// do settings为clickhouse的设置, params为查询参数
+func (c *Client) do(ctx context.Context, settings map[string]string, params map[string]interface{}, body io.Reader) (io.ReadCloser, error) {
+	query := url.Values{}
+	query.Set("database", c.config.Database)
+	// 64位整数以数字的形式输出, 方便json解析
+	query.Set("output_format_json_quote_64bit_integers", "0")
+	for k, v := range settings {
+		query.Set(k, v)
+	}
+	for k, v := range params {
+		query.Set("param_"+k, fmt.Sprint(v))
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		strings.TrimRight(c.config.Address, "/")+"/?"+query.Encode(), body)
+	if err != nil {
+		return nil, err
+	}
+	if c.config.Username != "" {
+		req.Header.Set("X-ClickHouse-User", c.config.Username)
+		req.Header.Set("X-ClickHouse-Key", c.config.Password)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer func() {
+			_ = resp.Body.Close()
+		}()
+		b, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("clickhouse error: %d %s", resp.StatusCode, bytes.TrimSpace(b))
+	}
+	return resp.Body, nil
+}
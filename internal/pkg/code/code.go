@@ -36,6 +36,10 @@ const (
 
 	ScriptDeleteReleaseNotLatest
 	ScriptCategoryNotFound
+	ScriptMatchUrlInvalid
+	ScriptMigrateRunning
+	ScriptMigrateNotSupport
+	ScriptMigrateModeMismatch
 )
 
 // issue
@@ -62,6 +66,13 @@ const (
 	StatisticsInfoUninitialized
 	StatisticsWhitelistInvalid
 	StatisticsWhitelistNotFound
+	StatisticsCollectDisabled
+	StatisticsDateRangeInvalid
+	StatisticsFraudReviewNotFound
+	StatisticsFraudReviewed
+	StatisticsErrorGroupNotFound
+	StatisticsErrorGroupConverted
+	StatisticsRealtimeStreamLimit
 )
 
 // access
@@ -105,6 +116,9 @@ const (
 const (
 	NotificationNotFound = iota + 109000
 	NotificationPermissionDenied
+	NotificationWebhookNotFound
+	NotificationWebhookURLInvalid
+	NotificationStreamLimit
 )
 
 // report
@@ -38,6 +38,10 @@ var zhCN = map[int]string{
 	WebhookRepositoryNotFound:    "仓库不存在",
 	ScriptDeleteReleaseNotLatest: "删除发布版本失败,没有新的正式版本了",
 	ScriptCategoryNotFound:       "脚本分类不存在",
+	ScriptMatchUrlInvalid:        "网址格式错误",
+	ScriptMigrateRunning:         "索引正在重建中",
+	ScriptMigrateNotSupport:      "当前搜索后端不支持该重建模式",
+	ScriptMigrateModeMismatch:    "存在未完成的其它模式的重建任务, 请选择重新开始",
 
 	IssueLabelNotExist:   "标签不存在",
 	IssueNotFound:        "反馈不存在",
@@ -50,11 +54,18 @@ var zhCN = map[int]string{
 	ResourceNotImage:      "不是图片",
 	ResourceNotFound:      "资源不存在",
 
-	StatisticsLimitExceeded:     "统计数据超过限制",
-	StatisticsResultLimit:       "统计结果限制1000条数据",
-	StatisticsInfoUninitialized: "统计信息未初始化",
-	StatisticsWhitelistInvalid:  "统计白名单无效,不支持顶级域名: %s",
-	StatisticsWhitelistNotFound: "不在白名单内",
+	StatisticsLimitExceeded:       "统计数据超过限制",
+	StatisticsResultLimit:         "统计结果限制1000条数据",
+	StatisticsInfoUninitialized:   "统计信息未初始化",
+	StatisticsWhitelistInvalid:    "统计白名单无效,不支持顶级域名: %s",
+	StatisticsWhitelistNotFound:   "不在白名单内",
+	StatisticsCollectDisabled:     "统计收集未启用",
+	StatisticsDateRangeInvalid:    "统计时间范围无效",
+	StatisticsFraudReviewNotFound: "审核记录不存在",
+	StatisticsFraudReviewed:       "该记录已审核",
+	StatisticsErrorGroupNotFound:  "错误记录不存在",
+	StatisticsErrorGroupConverted: "该错误已转为反馈",
+	StatisticsRealtimeStreamLimit: "实时统计连接过多, 请关闭其它页面后重试",
 
 	AccessAlreadyExist: "权限已存在",
 	AccessNotFound:     "权限不存在",
@@ -81,8 +92,11 @@ var zhCN = map[int]string{
 	ScriptFavoriteLimitExceeded:       "收藏夹脚本数量超过限制,最多100个脚本",
 	ScriptFavoriteFolderCannotEdit:    "系统默认收藏夹不能编辑",
 
-	NotificationNotFound:         "通知不存在",
-	NotificationPermissionDenied: "通知权限不足",
+	NotificationNotFound:          "通知不存在",
+	NotificationPermissionDenied:  "通知权限不足",
+	NotificationWebhookNotFound:   "未配置webhook",
+	NotificationWebhookURLInvalid: "webhook地址无效, 仅支持公网的http或https地址",
+	NotificationStreamLimit:       "通知连接过多, 请关闭其它页面后重试",
 
 	ReportNotFound:        "举报不存在",
 	ReportIsDelete:        "举报已删除",
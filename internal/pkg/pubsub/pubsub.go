@@ -0,0 +1,130 @@
+package pubsub
+
+import (
+	"context"
+	"sync"
+
+	"github.com/cago-frame/cago/database/redis"
+	"github.com/cago-frame/cago/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// subscriberBuffer 本地订阅者的缓冲, 消费过慢时丢弃后续的消息
+const subscriberBuffer = 16
+
+// Hub 每个实例对同一类频道只使用一个redis模式订阅, 再按频道分发给本地的订阅者,
+// 避免每个SSE连接都占用一个redis连接
+type Hub struct {
+	pattern string
+
+	mu      sync.Mutex
+	started bool
+	subs    map[string]map[chan string]struct{}
+}
+
+// NewHub pattern为redis的模式, 例如 notification:user:channel:*
+func NewHub(pattern string) *Hub {
+	return &Hub{
+		pattern: pattern,
+		subs:    make(map[string]map[chan string]struct{}),
+	}
+}
+
+// Subscribe 订阅频道的消息, ctx结束后关闭通道
+func (h *Hub) Subscribe(ctx context.Context, channel string) (<-chan string, error) {
+	if err := h.start(ctx); err != nil {
+		return nil, err
+	}
+	ch := make(chan string, subscriberBuffer)
+	h.mu.Lock()
+	if _, ok := h.subs[channel]; !ok {
+		h.subs[channel] = make(map[chan string]struct{})
+	}
+	h.subs[channel][ch] = struct{}{}
+	h.mu.Unlock()
+	go func() {
+		<-ctx.Done()
+		h.remove(channel, ch)
+	}()
+	return ch, nil
+}
+
+// start 第一次订阅时建立模式订阅, 之后一直保持, 断线后由redis客户端自动重连
+func (h *Hub) start(ctx context.Context) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.started {
+		return nil
+	}
+	sub := redis.Default().PSubscribe(context.Background(), h.pattern)
+	// 等待订阅成功
+	if _, err := sub.Receive(ctx); err != nil {
+		_ = sub.Close()
+		return err
+	}
+	h.started = true
+	go func() {
+		for msg := range sub.Channel() {
+			h.dispatch(msg.Channel, msg.Payload)
+		}
+	}()
+	return nil
+}
+
+func (h *Hub) dispatch(channel, payload string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for ch := range h.subs[channel] {
+		select {
+		case ch <- payload:
+		default:
+			logger.Ctx(context.Background()).Warn("订阅者消费过慢, 丢弃消息", zap.String("channel", channel))
+		}
+	}
+}
+
+func (h *Hub) remove(channel string, ch chan string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.subs[channel], ch)
+	if len(h.subs[channel]) == 0 {
+		delete(h.subs, channel)
+	}
+	close(ch)
+}
+
+// Limiter 限制每个key在本实例上同时存在的连接数量
+type Limiter struct {
+	max int
+
+	mu    sync.Mutex
+	count map[string]int
+}
+
+func NewLimiter(max int) *Limiter {
+	return &Limiter{
+		max:   max,
+		count: make(map[string]int),
+	}
+}
+
+// Acquire 占用一个连接, 超过数量时返回false, 成功时需要调用Release释放
+func (l *Limiter) Acquire(key string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.count[key] >= l.max {
+		return false
+	}
+	l.count[key]++
+	return true
+}
+
+func (l *Limiter) Release(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.count[key] <= 1 {
+		delete(l.count, key)
+		return
+	}
+	l.count[key]--
+}
@@ -0,0 +1,46 @@
+package pubsub
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHub_dispatch(t *testing.T) {
+	h := NewHub("test:*")
+	a, b := make(chan string, 1), make(chan string, 1)
+	h.subs["test:1"] = map[chan string]struct{}{a: {}, b: {}}
+
+	h.dispatch("test:1", "1")
+	assert.Equal(t, "1", <-a)
+	assert.Equal(t, "1", <-b)
+	// 消费过慢时丢弃, 不阻塞其它订阅者
+	h.dispatch("test:1", "2")
+	h.dispatch("test:1", "3")
+	assert.Equal(t, "2", <-a)
+	assert.Len(t, a, 0)
+	// 其它频道的消息不会分发
+	h.dispatch("test:2", "4")
+	assert.Equal(t, "2", <-b)
+	assert.Len(t, b, 0)
+
+	h.remove("test:1", a)
+	_, ok := <-a
+	assert.False(t, ok)
+	h.remove("test:1", b)
+	assert.Empty(t, h.subs)
+}
+
+func TestLimiter(t *testing.T) {
+	l := NewLimiter(2)
+	assert.True(t, l.Acquire("1"))
+	assert.True(t, l.Acquire("1"))
+	assert.False(t, l.Acquire("1"))
+	assert.True(t, l.Acquire("2"))
+	l.Release("1")
+	assert.True(t, l.Acquire("1"))
+	l.Release("1")
+	l.Release("1")
+	l.Release("2")
+	assert.Empty(t, l.count)
+}
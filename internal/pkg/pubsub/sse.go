@@ -0,0 +1,73 @@
+package pubsub
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SSEHeartbeat 没有事件时发送心跳的间隔, 避免连接被代理断开
+const SSEHeartbeat = 30 * time.Second
+
+// SSE 通过Server-Sent Events向客户端推送事件
+type SSE struct {
+	ctx *gin.Context
+}
+
+// NewSSE 写出SSE的响应头, 之后响应头已经发送, 错误无法再返回给客户端
+func NewSSE(ctx *gin.Context) *SSE {
+	header := ctx.Writer.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	// 关闭nginx的缓冲
+	header.Set("X-Accel-Buffering", "no")
+	ctx.Status(http.StatusOK)
+	return &SSE{ctx: ctx}
+}
+
+// Event 写入一个事件, id大于0时写入id, 用于客户端重连时通过Last-Event-ID恢复
+func (s *SSE) Event(id int64, event string, data interface{}) error {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if id > 0 {
+		if _, err := fmt.Fprintf(s.ctx.Writer, "id: %d\n", id); err != nil {
+			return err
+		}
+	}
+	_, err = fmt.Fprintf(s.ctx.Writer, "event: %s\ndata: %s\n\n", event, b)
+	return err
+}
+
+func (s *SSE) Flush() {
+	s.ctx.Writer.Flush()
+}
+
+// Serve 将events中的事件交给handle推送, 没有事件时定时发送心跳, 客户端断开或者events关闭时返回
+func Serve[T any](s *SSE, events <-chan T, handle func(T) error) error {
+	ticker := time.NewTicker(SSEHeartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.ctx.Request.Context().Done():
+			return nil
+		case event, ok := <-events:
+			if !ok {
+				return nil
+			}
+			if err := handle(event); err != nil {
+				return err
+			}
+		case t := <-ticker.C:
+			if err := s.Event(0, "ping", t.Unix()); err != nil {
+				return err
+			}
+		}
+		s.Flush()
+	}
+}
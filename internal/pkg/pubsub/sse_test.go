@@ -0,0 +1,36 @@
+package pubsub
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServe(t *testing.T) {
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = httptest.NewRequest("GET", "/stream", nil)
+	sse := NewSSE(ctx)
+	assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+
+	events := make(chan int64, 2)
+	events <- 1
+	events <- 2
+	close(events)
+	err := Serve(sse, events, func(id int64) error {
+		return sse.Event(id, "test", map[string]int64{"id": id})
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "id: 1\nevent: test\ndata: {\"id\":1}\n\nid: 2\nevent: test\ndata: {\"id\":2}\n\n", w.Body.String())
+
+	// 推送失败时返回错误
+	events = make(chan int64, 1)
+	events <- 1
+	err = Serve(sse, events, func(id int64) error {
+		return errors.New("closed")
+	})
+	assert.Error(t, err)
+}
@@ -58,6 +58,21 @@ func (mr *MockScriptIssueRepoMockRecorder) CountByScript(ctx, scriptId, status a
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountByScript", reflect.TypeOf((*MockScriptIssueRepo)(nil).CountByScript), ctx, scriptId, status)
 }
 
+// CountNewByScripts mocks base method.
+func (m *MockScriptIssueRepo) CountNewByScripts(ctx context.Context, scriptIds []int64, start, end int64) (map[int64]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountNewByScripts", ctx, scriptIds, start, end)
+	ret0, _ := ret[0].(map[int64]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountNewByScripts indicates an expected call of CountNewByScripts.
+func (mr *MockScriptIssueRepoMockRecorder) CountNewByScripts(ctx, scriptIds, start, end any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountNewByScripts", reflect.TypeOf((*MockScriptIssueRepo)(nil).CountNewByScripts), ctx, scriptIds, start, end)
+}
+
 // Create mocks base method.
 func (m *MockScriptIssueRepo) Create(ctx context.Context, scriptIssue *issue_entity.ScriptIssue) error {
 	m.ctrl.T.Helper()
@@ -19,6 +19,8 @@ type ScriptIssueRepo interface {
 	Update(ctx context.Context, scriptIssue *issue_entity.ScriptIssue) error
 	Delete(ctx context.Context, scriptId int64, id int64) error
 	CountByScript(ctx context.Context, scriptId int64, status int32) (int64, error)
+	// CountNewByScripts 统计多个脚本在[start,end)时间内新增的反馈数量, key为脚本id
+	CountNewByScripts(ctx context.Context, scriptIds []int64, start, end int64) (map[int64]int64, error)
 }
 
 var defaultScriptIssue ScriptIssueRepo
@@ -96,3 +98,24 @@ func (u *scriptIssueRepo) CountByScript(ctx context.Context, scriptId int64, sta
 	}
 	return count, nil
 }
+
+func (u *scriptIssueRepo) CountNewByScripts(ctx context.Context, scriptIds []int64, start, end int64) (map[int64]int64, error) {
+	ret := make(map[int64]int64)
+	if len(scriptIds) == 0 {
+		return ret, nil
+	}
+	var list []*struct {
+		ScriptID int64
+		Count    int64
+	}
+	if err := db.Ctx(ctx).Model(&issue_entity.ScriptIssue{}).
+		Select("script_id, count(*) as count").
+		Where("script_id in ? and status!=? and createtime>=? and createtime<?", scriptIds, consts.DELETE, start, end).
+		Group("script_id").Scan(&list).Error; err != nil {
+		return nil, err
+	}
+	for _, v := range list {
+		ret[v.ScriptID] = v.Count
+	}
+	return ret, nil
+}
@@ -0,0 +1,62 @@
+package notification_repo
+
+import (
+	"context"
+
+	"github.com/cago-frame/cago/database/db"
+	"github.com/scriptscat/scriptlist/internal/model/entity/notification_entity"
+)
+
+//go:generate mockgen -source=./mail_buffer.go -destination=./mock/mail_buffer.go
+type MailBufferRepo interface {
+	Create(ctx context.Context, buffer *notification_entity.NotificationMailBuffer) error
+	// FindUserIDs 有待发送邮件的用户
+	FindUserIDs(ctx context.Context) ([]int64, error)
+	// FindByUser 用户待发送的邮件, 按时间正序
+	FindByUser(ctx context.Context, userId int64, limit int) ([]*notification_entity.NotificationMailBuffer, error)
+	// DeleteByUser 删除用户id小于等于maxId的邮件
+	DeleteByUser(ctx context.Context, userId, maxId int64) error
+}
+
+var defaultMailBuffer MailBufferRepo
+
+func MailBuffer() MailBufferRepo {
+	return defaultMailBuffer
+}
+
+func RegisterMailBuffer(i MailBufferRepo) {
+	defaultMailBuffer = i
+}
+
+type mailBufferRepo struct {
+}
+
+func NewMailBufferRepo() MailBufferRepo {
+	return &mailBufferRepo{}
+}
+
+func (r *mailBufferRepo) Create(ctx context.Context, buffer *notification_entity.NotificationMailBuffer) error {
+	return db.Ctx(ctx).Create(buffer).Error
+}
+
+func (r *mailBufferRepo) FindUserIDs(ctx context.Context) ([]int64, error) {
+	var ret []int64
+	if err := db.Ctx(ctx).Model(&notification_entity.NotificationMailBuffer{}).
+		Distinct("user_id").Pluck("user_id", &ret).Error; err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func (r *mailBufferRepo) FindByUser(ctx context.Context, userId int64, limit int) ([]*notification_entity.NotificationMailBuffer, error) {
+	var list []*notification_entity.NotificationMailBuffer
+	if err := db.Ctx(ctx).Where("user_id=?", userId).Order("id asc").Limit(limit).Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *mailBufferRepo) DeleteByUser(ctx context.Context, userId, maxId int64) error {
+	return db.Ctx(ctx).Where("user_id=? and id<=?", userId, maxId).
+		Delete(&notification_entity.NotificationMailBuffer{}).Error
+}
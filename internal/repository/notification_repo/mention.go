@@ -0,0 +1,55 @@
+package notification_repo
+
+import (
+	"context"
+
+	"github.com/cago-frame/cago/database/db"
+	"github.com/scriptscat/scriptlist/internal/model/entity/notification_entity"
+	"gorm.io/gorm"
+)
+
+//go:generate mockgen -source=./mention.go -destination=./mock/mention.go
+type MentionRepo interface {
+	FindByTarget(ctx context.Context, target notification_entity.MentionTarget, targetId int64) ([]*notification_entity.Mention, error)
+	// ReplaceByTarget 替换内容的提及记录
+	ReplaceByTarget(ctx context.Context, target notification_entity.MentionTarget, targetId int64, list []*notification_entity.Mention) error
+}
+
+var defaultMention MentionRepo
+
+func Mention() MentionRepo {
+	return defaultMention
+}
+
+func RegisterMention(i MentionRepo) {
+	defaultMention = i
+}
+
+type mentionRepo struct {
+}
+
+func NewMentionRepo() MentionRepo {
+	return &mentionRepo{}
+}
+
+func (r *mentionRepo) FindByTarget(ctx context.Context, target notification_entity.MentionTarget, targetId int64) ([]*notification_entity.Mention, error) {
+	var list []*notification_entity.Mention
+	if err := db.Ctx(ctx).Where("target_type=? and target_id=?", target, targetId).
+		Order("id asc").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *mentionRepo) ReplaceByTarget(ctx context.Context, target notification_entity.MentionTarget, targetId int64, list []*notification_entity.Mention) error {
+	return db.Ctx(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("target_type=? and target_id=?", target, targetId).
+			Delete(&notification_entity.Mention{}).Error; err != nil {
+			return err
+		}
+		if len(list) == 0 {
+			return nil
+		}
+		return tx.Create(list).Error
+	})
+}
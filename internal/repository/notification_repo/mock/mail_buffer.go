@@ -0,0 +1,100 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: mail_buffer.go
+//
+// Generated by this command:
+//
+//	mockgen -source=mail_buffer.go -destination=mock/mail_buffer.go
+//
+
+// Package mock_notification_repo is a generated GoMock package.
+package mock_notification_repo
+
+import (
+	context "context"
+	reflect "reflect"
+
+	notification_entity "github.com/scriptscat/scriptlist/internal/model/entity/notification_entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockMailBufferRepo is a mock of MailBufferRepo interface.
+type MockMailBufferRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockMailBufferRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockMailBufferRepoMockRecorder is the mock recorder for MockMailBufferRepo.
+type MockMailBufferRepoMockRecorder struct {
+	mock *MockMailBufferRepo
+}
+
+// NewMockMailBufferRepo creates a new mock instance.
+func NewMockMailBufferRepo(ctrl *gomock.Controller) *MockMailBufferRepo {
+	mock := &MockMailBufferRepo{ctrl: ctrl}
+	mock.recorder = &MockMailBufferRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMailBufferRepo) EXPECT() *MockMailBufferRepoMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockMailBufferRepo) Create(ctx context.Context, buffer *notification_entity.NotificationMailBuffer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, buffer)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockMailBufferRepoMockRecorder) Create(ctx, buffer any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockMailBufferRepo)(nil).Create), ctx, buffer)
+}
+
+// DeleteByUser mocks base method.
+func (m *MockMailBufferRepo) DeleteByUser(ctx context.Context, userId, maxId int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByUser", ctx, userId, maxId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteByUser indicates an expected call of DeleteByUser.
+func (mr *MockMailBufferRepoMockRecorder) DeleteByUser(ctx, userId, maxId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByUser", reflect.TypeOf((*MockMailBufferRepo)(nil).DeleteByUser), ctx, userId, maxId)
+}
+
+// FindByUser mocks base method.
+func (m *MockMailBufferRepo) FindByUser(ctx context.Context, userId int64, limit int) ([]*notification_entity.NotificationMailBuffer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByUser", ctx, userId, limit)
+	ret0, _ := ret[0].([]*notification_entity.NotificationMailBuffer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByUser indicates an expected call of FindByUser.
+func (mr *MockMailBufferRepoMockRecorder) FindByUser(ctx, userId, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUser", reflect.TypeOf((*MockMailBufferRepo)(nil).FindByUser), ctx, userId, limit)
+}
+
+// FindUserIDs mocks base method.
+func (m *MockMailBufferRepo) FindUserIDs(ctx context.Context) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindUserIDs", ctx)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindUserIDs indicates an expected call of FindUserIDs.
+func (mr *MockMailBufferRepoMockRecorder) FindUserIDs(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindUserIDs", reflect.TypeOf((*MockMailBufferRepo)(nil).FindUserIDs), ctx)
+}
@@ -0,0 +1,71 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: mention.go
+//
+// Generated by this command:
+//
+//	mockgen -source=mention.go -destination=mock/mention.go
+//
+
+// Package mock_notification_repo is a generated GoMock package.
+package mock_notification_repo
+
+import (
+	context "context"
+	reflect "reflect"
+
+	notification_entity "github.com/scriptscat/scriptlist/internal/model/entity/notification_entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockMentionRepo is a mock of MentionRepo interface.
+type MockMentionRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockMentionRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockMentionRepoMockRecorder is the mock recorder for MockMentionRepo.
+type MockMentionRepoMockRecorder struct {
+	mock *MockMentionRepo
+}
+
+// NewMockMentionRepo creates a new mock instance.
+func NewMockMentionRepo(ctrl *gomock.Controller) *MockMentionRepo {
+	mock := &MockMentionRepo{ctrl: ctrl}
+	mock.recorder = &MockMentionRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMentionRepo) EXPECT() *MockMentionRepoMockRecorder {
+	return m.recorder
+}
+
+// FindByTarget mocks base method.
+func (m *MockMentionRepo) FindByTarget(ctx context.Context, target notification_entity.MentionTarget, targetId int64) ([]*notification_entity.Mention, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByTarget", ctx, target, targetId)
+	ret0, _ := ret[0].([]*notification_entity.Mention)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByTarget indicates an expected call of FindByTarget.
+func (mr *MockMentionRepoMockRecorder) FindByTarget(ctx, target, targetId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByTarget", reflect.TypeOf((*MockMentionRepo)(nil).FindByTarget), ctx, target, targetId)
+}
+
+// ReplaceByTarget mocks base method.
+func (m *MockMentionRepo) ReplaceByTarget(ctx context.Context, target notification_entity.MentionTarget, targetId int64, list []*notification_entity.Mention) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceByTarget", ctx, target, targetId, list)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceByTarget indicates an expected call of ReplaceByTarget.
+func (mr *MockMentionRepoMockRecorder) ReplaceByTarget(ctx, target, targetId, list any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceByTarget", reflect.TypeOf((*MockMentionRepo)(nil).ReplaceByTarget), ctx, target, targetId, list)
+}
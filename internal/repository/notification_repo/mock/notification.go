@@ -0,0 +1,146 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: notification.go
+//
+// Generated by this command:
+//
+//	mockgen -source=notification.go -destination=mock/notification.go
+//
+
+// Package mock_notification_repo is a generated GoMock package.
+package mock_notification_repo
+
+import (
+	context "context"
+	reflect "reflect"
+
+	notification "github.com/scriptscat/scriptlist/internal/api/notification"
+	notification_entity "github.com/scriptscat/scriptlist/internal/model/entity/notification_entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockNotificationRepo is a mock of NotificationRepo interface.
+type MockNotificationRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockNotificationRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockNotificationRepoMockRecorder is the mock recorder for MockNotificationRepo.
+type MockNotificationRepoMockRecorder struct {
+	mock *MockNotificationRepo
+}
+
+// NewMockNotificationRepo creates a new mock instance.
+func NewMockNotificationRepo(ctrl *gomock.Controller) *MockNotificationRepo {
+	mock := &MockNotificationRepo{ctrl: ctrl}
+	mock.recorder = &MockNotificationRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNotificationRepo) EXPECT() *MockNotificationRepoMockRecorder {
+	return m.recorder
+}
+
+// BatchMarkRead mocks base method.
+func (m *MockNotificationRepo) BatchMarkRead(ctx context.Context, userId int64, ids []int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BatchMarkRead", ctx, userId, ids)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BatchMarkRead indicates an expected call of BatchMarkRead.
+func (mr *MockNotificationRepoMockRecorder) BatchMarkRead(ctx, userId, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchMarkRead", reflect.TypeOf((*MockNotificationRepo)(nil).BatchMarkRead), ctx, userId, ids)
+}
+
+// CountUnread mocks base method.
+func (m *MockNotificationRepo) CountUnread(ctx context.Context, userId int64, templateType int32) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUnread", ctx, userId, templateType)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUnread indicates an expected call of CountUnread.
+func (mr *MockNotificationRepoMockRecorder) CountUnread(ctx, userId, templateType any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUnread", reflect.TypeOf((*MockNotificationRepo)(nil).CountUnread), ctx, userId, templateType)
+}
+
+// Create mocks base method.
+func (m *MockNotificationRepo) Create(ctx context.Context, notification *notification_entity.Notification) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, notification)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockNotificationRepoMockRecorder) Create(ctx, notification any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockNotificationRepo)(nil).Create), ctx, notification)
+}
+
+// Find mocks base method.
+func (m *MockNotificationRepo) Find(ctx context.Context, userId, id int64) (*notification_entity.Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Find", ctx, userId, id)
+	ret0, _ := ret[0].(*notification_entity.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find.
+func (mr *MockNotificationRepoMockRecorder) Find(ctx, userId, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockNotificationRepo)(nil).Find), ctx, userId, id)
+}
+
+// FindAfter mocks base method.
+func (m *MockNotificationRepo) FindAfter(ctx context.Context, userId, id int64, limit int) ([]*notification_entity.Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAfter", ctx, userId, id, limit)
+	ret0, _ := ret[0].([]*notification_entity.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAfter indicates an expected call of FindAfter.
+func (mr *MockNotificationRepoMockRecorder) FindAfter(ctx, userId, id, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAfter", reflect.TypeOf((*MockNotificationRepo)(nil).FindAfter), ctx, userId, id, limit)
+}
+
+// FindPage mocks base method.
+func (m *MockNotificationRepo) FindPage(ctx context.Context, userId int64, req *notification.ListRequest) ([]*notification_entity.Notification, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPage", ctx, userId, req)
+	ret0, _ := ret[0].([]*notification_entity.Notification)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FindPage indicates an expected call of FindPage.
+func (mr *MockNotificationRepoMockRecorder) FindPage(ctx, userId, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPage", reflect.TypeOf((*MockNotificationRepo)(nil).FindPage), ctx, userId, req)
+}
+
+// Update mocks base method.
+func (m *MockNotificationRepo) Update(ctx context.Context, notification *notification_entity.Notification) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, notification)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockNotificationRepoMockRecorder) Update(ctx, notification any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockNotificationRepo)(nil).Update), ctx, notification)
+}
@@ -0,0 +1,71 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: realtime.go
+//
+// Generated by this command:
+//
+//	mockgen -source=realtime.go -destination=mock/realtime.go
+//
+
+// Package mock_notification_repo is a generated GoMock package.
+package mock_notification_repo
+
+import (
+	context "context"
+	reflect "reflect"
+
+	notification_repo "github.com/scriptscat/scriptlist/internal/repository/notification_repo"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRealtimeRepo is a mock of RealtimeRepo interface.
+type MockRealtimeRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockRealtimeRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockRealtimeRepoMockRecorder is the mock recorder for MockRealtimeRepo.
+type MockRealtimeRepoMockRecorder struct {
+	mock *MockRealtimeRepo
+}
+
+// NewMockRealtimeRepo creates a new mock instance.
+func NewMockRealtimeRepo(ctrl *gomock.Controller) *MockRealtimeRepo {
+	mock := &MockRealtimeRepo{ctrl: ctrl}
+	mock.recorder = &MockRealtimeRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRealtimeRepo) EXPECT() *MockRealtimeRepoMockRecorder {
+	return m.recorder
+}
+
+// Publish mocks base method.
+func (m *MockRealtimeRepo) Publish(ctx context.Context, userId int64, event *notification_repo.RealtimeEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Publish", ctx, userId, event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockRealtimeRepoMockRecorder) Publish(ctx, userId, event any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockRealtimeRepo)(nil).Publish), ctx, userId, event)
+}
+
+// Subscribe mocks base method.
+func (m *MockRealtimeRepo) Subscribe(ctx context.Context, userId int64) (<-chan *notification_repo.RealtimeEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subscribe", ctx, userId)
+	ret0, _ := ret[0].(<-chan *notification_repo.RealtimeEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Subscribe indicates an expected call of Subscribe.
+func (mr *MockRealtimeRepoMockRecorder) Subscribe(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockRealtimeRepo)(nil).Subscribe), ctx, userId)
+}
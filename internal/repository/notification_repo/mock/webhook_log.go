@@ -0,0 +1,87 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./webhook_log.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./webhook_log.go -destination=./mock/webhook_log.go
+//
+
+// Package mock_notification_repo is a generated GoMock package.
+package mock_notification_repo
+
+import (
+	context "context"
+	reflect "reflect"
+
+	httputils "github.com/cago-frame/cago/pkg/utils/httputils"
+	notification_entity "github.com/scriptscat/scriptlist/internal/model/entity/notification_entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockWebhookLogRepo is a mock of WebhookLogRepo interface.
+type MockWebhookLogRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookLogRepoMockRecorder
+}
+
+// MockWebhookLogRepoMockRecorder is the mock recorder for MockWebhookLogRepo.
+type MockWebhookLogRepoMockRecorder struct {
+	mock *MockWebhookLogRepo
+}
+
+// NewMockWebhookLogRepo creates a new mock instance.
+func NewMockWebhookLogRepo(ctrl *gomock.Controller) *MockWebhookLogRepo {
+	mock := &MockWebhookLogRepo{ctrl: ctrl}
+	mock.recorder = &MockWebhookLogRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhookLogRepo) EXPECT() *MockWebhookLogRepoMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockWebhookLogRepo) Create(ctx context.Context, log *notification_entity.NotificationWebhookLog) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, log)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockWebhookLogRepoMockRecorder) Create(ctx, log any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockWebhookLogRepo)(nil).Create), ctx, log)
+}
+
+// DeleteBefore mocks base method.
+func (m *MockWebhookLogRepo) DeleteBefore(ctx context.Context, t int64, limit int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBefore", ctx, t, limit)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteBefore indicates an expected call of DeleteBefore.
+func (mr *MockWebhookLogRepoMockRecorder) DeleteBefore(ctx, t, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBefore", reflect.TypeOf((*MockWebhookLogRepo)(nil).DeleteBefore), ctx, t, limit)
+}
+
+// FindPage mocks base method.
+func (m *MockWebhookLogRepo) FindPage(ctx context.Context, userId int64, page httputils.PageRequest) ([]*notification_entity.NotificationWebhookLog, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPage", ctx, userId, page)
+	ret0, _ := ret[0].([]*notification_entity.NotificationWebhookLog)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FindPage indicates an expected call of FindPage.
+func (mr *MockWebhookLogRepoMockRecorder) FindPage(ctx, userId, page any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPage", reflect.TypeOf((*MockWebhookLogRepo)(nil).FindPage), ctx, userId, page)
+}
@@ -14,6 +14,8 @@ import (
 type NotificationRepo interface {
 	Find(ctx context.Context, userId, id int64) (*notification_entity.Notification, error)
 	FindPage(ctx context.Context, userId int64, req *api.ListRequest) ([]*notification_entity.Notification, int64, error)
+	// FindAfter 获取id之后的通知, 按id正序
+	FindAfter(ctx context.Context, userId, id int64, limit int) ([]*notification_entity.Notification, error)
 	Create(ctx context.Context, notification *notification_entity.Notification) error
 	Update(ctx context.Context, notification *notification_entity.Notification) error
 
@@ -77,6 +79,15 @@ func (r *notificationRepo) FindPage(ctx context.Context, userId int64, req *api.
 	return list, count, nil
 }
 
+func (r *notificationRepo) FindAfter(ctx context.Context, userId, id int64, limit int) ([]*notification_entity.Notification, error) {
+	var list []*notification_entity.Notification
+	if err := db.Ctx(ctx).Where("user_id=? and id>? and status=?", userId, id, consts.ACTIVE).
+		Order("id asc").Limit(limit).Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
 func (r *notificationRepo) CountUnread(ctx context.Context, userId int64, templateType int32) (int64, error) {
 	var count int64
 	query := db.Ctx(ctx).Model(&notification_entity.Notification{}).
@@ -0,0 +1,92 @@
+package notification_repo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/cago-frame/cago/database/redis"
+	"github.com/cago-frame/cago/pkg/logger"
+	"github.com/scriptscat/scriptlist/internal/pkg/pubsub"
+	"go.uber.org/zap"
+)
+
+// pubsub notification:user:channel:@uid 用户的实时通知事件
+
+//go:generate mockgen -source=./realtime.go -destination=./mock/realtime.go
+
+// 实时通知事件
+const (
+	RealtimeEventNotification = "notification" // 新通知
+	RealtimeEventUnread       = "unread"       // 未读数变化
+)
+
+// RealtimeEvent 实时通知事件, 只通知变化, 内容由订阅方查询
+type RealtimeEvent struct {
+	Event string `json:"event"`
+	ID    int64  `json:"id,omitempty"` // 新通知的id
+}
+
+// RealtimeRepo 通过redis发布订阅分发用户的实时通知, 使多个实例都能收到, 每个实例只使用一个模式订阅
+type RealtimeRepo interface {
+	// Publish 发布用户的通知事件
+	Publish(ctx context.Context, userId int64, event *RealtimeEvent) error
+	// Subscribe 订阅用户的通知事件, ctx结束后关闭通道
+	Subscribe(ctx context.Context, userId int64) (<-chan *RealtimeEvent, error)
+}
+
+var defaultRealtime RealtimeRepo
+
+func Realtime() RealtimeRepo {
+	return defaultRealtime
+}
+
+func RegisterRealtime(i RealtimeRepo) {
+	defaultRealtime = i
+}
+
+type realtimeRepo struct {
+	hub *pubsub.Hub
+}
+
+func NewRealtime() RealtimeRepo {
+	return &realtimeRepo{
+		hub: pubsub.NewHub("notification:user:channel:*"),
+	}
+}
+
+func (r *realtimeRepo) channel(userId int64) string {
+	return fmt.Sprintf("notification:user:channel:%d", userId)
+}
+
+func (r *realtimeRepo) Publish(ctx context.Context, userId int64, event *RealtimeEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return redis.Ctx(ctx).Publish(ctx, r.channel(userId), data).Err()
+}
+
+func (r *realtimeRepo) Subscribe(ctx context.Context, userId int64) (<-chan *RealtimeEvent, error) {
+	ch, err := r.hub.Subscribe(ctx, r.channel(userId))
+	if err != nil {
+		return nil, err
+	}
+	ret := make(chan *RealtimeEvent)
+	go func() {
+		defer close(ret)
+		for payload := range ch {
+			event := &RealtimeEvent{}
+			if err := json.Unmarshal([]byte(payload), event); err != nil {
+				logger.Ctx(ctx).Error("解析实时通知事件失败", zap.Error(err), zap.String("payload", payload))
+				continue
+			}
+			select {
+			case ret <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ret, nil
+}
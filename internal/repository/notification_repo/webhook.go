@@ -0,0 +1,63 @@
+package notification_repo
+
+import (
+	"context"
+
+	"github.com/cago-frame/cago/database/db"
+	"github.com/scriptscat/scriptlist/internal/model/entity/notification_entity"
+)
+
+//go:generate mockgen -source=./webhook.go -destination=./mock/webhook.go
+type WebhookRepo interface {
+	Find(ctx context.Context, id int64) (*notification_entity.NotificationWebhook, error)
+	FindByUser(ctx context.Context, userId int64) (*notification_entity.NotificationWebhook, error)
+	Create(ctx context.Context, webhook *notification_entity.NotificationWebhook) error
+	Update(ctx context.Context, webhook *notification_entity.NotificationWebhook) error
+}
+
+var defaultWebhook WebhookRepo
+
+func Webhook() WebhookRepo {
+	return defaultWebhook
+}
+
+func RegisterWebhook(i WebhookRepo) {
+	defaultWebhook = i
+}
+
+type webhookRepo struct {
+}
+
+func NewWebhookRepo() WebhookRepo {
+	return &webhookRepo{}
+}
+
+func (r *webhookRepo) Find(ctx context.Context, id int64) (*notification_entity.NotificationWebhook, error) {
+	ret := &notification_entity.NotificationWebhook{}
+	if err := db.Ctx(ctx).Where("id=?", id).First(ret).Error; err != nil {
+		if db.RecordNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return ret, nil
+}
+
+func (r *webhookRepo) FindByUser(ctx context.Context, userId int64) (*notification_entity.NotificationWebhook, error) {
+	ret := &notification_entity.NotificationWebhook{}
+	if err := db.Ctx(ctx).Where("user_id=?", userId).First(ret).Error; err != nil {
+		if db.RecordNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return ret, nil
+}
+
+func (r *webhookRepo) Create(ctx context.Context, webhook *notification_entity.NotificationWebhook) error {
+	return db.Ctx(ctx).Create(webhook).Error
+}
+
+func (r *webhookRepo) Update(ctx context.Context, webhook *notification_entity.NotificationWebhook) error {
+	return db.Ctx(ctx).Save(webhook).Error
+}
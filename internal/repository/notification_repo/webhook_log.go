@@ -0,0 +1,59 @@
+package notification_repo
+
+import (
+	"context"
+
+	"github.com/cago-frame/cago/database/db"
+	"github.com/cago-frame/cago/pkg/utils/httputils"
+	"github.com/scriptscat/scriptlist/internal/model/entity/notification_entity"
+)
+
+//go:generate mockgen -source=./webhook_log.go -destination=./mock/webhook_log.go
+type WebhookLogRepo interface {
+	Create(ctx context.Context, log *notification_entity.NotificationWebhookLog) error
+	FindPage(ctx context.Context, userId int64, page httputils.PageRequest) ([]*notification_entity.NotificationWebhookLog, int64, error)
+	// DeleteBefore 删除某个时间之前的投递记录, 每次最多删除limit条, 返回删除的数量
+	DeleteBefore(ctx context.Context, t int64, limit int) (int64, error)
+}
+
+var defaultWebhookLog WebhookLogRepo
+
+func WebhookLog() WebhookLogRepo {
+	return defaultWebhookLog
+}
+
+func RegisterWebhookLog(i WebhookLogRepo) {
+	defaultWebhookLog = i
+}
+
+type webhookLogRepo struct {
+}
+
+func NewWebhookLogRepo() WebhookLogRepo {
+	return &webhookLogRepo{}
+}
+
+func (r *webhookLogRepo) Create(ctx context.Context, log *notification_entity.NotificationWebhookLog) error {
+	return db.Ctx(ctx).Create(log).Error
+}
+
+func (r *webhookLogRepo) FindPage(ctx context.Context, userId int64, page httputils.PageRequest) ([]*notification_entity.NotificationWebhookLog, int64, error) {
+	var list []*notification_entity.NotificationWebhookLog
+	var count int64
+	find := db.Ctx(ctx).Model(&notification_entity.NotificationWebhookLog{}).Where("user_id=?", userId)
+	if err := find.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := find.Order("id desc").Offset(page.GetOffset()).Limit(page.GetLimit()).Find(&list).Error; err != nil {
+		return nil, 0, err
+	}
+	return list, count, nil
+}
+
+func (r *webhookLogRepo) DeleteBefore(ctx context.Context, t int64, limit int) (int64, error) {
+	result := db.Ctx(ctx).Where("createtime<?", t).Limit(limit).Delete(&notification_entity.NotificationWebhookLog{})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
@@ -57,6 +57,21 @@ func (mr *MockScriptReportRepoMockRecorder) CountByScript(ctx, scriptId, status
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountByScript", reflect.TypeOf((*MockScriptReportRepo)(nil).CountByScript), ctx, scriptId, status)
 }
 
+// CountNewByScripts mocks base method.
+func (m *MockScriptReportRepo) CountNewByScripts(ctx context.Context, scriptIds []int64, start, end int64) (map[int64]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountNewByScripts", ctx, scriptIds, start, end)
+	ret0, _ := ret[0].(map[int64]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountNewByScripts indicates an expected call of CountNewByScripts.
+func (mr *MockScriptReportRepoMockRecorder) CountNewByScripts(ctx, scriptIds, start, end any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountNewByScripts", reflect.TypeOf((*MockScriptReportRepo)(nil).CountNewByScripts), ctx, scriptIds, start, end)
+}
+
 // Create mocks base method.
 func (m *MockScriptReportRepo) Create(ctx context.Context, report *report_entity.ScriptReport) error {
 	m.ctrl.T.Helper()
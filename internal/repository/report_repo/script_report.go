@@ -18,6 +18,8 @@ type ScriptReportRepo interface {
 	Update(ctx context.Context, report *report_entity.ScriptReport) error
 	Delete(ctx context.Context, scriptId int64, id int64) error
 	CountByScript(ctx context.Context, scriptId int64, status int32) (int64, error)
+	// CountNewByScripts 统计多个脚本在[start,end)时间内新增的举报数量, key为脚本id
+	CountNewByScripts(ctx context.Context, scriptIds []int64, start, end int64) (map[int64]int64, error)
 }
 
 var defaultScriptReport ScriptReportRepo
@@ -89,3 +91,24 @@ func (r *scriptReportRepo) CountByScript(ctx context.Context, scriptId int64, st
 	}
 	return count, nil
 }
+
+func (r *scriptReportRepo) CountNewByScripts(ctx context.Context, scriptIds []int64, start, end int64) (map[int64]int64, error) {
+	ret := make(map[int64]int64)
+	if len(scriptIds) == 0 {
+		return ret, nil
+	}
+	var list []*struct {
+		ScriptID int64
+		Count    int64
+	}
+	if err := db.Ctx(ctx).Model(&report_entity.ScriptReport{}).
+		Select("script_id, count(*) as count").
+		Where("script_id in ? and status!=? and createtime>=? and createtime<?", scriptIds, consts.DELETE, start, end).
+		Group("script_id").Scan(&list).Error; err != nil {
+		return nil, err
+	}
+	for _, v := range list {
+		ret[v.ScriptID] = v.Count
+	}
+	return ret, nil
+}
@@ -1,35 +1,49 @@
 package script_repo
 
 import (
-	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
-	"fmt"
-	"io"
-	"net/http"
-	"strconv"
 	"time"
 
 	"github.com/cago-frame/cago/database/db"
-	"github.com/cago-frame/cago/database/elasticsearch"
-	"github.com/cago-frame/cago/pkg/consts"
-	"github.com/cago-frame/cago/pkg/logger"
+	"github.com/cago-frame/cago/database/redis"
+	redis2 "github.com/redis/go-redis/v9"
 	entity "github.com/scriptscat/scriptlist/internal/model/entity/script_entity"
-	"go.uber.org/zap"
 )
 
-// ScriptMigrateRepo 迁移到es或者其它数据库中
+//go:generate mockgen -source=migrate.go -destination=mock/migrate.go
+
+// ScriptMigrateRepo 迁移到es或者其它数据库中, 写入操作由搜索后端完成
 type ScriptMigrateRepo interface {
-	// Save 保存脚本数据到elasticsearch
+	// Save 保存脚本数据到搜索引擎
 	Save(ctx context.Context, s *entity.ScriptSearch) error
-	// List 列出脚本数据
-	List(ctx context.Context, start, size int) ([]*entity.Script, error)
+	// List 按id顺序列出大于lastId的脚本数据
+	List(ctx context.Context, lastId int64, size int) ([]*entity.Script, error)
+	// Count 统计大于lastId的脚本数量
+	Count(ctx context.Context, lastId int64) (int64, error)
 	// Convert 转换为es储存的数据
 	Convert(ctx context.Context, e *entity.Script) (*entity.ScriptSearch, error)
 	// Update 更新数据
 	Update(ctx context.Context, s *entity.ScriptSearch) error
 	// Delete 删除数据,但是是软删除
 	Delete(ctx context.Context, id int64) error
+
+	// GetProgress 获取重建索引的进度, 没有时返回nil
+	GetProgress(ctx context.Context) (*entity.MigrateProgress, error)
+	// SaveProgress 保存重建索引的进度
+	SaveProgress(ctx context.Context, progress *entity.MigrateProgress) error
+	// Lock 获取重建索引的锁, 避免多台机器同时执行, 执行中需要定时调用以续期
+	// owner为持有者标识, 续期时锁已不属于owner则返回false
+	Lock(ctx context.Context, owner string, renew bool) (bool, error)
+	// Unlock 释放锁, 只会释放owner持有的锁
+	Unlock(ctx context.Context, owner string) error
+	// IsLocked 是否有机器正在重建索引
+	IsLocked(ctx context.Context) (bool, error)
+	// Cancel 标记取消重建索引
+	Cancel(ctx context.Context) error
+	// IsCancelled 是否被标记取消, 返回true时会同时清除标记
+	IsCancelled(ctx context.Context) (bool, error)
 }
 
 var defaultSearch ScriptMigrateRepo
@@ -50,68 +64,132 @@ func NewMigrateRepo() ScriptMigrateRepo {
 }
 
 func (m *migrateRepo) Save(ctx context.Context, s *entity.ScriptSearch) error {
-	logger := logger.Ctx(ctx).With(zap.Int64("id", s.ID))
-	r, err := s.Reader()
-	if err != nil {
+	if err := SearchEngine().Save(ctx, s); err != nil {
+		return err
+	}
+	return m.dualWrite(ctx, func(reindexer ScriptSearchReindexer, index string) error {
+		return reindexer.SaveToIndex(ctx, index, s)
+	})
+}
+
+func (m *migrateRepo) Update(ctx context.Context, s *entity.ScriptSearch) error {
+	if err := SearchEngine().Update(ctx, s); err != nil {
 		return err
 	}
-	resp, err := elasticsearch.Ctx(ctx).Create(
-		s.CollectionName(), strconv.FormatInt(s.ID, 10), r,
-	)
+	return m.dualWrite(ctx, func(reindexer ScriptSearchReindexer, index string) error {
+		return reindexer.SaveToIndex(ctx, index, s)
+	})
+}
+
+// dualWrite alias模式重建索引期间, 变更需要同时写入正在构建的新索引, 否则切换后会丢失重建期间的更新
+func (m *migrateRepo) dualWrite(ctx context.Context, f func(reindexer ScriptSearchReindexer, index string) error) error {
+	reindexer, ok := SearchEngine().(ScriptSearchReindexer)
+	if !ok {
+		return nil
+	}
+	progress, err := m.GetProgress(ctx)
 	if err != nil {
-		logger.Error("insert error", zap.Error(err))
 		return err
 	}
-	defer func(Body io.ReadCloser) {
-		_ = Body.Close()
-	}(resp.Body)
-	if resp.StatusCode != http.StatusCreated {
-		if resp.StatusCode == http.StatusConflict {
-			// 更新
-			return m.Update(ctx, s)
-		}
-		b, _ := io.ReadAll(resp.Body)
-		logger.Error("insert error", zap.ByteString("body", b), zap.Int("status", resp.StatusCode))
-		return fmt.Errorf("insert error: %d body: %s", resp.StatusCode, b)
+	if progress == nil || progress.Mode != entity.MigrateModeAlias || progress.Index == "" ||
+		progress.Status == entity.MigrateStatusFinished {
+		return nil
 	}
-	logger.Info("insert success")
-	return nil
+	return f(reindexer, progress.Index)
 }
 
-func (m *migrateRepo) Update(ctx context.Context, s *entity.ScriptSearch) error {
-	logger := logger.Ctx(ctx).With(zap.Int64("id", s.ID))
-	r, err := s.Reader()
+func (m *migrateRepo) List(ctx context.Context, lastId int64, size int) ([]*entity.Script, error) {
+	list := make([]*entity.Script, 0, size)
+	if err := db.Ctx(ctx).Model(&entity.Script{}).Where("id>?", lastId).
+		Order("id").Limit(size).Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (m *migrateRepo) Count(ctx context.Context, lastId int64) (int64, error) {
+	var num int64
+	if err := db.Ctx(ctx).Model(&entity.Script{}).Where("id>?", lastId).Count(&num).Error; err != nil {
+		return 0, err
+	}
+	return num, nil
+}
+
+const (
+	migrateProgressKey = "script:migrate:progress"
+	migrateLockKey     = "script:migrate:lock"
+	migrateCancelKey   = "script:migrate:cancel"
+)
+
+func (m *migrateRepo) GetProgress(ctx context.Context) (*entity.MigrateProgress, error) {
+	b, err := redis.Ctx(ctx).Get(migrateProgressKey).Bytes()
 	if err != nil {
-		return err
+		if redis.Nil(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	ret := &entity.MigrateProgress{}
+	if err := json.Unmarshal(b, ret); err != nil {
+		return nil, err
 	}
-	buf := bytes.NewBuffer([]byte("{\"doc\":"))
-	_, _ = io.Copy(buf, r)
-	buf.WriteString("}")
-	resp, err := elasticsearch.Ctx(ctx).Update(
-		s.CollectionName(), strconv.FormatInt(s.ID, 10), buf,
-	)
+	return ret, nil
+}
+
+func (m *migrateRepo) SaveProgress(ctx context.Context, progress *entity.MigrateProgress) error {
+	b, err := json.Marshal(progress)
 	if err != nil {
-		logger.Error("update error", zap.Error(err))
 		return err
 	}
-	defer func(Body io.ReadCloser) {
-		_ = Body.Close()
-	}(resp.Body)
-	if resp.StatusCode != http.StatusOK {
-		b, _ := io.ReadAll(resp.Body)
-		logger.Error("update error", zap.ByteString("body", b), zap.Int("status", resp.StatusCode))
-		return fmt.Errorf("update error: %d body: %s", resp.StatusCode, b)
+	return redis.Ctx(ctx).Set(migrateProgressKey, b, 0).Err()
+}
+
+// 比较持有者后续期或者删除锁, 避免操作其它机器持有的锁
+var (
+	migrateRenewScript = redis2.NewScript(`if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`)
+	migrateUnlockScript = redis2.NewScript(`if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+)
+
+func (m *migrateRepo) Lock(ctx context.Context, owner string, renew bool) (bool, error) {
+	if renew {
+		num, err := migrateRenewScript.Run(ctx, redis.Default(), []string{migrateLockKey},
+			owner, (time.Minute * 5).Milliseconds()).Int64()
+		if err != nil {
+			return false, err
+		}
+		return num > 0, nil
+	}
+	return redis.Ctx(ctx).SetNX(migrateLockKey, owner, time.Minute*5).Result()
+}
+
+func (m *migrateRepo) Unlock(ctx context.Context, owner string) error {
+	return migrateUnlockScript.Run(ctx, redis.Default(), []string{migrateLockKey}, owner).Err()
+}
+
+func (m *migrateRepo) IsLocked(ctx context.Context) (bool, error) {
+	num, err := redis.Ctx(ctx).Exists(migrateLockKey).Result()
+	if err != nil {
+		return false, err
 	}
-	return nil
+	return num > 0, nil
 }
 
-func (m *migrateRepo) List(ctx context.Context, start, size int) ([]*entity.Script, error) {
-	list := make([]*entity.Script, 0, 20)
-	if err := db.Ctx(ctx).Model(&entity.Script{}).
-		Limit(size).Offset(start).Find(&list).Error; err != nil {
-		return nil, err
+func (m *migrateRepo) Cancel(ctx context.Context) error {
+	return redis.Ctx(ctx).Set(migrateCancelKey, "1", time.Hour).Err()
+}
+
+func (m *migrateRepo) IsCancelled(ctx context.Context) (bool, error) {
+	num, err := redis.Ctx(ctx).Del(migrateCancelKey).Result()
+	if err != nil {
+		return false, err
 	}
-	return list, nil
+	return num > 0, nil
 }
 
 func (m *migrateRepo) Convert(ctx context.Context, e *entity.Script) (*entity.ScriptSearch, error) {
@@ -126,6 +204,7 @@ func (m *migrateRepo) Convert(ctx context.Context, e *entity.Script) (*entity.Sc
 		Public:      e.Public,
 		Type:        e.Type,
 		Unwell:      e.Unwell,
+		Archive:     e.Archive,
 		Status:      e.Status,
 		Createtime:  e.Createtime,
 		Updatetime:  e.Updatetime,
@@ -151,6 +230,7 @@ func (m *migrateRepo) Convert(ctx context.Context, e *entity.Script) (*entity.Sc
 	}
 	if statistics != nil {
 		ret.TotalDownload = statistics.Download
+		ret.Trending = statistics.Trending
 		if statistics.ScoreCount > 0 {
 			ret.Score = float64(statistics.Score) / float64(statistics.ScoreCount)
 		}
@@ -161,6 +241,18 @@ func (m *migrateRepo) Convert(ctx context.Context, e *entity.Script) (*entity.Sc
 	}
 	if dateStatistics != nil {
 		ret.TodayDownload = dateStatistics.Download
+		ret.TodayUpdate = dateStatistics.Update
+	}
+	// 疑似刷量待审核的脚本冻结排行数据
+	review, err := ScriptFraudReview().FindPending(ctx, e.ID)
+	if err != nil {
+		return nil, err
+	}
+	if review.IsPending() {
+		ret.TotalDownload = review.Download
+		ret.Trending = review.Trending
+		ret.TodayDownload = 0
+		ret.TodayUpdate = 0
 	}
 	list, err := ScriptCategory().FindByScriptId(ctx, e.ID, entity.ScriptCategoryTypeCategory)
 	if err != nil {
@@ -168,7 +260,15 @@ func (m *migrateRepo) Convert(ctx context.Context, e *entity.Script) (*entity.Sc
 	}
 	ret.Category = make([]int64, 0, len(list))
 	for _, v := range list {
-		ret.Category = append(ret.Category, v.ID)
+		ret.Category = append(ret.Category, v.CategoryID)
+	}
+	tags, err := ScriptCategory().FindByScriptId(ctx, e.ID, entity.ScriptCategoryTypeTag)
+	if err != nil {
+		return nil, err
+	}
+	ret.Tag = make([]int64, 0, len(tags))
+	for _, v := range tags {
+		ret.Tag = append(ret.Tag, v.CategoryID)
 	}
 	domain, err := Domain().List(ctx, e.ID)
 	if err != nil {
@@ -178,27 +278,29 @@ func (m *migrateRepo) Convert(ctx context.Context, e *entity.Script) (*entity.Sc
 	for _, v := range domain {
 		ret.Domain = append(ret.Domain, v.Domain)
 	}
+	locales, err := ScriptLocale().FindByScriptID(ctx, e.ID)
+	if err != nil {
+		return nil, err
+	}
+	ret.LocaleName = make([]string, 0, len(locales))
+	ret.LocaleDescription = make([]string, 0, len(locales))
+	for _, v := range locales {
+		if v.Name != "" {
+			ret.LocaleName = append(ret.LocaleName, v.Name)
+		}
+		if v.Description != "" {
+			ret.LocaleDescription = append(ret.LocaleDescription, v.Description)
+		}
+	}
+	ret.Suggest = entity.NewScriptSuggest(ret)
 	return ret, nil
 }
 
 func (m *migrateRepo) Delete(ctx context.Context, id int64) error {
-	logger := logger.Ctx(ctx).With(zap.Int64("id", id))
-	buf := bytes.NewBuffer([]byte(fmt.Sprintf("{\"doc\":{\"status\":%d}}", consts.DELETE)))
-	resp, err := elasticsearch.Ctx(ctx).Update(
-		(&entity.ScriptSearch{}).CollectionName(), strconv.FormatInt(id, 10), buf,
-	)
-	if err != nil {
-		logger.Error("delete error", zap.Error(err))
+	if err := SearchEngine().Delete(ctx, id); err != nil {
 		return err
 	}
-	defer func(Body io.ReadCloser) {
-		_ = Body.Close()
-	}(resp.Body)
-	if resp.StatusCode != http.StatusOK {
-		b, _ := io.ReadAll(resp.Body)
-		logger.Error("delete error", zap.ByteString("body", b), zap.Int("status", resp.StatusCode))
-		return fmt.Errorf("delete error: %d body: %s", resp.StatusCode, b)
-	}
-	logger.Info("delete success")
-	return nil
+	return m.dualWrite(ctx, func(reindexer ScriptSearchReindexer, index string) error {
+		return reindexer.DeleteFromIndex(ctx, index, id)
+	})
 }
@@ -0,0 +1,230 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: migrate.go
+//
+// Generated by this command:
+//
+//	mockgen -source=migrate.go -destination=mock/migrate.go
+//
+
+// Package mock_script_repo is a generated GoMock package.
+package mock_script_repo
+
+import (
+	context "context"
+	reflect "reflect"
+
+	script_entity "github.com/scriptscat/scriptlist/internal/model/entity/script_entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockScriptMigrateRepo is a mock of ScriptMigrateRepo interface.
+type MockScriptMigrateRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockScriptMigrateRepoMockRecorder
+}
+
+// MockScriptMigrateRepoMockRecorder is the mock recorder for MockScriptMigrateRepo.
+type MockScriptMigrateRepoMockRecorder struct {
+	mock *MockScriptMigrateRepo
+}
+
+// NewMockScriptMigrateRepo creates a new mock instance.
+func NewMockScriptMigrateRepo(ctrl *gomock.Controller) *MockScriptMigrateRepo {
+	mock := &MockScriptMigrateRepo{ctrl: ctrl}
+	mock.recorder = &MockScriptMigrateRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockScriptMigrateRepo) EXPECT() *MockScriptMigrateRepoMockRecorder {
+	return m.recorder
+}
+
+// Cancel mocks base method.
+func (m *MockScriptMigrateRepo) Cancel(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cancel", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Cancel indicates an expected call of Cancel.
+func (mr *MockScriptMigrateRepoMockRecorder) Cancel(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cancel", reflect.TypeOf((*MockScriptMigrateRepo)(nil).Cancel), ctx)
+}
+
+// Convert mocks base method.
+func (m *MockScriptMigrateRepo) Convert(ctx context.Context, e *script_entity.Script) (*script_entity.ScriptSearch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Convert", ctx, e)
+	ret0, _ := ret[0].(*script_entity.ScriptSearch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Convert indicates an expected call of Convert.
+func (mr *MockScriptMigrateRepoMockRecorder) Convert(ctx, e any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Convert", reflect.TypeOf((*MockScriptMigrateRepo)(nil).Convert), ctx, e)
+}
+
+// Count mocks base method.
+func (m *MockScriptMigrateRepo) Count(ctx context.Context, lastId int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Count", ctx, lastId)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Count indicates an expected call of Count.
+func (mr *MockScriptMigrateRepoMockRecorder) Count(ctx, lastId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockScriptMigrateRepo)(nil).Count), ctx, lastId)
+}
+
+// Delete mocks base method.
+func (m *MockScriptMigrateRepo) Delete(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockScriptMigrateRepoMockRecorder) Delete(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockScriptMigrateRepo)(nil).Delete), ctx, id)
+}
+
+// GetProgress mocks base method.
+func (m *MockScriptMigrateRepo) GetProgress(ctx context.Context) (*script_entity.MigrateProgress, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProgress", ctx)
+	ret0, _ := ret[0].(*script_entity.MigrateProgress)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProgress indicates an expected call of GetProgress.
+func (mr *MockScriptMigrateRepoMockRecorder) GetProgress(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProgress", reflect.TypeOf((*MockScriptMigrateRepo)(nil).GetProgress), ctx)
+}
+
+// IsCancelled mocks base method.
+func (m *MockScriptMigrateRepo) IsCancelled(ctx context.Context) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsCancelled", ctx)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsCancelled indicates an expected call of IsCancelled.
+func (mr *MockScriptMigrateRepoMockRecorder) IsCancelled(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsCancelled", reflect.TypeOf((*MockScriptMigrateRepo)(nil).IsCancelled), ctx)
+}
+
+// IsLocked mocks base method.
+func (m *MockScriptMigrateRepo) IsLocked(ctx context.Context) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsLocked", ctx)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsLocked indicates an expected call of IsLocked.
+func (mr *MockScriptMigrateRepoMockRecorder) IsLocked(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsLocked", reflect.TypeOf((*MockScriptMigrateRepo)(nil).IsLocked), ctx)
+}
+
+// List mocks base method.
+func (m *MockScriptMigrateRepo) List(ctx context.Context, lastId int64, size int) ([]*script_entity.Script, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, lastId, size)
+	ret0, _ := ret[0].([]*script_entity.Script)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockScriptMigrateRepoMockRecorder) List(ctx, lastId, size any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockScriptMigrateRepo)(nil).List), ctx, lastId, size)
+}
+
+// Lock mocks base method.
+func (m *MockScriptMigrateRepo) Lock(ctx context.Context, owner string, renew bool) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Lock", ctx, owner, renew)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Lock indicates an expected call of Lock.
+func (mr *MockScriptMigrateRepoMockRecorder) Lock(ctx, owner, renew any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lock", reflect.TypeOf((*MockScriptMigrateRepo)(nil).Lock), ctx, owner, renew)
+}
+
+// Save mocks base method.
+func (m *MockScriptMigrateRepo) Save(ctx context.Context, s *script_entity.ScriptSearch) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, s)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockScriptMigrateRepoMockRecorder) Save(ctx, s any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockScriptMigrateRepo)(nil).Save), ctx, s)
+}
+
+// SaveProgress mocks base method.
+func (m *MockScriptMigrateRepo) SaveProgress(ctx context.Context, progress *script_entity.MigrateProgress) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveProgress", ctx, progress)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveProgress indicates an expected call of SaveProgress.
+func (mr *MockScriptMigrateRepoMockRecorder) SaveProgress(ctx, progress any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveProgress", reflect.TypeOf((*MockScriptMigrateRepo)(nil).SaveProgress), ctx, progress)
+}
+
+// Unlock mocks base method.
+func (m *MockScriptMigrateRepo) Unlock(ctx context.Context, owner string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unlock", ctx, owner)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unlock indicates an expected call of Unlock.
+func (mr *MockScriptMigrateRepoMockRecorder) Unlock(ctx, owner any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unlock", reflect.TypeOf((*MockScriptMigrateRepo)(nil).Unlock), ctx, owner)
+}
+
+// Update mocks base method.
+func (m *MockScriptMigrateRepo) Update(ctx context.Context, s *script_entity.ScriptSearch) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, s)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockScriptMigrateRepoMockRecorder) Update(ctx, s any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockScriptMigrateRepo)(nil).Update), ctx, s)
+}
@@ -86,6 +86,21 @@ func (mr *MockScriptRepoMockRecorder) Find(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockScriptRepo)(nil).Find), ctx, id)
 }
 
+// FindByUser mocks base method.
+func (m *MockScriptRepo) FindByUser(ctx context.Context, uid int64) ([]*script_entity.Script, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByUser", ctx, uid)
+	ret0, _ := ret[0].([]*script_entity.Script)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByUser indicates an expected call of FindByUser.
+func (mr *MockScriptRepoMockRecorder) FindByUser(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUser", reflect.TypeOf((*MockScriptRepo)(nil).FindByUser), ctx, uid)
+}
+
 // FindSyncPrefix mocks base method.
 func (m *MockScriptRepo) FindSyncPrefix(ctx context.Context, uid int64, prefix string) ([]*script_entity.Script, error) {
 	m.ctrl.T.Helper()
@@ -85,6 +85,21 @@ func (mr *MockScriptAccessRepoMockRecorder) Find(ctx, scriptId, id any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockScriptAccessRepo)(nil).Find), ctx, scriptId, id)
 }
 
+// FindAllByLinkID mocks base method.
+func (m *MockScriptAccessRepo) FindAllByLinkID(ctx context.Context, linkIds []int64, accessType script_entity.AccessType) ([]*script_entity.ScriptAccess, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAllByLinkID", ctx, linkIds, accessType)
+	ret0, _ := ret[0].([]*script_entity.ScriptAccess)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAllByLinkID indicates an expected call of FindAllByLinkID.
+func (mr *MockScriptAccessRepoMockRecorder) FindAllByLinkID(ctx, linkIds, accessType any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAllByLinkID", reflect.TypeOf((*MockScriptAccessRepo)(nil).FindAllByLinkID), ctx, linkIds, accessType)
+}
+
 // FindByLinkID mocks base method.
 func (m *MockScriptAccessRepo) FindByLinkID(ctx context.Context, scriptId, linkId int64, accessType script_entity.AccessType) ([]*script_entity.ScriptAccess, error) {
 	m.ctrl.T.Helper()
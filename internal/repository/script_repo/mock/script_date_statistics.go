@@ -0,0 +1,157 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: script_date_statistics.go
+//
+// Generated by this command:
+//
+//	mockgen -source=script_date_statistics.go -destination=mock/script_date_statistics.go
+//
+
+// Package mock_script_repo is a generated GoMock package.
+package mock_script_repo
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	entity "github.com/scriptscat/scriptlist/internal/model/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockScriptDateStatisticsRepo is a mock of ScriptDateStatisticsRepo interface.
+type MockScriptDateStatisticsRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockScriptDateStatisticsRepoMockRecorder
+}
+
+// MockScriptDateStatisticsRepoMockRecorder is the mock recorder for MockScriptDateStatisticsRepo.
+type MockScriptDateStatisticsRepoMockRecorder struct {
+	mock *MockScriptDateStatisticsRepo
+}
+
+// NewMockScriptDateStatisticsRepo creates a new mock instance.
+func NewMockScriptDateStatisticsRepo(ctrl *gomock.Controller) *MockScriptDateStatisticsRepo {
+	mock := &MockScriptDateStatisticsRepo{ctrl: ctrl}
+	mock.recorder = &MockScriptDateStatisticsRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockScriptDateStatisticsRepo) EXPECT() *MockScriptDateStatisticsRepoMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockScriptDateStatisticsRepo) Create(ctx context.Context, scriptDateStatistics *entity.ScriptDateStatistics) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, scriptDateStatistics)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockScriptDateStatisticsRepoMockRecorder) Create(ctx, scriptDateStatistics any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockScriptDateStatisticsRepo)(nil).Create), ctx, scriptDateStatistics)
+}
+
+// Delete mocks base method.
+func (m *MockScriptDateStatisticsRepo) Delete(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockScriptDateStatisticsRepoMockRecorder) Delete(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockScriptDateStatisticsRepo)(nil).Delete), ctx, id)
+}
+
+// Find mocks base method.
+func (m *MockScriptDateStatisticsRepo) Find(ctx context.Context, id int64) (*entity.ScriptDateStatistics, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Find", ctx, id)
+	ret0, _ := ret[0].(*entity.ScriptDateStatistics)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find.
+func (mr *MockScriptDateStatisticsRepoMockRecorder) Find(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockScriptDateStatisticsRepo)(nil).Find), ctx, id)
+}
+
+// FindByScriptID mocks base method.
+func (m *MockScriptDateStatisticsRepo) FindByScriptID(ctx context.Context, scriptId int64, t time.Time) (*entity.ScriptDateStatistics, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByScriptID", ctx, scriptId, t)
+	ret0, _ := ret[0].(*entity.ScriptDateStatistics)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByScriptID indicates an expected call of FindByScriptID.
+func (mr *MockScriptDateStatisticsRepoMockRecorder) FindByScriptID(ctx, scriptId, t any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByScriptID", reflect.TypeOf((*MockScriptDateStatisticsRepo)(nil).FindByScriptID), ctx, scriptId, t)
+}
+
+// FindByScriptIDs mocks base method.
+func (m *MockScriptDateStatisticsRepo) FindByScriptIDs(ctx context.Context, scriptIds []int64, start time.Time) ([]*entity.ScriptDateStatistics, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByScriptIDs", ctx, scriptIds, start)
+	ret0, _ := ret[0].([]*entity.ScriptDateStatistics)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByScriptIDs indicates an expected call of FindByScriptIDs.
+func (mr *MockScriptDateStatisticsRepoMockRecorder) FindByScriptIDs(ctx, scriptIds, start any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByScriptIDs", reflect.TypeOf((*MockScriptDateStatisticsRepo)(nil).FindByScriptIDs), ctx, scriptIds, start)
+}
+
+// IncrDownload mocks base method.
+func (m *MockScriptDateStatisticsRepo) IncrDownload(ctx context.Context, scriptId int64, t time.Time, num int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrDownload", ctx, scriptId, t, num)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IncrDownload indicates an expected call of IncrDownload.
+func (mr *MockScriptDateStatisticsRepoMockRecorder) IncrDownload(ctx, scriptId, t, num any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrDownload", reflect.TypeOf((*MockScriptDateStatisticsRepo)(nil).IncrDownload), ctx, scriptId, t, num)
+}
+
+// IncrUpdate mocks base method.
+func (m *MockScriptDateStatisticsRepo) IncrUpdate(ctx context.Context, scriptId int64, t time.Time, num int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrUpdate", ctx, scriptId, t, num)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IncrUpdate indicates an expected call of IncrUpdate.
+func (mr *MockScriptDateStatisticsRepoMockRecorder) IncrUpdate(ctx, scriptId, t, num any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrUpdate", reflect.TypeOf((*MockScriptDateStatisticsRepo)(nil).IncrUpdate), ctx, scriptId, t, num)
+}
+
+// Update mocks base method.
+func (m *MockScriptDateStatisticsRepo) Update(ctx context.Context, scriptDateStatistics *entity.ScriptDateStatistics) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, scriptDateStatistics)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockScriptDateStatisticsRepoMockRecorder) Update(ctx, scriptDateStatistics any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockScriptDateStatisticsRepo)(nil).Update), ctx, scriptDateStatistics)
+}
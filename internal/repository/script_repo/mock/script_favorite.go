@@ -0,0 +1,192 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: script_favorite.go
+//
+// Generated by this command:
+//
+//	mockgen -source=script_favorite.go -destination=mock/script_favorite.go
+//
+
+// Package mock_script_repo is a generated GoMock package.
+package mock_script_repo
+
+import (
+	context "context"
+	reflect "reflect"
+
+	httputils "github.com/cago-frame/cago/pkg/utils/httputils"
+	script_entity "github.com/scriptscat/scriptlist/internal/model/entity/script_entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockScriptFavoriteRepo is a mock of ScriptFavoriteRepo interface.
+type MockScriptFavoriteRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockScriptFavoriteRepoMockRecorder
+}
+
+// MockScriptFavoriteRepoMockRecorder is the mock recorder for MockScriptFavoriteRepo.
+type MockScriptFavoriteRepoMockRecorder struct {
+	mock *MockScriptFavoriteRepo
+}
+
+// NewMockScriptFavoriteRepo creates a new mock instance.
+func NewMockScriptFavoriteRepo(ctrl *gomock.Controller) *MockScriptFavoriteRepo {
+	mock := &MockScriptFavoriteRepo{ctrl: ctrl}
+	mock.recorder = &MockScriptFavoriteRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockScriptFavoriteRepo) EXPECT() *MockScriptFavoriteRepoMockRecorder {
+	return m.recorder
+}
+
+// CountUniqueUsersByScriptID mocks base method.
+func (m *MockScriptFavoriteRepo) CountUniqueUsersByScriptID(ctx context.Context, scriptId int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUniqueUsersByScriptID", ctx, scriptId)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUniqueUsersByScriptID indicates an expected call of CountUniqueUsersByScriptID.
+func (mr *MockScriptFavoriteRepoMockRecorder) CountUniqueUsersByScriptID(ctx, scriptId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUniqueUsersByScriptID", reflect.TypeOf((*MockScriptFavoriteRepo)(nil).CountUniqueUsersByScriptID), ctx, scriptId)
+}
+
+// Create mocks base method.
+func (m *MockScriptFavoriteRepo) Create(ctx context.Context, scriptFavorite *script_entity.ScriptFavorite) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, scriptFavorite)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockScriptFavoriteRepoMockRecorder) Create(ctx, scriptFavorite any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockScriptFavoriteRepo)(nil).Create), ctx, scriptFavorite)
+}
+
+// Delete mocks base method.
+func (m *MockScriptFavoriteRepo) Delete(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockScriptFavoriteRepoMockRecorder) Delete(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockScriptFavoriteRepo)(nil).Delete), ctx, id)
+}
+
+// Find mocks base method.
+func (m *MockScriptFavoriteRepo) Find(ctx context.Context, id int64) (*script_entity.ScriptFavorite, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Find", ctx, id)
+	ret0, _ := ret[0].(*script_entity.ScriptFavorite)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find.
+func (mr *MockScriptFavoriteRepoMockRecorder) Find(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockScriptFavoriteRepo)(nil).Find), ctx, id)
+}
+
+// FindByFavoriteAndScriptID mocks base method.
+func (m *MockScriptFavoriteRepo) FindByFavoriteAndScriptID(ctx context.Context, userId, folderID, scriptID int64) (*script_entity.ScriptFavorite, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByFavoriteAndScriptID", ctx, userId, folderID, scriptID)
+	ret0, _ := ret[0].(*script_entity.ScriptFavorite)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByFavoriteAndScriptID indicates an expected call of FindByFavoriteAndScriptID.
+func (mr *MockScriptFavoriteRepoMockRecorder) FindByFavoriteAndScriptID(ctx, userId, folderID, scriptID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByFavoriteAndScriptID", reflect.TypeOf((*MockScriptFavoriteRepo)(nil).FindByFavoriteAndScriptID), ctx, userId, folderID, scriptID)
+}
+
+// FindByFavoriteFolder mocks base method.
+func (m *MockScriptFavoriteRepo) FindByFavoriteFolder(ctx context.Context, folderId int64, page httputils.PageRequest) ([]*script_entity.ScriptFavorite, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByFavoriteFolder", ctx, folderId, page)
+	ret0, _ := ret[0].([]*script_entity.ScriptFavorite)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FindByFavoriteFolder indicates an expected call of FindByFavoriteFolder.
+func (mr *MockScriptFavoriteRepoMockRecorder) FindByFavoriteFolder(ctx, folderId, page any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByFavoriteFolder", reflect.TypeOf((*MockScriptFavoriteRepo)(nil).FindByFavoriteFolder), ctx, folderId, page)
+}
+
+// FindByUserIDAndScriptID mocks base method.
+func (m *MockScriptFavoriteRepo) FindByUserIDAndScriptID(ctx context.Context, userId, scriptID int64) ([]*script_entity.ScriptFavorite, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByUserIDAndScriptID", ctx, userId, scriptID)
+	ret0, _ := ret[0].([]*script_entity.ScriptFavorite)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByUserIDAndScriptID indicates an expected call of FindByUserIDAndScriptID.
+func (mr *MockScriptFavoriteRepoMockRecorder) FindByUserIDAndScriptID(ctx, userId, scriptID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUserIDAndScriptID", reflect.TypeOf((*MockScriptFavoriteRepo)(nil).FindByUserIDAndScriptID), ctx, userId, scriptID)
+}
+
+// FindByUserUnique mocks base method.
+func (m *MockScriptFavoriteRepo) FindByUserUnique(ctx context.Context, userId int64, self bool, page httputils.PageRequest) ([]*script_entity.ScriptFavorite, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByUserUnique", ctx, userId, self, page)
+	ret0, _ := ret[0].([]*script_entity.ScriptFavorite)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FindByUserUnique indicates an expected call of FindByUserUnique.
+func (mr *MockScriptFavoriteRepoMockRecorder) FindByUserUnique(ctx, userId, self, page any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUserUnique", reflect.TypeOf((*MockScriptFavoriteRepo)(nil).FindByUserUnique), ctx, userId, self, page)
+}
+
+// FindPage mocks base method.
+func (m *MockScriptFavoriteRepo) FindPage(ctx context.Context, page httputils.PageRequest) ([]*script_entity.ScriptFavorite, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPage", ctx, page)
+	ret0, _ := ret[0].([]*script_entity.ScriptFavorite)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FindPage indicates an expected call of FindPage.
+func (mr *MockScriptFavoriteRepoMockRecorder) FindPage(ctx, page any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPage", reflect.TypeOf((*MockScriptFavoriteRepo)(nil).FindPage), ctx, page)
+}
+
+// Update mocks base method.
+func (m *MockScriptFavoriteRepo) Update(ctx context.Context, scriptFavorite *script_entity.ScriptFavorite) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, scriptFavorite)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockScriptFavoriteRepoMockRecorder) Update(ctx, scriptFavorite any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockScriptFavoriteRepo)(nil).Update), ctx, scriptFavorite)
+}
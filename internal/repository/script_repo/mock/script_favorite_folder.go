@@ -0,0 +1,115 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: script_favorite_folder.go
+//
+// Generated by this command:
+//
+//	mockgen -source=script_favorite_folder.go -destination=mock/script_favorite_folder.go
+//
+
+// Package mock_script_repo is a generated GoMock package.
+package mock_script_repo
+
+import (
+	context "context"
+	reflect "reflect"
+
+	httputils "github.com/cago-frame/cago/pkg/utils/httputils"
+	script_entity "github.com/scriptscat/scriptlist/internal/model/entity/script_entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockScriptFavoriteFolderRepo is a mock of ScriptFavoriteFolderRepo interface.
+type MockScriptFavoriteFolderRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockScriptFavoriteFolderRepoMockRecorder
+}
+
+// MockScriptFavoriteFolderRepoMockRecorder is the mock recorder for MockScriptFavoriteFolderRepo.
+type MockScriptFavoriteFolderRepoMockRecorder struct {
+	mock *MockScriptFavoriteFolderRepo
+}
+
+// NewMockScriptFavoriteFolderRepo creates a new mock instance.
+func NewMockScriptFavoriteFolderRepo(ctrl *gomock.Controller) *MockScriptFavoriteFolderRepo {
+	mock := &MockScriptFavoriteFolderRepo{ctrl: ctrl}
+	mock.recorder = &MockScriptFavoriteFolderRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockScriptFavoriteFolderRepo) EXPECT() *MockScriptFavoriteFolderRepoMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockScriptFavoriteFolderRepo) Create(ctx context.Context, scriptFavoriteFolder *script_entity.ScriptFavoriteFolder) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, scriptFavoriteFolder)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockScriptFavoriteFolderRepoMockRecorder) Create(ctx, scriptFavoriteFolder any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockScriptFavoriteFolderRepo)(nil).Create), ctx, scriptFavoriteFolder)
+}
+
+// Delete mocks base method.
+func (m *MockScriptFavoriteFolderRepo) Delete(ctx context.Context, id, userId int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockScriptFavoriteFolderRepoMockRecorder) Delete(ctx, id, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockScriptFavoriteFolderRepo)(nil).Delete), ctx, id, userId)
+}
+
+// Find mocks base method.
+func (m *MockScriptFavoriteFolderRepo) Find(ctx context.Context, id int64) (*script_entity.ScriptFavoriteFolder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Find", ctx, id)
+	ret0, _ := ret[0].(*script_entity.ScriptFavoriteFolder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find.
+func (mr *MockScriptFavoriteFolderRepoMockRecorder) Find(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockScriptFavoriteFolderRepo)(nil).Find), ctx, id)
+}
+
+// FindPage mocks base method.
+func (m *MockScriptFavoriteFolderRepo) FindPage(ctx context.Context, userId int64, private bool, page httputils.PageRequest) ([]*script_entity.ScriptFavoriteFolder, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPage", ctx, userId, private, page)
+	ret0, _ := ret[0].([]*script_entity.ScriptFavoriteFolder)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FindPage indicates an expected call of FindPage.
+func (mr *MockScriptFavoriteFolderRepoMockRecorder) FindPage(ctx, userId, private, page any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPage", reflect.TypeOf((*MockScriptFavoriteFolderRepo)(nil).FindPage), ctx, userId, private, page)
+}
+
+// Update mocks base method.
+func (m *MockScriptFavoriteFolderRepo) Update(ctx context.Context, scriptFavoriteFolder *script_entity.ScriptFavoriteFolder) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, scriptFavoriteFolder)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockScriptFavoriteFolderRepoMockRecorder) Update(ctx, scriptFavoriteFolder any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockScriptFavoriteFolderRepo)(nil).Update), ctx, scriptFavoriteFolder)
+}
@@ -0,0 +1,117 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: script_fraud_review.go
+//
+// Generated by this command:
+//
+//	mockgen -source=script_fraud_review.go -destination=mock/script_fraud_review.go
+//
+
+// Package mock_script_repo is a generated GoMock package.
+package mock_script_repo
+
+import (
+	context "context"
+	reflect "reflect"
+
+	httputils "github.com/cago-frame/cago/pkg/utils/httputils"
+	script_entity "github.com/scriptscat/scriptlist/internal/model/entity/script_entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockScriptFraudReviewRepo is a mock of ScriptFraudReviewRepo interface.
+type MockScriptFraudReviewRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockScriptFraudReviewRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockScriptFraudReviewRepoMockRecorder is the mock recorder for MockScriptFraudReviewRepo.
+type MockScriptFraudReviewRepoMockRecorder struct {
+	mock *MockScriptFraudReviewRepo
+}
+
+// NewMockScriptFraudReviewRepo creates a new mock instance.
+func NewMockScriptFraudReviewRepo(ctrl *gomock.Controller) *MockScriptFraudReviewRepo {
+	mock := &MockScriptFraudReviewRepo{ctrl: ctrl}
+	mock.recorder = &MockScriptFraudReviewRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockScriptFraudReviewRepo) EXPECT() *MockScriptFraudReviewRepoMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockScriptFraudReviewRepo) Create(ctx context.Context, review *script_entity.ScriptFraudReview) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, review)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockScriptFraudReviewRepoMockRecorder) Create(ctx, review any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockScriptFraudReviewRepo)(nil).Create), ctx, review)
+}
+
+// Find mocks base method.
+func (m *MockScriptFraudReviewRepo) Find(ctx context.Context, id int64) (*script_entity.ScriptFraudReview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Find", ctx, id)
+	ret0, _ := ret[0].(*script_entity.ScriptFraudReview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find.
+func (mr *MockScriptFraudReviewRepoMockRecorder) Find(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockScriptFraudReviewRepo)(nil).Find), ctx, id)
+}
+
+// FindPage mocks base method.
+func (m *MockScriptFraudReviewRepo) FindPage(ctx context.Context, status script_entity.FraudReviewStatus, page httputils.PageRequest) ([]*script_entity.ScriptFraudReview, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPage", ctx, status, page)
+	ret0, _ := ret[0].([]*script_entity.ScriptFraudReview)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FindPage indicates an expected call of FindPage.
+func (mr *MockScriptFraudReviewRepoMockRecorder) FindPage(ctx, status, page any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPage", reflect.TypeOf((*MockScriptFraudReviewRepo)(nil).FindPage), ctx, status, page)
+}
+
+// FindPending mocks base method.
+func (m *MockScriptFraudReviewRepo) FindPending(ctx context.Context, scriptId int64) (*script_entity.ScriptFraudReview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPending", ctx, scriptId)
+	ret0, _ := ret[0].(*script_entity.ScriptFraudReview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindPending indicates an expected call of FindPending.
+func (mr *MockScriptFraudReviewRepoMockRecorder) FindPending(ctx, scriptId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPending", reflect.TypeOf((*MockScriptFraudReviewRepo)(nil).FindPending), ctx, scriptId)
+}
+
+// Update mocks base method.
+func (m *MockScriptFraudReviewRepo) Update(ctx context.Context, review *script_entity.ScriptFraudReview) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, review)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockScriptFraudReviewRepoMockRecorder) Update(ctx, review any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockScriptFraudReviewRepo)(nil).Update), ctx, review)
+}
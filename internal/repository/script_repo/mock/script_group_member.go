@@ -85,6 +85,21 @@ func (mr *MockScriptGroupMemberRepoMockRecorder) Find(ctx, scriptId, id any) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockScriptGroupMemberRepo)(nil).Find), ctx, scriptId, id)
 }
 
+// FindAllByUserId mocks base method.
+func (m *MockScriptGroupMemberRepo) FindAllByUserId(ctx context.Context, userId int64) ([]*script_entity.ScriptGroupMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAllByUserId", ctx, userId)
+	ret0, _ := ret[0].([]*script_entity.ScriptGroupMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAllByUserId indicates an expected call of FindAllByUserId.
+func (mr *MockScriptGroupMemberRepoMockRecorder) FindAllByUserId(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAllByUserId", reflect.TypeOf((*MockScriptGroupMemberRepo)(nil).FindAllByUserId), ctx, userId)
+}
+
 // FindByUserId mocks base method.
 func (m *MockScriptGroupMemberRepo) FindByUserId(ctx context.Context, scriptId, userId int64) ([]*script_entity.ScriptGroupMember, error) {
 	m.ctrl.T.Helper()
@@ -176,6 +176,21 @@ func (mr *MockScriptScoreRepoMockRecorder) ScoreList(ctx, scriptId, page any) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScoreList", reflect.TypeOf((*MockScriptScoreRepo)(nil).ScoreList), ctx, scriptId, page)
 }
 
+// StatByScripts mocks base method.
+func (m *MockScriptScoreRepo) StatByScripts(ctx context.Context, scriptIds []int64, start, end int64) ([]*script_repo.ScoreStat, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StatByScripts", ctx, scriptIds, start, end)
+	ret0, _ := ret[0].([]*script_repo.ScoreStat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StatByScripts indicates an expected call of StatByScripts.
+func (mr *MockScriptScoreRepoMockRecorder) StatByScripts(ctx, scriptIds, start, end any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StatByScripts", reflect.TypeOf((*MockScriptScoreRepo)(nil).StatByScripts), ctx, scriptIds, start, end)
+}
+
 // Update mocks base method.
 func (m *MockScriptScoreRepo) Update(ctx context.Context, scriptScore *script_entity.ScriptScore) error {
 	m.ctrl.T.Helper()
@@ -154,3 +154,17 @@ func (mr *MockScriptStatisticsRepoMockRecorder) Update(ctx, scriptStatistics any
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockScriptStatisticsRepo)(nil).Update), ctx, scriptStatistics)
 }
+
+// UpdateTrending mocks base method.
+func (m *MockScriptStatisticsRepo) UpdateTrending(ctx context.Context, scriptId int64, trending float64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTrending", ctx, scriptId, trending)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTrending indicates an expected call of UpdateTrending.
+func (mr *MockScriptStatisticsRepoMockRecorder) UpdateTrending(ctx, scriptId, trending any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTrending", reflect.TypeOf((*MockScriptStatisticsRepo)(nil).UpdateTrending), ctx, scriptId, trending)
+}
@@ -1,11 +1,8 @@
 package script_repo
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
 	"strconv"
 	"strings"
 	"time"
@@ -22,7 +19,6 @@ import (
 	"github.com/cago-frame/cago/database/cache"
 	cache2 "github.com/cago-frame/cago/database/cache/cache"
 	"github.com/cago-frame/cago/database/db"
-	"github.com/cago-frame/cago/database/elasticsearch"
 	"github.com/cago-frame/cago/pkg/consts"
 	"github.com/cago-frame/cago/pkg/utils/httputils"
 	entity "github.com/scriptscat/scriptlist/internal/model/entity/script_entity"
@@ -49,6 +45,8 @@ type ScriptRepo interface {
 	// FindSyncScript 查找需要自动同步的脚本
 	FindSyncScript(ctx context.Context, page httputils.PageRequest) ([]*entity.Script, error)
 	FindSyncPrefix(ctx context.Context, uid int64, prefix string) ([]*entity.Script, error)
+	// FindByUser 获取用户拥有的所有脚本
+	FindByUser(ctx context.Context, uid int64) ([]*entity.Script, error)
 }
 
 var defaultScript ScriptRepo
@@ -130,8 +128,11 @@ func (u *scriptRepo) Search(ctx context.Context, options *SearchOptions, page ht
 		options.Category = make([]int64, 0)
 	}
 	if options.Keyword != "" {
-		// 暂时不支持排序等
-		return u.SearchByEs(ctx, options, page)
+		ret, err := SearchEngine().Search(ctx, options, page)
+		if err != nil {
+			return nil, 0, err
+		}
+		return ret.List, ret.Total, nil
 	}
 	// 无关键字从mysql数据库中获取
 	list := make([]*entity.Script, 0)
@@ -176,23 +177,33 @@ func (u *scriptRepo) Search(ctx context.Context, options *SearchOptions, page ht
 		}
 	}
 
+	// 疑似刷量待审核的脚本冻结排行数据, 今日数据记为0, 总下载量与热度使用标记时的值
+	fraudTbName := db.Default().NamingStrategy.TableName("script_fraud_review")
+	joinFraud := func(find *gorm.DB) *gorm.DB {
+		return find.Joins(fmt.Sprintf("left join %s on %s.script_id=%s.id and %s.status=?",
+			fraudTbName, fraudTbName, scriptTbName, fraudTbName), entity.FraudReviewPending)
+	}
 	switch options.Sort {
 	case "today_update":
 		tabname := db.Default().NamingStrategy.TableName("script_date_statistics")
-		find = find.Joins(fmt.Sprintf("left join %s on %s.script_id=%s.id and %s.date=?", tabname, tabname, scriptTbName, tabname), time.Now().Format("2006-01-02")).
-			Order(tabname + ".update desc,createtime desc")
+		find = joinFraud(find.Joins(fmt.Sprintf("left join %s on %s.script_id=%s.id and %s.date=?", tabname, tabname, scriptTbName, tabname), time.Now().Format("2006-01-02"))).
+			Order(fmt.Sprintf("if(%s.id is null,%s.update,0) desc,%s.createtime desc", fraudTbName, tabname, scriptTbName))
 	case "today_download":
 		tabname := db.Default().NamingStrategy.TableName("script_date_statistics")
-		find = find.Joins(fmt.Sprintf("left join %s on %s.script_id=%s.id and %s.date=?", tabname, tabname, scriptTbName, tabname), time.Now().Format("2006-01-02")).
-			Order(tabname + ".download desc,createtime desc")
+		find = joinFraud(find.Joins(fmt.Sprintf("left join %s on %s.script_id=%s.id and %s.date=?", tabname, tabname, scriptTbName, tabname), time.Now().Format("2006-01-02"))).
+			Order(fmt.Sprintf("if(%s.id is null,%s.download,0) desc,%s.createtime desc", fraudTbName, tabname, scriptTbName))
 	case "total_download":
 		tabname := db.Default().NamingStrategy.TableName("script_statistics")
-		find = find.Joins(fmt.Sprintf("left join %s on %s.script_id=%s.id", tabname, tabname, scriptTbName)).
-			Order(tabname + ".download desc,createtime desc")
+		find = joinFraud(find.Joins(fmt.Sprintf("left join %s on %s.script_id=%s.id", tabname, tabname, scriptTbName))).
+			Order(fmt.Sprintf("if(%s.id is null,%s.download,%s.download) desc,%s.createtime desc", fraudTbName, tabname, fraudTbName, scriptTbName))
 	case "score":
 		tabname := db.Default().NamingStrategy.TableName("script_statistics")
 		find = find.Joins(fmt.Sprintf("left join %s on %s.script_id=%s.id", tabname, tabname, scriptTbName)).
 			Order(tabname + ".score desc,createtime desc")
+	case "trending":
+		tabname := db.Default().NamingStrategy.TableName("script_statistics")
+		find = joinFraud(find.Joins(fmt.Sprintf("left join %s on %s.script_id=%s.id", tabname, tabname, scriptTbName))).
+			Order(fmt.Sprintf("if(%s.id is null,%s.trending,%s.trending) desc,%s.createtime desc", fraudTbName, tabname, fraudTbName, scriptTbName))
 	case "updatetime":
 		find = find.Where(scriptTbName + ".updatetime>0").Order(scriptTbName + ".updatetime desc," + scriptTbName + ".createtime desc")
 	default:
@@ -214,166 +225,6 @@ func (u *scriptRepo) Search(ctx context.Context, options *SearchOptions, page ht
 	return list, num, nil
 }
 
-// SearchByEs 通过elasticsearch搜索
-func (u *scriptRepo) SearchByEs(ctx context.Context, options *SearchOptions, page httputils.PageRequest) ([]*entity.Script, int64, error) {
-	script := &entity.ScriptSearch{}
-	search := elasticsearch.Ctx(ctx).Search
-	must := []map[string]interface{}{
-		{
-			"multi_match": map[string]interface{}{
-				"query":  options.Keyword,
-				"fields": []string{"name", "description", "content"},
-			},
-		},
-		{
-			"match": map[string]interface{}{
-				"status": consts.ACTIVE,
-			},
-		},
-	}
-	if !options.Self {
-		user := auth_svc.Auth().Get(ctx)
-		if user == nil || !user.AdminLevel.IsAdmin(model.Admin) {
-			must = append(must, map[string]interface{}{
-				"match": map[string]interface{}{
-					"public": entity.PublicScript,
-				},
-			}, map[string]interface{}{
-				"match": map[string]interface{}{
-					"unwell": entity.Well,
-				},
-			})
-		}
-	}
-	if options.UserID != 0 {
-		must = append(must, map[string]interface{}{
-			"match": map[string]interface{}{
-				"user_id": options.UserID,
-			},
-		})
-	}
-	functionSearch := map[string]interface{}{
-		"query": map[string]interface{}{
-			"bool": map[string]interface{}{
-				"must": must,
-			},
-		},
-	}
-	switch options.Sort {
-	case "today_download":
-		functionSearch["script_score"] = map[string]interface{}{
-			"script": map[string]interface{}{
-				"source": "def todayDownload = doc['today_download'].size() > 0 ? doc['today_download'].value : 0; todayDownload = todayDownload < 0 ? 0 : todayDownload; return _score * 500 + todayDownload;",
-			},
-		}
-	case "score":
-		functionSearch["script_score"] = map[string]interface{}{
-			"script": map[string]interface{}{
-				"source": "(doc['score'].value+10)/1.5",
-			},
-		}
-	case "createtime":
-		functionSearch["functions"] = []interface{}{
-			map[string]interface{}{
-				"gauss": map[string]interface{}{
-					"createtime": map[string]interface{}{
-						"origin": time.Now().Unix(),
-						"offset": 1209600,
-						"scale":  38880000,
-						"decay":  0.33,
-					},
-				},
-				"weight": 10,
-			},
-		}
-	case "updatetime":
-		functionSearch["functions"] = []interface{}{
-			map[string]interface{}{
-				"gauss": map[string]interface{}{
-					"updatetime": map[string]interface{}{
-						"origin": time.Now().Unix(),
-						"offset": 1209600,
-						"scale":  38880000,
-						"decay":  0.33,
-					},
-				},
-				"weight": 10,
-			},
-		}
-	case "total_download":
-		functionSearch["script_score"] = map[string]interface{}{
-			"script": map[string]interface{}{
-				"source": "def totalDownload = doc['total_download'].size() > 0 ? doc['total_download'].value : 0; totalDownload = totalDownload < 0 ? 0 : totalDownload; return _score * 500 + totalDownload;",
-			},
-		}
-	default:
-		functionSearch["script_score"] = map[string]interface{}{
-			"script": map[string]interface{}{
-				"source": "def todayDownload = doc['today_download'].size() > 0 ? doc['today_download'].value : 0; todayDownload = todayDownload < 0 ? 0 : todayDownload; return _score * 500 + todayDownload;",
-			},
-		}
-
-	}
-	query := map[string]interface{}{
-		"query": map[string]interface{}{
-			"function_score": functionSearch,
-		},
-		"size": page.GetLimit(),
-		"from": page.GetOffset(),
-	}
-	var body []byte
-	body, err := json.Marshal(query)
-	if err != nil {
-		return nil, 0, err
-	}
-	buf := bytes.NewReader(body)
-	resp, err := elasticsearch.Ctx(ctx).Search(
-		search.WithIndex(script.CollectionName()),
-		search.WithBody(buf),
-		search.WithTrackTotalHits(true),
-		search.WithPretty())
-	if err != nil {
-		return nil, 0, err
-	}
-	respByte, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, 0, err
-	}
-	if resp.IsError() {
-		logger.Ctx(ctx).Error("elasticsearch search error", zap.Error(err), zap.String("body", string(body)))
-		return nil, 0, fmt.Errorf("elasticsearch error: [%s] %s", resp.Status(), respByte)
-	}
-	m := &elasticsearch.SearchResponse[*entity.ScriptSearch]{}
-	if err := json.Unmarshal(respByte, &m); err != nil {
-		return nil, 0, err
-	}
-	ret := make([]*entity.Script, 0)
-	for _, v := range m.Hits.Hits {
-		source := v.Source
-		ret = append(ret, &entity.Script{
-			ID:     source.ID,
-			PostID: 0,
-			//PostID:        source.PostID,
-			UserID:        source.UserID,
-			Name:          source.Name,
-			Description:   source.Description,
-			Content:       source.Content,
-			Type:          source.Type,
-			Public:        source.Public,
-			Unwell:        source.Unwell,
-			SyncUrl:       "",
-			ContentUrl:    "",
-			DefinitionUrl: "",
-			SyncMode:      0,
-			Archive:       source.Archive,
-			Status:        source.Status,
-			Createtime:    source.Createtime,
-			Updatetime:    source.Updatetime,
-		})
-	}
-	return ret, m.Hits.Total.Value, nil
-}
-
 func (u *scriptRepo) FindSyncScript(ctx context.Context, page httputils.PageRequest) ([]*entity.Script, error) {
 	var list []*entity.Script
 	if err := db.Ctx(ctx).Where("sync_mode=? and status=? and sync_url<>''", entity.SyncModeAuto, consts.ACTIVE).Offset(page.GetOffset()).Limit(page.GetLimit()).Find(&list).Error; err != nil {
@@ -392,3 +243,12 @@ func (u *scriptRepo) FindSyncPrefix(ctx context.Context, uid int64, prefix strin
 	}
 	return list, nil
 }
+
+func (u *scriptRepo) FindByUser(ctx context.Context, uid int64) ([]*entity.Script, error) {
+	var list []*entity.Script
+	if err := db.Ctx(ctx).Where("user_id=? and status=?", uid, consts.ACTIVE).
+		Order("id").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
@@ -19,6 +19,8 @@ type ScriptAccessRepo interface {
 	Delete(ctx context.Context, id int64) error
 
 	FindByLinkID(ctx context.Context, scriptId int64, linkId int64, accessType script_entity.AccessType) ([]*script_entity.ScriptAccess, error)
+	// FindAllByLinkID 获取用户或者用户组在所有脚本中的访问权限
+	FindAllByLinkID(ctx context.Context, linkIds []int64, accessType script_entity.AccessType) ([]*script_entity.ScriptAccess, error)
 }
 
 var defaultScriptAccess ScriptAccessRepo
@@ -83,3 +85,15 @@ func (u *scriptAccessRepo) FindByLinkID(ctx context.Context, scriptId int64, lin
 	}
 	return list, nil
 }
+
+func (u *scriptAccessRepo) FindAllByLinkID(ctx context.Context, linkIds []int64, accessType script_entity.AccessType) ([]*script_entity.ScriptAccess, error) {
+	var list []*script_entity.ScriptAccess
+	if len(linkIds) == 0 {
+		return list, nil
+	}
+	if err := db.Ctx(ctx).Where("link_id in ? and type=? and status=?",
+		linkIds, accessType, consts.ACTIVE).Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
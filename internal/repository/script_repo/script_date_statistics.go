@@ -12,6 +12,7 @@ import (
 	"gorm.io/gorm"
 )
 
+//go:generate mockgen -source=script_date_statistics.go -destination=mock/script_date_statistics.go
 type ScriptDateStatisticsRepo interface {
 	Find(ctx context.Context, id int64) (*entity.ScriptDateStatistics, error)
 	Create(ctx context.Context, scriptDateStatistics *entity.ScriptDateStatistics) error
@@ -21,6 +22,8 @@ type ScriptDateStatisticsRepo interface {
 	FindByScriptID(ctx context.Context, scriptId int64, t time.Time) (*entity.ScriptDateStatistics, error)
 	IncrDownload(ctx context.Context, scriptId int64, t time.Time, num int64) error
 	IncrUpdate(ctx context.Context, scriptId int64, t time.Time, num int64) error
+	// FindByScriptIDs 获取多个脚本从start开始的每日统计
+	FindByScriptIDs(ctx context.Context, scriptIds []int64, start time.Time) ([]*entity.ScriptDateStatistics, error)
 }
 
 var defaultScriptDateStatistics ScriptDateStatisticsRepo
@@ -113,3 +116,15 @@ func (u *scriptDateStatisticsRepo) IncrUpdate(ctx context.Context, scriptId int6
 	}
 	return nil
 }
+
+func (u *scriptDateStatisticsRepo) FindByScriptIDs(ctx context.Context, scriptIds []int64, start time.Time) ([]*entity.ScriptDateStatistics, error) {
+	var ret []*entity.ScriptDateStatistics
+	if len(scriptIds) == 0 {
+		return ret, nil
+	}
+	if err := db.Ctx(ctx).Where("script_id in ? and date>=?", scriptIds, start.Format("2006-01-02")).
+		Find(&ret).Error; err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
@@ -11,6 +11,8 @@ import (
 	"github.com/cago-frame/cago/pkg/utils/httputils"
 )
 
+//go:generate mockgen -source=script_favorite.go -destination=mock/script_favorite.go
+
 type ScriptFavoriteRepo interface {
 	Find(ctx context.Context, id int64) (*entity.ScriptFavorite, error)
 	FindPage(ctx context.Context, page httputils.PageRequest) ([]*entity.ScriptFavorite, int64, error)
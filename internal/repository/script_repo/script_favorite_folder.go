@@ -10,6 +10,8 @@ import (
 	"github.com/cago-frame/cago/pkg/utils/httputils"
 )
 
+//go:generate mockgen -source=script_favorite_folder.go -destination=mock/script_favorite_folder.go
+
 type ScriptFavoriteFolderRepo interface {
 	Find(ctx context.Context, id int64) (*entity.ScriptFavoriteFolder, error)
 	FindPage(ctx context.Context, userId int64, private bool, page httputils.PageRequest) ([]*entity.ScriptFavoriteFolder, int64, error)
@@ -0,0 +1,85 @@
+package script_repo
+
+import (
+	"context"
+
+	"github.com/cago-frame/cago/database/db"
+	"github.com/cago-frame/cago/pkg/utils/httputils"
+	entity "github.com/scriptscat/scriptlist/internal/model/entity/script_entity"
+)
+
+//go:generate mockgen -source=./script_fraud_review.go -destination=./mock/script_fraud_review.go
+
+// ScriptFraudReviewRepo 疑似刷量脚本的审核队列
+type ScriptFraudReviewRepo interface {
+	Find(ctx context.Context, id int64) (*entity.ScriptFraudReview, error)
+	Create(ctx context.Context, review *entity.ScriptFraudReview) error
+	Update(ctx context.Context, review *entity.ScriptFraudReview) error
+	// FindPending 获取脚本待审核的记录, 没有时返回nil
+	FindPending(ctx context.Context, scriptId int64) (*entity.ScriptFraudReview, error)
+	// FindPage 按状态分页获取, status为0时返回全部
+	FindPage(ctx context.Context, status entity.FraudReviewStatus, page httputils.PageRequest) ([]*entity.ScriptFraudReview, int64, error)
+}
+
+var defaultScriptFraudReview ScriptFraudReviewRepo
+
+func ScriptFraudReview() ScriptFraudReviewRepo {
+	return defaultScriptFraudReview
+}
+
+func RegisterScriptFraudReview(i ScriptFraudReviewRepo) {
+	defaultScriptFraudReview = i
+}
+
+type scriptFraudReviewRepo struct {
+}
+
+func NewScriptFraudReview() ScriptFraudReviewRepo {
+	return &scriptFraudReviewRepo{}
+}
+
+func (s *scriptFraudReviewRepo) Find(ctx context.Context, id int64) (*entity.ScriptFraudReview, error) {
+	ret := &entity.ScriptFraudReview{}
+	if err := db.Ctx(ctx).First(ret, "id=?", id).Error; err != nil {
+		if db.RecordNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return ret, nil
+}
+
+func (s *scriptFraudReviewRepo) Create(ctx context.Context, review *entity.ScriptFraudReview) error {
+	return db.Ctx(ctx).Create(review).Error
+}
+
+func (s *scriptFraudReviewRepo) Update(ctx context.Context, review *entity.ScriptFraudReview) error {
+	return db.Ctx(ctx).Updates(review).Error
+}
+
+func (s *scriptFraudReviewRepo) FindPending(ctx context.Context, scriptId int64) (*entity.ScriptFraudReview, error) {
+	ret := &entity.ScriptFraudReview{}
+	if err := db.Ctx(ctx).First(ret, "script_id=? and status=?", scriptId, entity.FraudReviewPending).Error; err != nil {
+		if db.RecordNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return ret, nil
+}
+
+func (s *scriptFraudReviewRepo) FindPage(ctx context.Context, status entity.FraudReviewStatus, page httputils.PageRequest) ([]*entity.ScriptFraudReview, int64, error) {
+	var list []*entity.ScriptFraudReview
+	var count int64
+	find := db.Ctx(ctx).Model(&entity.ScriptFraudReview{})
+	if status != 0 {
+		find = find.Where("status=?", status)
+	}
+	if err := find.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := find.Order("createtime desc").Offset(page.GetOffset()).Limit(page.GetLimit()).Find(&list).Error; err != nil {
+		return nil, 0, err
+	}
+	return list, count, nil
+}
@@ -19,6 +19,8 @@ type ScriptGroupMemberRepo interface {
 	Delete(ctx context.Context, id int64) error
 
 	FindByUserId(ctx context.Context, scriptId, userId int64) ([]*script_entity.ScriptGroupMember, error)
+	// FindAllByUserId 获取用户在所有脚本中加入的组
+	FindAllByUserId(ctx context.Context, userId int64) ([]*script_entity.ScriptGroupMember, error)
 }
 
 var defaultScriptGroupMember ScriptGroupMemberRepo
@@ -83,3 +85,12 @@ func (u *scriptGroupMemberRepo) FindByUserId(ctx context.Context, scriptId, user
 	}
 	return list, nil
 }
+
+func (u *scriptGroupMemberRepo) FindAllByUserId(ctx context.Context, userId int64) ([]*script_entity.ScriptGroupMember, error) {
+	var list []*script_entity.ScriptGroupMember
+	if err := db.Ctx(ctx).Model(&script_entity.ScriptGroupMember{}).
+		Where("user_id=? and status=?", userId, consts.ACTIVE).Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
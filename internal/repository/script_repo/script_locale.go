@@ -0,0 +1,95 @@
+package script_repo
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/cago-frame/cago/database/cache"
+	cache2 "github.com/cago-frame/cago/database/cache/cache"
+	"github.com/cago-frame/cago/database/db"
+	entity "github.com/scriptscat/scriptlist/internal/model/entity/script_entity"
+)
+
+// ScriptLocaleRepo 脚本多语言元数据
+type ScriptLocaleRepo interface {
+	// FindByScriptID 获取脚本所有语言的名称与描述
+	FindByScriptID(ctx context.Context, scriptId int64) ([]*entity.ScriptLocale, error)
+	// Save 保存脚本的多语言数据, 不在列表中的语言会被删除
+	Save(ctx context.Context, scriptId int64, locales []*entity.ScriptLocale) error
+}
+
+var defaultScriptLocale ScriptLocaleRepo
+
+func ScriptLocale() ScriptLocaleRepo {
+	return defaultScriptLocale
+}
+
+func RegisterScriptLocale(i ScriptLocaleRepo) {
+	defaultScriptLocale = i
+}
+
+type scriptLocaleRepo struct {
+}
+
+func NewScriptLocaleRepo() ScriptLocaleRepo {
+	return &scriptLocaleRepo{}
+}
+
+func (s *scriptLocaleRepo) key(scriptId int64) string {
+	return "script:locale:" + strconv.FormatInt(scriptId, 10)
+}
+
+func (s *scriptLocaleRepo) FindByScriptID(ctx context.Context, scriptId int64) ([]*entity.ScriptLocale, error) {
+	var ret []*entity.ScriptLocale
+	if err := cache.Ctx(ctx).GetOrSet(s.key(scriptId), func() (interface{}, error) {
+		var list []*entity.ScriptLocale
+		if err := db.Ctx(ctx).Where("script_id=?", scriptId).
+			Order("language").Find(&list).Error; err != nil {
+			return nil, err
+		}
+		return list, nil
+	}, cache2.Expiration(time.Hour)).Scan(&ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func (s *scriptLocaleRepo) Save(ctx context.Context, scriptId int64, locales []*entity.ScriptLocale) error {
+	var old []*entity.ScriptLocale
+	if err := db.Ctx(ctx).Where("script_id=?", scriptId).Find(&old).Error; err != nil {
+		return err
+	}
+	oldMap := make(map[string]*entity.ScriptLocale, len(old))
+	for _, v := range old {
+		oldMap[v.Language] = v
+	}
+	now := time.Now().Unix()
+	for _, v := range locales {
+		v.ScriptID = scriptId
+		v.Updatetime = now
+		if o, ok := oldMap[v.Language]; ok {
+			delete(oldMap, v.Language)
+			v.ID = o.ID
+			v.Createtime = o.Createtime
+			if o.Name == v.Name && o.Description == v.Description {
+				continue
+			}
+			if err := db.Ctx(ctx).Select("name", "description", "updatetime").
+				Updates(v).Error; err != nil {
+				return err
+			}
+			continue
+		}
+		v.Createtime = now
+		if err := db.Ctx(ctx).Create(v).Error; err != nil {
+			return err
+		}
+	}
+	for _, v := range oldMap {
+		if err := db.Ctx(ctx).Delete(&entity.ScriptLocale{ID: v.ID}).Error; err != nil {
+			return err
+		}
+	}
+	return cache.Ctx(ctx).Del(s.key(scriptId))
+}
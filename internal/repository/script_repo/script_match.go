@@ -0,0 +1,109 @@
+package script_repo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cago-frame/cago/database/db"
+	"github.com/cago-frame/cago/pkg/consts"
+	"github.com/scriptscat/scriptlist/internal/model/entity/script_entity"
+)
+
+// ScriptMatchRepo 脚本完整的匹配规则
+type ScriptMatchRepo interface {
+	Create(ctx context.Context, scriptMatch *script_entity.ScriptMatch) error
+	Update(ctx context.Context, scriptMatch *script_entity.ScriptMatch) error
+	Delete(ctx context.Context, id int64) error
+
+	// List 获取脚本的所有规则, 包括已删除的
+	List(ctx context.Context, scriptId int64) ([]*script_entity.ScriptMatch, error)
+	// FindCandidates 根据主机查找拥有包含规则且公开可用的用户脚本id, 按id升序返回lastId之后的最多limit个, 用于分批匹配
+	FindCandidates(ctx context.Context, hosts []string, lastId int64, limit int) ([]int64, error)
+	// FindByDownload 获取脚本并按总下载量排序分页
+	FindByDownload(ctx context.Context, scriptIds []int64, offset, limit int) ([]*script_entity.Script, error)
+	// FindByScriptIDs 获取多个脚本的有效规则
+	FindByScriptIDs(ctx context.Context, scriptIds []int64) ([]*script_entity.ScriptMatch, error)
+}
+
+var defaultScriptMatch ScriptMatchRepo
+
+func ScriptMatch() ScriptMatchRepo {
+	return defaultScriptMatch
+}
+
+func RegisterScriptMatch(i ScriptMatchRepo) {
+	defaultScriptMatch = i
+}
+
+type scriptMatchRepo struct {
+}
+
+func NewScriptMatchRepo() ScriptMatchRepo {
+	return &scriptMatchRepo{}
+}
+
+func (u *scriptMatchRepo) Create(ctx context.Context, scriptMatch *script_entity.ScriptMatch) error {
+	return db.Ctx(ctx).Create(scriptMatch).Error
+}
+
+func (u *scriptMatchRepo) Update(ctx context.Context, scriptMatch *script_entity.ScriptMatch) error {
+	return db.Ctx(ctx).Updates(scriptMatch).Error
+}
+
+func (u *scriptMatchRepo) Delete(ctx context.Context, id int64) error {
+	return db.Ctx(ctx).Model(&script_entity.ScriptMatch{}).
+		Where("id=?", id).Update("status", consts.DELETE).Error
+}
+
+func (u *scriptMatchRepo) List(ctx context.Context, scriptId int64) ([]*script_entity.ScriptMatch, error) {
+	var ret []*script_entity.ScriptMatch
+	if err := db.Ctx(ctx).Find(&ret, "script_id=?", scriptId).Error; err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func (u *scriptMatchRepo) FindCandidates(ctx context.Context, hosts []string, lastId int64, limit int) ([]int64, error) {
+	ret := make([]int64, 0)
+	scriptIds := db.Ctx(ctx).Model(&script_entity.Script{}).Select("id").
+		Where("status=? and type=? and public=? and unwell=?",
+			consts.ACTIVE, script_entity.UserscriptType, script_entity.PublicScript, script_entity.Well)
+	if err := db.Ctx(ctx).Model(&script_entity.ScriptMatch{}).Distinct("script_id").
+		Where("host in ? and type in ? and status=?", hosts, []script_entity.ScriptMatchType{
+			script_entity.ScriptMatchTypeMatch, script_entity.ScriptMatchTypeInclude,
+		}, consts.ACTIVE).
+		Where("script_id>? and script_id in (?)", lastId, scriptIds).
+		Order("script_id").Limit(limit).Pluck("script_id", &ret).Error; err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func (u *scriptMatchRepo) FindByDownload(ctx context.Context, scriptIds []int64, offset, limit int) ([]*script_entity.Script, error) {
+	list := make([]*script_entity.Script, 0)
+	if len(scriptIds) == 0 {
+		return list, nil
+	}
+	scriptTbName := (&script_entity.Script{}).TableName()
+	statisticsTbName := db.Default().NamingStrategy.TableName("script_statistics")
+	if err := db.Ctx(ctx).Model(&script_entity.Script{}).Select(scriptTbName+".*").
+		Joins(fmt.Sprintf("left join %s on %s.script_id=%s.id", statisticsTbName, statisticsTbName, scriptTbName)).
+		Where(scriptTbName+".id in ?", scriptIds).
+		Order(fmt.Sprintf("%s.download desc,%s.id desc", statisticsTbName, scriptTbName)).
+		Offset(offset).Limit(limit).Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (u *scriptMatchRepo) FindByScriptIDs(ctx context.Context, scriptIds []int64) ([]*script_entity.ScriptMatch, error) {
+	var ret []*script_entity.ScriptMatch
+	if len(scriptIds) == 0 {
+		return ret, nil
+	}
+	if err := db.Ctx(ctx).Where("script_id in ? and status=?", scriptIds, consts.ACTIVE).
+		Find(&ret).Error; err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
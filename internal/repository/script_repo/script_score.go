@@ -18,6 +18,13 @@ type ScoreGroup struct {
 	Count int64 `json:"count"`
 }
 
+// ScoreStat 脚本一段时间内的评分
+type ScoreStat struct {
+	ScriptID int64
+	Score    int64 // 评分总和
+	Count    int64
+}
+
 //go:generate mockgen -source script_score.go -destination mock/script_score.go
 type ScriptScoreRepo interface {
 	Find(ctx context.Context, id int64) (*script_entity.ScriptScore, error)
@@ -35,6 +42,8 @@ type ScriptScoreRepo interface {
 	LastScore(ctx context.Context, page httputils.PageRequest) ([]int64, error)
 	// GroupByScore 根据分数分组
 	GroupByScore(ctx context.Context, scriptId int64) ([]*ScoreGroup, error)
+	// StatByScripts 统计多个脚本在[start,end)时间内新增的评分
+	StatByScripts(ctx context.Context, scriptIds []int64, start, end int64) ([]*ScoreStat, error)
 }
 
 var defaultScriptScore ScriptScoreRepo
@@ -177,3 +186,17 @@ func (u *scriptScoreRepo) GroupByScore(ctx context.Context, scriptId int64) ([]*
 	}
 	return list, nil
 }
+
+func (u *scriptScoreRepo) StatByScripts(ctx context.Context, scriptIds []int64, start, end int64) ([]*ScoreStat, error) {
+	var list []*ScoreStat
+	if len(scriptIds) == 0 {
+		return list, nil
+	}
+	if err := db.Ctx(ctx).Model(&script_entity.ScriptScore{}).
+		Select("script_id, sum(score) as score, count(*) as count").
+		Where("script_id in ? and state=? and createtime>=? and createtime<?", scriptIds, consts.ACTIVE, start, end).
+		Group("script_id").Scan(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/cago-frame/cago/database/db"
 	"github.com/scriptscat/scriptlist/internal/model/entity"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 //go:generate mockgen -source=script_statistics.go -destination=mock/script_statistics.go
@@ -25,6 +26,8 @@ type ScriptStatisticsRepo interface {
 	IncrUpdate(ctx context.Context, scriptId int64, num int64) error
 	// IncrScore 分数统计,当用户分数变更时可以使用之前的分数和之后的分数进行计算,num为0
 	IncrScore(ctx context.Context, scriptId, score int64, num int) error
+	// UpdateTrending 更新热度
+	UpdateTrending(ctx context.Context, scriptId int64, trending float64) error
 }
 
 var defaultScriptStatistics ScriptStatisticsRepo
@@ -123,3 +126,17 @@ func (u *scriptStatisticsRepo) IncrScore(ctx context.Context, scriptId, score in
 	}
 	return nil
 }
+
+func (u *scriptStatisticsRepo) UpdateTrending(ctx context.Context, scriptId int64, trending float64) error {
+	// 值未变化时mysql的RowsAffected也为0, 所以使用upsert
+	if err := db.Ctx(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "script_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"trending"}),
+	}).Create(&entity.ScriptStatistics{
+		ScriptID: scriptId,
+		Trending: trending,
+	}).Error; err != nil {
+		return err
+	}
+	return cache.Ctx(ctx).Del(u.key(scriptId))
+}
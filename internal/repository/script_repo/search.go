@@ -0,0 +1,93 @@
+package script_repo
+
+import (
+	"context"
+
+	"github.com/cago-frame/cago/pkg/utils/httputils"
+	entity "github.com/scriptscat/scriptlist/internal/model/entity/script_entity"
+)
+
+// SearchResult 关键字搜索的结果
+type SearchResult struct {
+	List  []*entity.Script
+	Total int64
+	// Highlight 高亮片段, key为脚本id
+	Highlight map[int64]map[string][]string
+	// Facets 分面统计, key为category, tag, type, domain
+	Facets map[string][]*SearchFacet
+}
+
+// SearchFacet 分面统计项
+type SearchFacet struct {
+	Key   string
+	Count int64
+}
+
+// 搜索结果的分面字段
+var searchFacetFields = []string{"category", "tag", "type", "domain"}
+
+const (
+	// EsSearchEngine elasticsearch搜索后端, 需要ik分词插件
+	EsSearchEngine = "elasticsearch"
+	// MysqlSearchEngine 内嵌的mysql全文索引搜索后端, 使用ngram分词, 无需额外部署
+	MysqlSearchEngine = "mysql"
+)
+
+// ScriptSearchEngine 脚本搜索后端, 与mysql的列表支持相同的筛选与排序
+type ScriptSearchEngine interface {
+	// Search 关键字搜索, 额外返回高亮与分面统计
+	Search(ctx context.Context, options *SearchOptions, page httputils.PageRequest) (*SearchResult, error)
+	// Save 保存脚本索引, 已存在时更新
+	Save(ctx context.Context, s *entity.ScriptSearch) error
+	// Update 更新脚本索引
+	Update(ctx context.Context, s *entity.ScriptSearch) error
+	// Delete 删除脚本索引,但是是软删除
+	Delete(ctx context.Context, id int64) error
+	// Suggest 根据名称前缀建议公开的脚本, 按总下载量排序
+	Suggest(ctx context.Context, prefix string, size int) ([]*entity.ScriptSearch, error)
+	// Similar 根据名称,描述,标签与域名查找内容相似的公开脚本, 返回脚本id
+	Similar(ctx context.Context, s *entity.ScriptSearch, size int) ([]int64, error)
+	// FindByIDRange 列出id在[startId,endId]范围内的索引数据, 只包含id,status,public,unwell,updatetime, 用于与mysql对账
+	FindByIDRange(ctx context.Context, startId, endId int64) ([]*entity.ScriptSearch, error)
+	// MaxID 索引中最大的脚本id, 没有数据时返回0, 用于对账时检查mysql最大id之后多余的索引
+	MaxID(ctx context.Context) (int64, error)
+}
+
+// ScriptSearchReindexer 支持构建新索引并原子切换的搜索后端, 用于重建索引的alias模式
+type ScriptSearchReindexer interface {
+	// NewIndex 创建一个新的索引, 返回索引名
+	NewIndex(ctx context.Context) (string, error)
+	// SaveToIndex 保存脚本数据到指定的索引
+	SaveToIndex(ctx context.Context, index string, s *entity.ScriptSearch) error
+	// CopyToIndex 重建索引时复制脚本数据到指定的索引, 只在数据不存在时写入,
+	// 已存在的是重建期间双写的数据, 比复制时的快照新, 不能被覆盖
+	CopyToIndex(ctx context.Context, index string, s *entity.ScriptSearch) error
+	// DeleteFromIndex 软删除指定索引中的脚本数据, 数据不存在时忽略
+	DeleteFromIndex(ctx context.Context, index string, id int64) error
+	// SwitchIndex 将当前使用的索引原子地切换为指定的索引, 并删除原来的索引
+	SwitchIndex(ctx context.Context, index string) error
+	// DeleteIndex 删除指定的索引, 用于清理被放弃的重建任务
+	DeleteIndex(ctx context.Context, index string) error
+	// MappingVersion 当前使用的索引的映射版本, 早于 ScriptSearchMappingVersion 时需要重建索引
+	MappingVersion(ctx context.Context) (int, error)
+}
+
+var defaultSearchEngine ScriptSearchEngine
+
+func SearchEngine() ScriptSearchEngine {
+	return defaultSearchEngine
+}
+
+func RegisterSearchEngine(i ScriptSearchEngine) {
+	defaultSearchEngine = i
+}
+
+// NewSearchEngine 根据配置创建搜索后端, 默认为elasticsearch
+func NewSearchEngine(engine string) ScriptSearchEngine {
+	switch engine {
+	case MysqlSearchEngine:
+		return NewMysqlSearchEngine()
+	default:
+		return NewEsSearchEngine()
+	}
+}
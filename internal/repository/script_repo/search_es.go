@@ -0,0 +1,764 @@
+package script_repo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cago-frame/cago/database/cache"
+	cache2 "github.com/cago-frame/cago/database/cache/cache"
+	"github.com/cago-frame/cago/database/elasticsearch"
+	"github.com/cago-frame/cago/pkg/consts"
+	"github.com/cago-frame/cago/pkg/logger"
+	"github.com/cago-frame/cago/pkg/utils/httputils"
+	"github.com/scriptscat/scriptlist/internal/model"
+	entity "github.com/scriptscat/scriptlist/internal/model/entity/script_entity"
+	"github.com/scriptscat/scriptlist/internal/service/auth_svc"
+	"go.uber.org/zap"
+)
+
+// esSearchEngine elasticsearch搜索后端
+type esSearchEngine struct {
+}
+
+func NewEsSearchEngine() ScriptSearchEngine {
+	return &esSearchEngine{}
+}
+
+type esSearchResponse struct {
+	Hits struct {
+		Total struct {
+			Value int64
+		}
+		Hits []*struct {
+			Source    *entity.ScriptSearch `json:"_source"`
+			Highlight map[string][]string  `json:"highlight"`
+		}
+	}
+	Aggregations map[string]struct {
+		Buckets []struct {
+			Key      json.RawMessage `json:"key"`
+			DocCount int64           `json:"doc_count"`
+		}
+	}
+}
+
+func (e *esSearchEngine) Search(ctx context.Context, options *SearchOptions, page httputils.PageRequest) (*SearchResult, error) {
+	script := &entity.ScriptSearch{}
+	search := elasticsearch.Ctx(ctx).Search
+	must := []map[string]interface{}{
+		{
+			"multi_match": map[string]interface{}{
+				"query":  options.Keyword,
+				"fields": []string{"name", "description", "content", "locale_name", "locale_description"},
+			},
+		},
+	}
+	filter := []map[string]interface{}{
+		{
+			"term": map[string]interface{}{
+				"status": consts.ACTIVE,
+			},
+		},
+	}
+	if !options.Self {
+		user := auth_svc.Auth().Get(ctx)
+		if user == nil || !user.AdminLevel.IsAdmin(model.Moderator) {
+			filter = append(filter, map[string]interface{}{
+				"term": map[string]interface{}{
+					"public": entity.PublicScript,
+				},
+			}, map[string]interface{}{
+				"term": map[string]interface{}{
+					"unwell": entity.Well,
+				},
+			})
+		}
+	}
+	if options.UserID != 0 {
+		filter = append(filter, map[string]interface{}{
+			"term": map[string]interface{}{
+				"user_id": options.UserID,
+			},
+		})
+	}
+	category := options.Category
+	switch options.Type {
+	case 1: // 用户脚本
+		filter = append(filter, map[string]interface{}{
+			"term": map[string]interface{}{"type": entity.UserscriptType},
+		})
+	case 2: // 库
+		filter = append(filter, map[string]interface{}{
+			"term": map[string]interface{}{"type": entity.LibraryType},
+		})
+	case 3: // 后台脚本
+		category = append(category, 1)
+	case 4: // 定时脚本
+		category = append(category, 2)
+	}
+	if len(category) != 0 {
+		// 与mysql中一致, 分类与标签都储存在script_category中
+		filter = append(filter, map[string]interface{}{
+			"bool": map[string]interface{}{
+				"should": []map[string]interface{}{
+					{"terms": map[string]interface{}{"category": category}},
+					{"terms": map[string]interface{}{"tag": category}},
+				},
+				"minimum_should_match": 1,
+			},
+		})
+	}
+	mapping, err := e.mapping(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// 旧的索引中domain为动态映射的text类型, 重建索引完成前使用keyword子字段, 避免terms查询与聚合失败
+	domainField := "domain"
+	if mapping.DomainType == "text" {
+		domainField = "domain.keyword"
+	}
+	if options.Domain != "" {
+		// 保存域名时会同时保存顶级域名, 所以匹配各级父域名即可支持子域名
+		domains := strings.Split(options.Domain, ".")
+		terms := []string{options.Domain}
+		for i := 1; i < len(domains)-1; i++ {
+			terms = append(terms, strings.Join(domains[i:], "."))
+		}
+		filter = append(filter, map[string]interface{}{
+			"terms": map[string]interface{}{domainField: terms},
+		})
+	}
+	var sort []map[string]interface{}
+	switch options.Sort {
+	case "today_update", "today_download", "total_download", "score", "trending", "createtime":
+		sort = append(sort, map[string]interface{}{options.Sort: "desc"})
+	case "updatetime":
+		filter = append(filter, map[string]interface{}{
+			"range": map[string]interface{}{"updatetime": map[string]interface{}{"gt": 0}},
+		})
+		sort = append(sort, map[string]interface{}{"updatetime": "desc"})
+	}
+	sort = append(sort, map[string]interface{}{"_score": "desc"}, map[string]interface{}{"createtime": "desc"})
+	aggs := make(map[string]interface{})
+	for _, field := range searchFacetFields {
+		name := field
+		if field == "domain" {
+			name = domainField
+		}
+		aggs[field] = map[string]interface{}{
+			"terms": map[string]interface{}{"field": name, "size": 20},
+		}
+	}
+	query := map[string]interface{}{
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"must":   must,
+				"filter": filter,
+			},
+		},
+		"sort":         sort,
+		"track_scores": true,
+		"aggs":         aggs,
+		"highlight": map[string]interface{}{
+			"pre_tags":  []string{"<em>"},
+			"post_tags": []string{"</em>"},
+			"fields": map[string]interface{}{
+				"name":               map[string]interface{}{"number_of_fragments": 0},
+				"locale_name":        map[string]interface{}{"number_of_fragments": 0},
+				"description":        map[string]interface{}{"fragment_size": 150, "number_of_fragments": 1},
+				"locale_description": map[string]interface{}{"fragment_size": 150, "number_of_fragments": 1},
+				"content":            map[string]interface{}{"fragment_size": 150, "number_of_fragments": 1},
+			},
+		},
+		"size": page.GetLimit(),
+		"from": page.GetOffset(),
+	}
+	body, err := json.Marshal(query)
+	if err != nil {
+		return nil, err
+	}
+	buf := bytes.NewReader(body)
+	resp, err := elasticsearch.Ctx(ctx).Search(
+		search.WithIndex(script.CollectionName()),
+		search.WithBody(buf),
+		search.WithTrackTotalHits(true),
+		search.WithPretty())
+	if err != nil {
+		return nil, err
+	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+	respByte, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.IsError() {
+		logger.Ctx(ctx).Error("elasticsearch search error", zap.Error(err), zap.String("body", string(body)))
+		return nil, fmt.Errorf("elasticsearch error: [%s] %s", resp.Status(), respByte)
+	}
+	m := &esSearchResponse{}
+	if err := json.Unmarshal(respByte, &m); err != nil {
+		return nil, err
+	}
+	ret := &SearchResult{
+		List:      make([]*entity.Script, 0, len(m.Hits.Hits)),
+		Total:     m.Hits.Total.Value,
+		Highlight: make(map[int64]map[string][]string),
+		Facets:    make(map[string][]*SearchFacet),
+	}
+	for _, v := range m.Hits.Hits {
+		source := v.Source
+		ret.List = append(ret.List, &entity.Script{
+			ID:     source.ID,
+			PostID: 0,
+			//PostID:        source.PostID,
+			UserID:        source.UserID,
+			Name:          source.Name,
+			Description:   source.Description,
+			Content:       source.Content,
+			Type:          source.Type,
+			Public:        source.Public,
+			Unwell:        source.Unwell,
+			SyncUrl:       "",
+			ContentUrl:    "",
+			DefinitionUrl: "",
+			SyncMode:      0,
+			Archive:       source.Archive,
+			Status:        source.Status,
+			Createtime:    source.Createtime,
+			Updatetime:    source.Updatetime,
+		})
+		if len(v.Highlight) > 0 {
+			ret.Highlight[source.ID] = v.Highlight
+		}
+	}
+	for _, field := range searchFacetFields {
+		buckets := m.Aggregations[field].Buckets
+		facets := make([]*SearchFacet, 0, len(buckets))
+		for _, b := range buckets {
+			facets = append(facets, &SearchFacet{
+				Key:   strings.Trim(string(b.Key), `"`),
+				Count: b.DocCount,
+			})
+		}
+		ret.Facets[field] = facets
+	}
+	return ret, nil
+}
+
+func (e *esSearchEngine) Save(ctx context.Context, s *entity.ScriptSearch) error {
+	return e.SaveToIndex(ctx, s.CollectionName(), s)
+}
+
+func (e *esSearchEngine) SaveToIndex(ctx context.Context, index string, s *entity.ScriptSearch) error {
+	logger := logger.Ctx(ctx).With(zap.Int64("id", s.ID), zap.String("index", index))
+	r, err := s.Reader()
+	if err != nil {
+		return err
+	}
+	resp, err := elasticsearch.Ctx(ctx).Create(
+		index, strconv.FormatInt(s.ID, 10), r,
+	)
+	if err != nil {
+		logger.Error("insert error", zap.Error(err))
+		return err
+	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+	if resp.StatusCode != http.StatusCreated {
+		if resp.StatusCode == http.StatusConflict {
+			// 更新
+			return e.update(ctx, index, s)
+		}
+		b, _ := io.ReadAll(resp.Body)
+		logger.Error("insert error", zap.ByteString("body", b), zap.Int("status", resp.StatusCode))
+		return fmt.Errorf("insert error: %d body: %s", resp.StatusCode, b)
+	}
+	logger.Info("insert success")
+	return nil
+}
+
+func (e *esSearchEngine) CopyToIndex(ctx context.Context, index string, s *entity.ScriptSearch) error {
+	logger := logger.Ctx(ctx).With(zap.Int64("id", s.ID), zap.String("index", index))
+	r, err := s.Reader()
+	if err != nil {
+		return err
+	}
+	resp, err := elasticsearch.Ctx(ctx).Create(
+		index, strconv.FormatInt(s.ID, 10), r,
+	)
+	if err != nil {
+		logger.Error("copy error", zap.Error(err))
+		return err
+	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+	if resp.StatusCode == http.StatusConflict {
+		// 已经双写了更新的数据, 跳过
+		return nil
+	}
+	if resp.StatusCode != http.StatusCreated {
+		b, _ := io.ReadAll(resp.Body)
+		logger.Error("copy error", zap.ByteString("body", b), zap.Int("status", resp.StatusCode))
+		return fmt.Errorf("copy error: %d body: %s", resp.StatusCode, b)
+	}
+	return nil
+}
+
+func (e *esSearchEngine) Update(ctx context.Context, s *entity.ScriptSearch) error {
+	return e.update(ctx, s.CollectionName(), s)
+}
+
+func (e *esSearchEngine) update(ctx context.Context, index string, s *entity.ScriptSearch) error {
+	logger := logger.Ctx(ctx).With(zap.Int64("id", s.ID), zap.String("index", index))
+	r, err := s.Reader()
+	if err != nil {
+		return err
+	}
+	buf := bytes.NewBuffer([]byte("{\"doc\":"))
+	_, _ = io.Copy(buf, r)
+	buf.WriteString("}")
+	resp, err := elasticsearch.Ctx(ctx).Update(
+		index, strconv.FormatInt(s.ID, 10), buf,
+	)
+	if err != nil {
+		logger.Error("update error", zap.Error(err))
+		return err
+	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(resp.Body)
+		logger.Error("update error", zap.ByteString("body", b), zap.Int("status", resp.StatusCode))
+		return fmt.Errorf("update error: %d body: %s", resp.StatusCode, b)
+	}
+	return nil
+}
+
+func (e *esSearchEngine) Delete(ctx context.Context, id int64) error {
+	return e.deleteFromIndex(ctx, (&entity.ScriptSearch{}).CollectionName(), id, false)
+}
+
+func (e *esSearchEngine) DeleteFromIndex(ctx context.Context, index string, id int64) error {
+	return e.deleteFromIndex(ctx, index, id, true)
+}
+
+func (e *esSearchEngine) deleteFromIndex(ctx context.Context, index string, id int64, ignoreNotFound bool) error {
+	logger := logger.Ctx(ctx).With(zap.Int64("id", id), zap.String("index", index))
+	buf := bytes.NewBuffer([]byte(fmt.Sprintf("{\"doc\":{\"status\":%d}}", consts.DELETE)))
+	resp, err := elasticsearch.Ctx(ctx).Update(
+		index, strconv.FormatInt(id, 10), buf,
+	)
+	if err != nil {
+		logger.Error("delete error", zap.Error(err))
+		return err
+	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+	if resp.StatusCode == http.StatusNotFound && ignoreNotFound {
+		// 新索引中还没有迁移到该数据, 迁移时会从mysql中读取到删除状态
+		return nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(resp.Body)
+		logger.Error("delete error", zap.ByteString("body", b), zap.Int("status", resp.StatusCode))
+		return fmt.Errorf("delete error: %d body: %s", resp.StatusCode, b)
+	}
+	logger.Info("delete success")
+	return nil
+}
+
+func (e *esSearchEngine) Suggest(ctx context.Context, prefix string, size int) ([]*entity.ScriptSearch, error) {
+	search := elasticsearch.Ctx(ctx).Search
+	body, err := json.Marshal(map[string]interface{}{
+		"_source": []string{"id", "name", "total_download"},
+		"suggest": map[string]interface{}{
+			"script": map[string]interface{}{
+				"prefix": prefix,
+				"completion": map[string]interface{}{
+					"field":           "suggest",
+					"size":            size,
+					"skip_duplicates": true,
+					"contexts": map[string]interface{}{
+						"visible": []string{"true"},
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp, err := search(
+		search.WithIndex((&entity.ScriptSearch{}).CollectionName()),
+		search.WithBody(bytes.NewReader(body)),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+	respByte, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.IsError() {
+		return nil, fmt.Errorf("elasticsearch error: [%s] %s", resp.Status(), respByte)
+	}
+	m := &struct {
+		Suggest map[string][]struct {
+			Options []struct {
+				Source *entity.ScriptSearch `json:"_source"`
+			} `json:"options"`
+		} `json:"suggest"`
+	}{}
+	if err := json.Unmarshal(respByte, &m); err != nil {
+		return nil, err
+	}
+	ret := make([]*entity.ScriptSearch, 0, size)
+	for _, v := range m.Suggest["script"] {
+		for _, option := range v.Options {
+			ret = append(ret, option.Source)
+		}
+	}
+	return ret, nil
+}
+
+func (e *esSearchEngine) Similar(ctx context.Context, s *entity.ScriptSearch, size int) ([]int64, error) {
+	search := elasticsearch.Ctx(ctx).Search
+	should := []map[string]interface{}{
+		{
+			"more_like_this": map[string]interface{}{
+				"fields": []string{"name", "description", "locale_name", "locale_description"},
+				"like": []map[string]interface{}{
+					{"_index": s.CollectionName(), "_id": strconv.FormatInt(s.ID, 10)},
+				},
+				"min_term_freq": 1,
+				"min_doc_freq":  2,
+			},
+		},
+	}
+	if len(s.Tag) > 0 {
+		should = append(should, map[string]interface{}{
+			"terms": map[string]interface{}{"tag": s.Tag, "boost": 2},
+		})
+	}
+	if len(s.Domain) > 0 {
+		should = append(should, map[string]interface{}{
+			"terms": map[string]interface{}{"domain": s.Domain, "boost": 2},
+		})
+	}
+	body, err := json.Marshal(map[string]interface{}{
+		"_source": []string{"id"},
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"should":               should,
+				"minimum_should_match": 1,
+				"filter": []map[string]interface{}{
+					{"term": map[string]interface{}{"status": consts.ACTIVE}},
+					{"term": map[string]interface{}{"public": entity.PublicScript}},
+					{"term": map[string]interface{}{"unwell": entity.Well}},
+					{"term": map[string]interface{}{"archive": entity.IsActive}},
+				},
+				"must_not": []map[string]interface{}{
+					{"ids": map[string]interface{}{"values": []string{strconv.FormatInt(s.ID, 10)}}},
+				},
+			},
+		},
+		"size": size,
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp, err := search(
+		search.WithIndex(s.CollectionName()),
+		search.WithBody(bytes.NewReader(body)),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+	respByte, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.IsError() {
+		return nil, fmt.Errorf("elasticsearch error: [%s] %s", resp.Status(), respByte)
+	}
+	m := &esSearchResponse{}
+	if err := json.Unmarshal(respByte, &m); err != nil {
+		return nil, err
+	}
+	ret := make([]int64, 0, len(m.Hits.Hits))
+	for _, v := range m.Hits.Hits {
+		ret = append(ret, v.Source.ID)
+	}
+	return ret, nil
+}
+
+func (e *esSearchEngine) FindByIDRange(ctx context.Context, startId, endId int64) ([]*entity.ScriptSearch, error) {
+	search := elasticsearch.Ctx(ctx).Search
+	body, err := json.Marshal(map[string]interface{}{
+		"query": map[string]interface{}{
+			"range": map[string]interface{}{
+				"id": map[string]interface{}{"gte": startId, "lte": endId},
+			},
+		},
+		"_source": []string{"id", "status", "public", "unwell", "updatetime"},
+		"sort":    []map[string]interface{}{{"id": "asc"}},
+		"size":    10000,
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp, err := search(
+		search.WithIndex((&entity.ScriptSearch{}).CollectionName()),
+		search.WithBody(bytes.NewReader(body)),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+	respByte, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.IsError() {
+		return nil, fmt.Errorf("elasticsearch error: [%s] %s", resp.Status(), respByte)
+	}
+	m := &esSearchResponse{}
+	if err := json.Unmarshal(respByte, &m); err != nil {
+		return nil, err
+	}
+	ret := make([]*entity.ScriptSearch, 0, len(m.Hits.Hits))
+	for _, v := range m.Hits.Hits {
+		ret = append(ret, v.Source)
+	}
+	return ret, nil
+}
+
+func (e *esSearchEngine) MaxID(ctx context.Context) (int64, error) {
+	search := elasticsearch.Ctx(ctx).Search
+	body, err := json.Marshal(map[string]interface{}{
+		"_source": []string{"id"},
+		"sort":    []map[string]interface{}{{"id": "desc"}},
+		"size":    1,
+	})
+	if err != nil {
+		return 0, err
+	}
+	resp, err := search(
+		search.WithIndex((&entity.ScriptSearch{}).CollectionName()),
+		search.WithBody(bytes.NewReader(body)),
+	)
+	if err != nil {
+		return 0, err
+	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+	respByte, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, err
+	}
+	if resp.IsError() {
+		return 0, fmt.Errorf("elasticsearch error: [%s] %s", resp.Status(), respByte)
+	}
+	m := &esSearchResponse{}
+	if err := json.Unmarshal(respByte, &m); err != nil {
+		return 0, err
+	}
+	if len(m.Hits.Hits) == 0 {
+		return 0, nil
+	}
+	return m.Hits.Hits[0].Source.ID, nil
+}
+
+func (e *esSearchEngine) NewIndex(ctx context.Context) (string, error) {
+	// 索引名需要匹配索引模板的 index_patterns
+	index := (&entity.ScriptSearch{}).CollectionName() + "." + time.Now().Format("20060102150405")
+	es := elasticsearch.Ctx(ctx)
+	resp, err := es.Indices.Create(index, es.Indices.Create.WithBody(
+		strings.NewReader(`{"mappings":`+entity.ScriptSearchMappings+`}`),
+	))
+	if err != nil {
+		return "", err
+	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+	if resp.IsError() {
+		b, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("create index error: %d body: %s", resp.StatusCode, b)
+	}
+	return index, nil
+}
+
+func (e *esSearchEngine) SwitchIndex(ctx context.Context, index string) error {
+	alias := (&entity.ScriptSearch{}).CollectionName()
+	es := elasticsearch.Ctx(ctx)
+	actions := make([]map[string]interface{}, 0)
+	oldIndices := make([]string, 0)
+	resp, err := es.Indices.GetAlias(es.Indices.GetAlias.WithName(alias))
+	if err != nil {
+		return err
+	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+	switch resp.StatusCode {
+	case http.StatusOK:
+		// 移除别名原来指向的索引
+		indices := make(map[string]interface{})
+		if err := json.NewDecoder(resp.Body).Decode(&indices); err != nil {
+			return err
+		}
+		for v := range indices {
+			actions = append(actions, map[string]interface{}{
+				"remove": map[string]interface{}{"index": v, "alias": alias},
+			})
+			if v != index {
+				oldIndices = append(oldIndices, v)
+			}
+		}
+	case http.StatusNotFound:
+		// 别名不存在时, 旧数据可能直接储存在同名的索引中, 需要同时删除该索引
+		exists, err := es.Indices.Exists([]string{alias})
+		if err != nil {
+			return err
+		}
+		_ = exists.Body.Close()
+		if exists.StatusCode == http.StatusOK {
+			actions = append(actions, map[string]interface{}{
+				"remove_index": map[string]interface{}{"index": alias},
+			})
+		}
+	default:
+		b, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("get alias error: %d body: %s", resp.StatusCode, b)
+	}
+	actions = append(actions, map[string]interface{}{
+		"add": map[string]interface{}{"index": index, "alias": alias},
+	})
+	body, err := json.Marshal(map[string]interface{}{"actions": actions})
+	if err != nil {
+		return err
+	}
+	update, err := es.Indices.UpdateAliases(bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(update.Body)
+	if update.IsError() {
+		b, _ := io.ReadAll(update.Body)
+		return fmt.Errorf("update aliases error: %d body: %s", update.StatusCode, b)
+	}
+	logger.Ctx(ctx).Info("switch index alias success", zap.String("alias", alias), zap.String("index", index))
+	if err := cache.Ctx(ctx).Del(esMappingKey); err != nil {
+		logger.Ctx(ctx).Error("delete mapping cache error", zap.Error(err))
+	}
+	// 别名已经切换, 旧索引删除失败不影响使用, 只记录日志
+	for _, v := range oldIndices {
+		if err := e.DeleteIndex(ctx, v); err != nil {
+			logger.Ctx(ctx).Error("delete old index error", zap.String("index", v), zap.Error(err))
+		}
+	}
+	return nil
+}
+
+func (e *esSearchEngine) DeleteIndex(ctx context.Context, index string) error {
+	resp, err := elasticsearch.Ctx(ctx).Indices.Delete([]string{index})
+	if err != nil {
+		return err
+	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+	if resp.IsError() && resp.StatusCode != http.StatusNotFound {
+		b, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("delete index error: %d body: %s", resp.StatusCode, b)
+	}
+	logger.Ctx(ctx).Info("delete index success", zap.String("index", index))
+	return nil
+}
+
+const esMappingKey = "script:search:mapping"
+
+// esMapping 当前使用的索引的映射信息
+type esMapping struct {
+	Version    int
+	DomainType string
+}
+
+func (e *esSearchEngine) MappingVersion(ctx context.Context) (int, error) {
+	mapping, err := e.mapping(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return mapping.Version, nil
+}
+
+func (e *esSearchEngine) mapping(ctx context.Context) (*esMapping, error) {
+	ret := &esMapping{}
+	if err := cache.Ctx(ctx).GetOrSet(esMappingKey, func() (interface{}, error) {
+		es := elasticsearch.Ctx(ctx)
+		resp, err := es.Indices.GetMapping(es.Indices.GetMapping.WithIndex((&entity.ScriptSearch{}).CollectionName()))
+		if err != nil {
+			return nil, err
+		}
+		defer func(Body io.ReadCloser) {
+			_ = Body.Close()
+		}(resp.Body)
+		if resp.StatusCode == http.StatusNotFound {
+			return &esMapping{}, nil
+		}
+		if resp.IsError() {
+			b, _ := io.ReadAll(resp.Body)
+			return nil, fmt.Errorf("get mapping error: %d body: %s", resp.StatusCode, b)
+		}
+		// 使用别名时key为实际的索引名
+		m := make(map[string]struct {
+			Mappings struct {
+				Meta struct {
+					Version int `json:"version"`
+				} `json:"_meta"`
+				Properties struct {
+					Domain struct {
+						Type string `json:"type"`
+					} `json:"domain"`
+				} `json:"properties"`
+			} `json:"mappings"`
+		})
+		if err := json.NewDecoder(resp.Body).Decode(&m); err != nil {
+			return nil, err
+		}
+		ret := &esMapping{}
+		for _, v := range m {
+			ret.Version = v.Mappings.Meta.Version
+			ret.DomainType = v.Mappings.Properties.Domain.Type
+		}
+		return ret, nil
+	}, cache2.Expiration(time.Minute)).Scan(ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
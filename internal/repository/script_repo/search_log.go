@@ -0,0 +1,104 @@
+package script_repo
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cago-frame/cago/database/db"
+	entity "github.com/scriptscat/scriptlist/internal/model/entity/script_entity"
+	"gorm.io/gorm"
+)
+
+// SearchQueryStat 搜索词统计
+type SearchQueryStat struct {
+	Query       string
+	Searches    int64 // 搜索次数
+	ZeroResults int64 // 没有结果的次数
+	Clicked     int64 // 有点击结果的次数
+}
+
+// ErrSearchLogNotFound 点击的搜索日志不存在, 搜索日志可能还没有写入
+var ErrSearchLogNotFound = errors.New("search log not found")
+
+// ScriptSearchLogRepo 关键字搜索日志
+type ScriptSearchLogRepo interface {
+	Create(ctx context.Context, log *entity.ScriptSearchLog) error
+	// Click 记录搜索结果的点击, 只保存第一次点击的脚本, 搜索日志不存在时返回 ErrSearchLogNotFound
+	Click(ctx context.Context, searchId string, scriptId int64, clicktime int64) error
+	// DeleteBefore 删除某个时间之前的搜索日志, 每次最多删除limit条, 返回删除的数量
+	DeleteBefore(ctx context.Context, t int64, limit int) (int64, error)
+	// Summary 统计时间范围内的所有搜索
+	Summary(ctx context.Context, start, end int64) (*SearchQueryStat, error)
+	// TopQueries 按搜索次数排序的搜索词, zero为true时只返回有无结果搜索的词, 并按无结果次数排序
+	TopQueries(ctx context.Context, start, end int64, zero bool, size int) ([]*SearchQueryStat, error)
+}
+
+var defaultScriptSearchLog ScriptSearchLogRepo
+
+func ScriptSearchLog() ScriptSearchLogRepo {
+	return defaultScriptSearchLog
+}
+
+func RegisterScriptSearchLog(i ScriptSearchLogRepo) {
+	defaultScriptSearchLog = i
+}
+
+type scriptSearchLogRepo struct {
+}
+
+func NewScriptSearchLogRepo() ScriptSearchLogRepo {
+	return &scriptSearchLogRepo{}
+}
+
+func (s *scriptSearchLogRepo) Create(ctx context.Context, log *entity.ScriptSearchLog) error {
+	return db.Ctx(ctx).Create(log).Error
+}
+
+func (s *scriptSearchLogRepo) Click(ctx context.Context, searchId string, scriptId int64, clicktime int64) error {
+	result := db.Ctx(ctx).Model(&entity.ScriptSearchLog{}).Where("search_id=?", searchId).
+		Updates(map[string]interface{}{
+			"clicks":          gorm.Expr("clicks+1"),
+			"click_script_id": gorm.Expr("if(click_script_id=0,?,click_script_id)", scriptId),
+			"clicktime":       gorm.Expr("if(clicktime=0,?,clicktime)", clicktime),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	// clicks每次都会变化, 没有影响的行说明搜索日志不存在
+	if result.RowsAffected == 0 {
+		return ErrSearchLogNotFound
+	}
+	return nil
+}
+
+func (s *scriptSearchLogRepo) DeleteBefore(ctx context.Context, t int64, limit int) (int64, error) {
+	result := db.Ctx(ctx).Where("createtime<?", t).Limit(limit).Delete(&entity.ScriptSearchLog{})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
+
+const searchQueryStatSelect = "count(*) as searches, sum(total=0) as zero_results, sum(clicks>0) as clicked"
+
+func (s *scriptSearchLogRepo) Summary(ctx context.Context, start, end int64) (*SearchQueryStat, error) {
+	ret := &SearchQueryStat{}
+	if err := db.Ctx(ctx).Model(&entity.ScriptSearchLog{}).Select(searchQueryStatSelect).
+		Where("createtime between ? and ?", start, end).Scan(ret).Error; err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func (s *scriptSearchLogRepo) TopQueries(ctx context.Context, start, end int64, zero bool, size int) ([]*SearchQueryStat, error) {
+	var ret []*SearchQueryStat
+	find := db.Ctx(ctx).Model(&entity.ScriptSearchLog{}).Select("query, "+searchQueryStatSelect).
+		Where("createtime between ? and ?", start, end).Group("query")
+	if zero {
+		find = find.Having("zero_results>0").Order("zero_results desc")
+	}
+	if err := find.Order("searches desc").Limit(size).Scan(&ret).Error; err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
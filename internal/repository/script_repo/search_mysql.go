@@ -0,0 +1,266 @@
+package script_repo
+
+import (
+	"context"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/cago-frame/cago/database/db"
+	"github.com/cago-frame/cago/pkg/consts"
+	"github.com/cago-frame/cago/pkg/utils/httputils"
+	"github.com/scriptscat/scriptlist/internal/model"
+	entity "github.com/scriptscat/scriptlist/internal/model/entity/script_entity"
+	"github.com/scriptscat/scriptlist/internal/service/auth_svc"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// mysqlSearchEngine 内嵌的mysql全文索引搜索后端, 适合小型部署与集成测试
+// 需要mysql5.7.6以上版本的ngram全文解析器
+type mysqlSearchEngine struct {
+}
+
+func NewMysqlSearchEngine() ScriptSearchEngine {
+	return &mysqlSearchEngine{}
+}
+
+func (m *mysqlSearchEngine) Search(ctx context.Context, options *SearchOptions, page httputils.PageRequest) (*SearchResult, error) {
+	match := "MATCH(" + entity.ScriptFulltextFields + ") AGAINST (? IN NATURAL LANGUAGE MODE)"
+	// 每次返回新的查询, 以便复用条件进行统计
+	query := func() *gorm.DB {
+		find := db.Ctx(ctx).Model(&entity.ScriptFulltext{}).
+			Where(match, options.Keyword).Where("status=?", consts.ACTIVE)
+		if !options.Self {
+			user := auth_svc.Auth().Get(ctx)
+			if user == nil || !user.AdminLevel.IsAdmin(model.Moderator) {
+				find = find.Where("public=? and unwell=?", entity.PublicScript, entity.Well)
+			}
+		}
+		if options.UserID != 0 {
+			find = find.Where("user_id=?", options.UserID)
+		}
+		category := options.Category
+		switch options.Type {
+		case 1: // 用户脚本
+			find = find.Where("type=?", entity.UserscriptType)
+		case 2: // 库
+			find = find.Where("type=?", entity.LibraryType)
+		case 3: // 后台脚本
+			category = append(category, 1)
+		case 4: // 定时脚本
+			category = append(category, 2)
+		}
+		if len(category) != 0 {
+			find = find.Where("script_id in (?)", db.Ctx(ctx).Model(&entity.ScriptCategory{}).
+				Select("script_id").Where("category_id in ?", category))
+		}
+		if options.Domain != "" {
+			domains := strings.Split(options.Domain, ".")
+			terms := []string{options.Domain}
+			for i := 1; i < len(domains)-1; i++ {
+				terms = append(terms, strings.Join(domains[i:], "."))
+			}
+			find = find.Where("script_id in (?)", db.Ctx(ctx).Model(&entity.ScriptDomain{}).
+				Select("script_id").Where("domain in ? and status=?", terms, consts.ACTIVE))
+		}
+		if options.Sort == "updatetime" {
+			find = find.Where("updatetime>0")
+		}
+		return find
+	}
+	ret := &SearchResult{
+		List:      make([]*entity.Script, 0),
+		Highlight: make(map[int64]map[string][]string),
+		Facets:    make(map[string][]*SearchFacet),
+	}
+	if err := query().Count(&ret.Total).Error; err != nil {
+		return nil, err
+	}
+	find := query().Select("*, "+match+" as relevance", options.Keyword)
+	switch options.Sort {
+	case "today_update", "today_download", "total_download", "score", "trending", "createtime", "updatetime":
+		find = find.Order(options.Sort + " desc")
+	}
+	var list []*entity.ScriptFulltext
+	if err := find.Order("relevance desc,createtime desc").
+		Limit(page.GetLimit()).Offset(page.GetOffset()).Find(&list).Error; err != nil {
+		return nil, err
+	}
+	for _, v := range list {
+		ret.List = append(ret.List, &entity.Script{
+			ID:          v.ScriptID,
+			UserID:      v.UserID,
+			Name:        v.Name,
+			Description: v.Description,
+			Content:     v.Content,
+			Type:        v.Type,
+			Public:      v.Public,
+			Unwell:      v.Unwell,
+			Archive:     v.Archive,
+			Status:      v.Status,
+			Createtime:  v.Createtime,
+			Updatetime:  v.Updatetime,
+		})
+		highlight := make(map[string][]string)
+		for field, text := range map[string]string{
+			"name":               v.Name,
+			"description":        v.Description,
+			"content":            v.Content,
+			"locale_name":        v.LocaleName,
+			"locale_description": v.LocaleDescription,
+		} {
+			if fragment, ok := highlightFragment(text, options.Keyword, 150); ok {
+				highlight[field] = []string{fragment}
+			}
+		}
+		if len(highlight) > 0 {
+			ret.Highlight[v.ScriptID] = highlight
+		}
+	}
+	// 分面统计
+	ids := query().Select("script_id")
+	type facet struct {
+		Key   string
+		Count int64
+	}
+	for _, field := range searchFacetFields {
+		var rows []*facet
+		var find *gorm.DB
+		switch field {
+		case "category", "tag":
+			categoryType := entity.ScriptCategoryTypeCategory
+			if field == "tag" {
+				categoryType = entity.ScriptCategoryTypeTag
+			}
+			categoryTable := db.Ctx(ctx).NamingStrategy.TableName("script_category")
+			listTable := db.Ctx(ctx).NamingStrategy.TableName("script_category_list")
+			find = db.Ctx(ctx).Table(categoryTable).
+				Joins("LEFT JOIN "+listTable+" ON "+categoryTable+".category_id = "+listTable+".id").
+				Where(categoryTable+".script_id in (?) and "+listTable+".type=?", ids, categoryType).
+				Select(categoryTable + ".category_id as `key`, count(*) as count").
+				Group(categoryTable + ".category_id")
+		case "type":
+			find = query().Select("type as `key`, count(*) as count").Group("type")
+		case "domain":
+			find = db.Ctx(ctx).Model(&entity.ScriptDomain{}).
+				Where("script_id in (?) and status=?", ids, consts.ACTIVE).
+				Select("domain as `key`, count(*) as count").Group("domain")
+		}
+		if err := find.Order("count desc").Limit(20).Scan(&rows).Error; err != nil {
+			return nil, err
+		}
+		facets := make([]*SearchFacet, 0, len(rows))
+		for _, v := range rows {
+			facets = append(facets, &SearchFacet{Key: v.Key, Count: v.Count})
+		}
+		ret.Facets[field] = facets
+	}
+	return ret, nil
+}
+
+func (m *mysqlSearchEngine) Save(ctx context.Context, s *entity.ScriptSearch) error {
+	return db.Ctx(ctx).Clauses(clause.OnConflict{UpdateAll: true}).
+		Create(entity.NewScriptFulltext(s)).Error
+}
+
+func (m *mysqlSearchEngine) Update(ctx context.Context, s *entity.ScriptSearch) error {
+	return m.Save(ctx, s)
+}
+
+func (m *mysqlSearchEngine) Delete(ctx context.Context, id int64) error {
+	return db.Ctx(ctx).Model(&entity.ScriptFulltext{}).
+		Where("script_id=?", id).Update("status", consts.DELETE).Error
+}
+
+func (m *mysqlSearchEngine) Suggest(ctx context.Context, prefix string, size int) ([]*entity.ScriptSearch, error) {
+	var list []*entity.ScriptFulltext
+	// 使用name_prefix索引进行前缀匹配
+	if err := db.Ctx(ctx).Select("script_id,name,total_download").
+		Where("name like ? and status=? and public=? and unwell=?",
+			escapeLike(prefix)+"%", consts.ACTIVE, entity.PublicScript, entity.Well).
+		Order("total_download desc").Limit(size).Find(&list).Error; err != nil {
+		return nil, err
+	}
+	ret := make([]*entity.ScriptSearch, 0, len(list))
+	for _, v := range list {
+		ret = append(ret, &entity.ScriptSearch{
+			ID:            v.ScriptID,
+			Name:          v.Name,
+			TotalDownload: v.TotalDownload,
+		})
+	}
+	return ret, nil
+}
+
+// Similar 全文索引只能按名称与描述的相关度排序, 不考虑标签与域名
+func (m *mysqlSearchEngine) Similar(ctx context.Context, s *entity.ScriptSearch, size int) ([]int64, error) {
+	keyword := strings.Join(append([]string{s.Name, s.Description}, s.LocaleName...), " ")
+	find := db.Ctx(ctx).Model(&entity.ScriptFulltext{}).
+		Where("MATCH("+entity.ScriptFulltextFields+") AGAINST (? IN NATURAL LANGUAGE MODE)", keyword).
+		Where("script_id<>? and status=? and public=? and unwell=? and archive=?",
+			s.ID, consts.ACTIVE, entity.PublicScript, entity.Well, entity.IsActive)
+	var ret []int64
+	if err := find.Limit(size).Pluck("script_id", &ret).Error; err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func (m *mysqlSearchEngine) FindByIDRange(ctx context.Context, startId, endId int64) ([]*entity.ScriptSearch, error) {
+	var list []*entity.ScriptFulltext
+	if err := db.Ctx(ctx).Select("script_id,status,public,unwell,updatetime").
+		Where("script_id between ? and ?", startId, endId).Order("script_id").
+		Find(&list).Error; err != nil {
+		return nil, err
+	}
+	ret := make([]*entity.ScriptSearch, 0, len(list))
+	for _, v := range list {
+		ret = append(ret, &entity.ScriptSearch{
+			ID:         v.ScriptID,
+			Public:     v.Public,
+			Unwell:     v.Unwell,
+			Status:     v.Status,
+			Updatetime: v.Updatetime,
+		})
+	}
+	return ret, nil
+}
+
+func (m *mysqlSearchEngine) MaxID(ctx context.Context) (int64, error) {
+	var id int64
+	if err := db.Ctx(ctx).Model(&entity.ScriptFulltext{}).
+		Select("coalesce(max(script_id),0)").Scan(&id).Error; err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// escapeLike 转义like中的通配符
+func escapeLike(s string) string {
+	return strings.NewReplacer("\\", "\\\\", "%", "\\%", "_", "\\_").Replace(s)
+}
+
+// highlightFragment 截取关键字附近的片段并使用<em>标记关键字, 与es的高亮格式一致
+func highlightFragment(text, keyword string, size int) (string, bool) {
+	keyword = strings.TrimSpace(keyword)
+	if text == "" || keyword == "" {
+		return "", false
+	}
+	lower := strings.ToLower(text)
+	index := strings.Index(lower, strings.ToLower(keyword))
+	if index == -1 || len(lower) != len(text) {
+		return "", false
+	}
+	// 以关键字为中心截取片段
+	start := index
+	for n := 0; start > 0 && n < size/2; n++ {
+		_, w := utf8.DecodeLastRuneInString(text[:start])
+		start -= w
+	}
+	end := index + len(keyword)
+	for n := 0; end < len(text) && n < size/2; n++ {
+		_, w := utf8.DecodeRuneInString(text[end:])
+		end += w
+	}
+	return text[start:index] + "<em>" + text[index:index+len(keyword)] + "</em>" + text[index+len(keyword):end], true
+}
@@ -0,0 +1,29 @@
+package script_repo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEscapeLike(t *testing.T) {
+	assert.Equal(t, `100\%\_`, escapeLike("100%_"))
+	assert.Equal(t, `a\\b`, escapeLike(`a\b`))
+}
+
+func TestHighlightFragment(t *testing.T) {
+	fragment, ok := highlightFragment("一个好用的Script脚本", "script", 4)
+	assert.True(t, ok)
+	assert.Equal(t, "用的<em>Script</em>脚本", fragment)
+
+	_, ok = highlightFragment("一个好用的脚本", "script", 150)
+	assert.False(t, ok)
+	_, ok = highlightFragment("", "script", 150)
+	assert.False(t, ok)
+}
+
+func TestNewSearchEngine(t *testing.T) {
+	assert.IsType(t, &mysqlSearchEngine{}, NewSearchEngine(MysqlSearchEngine))
+	assert.IsType(t, &esSearchEngine{}, NewSearchEngine(EsSearchEngine))
+	assert.IsType(t, &esSearchEngine{}, NewSearchEngine(""))
+}
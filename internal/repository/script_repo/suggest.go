@@ -0,0 +1,87 @@
+package script_repo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cago-frame/cago/database/db"
+	"github.com/cago-frame/cago/pkg/consts"
+	entity "github.com/scriptscat/scriptlist/internal/model/entity/script_entity"
+	"github.com/scriptscat/scriptlist/internal/model/entity/user_entity"
+)
+
+// SuggestItem 搜索建议项, Count为排序使用的热度
+type SuggestItem struct {
+	ID    int64
+	Name  string
+	Count int64
+}
+
+// ScriptSuggestRepo 列表页搜索框的前缀建议, 脚本名称的建议由搜索后端完成
+type ScriptSuggestRepo interface {
+	// Tag 根据前缀建议标签, 按使用的脚本数量排序
+	Tag(ctx context.Context, prefix string, size int) ([]*SuggestItem, error)
+	// Author 根据前缀建议作者, 按公开脚本的总下载量排序
+	Author(ctx context.Context, prefix string, size int) ([]*SuggestItem, error)
+	// Domain 根据前缀建议域名, 按脚本数量排序
+	Domain(ctx context.Context, prefix string, size int) ([]*SuggestItem, error)
+}
+
+var defaultScriptSuggest ScriptSuggestRepo
+
+func ScriptSuggest() ScriptSuggestRepo {
+	return defaultScriptSuggest
+}
+
+func RegisterScriptSuggest(i ScriptSuggestRepo) {
+	defaultScriptSuggest = i
+}
+
+type scriptSuggestRepo struct {
+}
+
+func NewScriptSuggestRepo() ScriptSuggestRepo {
+	return &scriptSuggestRepo{}
+}
+
+func (s *scriptSuggestRepo) Tag(ctx context.Context, prefix string, size int) ([]*SuggestItem, error) {
+	var ret []*SuggestItem
+	if err := db.Ctx(ctx).Model(&entity.ScriptCategoryList{}).
+		Select("id, name, num as count").
+		Where("name like ? and type=?", escapeLike(prefix)+"%", entity.ScriptCategoryTypeTag).
+		Order("num desc").Limit(size).Scan(&ret).Error; err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func (s *scriptSuggestRepo) Author(ctx context.Context, prefix string, size int) ([]*SuggestItem, error) {
+	var ret []*SuggestItem
+	userTbName := (&user_entity.User{}).TableName()
+	scriptTbName := (&entity.Script{}).TableName()
+	statisticsTbName := db.Default().NamingStrategy.TableName("script_statistics")
+	if err := db.Ctx(ctx).Table(userTbName).
+		Select(fmt.Sprintf("%s.uid as id, %s.username as name, sum(%s.download) as count",
+			userTbName, userTbName, statisticsTbName)).
+		Joins(fmt.Sprintf("inner join %s on %s.user_id=%s.uid and %s.status=? and %s.public=? and %s.unwell=?",
+			scriptTbName, scriptTbName, userTbName, scriptTbName, scriptTbName, scriptTbName),
+			consts.ACTIVE, entity.PublicScript, entity.Well).
+		Joins(fmt.Sprintf("left join %s on %s.script_id=%s.id", statisticsTbName, statisticsTbName, scriptTbName)).
+		Where(userTbName+".username like ?", escapeLike(prefix)+"%").
+		Group(userTbName + ".uid, " + userTbName + ".username").
+		Order("count desc").Limit(size).Scan(&ret).Error; err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func (s *scriptSuggestRepo) Domain(ctx context.Context, prefix string, size int) ([]*SuggestItem, error) {
+	var ret []*SuggestItem
+	if err := db.Ctx(ctx).Model(&entity.ScriptDomain{}).
+		Select("domain as name, count(distinct script_id) as count").
+		Where("domain like ? and status=?", escapeLike(prefix)+"%", consts.ACTIVE).
+		Group("domain").Order("count desc").Limit(size).Scan(&ret).Error; err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
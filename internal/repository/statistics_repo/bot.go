@@ -0,0 +1,93 @@
+package statistics_repo
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/cago-frame/cago/database/redis"
+)
+
+// string statistics:bot:nocookie:@ip:@hour 一小时过期
+// hash statistics:bot:script:@id:@date @op:@reason @num 90天过期
+// hash statistics:bot:total:@date @op:@reason @num 90天过期
+
+//go:generate mockgen -source=./bot.go -destination=./mock/bot.go
+
+// BotRepo 被识别为机器人的请求记录, 这部分请求不计入统计, 仅保留原始数量给管理员查看
+type BotRepo interface {
+	// IncrNoCookie 记录ip一小时内不带cookie的请求, 返回当前小时内的次数
+	IncrNoCookie(ctx context.Context, ip string, t time.Time) (int64, error)
+	// IncrFiltered 记录被过滤的请求
+	IncrFiltered(ctx context.Context, scriptId int64, op ScriptStatisticsType, reason string, t time.Time) error
+	// DayFiltered 获取某一天被过滤的请求数量, key为 op:reason, scriptId为0时返回全站的数量
+	DayFiltered(ctx context.Context, scriptId int64, t time.Time) (map[string]int64, error)
+}
+
+var defaultBot BotRepo
+
+func Bot() BotRepo {
+	return defaultBot
+}
+
+func RegisterBot(i BotRepo) {
+	defaultBot = i
+}
+
+type botRepo struct {
+}
+
+func NewBot() BotRepo {
+	return &botRepo{}
+}
+
+func (b *botRepo) filteredKey(scriptId int64, t time.Time) string {
+	if scriptId == 0 {
+		return "statistics:bot:total:" + t.Format("2006/01/02")
+	}
+	return fmt.Sprintf("statistics:bot:script:%d:%s", scriptId, t.Format("2006/01/02"))
+}
+
+func (b *botRepo) IncrNoCookie(ctx context.Context, ip string, t time.Time) (int64, error) {
+	key := "statistics:bot:nocookie:" + ip + ":" + t.Format("2006010215")
+	num, err := redis.Ctx(ctx).Incr(key).Result()
+	if err != nil {
+		return 0, err
+	}
+	if num == 1 {
+		if err := redis.Ctx(ctx).Expire(key, time.Hour).Err(); err != nil {
+			return 0, err
+		}
+	}
+	return num, nil
+}
+
+func (b *botRepo) IncrFiltered(ctx context.Context, scriptId int64, op ScriptStatisticsType, reason string, t time.Time) error {
+	field := string(op) + ":" + reason
+	for _, key := range []string{b.filteredKey(scriptId, t), b.filteredKey(0, t)} {
+		if err := redis.Ctx(ctx).HIncrBy(key, field, 1).Err(); err != nil {
+			return err
+		}
+		if err := redis.Ctx(ctx).Expire(key, time.Hour*24*90).Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *botRepo) DayFiltered(ctx context.Context, scriptId int64, t time.Time) (map[string]int64, error) {
+	result, err := redis.Ctx(ctx).HGetAll(b.filteredKey(scriptId, t)).Result()
+	if err != nil {
+		return nil, err
+	}
+	ret := make(map[string]int64, len(result))
+	for k, v := range result {
+		num, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			continue
+		}
+		ret[k] = num
+	}
+	return ret, nil
+}
@@ -0,0 +1,132 @@
+package statistics_repo
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/cago-frame/cago/database/redis"
+	redis2 "github.com/redis/go-redis/v9"
+)
+
+// hash statistics:coinstall:actor:@actor @script_id @time 90天过期
+// zset statistics:coinstall:script:@id @script_id @num 180天过期, 超过400个时裁剪到前200
+
+// CoInstallRepo 记录同一个统计token或者用户安装过的脚本, 计算脚本间的共同安装关系
+type CoInstallRepo interface {
+	// Record 记录安装, actor为统计token或者用户
+	Record(ctx context.Context, actor string, scriptId int64) error
+	// Related 按共同安装次数获取相关的脚本
+	Related(ctx context.Context, scriptId int64, size int) ([]int64, error)
+}
+
+var defaultCoInstall CoInstallRepo
+
+func CoInstall() CoInstallRepo {
+	return defaultCoInstall
+}
+
+func RegisterCoInstall(i CoInstallRepo) {
+	defaultCoInstall = i
+}
+
+type coInstallRepo struct {
+}
+
+func NewCoInstall() CoInstallRepo {
+	return &coInstallRepo{}
+}
+
+const (
+	// 安装过多脚本的一般是爬虫, 不计算共同安装
+	coInstallActorMax  = 200
+	coInstallRelateMax = 200
+	// 相关脚本超过该数量时才裁剪, 留出空间让新的脚本积累次数, 避免刚加入就被淘汰
+	coInstallTrimMax = coInstallRelateMax * 2
+)
+
+func (c *coInstallRepo) actorKey(actor string) string {
+	return "statistics:coinstall:actor:" + actor
+}
+
+func (c *coInstallRepo) scriptKey(scriptId int64) string {
+	return "statistics:coinstall:script:" + strconv.FormatInt(scriptId, 10)
+}
+
+func (c *coInstallRepo) Record(ctx context.Context, actor string, scriptId int64) error {
+	key := c.actorKey(actor)
+	id := strconv.FormatInt(scriptId, 10)
+	ok, err := redis.Ctx(ctx).HSetNX(key, id, time.Now().Unix()).Result()
+	if err != nil {
+		return err
+	}
+	redis.Ctx(ctx).Expire(key, time.Hour*24*90)
+	if !ok {
+		return nil
+	}
+	installed, err := redis.Ctx(ctx).HKeys(key).Result()
+	if err != nil {
+		return err
+	}
+	if len(installed) > coInstallActorMax {
+		return nil
+	}
+	pipe := redis.Default().Pipeline()
+	cards := make(map[int64]*redis2.IntCmd)
+	for _, v := range installed {
+		if v == id {
+			continue
+		}
+		otherId, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			continue
+		}
+		cards[scriptId] = c.incr(ctx, pipe, scriptId, v)
+		cards[otherId] = c.incr(ctx, pipe, otherId, id)
+	}
+	if len(cards) == 0 {
+		return nil
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return err
+	}
+	// 超过数量的才裁剪, 保留共同安装次数最多的
+	pipe = redis.Default().Pipeline()
+	trim := false
+	for sid, card := range cards {
+		if card.Val() > coInstallTrimMax {
+			pipe.ZRemRangeByRank(ctx, c.scriptKey(sid), 0, -coInstallRelateMax-1)
+			trim = true
+		}
+	}
+	if !trim {
+		return nil
+	}
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// incr 在管道中增加共同安装次数, 返回相关脚本数量的命令
+func (c *coInstallRepo) incr(ctx context.Context, pipe redis2.Pipeliner, scriptId int64, member string) *redis2.IntCmd {
+	key := c.scriptKey(scriptId)
+	pipe.ZIncrBy(ctx, key, 1, member)
+	pipe.Expire(ctx, key, time.Hour*24*180)
+	return pipe.ZCard(ctx, key)
+}
+
+func (c *coInstallRepo) Related(ctx context.Context, scriptId int64, size int) ([]int64, error) {
+	result, err := redis.Ctx(ctx).ZRevRangeWithScores(c.scriptKey(scriptId), 0, int64(size-1)).Result()
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]int64, 0, len(result))
+	for _, v := range result {
+		member, _ := v.Member.(string)
+		id, err := strconv.ParseInt(member, 10, 64)
+		if err != nil {
+			continue
+		}
+		ret = append(ret, id)
+	}
+	return ret, nil
+}
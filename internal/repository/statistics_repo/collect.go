@@ -0,0 +1,281 @@
+package statistics_repo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"time"
+
+	"github.com/cago-frame/cago/database/redis"
+	redis2 "github.com/redis/go-redis/v9"
+	"github.com/scriptscat/scriptlist/internal/model/entity/statistics_entity"
+	"github.com/scriptscat/scriptlist/internal/pkg/clickhouse"
+)
+
+// bitmap statistics:collect:visitor:@script_id 访客的布隆过滤器, 固定大小, 180天没有访问过期
+// list statistics:collect:buffer 等待批量写入clickhouse的数据
+
+// CollectBuffer 缓冲区中等待写入的一次会话
+type CollectBuffer struct {
+	Collect *statistics_entity.StatisticsCollect `json:"collect"`
+	Visitor *statistics_entity.StatisticsVisitor `json:"visitor"`
+}
+
+// CollectTop 按域名/页面聚合的访问数据
+type CollectTop struct {
+	Key string `json:"key"`
+	PV  int64  `json:"pv"`
+	UV  int64  `json:"uv"`
+}
+
+// DurationTrend 每日会话数与平均停留时长
+type DurationTrend struct {
+	Date        string  `json:"date"`
+	Sessions    int64   `json:"sessions"`
+	AvgDuration float64 `json:"avg_duration"`
+}
+
+// VisitorTrend 每日访客数据
+type VisitorTrend struct {
+	Date        string `json:"date"`
+	Visitors    int64  `json:"visitors"`
+	NewVisitors int64  `json:"new_visitors"`
+	PV          int64  `json:"pv"`
+}
+
+//go:generate mockgen -source=./collect.go -destination=./mock/collect.go
+
+// StatisticsCollectRepo 统计平台收集的数据, 存储在clickhouse中
+type StatisticsCollectRepo interface {
+	// BatchInsertCollect 批量写入会话数据
+	BatchInsertCollect(ctx context.Context, list []*statistics_entity.StatisticsCollect) error
+	// BatchInsertVisitor 批量写入访客数据
+	BatchInsertVisitor(ctx context.Context, list []*statistics_entity.StatisticsVisitor) error
+	// PushBuffer 将数据放入缓冲区, 返回缓冲区的长度
+	PushBuffer(ctx context.Context, list []*CollectBuffer) (int64, error)
+	// PopBuffer 从缓冲区取出最多limit条数据
+	PopBuffer(ctx context.Context, limit int) ([]*CollectBuffer, error)
+	// NewVisitors 判断访客是否为新访客, 不记录访客, 使用布隆过滤器存在少量误判为老访客的情况
+	NewVisitors(ctx context.Context, list []*statistics_entity.StatisticsVisitor) ([]bool, error)
+	// MarkVisitors 记录访客, 之后的访问为老访客
+	MarkVisitors(ctx context.Context, list []*statistics_entity.StatisticsVisitor) error
+	// TopHost 访问量最高的域名
+	TopHost(ctx context.Context, scriptId int64, start, end time.Time, limit int) ([]*CollectTop, error)
+	// TopPage 访问量最高的页面
+	TopPage(ctx context.Context, scriptId int64, start, end time.Time, limit int) ([]*CollectTop, error)
+	// DurationTrend 每日停留时长趋势
+	DurationTrend(ctx context.Context, scriptId int64, start, end time.Time) ([]*DurationTrend, error)
+	// VisitorTrend 每日访客趋势
+	VisitorTrend(ctx context.Context, scriptId int64, start, end time.Time) ([]*VisitorTrend, error)
+}
+
+var defaultStatisticsCollect StatisticsCollectRepo
+
+func StatisticsCollect() StatisticsCollectRepo {
+	return defaultStatisticsCollect
+}
+
+func RegisterStatisticsCollect(i StatisticsCollectRepo) {
+	defaultStatisticsCollect = i
+}
+
+type statisticsCollectRepo struct {
+}
+
+func NewStatisticsCollect() StatisticsCollectRepo {
+	return &statisticsCollectRepo{}
+}
+
+func (s *statisticsCollectRepo) client() (*clickhouse.Client, error) {
+	client := clickhouse.Default()
+	if client == nil {
+		return nil, clickhouse.ErrNotConfigured
+	}
+	return client, nil
+}
+
+func (s *statisticsCollectRepo) BatchInsertCollect(ctx context.Context, list []*statistics_entity.StatisticsCollect) error {
+	client, err := s.client()
+	if err != nil {
+		return err
+	}
+	return client.Insert(ctx, "statistics_collect", list)
+}
+
+func (s *statisticsCollectRepo) BatchInsertVisitor(ctx context.Context, list []*statistics_entity.StatisticsVisitor) error {
+	client, err := s.client()
+	if err != nil {
+		return err
+	}
+	return client.Insert(ctx, "statistics_visitor", list)
+}
+
+const (
+	// 每个脚本的访客布隆过滤器占用 2^20 bit(128KB), 10万访客时误判率约为2%
+	visitorBloomBits   = 1 << 20
+	visitorBloomHashes = 3
+)
+
+func (s *statisticsCollectRepo) visitorKey(scriptId int64) string {
+	return "statistics:collect:visitor:" + strconv.FormatInt(scriptId, 10)
+}
+
+// visitorOffsets 使用双重哈希计算访客在布隆过滤器中的位置
+func visitorOffsets(visitorId string) []int64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(visitorId))
+	sum := h.Sum64()
+	h1, h2 := sum&0xffffffff, sum>>32|1
+	ret := make([]int64, 0, visitorBloomHashes)
+	for i := uint64(0); i < visitorBloomHashes; i++ {
+		ret = append(ret, int64((h1+i*h2)%visitorBloomBits))
+	}
+	return ret
+}
+
+func (s *statisticsCollectRepo) NewVisitors(ctx context.Context, list []*statistics_entity.StatisticsVisitor) ([]bool, error) {
+	pipe := redis.Default().Pipeline()
+	cmds := make([][]*redis2.IntCmd, 0, len(list))
+	for _, v := range list {
+		key := s.visitorKey(v.ScriptID)
+		bits := make([]*redis2.IntCmd, 0, visitorBloomHashes)
+		for _, offset := range visitorOffsets(v.VisitorID) {
+			bits = append(bits, pipe.GetBit(ctx, key, offset))
+		}
+		cmds = append(cmds, bits)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+	ret := make([]bool, len(list))
+	for i, bits := range cmds {
+		// 任意一位没有设置过说明是新访客
+		for _, cmd := range bits {
+			if cmd.Val() == 0 {
+				ret[i] = true
+				break
+			}
+		}
+	}
+	return ret, nil
+}
+
+func (s *statisticsCollectRepo) MarkVisitors(ctx context.Context, list []*statistics_entity.StatisticsVisitor) error {
+	pipe := redis.Default().Pipeline()
+	keys := make(map[string]struct{})
+	for _, v := range list {
+		key := s.visitorKey(v.ScriptID)
+		for _, offset := range visitorOffsets(v.VisitorID) {
+			pipe.SetBit(ctx, key, offset, 1)
+		}
+		keys[key] = struct{}{}
+	}
+	for key := range keys {
+		pipe.Expire(ctx, key, time.Hour*24*180)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (s *statisticsCollectRepo) bufferKey() string {
+	return "statistics:collect:buffer"
+}
+
+func (s *statisticsCollectRepo) PushBuffer(ctx context.Context, list []*CollectBuffer) (int64, error) {
+	values := make([]interface{}, 0, len(list))
+	for _, v := range list {
+		b, err := json.Marshal(v)
+		if err != nil {
+			return 0, err
+		}
+		values = append(values, b)
+	}
+	return redis.Ctx(ctx).RPush(s.bufferKey(), values...).Result()
+}
+
+func (s *statisticsCollectRepo) PopBuffer(ctx context.Context, limit int) ([]*CollectBuffer, error) {
+	values, err := redis.Default().LPopCount(ctx, s.bufferKey(), limit).Result()
+	if err != nil {
+		if redis.Nil(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	ret := make([]*CollectBuffer, 0, len(values))
+	for _, v := range values {
+		item := &CollectBuffer{}
+		if err := json.Unmarshal([]byte(v), item); err != nil {
+			// 格式错误的数据直接丢弃
+			continue
+		}
+		ret = append(ret, item)
+	}
+	return ret, nil
+}
+
+func (s *statisticsCollectRepo) params(scriptId int64, start, end time.Time) map[string]interface{} {
+	return map[string]interface{}{
+		"script_id": scriptId,
+		"start":     start.Unix(),
+		"end":       end.Unix(),
+	}
+}
+
+func (s *statisticsCollectRepo) top(ctx context.Context, column string, scriptId int64, start, end time.Time, limit int) ([]*CollectTop, error) {
+	client, err := s.client()
+	if err != nil {
+		return nil, err
+	}
+	var list []*CollectTop
+	if err := client.Query(ctx, &list, fmt.Sprintf("SELECT %s AS key, count() AS pv, uniq(visitor_id) AS uv "+
+		"FROM statistics_collect FINAL "+
+		"WHERE script_id={script_id:Int64} AND visit_time>={start:Int64} AND visit_time<{end:Int64} "+
+		"GROUP BY key ORDER BY pv DESC LIMIT %d", column, limit), s.params(scriptId, start, end)); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (s *statisticsCollectRepo) TopHost(ctx context.Context, scriptId int64, start, end time.Time, limit int) ([]*CollectTop, error) {
+	return s.top(ctx, "operation_host", scriptId, start, end, limit)
+}
+
+func (s *statisticsCollectRepo) TopPage(ctx context.Context, scriptId int64, start, end time.Time, limit int) ([]*CollectTop, error) {
+	return s.top(ctx, "operation_page", scriptId, start, end, limit)
+}
+
+func (s *statisticsCollectRepo) DurationTrend(ctx context.Context, scriptId int64, start, end time.Time) ([]*DurationTrend, error) {
+	client, err := s.client()
+	if err != nil {
+		return nil, err
+	}
+	var list []*DurationTrend
+	if err := client.Query(ctx, &list, "SELECT toString(toDate(toDateTime(visit_time))) AS date, "+
+		"count() AS sessions, avg(duration) AS avg_duration "+
+		"FROM statistics_collect FINAL "+
+		"WHERE script_id={script_id:Int64} AND visit_time>={start:Int64} AND visit_time<{end:Int64} "+
+		"GROUP BY date ORDER BY date", s.params(scriptId, start, end)); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (s *statisticsCollectRepo) VisitorTrend(ctx context.Context, scriptId int64, start, end time.Time) ([]*VisitorTrend, error) {
+	client, err := s.client()
+	if err != nil {
+		return nil, err
+	}
+	var list []*VisitorTrend
+	if err := client.Query(ctx, &list, "SELECT toString(toDate(toDateTime(visit_time))) AS date, "+
+		"uniq(visitor_id) AS visitors, "+
+		"uniqIf(visitor_id, toDate(toDateTime(first_visit_time))=toDate(toDateTime(visit_time))) AS new_visitors, "+
+		"count() AS pv "+
+		"FROM statistics_visitor "+
+		"WHERE script_id={script_id:Int64} AND visit_time>={start:Int64} AND visit_time<{end:Int64} "+
+		"GROUP BY date ORDER BY date", s.params(scriptId, start, end)); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
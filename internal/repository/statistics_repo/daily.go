@@ -0,0 +1,172 @@
+package statistics_repo
+
+import (
+	"context"
+
+	"github.com/cago-frame/cago/database/db"
+	"github.com/scriptscat/scriptlist/internal/model/entity/statistics_entity"
+	"gorm.io/gorm/clause"
+)
+
+//go:generate mockgen -source=./daily.go -destination=./mock/daily.go
+
+// StatisticsDailyRepo 脚本每日统计数据
+type StatisticsDailyRepo interface {
+	// Save 保存每日数据, 已存在的会被覆盖
+	Save(ctx context.Context, list []*statistics_entity.StatisticsDaily) error
+	// FindRange 获取某段时间内的每日数据, 日期格式为2006-01-02, 包含start和end
+	FindRange(ctx context.Context, scriptId int64, op ScriptStatisticsType, start, end string) ([]*statistics_entity.StatisticsDaily, error)
+	// SaveUA 保存每日ua分布, 已存在的会被覆盖
+	SaveUA(ctx context.Context, list []*statistics_entity.StatisticsUaDaily) error
+	// SumUARange 汇总某段时间内的ua分布, 返回的Date为空
+	SumUARange(ctx context.Context, scriptId int64, ops []ScriptStatisticsType, start, end string) ([]*statistics_entity.StatisticsUaDaily, error)
+	// FindUARange 获取某段时间内每天的ua分布, 按日期排序
+	FindUARange(ctx context.Context, scriptId int64, start, end string) ([]*statistics_entity.StatisticsUaDaily, error)
+	// FindUADimension 获取某段时间内某操作某一维度每天的数量, 按日期排序
+	FindUADimension(ctx context.Context, scriptId int64, op ScriptStatisticsType, dimension, start, end string) ([]*statistics_entity.StatisticsUaDaily, error)
+	// SaveSource 保存每日安装来源, 已存在的会被覆盖
+	SaveSource(ctx context.Context, list []*statistics_entity.StatisticsSourceDaily) error
+	// SumSourceRange 汇总某段时间内的安装来源, 返回的Date为空
+	SumSourceRange(ctx context.Context, scriptId int64, start, end string) ([]*statistics_entity.StatisticsSourceDaily, error)
+	// FindSourceRange 获取某段时间内每天的安装来源, 按日期排序
+	FindSourceRange(ctx context.Context, scriptId int64, start, end string) ([]*statistics_entity.StatisticsSourceDaily, error)
+	// SumByDate 按日期汇总多个脚本的每日数据, 返回的ScriptID为0
+	SumByDate(ctx context.Context, scriptIds []int64, op ScriptStatisticsType, start, end string) ([]*statistics_entity.StatisticsDaily, error)
+	// SumByScript 按脚本汇总某段时间内的数据, 返回的Date为空
+	SumByScript(ctx context.Context, scriptIds []int64, op ScriptStatisticsType, start, end string) ([]*statistics_entity.StatisticsDaily, error)
+}
+
+var defaultStatisticsDaily StatisticsDailyRepo
+
+func StatisticsDaily() StatisticsDailyRepo {
+	return defaultStatisticsDaily
+}
+
+func RegisterStatisticsDaily(i StatisticsDailyRepo) {
+	defaultStatisticsDaily = i
+}
+
+type statisticsDailyRepo struct {
+}
+
+func NewStatisticsDaily() StatisticsDailyRepo {
+	return &statisticsDailyRepo{}
+}
+
+func (s *statisticsDailyRepo) Save(ctx context.Context, list []*statistics_entity.StatisticsDaily) error {
+	if len(list) == 0 {
+		return nil
+	}
+	return db.Ctx(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "script_id"}, {Name: "op"}, {Name: "date"}},
+		DoUpdates: clause.AssignmentColumns([]string{"pv", "uv"}),
+	}).Create(list).Error
+}
+
+func (s *statisticsDailyRepo) FindRange(ctx context.Context, scriptId int64, op ScriptStatisticsType, start, end string) ([]*statistics_entity.StatisticsDaily, error) {
+	var list []*statistics_entity.StatisticsDaily
+	if err := db.Ctx(ctx).Where("script_id=? and op=? and date>=? and date<=?", scriptId, op, start, end).
+		Order("date").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (s *statisticsDailyRepo) SaveUA(ctx context.Context, list []*statistics_entity.StatisticsUaDaily) error {
+	if len(list) == 0 {
+		return nil
+	}
+	return db.Ctx(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "script_id"}, {Name: "op"}, {Name: "date"},
+			{Name: "dimension"}, {Name: "value"}},
+		DoUpdates: clause.AssignmentColumns([]string{"num"}),
+	}).CreateInBatches(list, 500).Error
+}
+
+func (s *statisticsDailyRepo) SumUARange(ctx context.Context, scriptId int64, ops []ScriptStatisticsType, start, end string) ([]*statistics_entity.StatisticsUaDaily, error) {
+	var list []*statistics_entity.StatisticsUaDaily
+	if err := db.Ctx(ctx).Model(&statistics_entity.StatisticsUaDaily{}).
+		Select("dimension, value, sum(num) as num").
+		Where("script_id=? and op in ? and date>=? and date<=?", scriptId, ops, start, end).
+		Group("dimension, value").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (s *statisticsDailyRepo) FindUARange(ctx context.Context, scriptId int64, start, end string) ([]*statistics_entity.StatisticsUaDaily, error) {
+	var list []*statistics_entity.StatisticsUaDaily
+	if err := db.Ctx(ctx).Where("script_id=? and date>=? and date<=?", scriptId, start, end).
+		Order("date, op, dimension, num desc").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (s *statisticsDailyRepo) FindUADimension(ctx context.Context, scriptId int64, op ScriptStatisticsType, dimension, start, end string) ([]*statistics_entity.StatisticsUaDaily, error) {
+	var list []*statistics_entity.StatisticsUaDaily
+	if err := db.Ctx(ctx).Where("script_id=? and op=? and dimension=? and date>=? and date<=?", scriptId, op, dimension, start, end).
+		Order("date").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (s *statisticsDailyRepo) SaveSource(ctx context.Context, list []*statistics_entity.StatisticsSourceDaily) error {
+	if len(list) == 0 {
+		return nil
+	}
+	return db.Ctx(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "script_id"}, {Name: "date"},
+			{Name: "dimension"}, {Name: "value"}},
+		DoUpdates: clause.AssignmentColumns([]string{"num"}),
+	}).CreateInBatches(list, 500).Error
+}
+
+func (s *statisticsDailyRepo) SumSourceRange(ctx context.Context, scriptId int64, start, end string) ([]*statistics_entity.StatisticsSourceDaily, error) {
+	var list []*statistics_entity.StatisticsSourceDaily
+	if err := db.Ctx(ctx).Model(&statistics_entity.StatisticsSourceDaily{}).
+		Select("dimension, value, sum(num) as num").
+		Where("script_id=? and date>=? and date<=?", scriptId, start, end).
+		Group("dimension, value").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (s *statisticsDailyRepo) FindSourceRange(ctx context.Context, scriptId int64, start, end string) ([]*statistics_entity.StatisticsSourceDaily, error) {
+	var list []*statistics_entity.StatisticsSourceDaily
+	if err := db.Ctx(ctx).Where("script_id=? and date>=? and date<=?", scriptId, start, end).
+		Order("date, dimension, num desc").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (s *statisticsDailyRepo) SumByDate(ctx context.Context, scriptIds []int64, op ScriptStatisticsType, start, end string) ([]*statistics_entity.StatisticsDaily, error) {
+	var list []*statistics_entity.StatisticsDaily
+	if len(scriptIds) == 0 {
+		return list, nil
+	}
+	if err := db.Ctx(ctx).Model(&statistics_entity.StatisticsDaily{}).
+		Select("date, sum(pv) as pv, sum(uv) as uv").
+		Where("script_id in ? and op=? and date>=? and date<=?", scriptIds, op, start, end).
+		Group("date").Order("date").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (s *statisticsDailyRepo) SumByScript(ctx context.Context, scriptIds []int64, op ScriptStatisticsType, start, end string) ([]*statistics_entity.StatisticsDaily, error) {
+	var list []*statistics_entity.StatisticsDaily
+	if len(scriptIds) == 0 {
+		return list, nil
+	}
+	if err := db.Ctx(ctx).Model(&statistics_entity.StatisticsDaily{}).
+		Select("script_id, sum(pv) as pv, sum(uv) as uv").
+		Where("script_id in ? and op=? and date>=? and date<=?", scriptIds, op, start, end).
+		Group("script_id").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
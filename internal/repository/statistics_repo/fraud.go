@@ -0,0 +1,152 @@
+package statistics_repo
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/cago-frame/cago/database/redis"
+	redis2 "github.com/redis/go-redis/v9"
+)
+
+// hash statistics:fraud:@id:@date total,impossible @num 2天过期
+// zset statistics:fraud:@id:@date:ipnet @ipnet @num 2天过期
+// zset statistics:fraud:@id:@date:token @token @num 2天过期
+// hash statistics:fraud:baseline @id @avg 永不过期
+// string statistics:fraud:flag:@id:@date 2天过期
+
+//go:generate mockgen -source=./fraud.go -destination=./mock/fraud.go
+
+// FraudStat 脚本当日下载的异常检测数据
+// Record返回的是本次下载所在ip段与token的数量, DayStat返回的是当日最多的ip段与token的数量
+type FraudStat struct {
+	Total      int64 // 当日下载量
+	IPNet      string
+	IPNetNum   int64
+	TokenNum   int64
+	Impossible int64 // 不可能的ua组合数量
+}
+
+// FraudRepo 刷量检测使用的实时数据
+type FraudRepo interface {
+	// Record 记录一次计入统计的下载, 返回当日累计的检测数据
+	Record(ctx context.Context, scriptId int64, t time.Time, ipNet, token string, impossible bool) (*FraudStat, error)
+	// DayStat 获取某一天的检测数据
+	DayStat(ctx context.Context, scriptId int64, t time.Time) (*FraudStat, error)
+	// Baseline 脚本近期的日均下载量, 由每日任务计算
+	Baseline(ctx context.Context, scriptId int64) (float64, error)
+	// SetBaseline 设置日均下载量, 为0时删除
+	SetBaseline(ctx context.Context, scriptId int64, baseline float64) error
+	// TryFlag 每个脚本每天只标记一次, 返回是否可以标记
+	TryFlag(ctx context.Context, scriptId int64, t time.Time) (bool, error)
+}
+
+var defaultFraud FraudRepo
+
+func Fraud() FraudRepo {
+	return defaultFraud
+}
+
+func RegisterFraud(i FraudRepo) {
+	defaultFraud = i
+}
+
+type fraudRepo struct {
+}
+
+func NewFraud() FraudRepo {
+	return &fraudRepo{}
+}
+
+func (f *fraudRepo) key(scriptId int64, t time.Time) string {
+	return fmt.Sprintf("statistics:fraud:%d:%s", scriptId, t.Format("2006/01/02"))
+}
+
+func (f *fraudRepo) Record(ctx context.Context, scriptId int64, t time.Time, ipNet, token string, impossible bool) (*FraudStat, error) {
+	key := f.key(scriptId, t)
+	ret := &FraudStat{IPNet: ipNet}
+	var err error
+	if ret.Total, err = redis.Ctx(ctx).HIncrBy(key, "total", 1).Result(); err != nil {
+		return nil, err
+	}
+	if impossible {
+		if ret.Impossible, err = redis.Ctx(ctx).HIncrBy(key, "impossible", 1).Result(); err != nil {
+			return nil, err
+		}
+	} else if ret.Impossible, err = redis.Ctx(ctx).HGet(key, "impossible").Int64(); err != nil && !redis.Nil(err) {
+		return nil, err
+	}
+	if ipNet != "" {
+		num, err := redis.Ctx(ctx).ZAddArgsIncr(key+":ipnet", redis2.ZAddArgs{
+			Members: []redis2.Z{{Score: 1, Member: ipNet}},
+		}).Result()
+		if err != nil {
+			return nil, err
+		}
+		ret.IPNetNum = int64(num)
+	}
+	if token != "" {
+		num, err := redis.Ctx(ctx).ZAddArgsIncr(key+":token", redis2.ZAddArgs{
+			Members: []redis2.Z{{Score: 1, Member: token}},
+		}).Result()
+		if err != nil {
+			return nil, err
+		}
+		ret.TokenNum = int64(num)
+	}
+	for _, k := range []string{key, key + ":ipnet", key + ":token"} {
+		redis.Ctx(ctx).Expire(k, time.Hour*48)
+	}
+	return ret, nil
+}
+
+func (f *fraudRepo) DayStat(ctx context.Context, scriptId int64, t time.Time) (*FraudStat, error) {
+	key := f.key(scriptId, t)
+	ret := &FraudStat{}
+	result, err := redis.Ctx(ctx).HGetAll(key).Result()
+	if err != nil {
+		return nil, err
+	}
+	ret.Total, _ = strconv.ParseInt(result["total"], 10, 64)
+	ret.Impossible, _ = strconv.ParseInt(result["impossible"], 10, 64)
+	ipNet, err := redis.Ctx(ctx).ZRevRangeWithScores(key+":ipnet", 0, 0).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(ipNet) > 0 {
+		ret.IPNet, _ = ipNet[0].Member.(string)
+		ret.IPNetNum = int64(ipNet[0].Score)
+	}
+	token, err := redis.Ctx(ctx).ZRevRangeWithScores(key+":token", 0, 0).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(token) > 0 {
+		ret.TokenNum = int64(token[0].Score)
+	}
+	return ret, nil
+}
+
+func (f *fraudRepo) Baseline(ctx context.Context, scriptId int64) (float64, error) {
+	ret, err := redis.Ctx(ctx).HGet("statistics:fraud:baseline", strconv.FormatInt(scriptId, 10)).Float64()
+	if err != nil {
+		if redis.Nil(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return ret, nil
+}
+
+func (f *fraudRepo) SetBaseline(ctx context.Context, scriptId int64, baseline float64) error {
+	if baseline == 0 {
+		return redis.Ctx(ctx).HDel("statistics:fraud:baseline", strconv.FormatInt(scriptId, 10)).Err()
+	}
+	return redis.Ctx(ctx).HSet("statistics:fraud:baseline", strconv.FormatInt(scriptId, 10), baseline).Err()
+}
+
+func (f *fraudRepo) TryFlag(ctx context.Context, scriptId int64, t time.Time) (bool, error) {
+	return redis.Ctx(ctx).SetNX(fmt.Sprintf("statistics:fraud:flag:%d:%s", scriptId, t.Format("2006/01/02")),
+		"1", time.Hour*48).Result()
+}
@@ -0,0 +1,87 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: bot.go
+//
+// Generated by this command:
+//
+//	mockgen -source=bot.go -destination=mock/bot.go
+//
+
+// Package mock_statistics_repo is a generated GoMock package.
+package mock_statistics_repo
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	statistics_repo "github.com/scriptscat/scriptlist/internal/repository/statistics_repo"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockBotRepo is a mock of BotRepo interface.
+type MockBotRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockBotRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockBotRepoMockRecorder is the mock recorder for MockBotRepo.
+type MockBotRepoMockRecorder struct {
+	mock *MockBotRepo
+}
+
+// NewMockBotRepo creates a new mock instance.
+func NewMockBotRepo(ctrl *gomock.Controller) *MockBotRepo {
+	mock := &MockBotRepo{ctrl: ctrl}
+	mock.recorder = &MockBotRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBotRepo) EXPECT() *MockBotRepoMockRecorder {
+	return m.recorder
+}
+
+// DayFiltered mocks base method.
+func (m *MockBotRepo) DayFiltered(ctx context.Context, scriptId int64, t time.Time) (map[string]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DayFiltered", ctx, scriptId, t)
+	ret0, _ := ret[0].(map[string]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DayFiltered indicates an expected call of DayFiltered.
+func (mr *MockBotRepoMockRecorder) DayFiltered(ctx, scriptId, t any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DayFiltered", reflect.TypeOf((*MockBotRepo)(nil).DayFiltered), ctx, scriptId, t)
+}
+
+// IncrFiltered mocks base method.
+func (m *MockBotRepo) IncrFiltered(ctx context.Context, scriptId int64, op statistics_repo.ScriptStatisticsType, reason string, t time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrFiltered", ctx, scriptId, op, reason, t)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IncrFiltered indicates an expected call of IncrFiltered.
+func (mr *MockBotRepoMockRecorder) IncrFiltered(ctx, scriptId, op, reason, t any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrFiltered", reflect.TypeOf((*MockBotRepo)(nil).IncrFiltered), ctx, scriptId, op, reason, t)
+}
+
+// IncrNoCookie mocks base method.
+func (m *MockBotRepo) IncrNoCookie(ctx context.Context, ip string, t time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrNoCookie", ctx, ip, t)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IncrNoCookie indicates an expected call of IncrNoCookie.
+func (mr *MockBotRepoMockRecorder) IncrNoCookie(ctx, ip, t any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrNoCookie", reflect.TypeOf((*MockBotRepo)(nil).IncrNoCookie), ctx, ip, t)
+}
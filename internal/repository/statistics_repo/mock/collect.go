@@ -0,0 +1,190 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: collect.go
+//
+// Generated by this command:
+//
+//	mockgen -source=collect.go -destination=mock/collect.go
+//
+
+// Package mock_statistics_repo is a generated GoMock package.
+package mock_statistics_repo
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	statistics_entity "github.com/scriptscat/scriptlist/internal/model/entity/statistics_entity"
+	statistics_repo "github.com/scriptscat/scriptlist/internal/repository/statistics_repo"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockStatisticsCollectRepo is a mock of StatisticsCollectRepo interface.
+type MockStatisticsCollectRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockStatisticsCollectRepoMockRecorder
+}
+
+// MockStatisticsCollectRepoMockRecorder is the mock recorder for MockStatisticsCollectRepo.
+type MockStatisticsCollectRepoMockRecorder struct {
+	mock *MockStatisticsCollectRepo
+}
+
+// NewMockStatisticsCollectRepo creates a new mock instance.
+func NewMockStatisticsCollectRepo(ctrl *gomock.Controller) *MockStatisticsCollectRepo {
+	mock := &MockStatisticsCollectRepo{ctrl: ctrl}
+	mock.recorder = &MockStatisticsCollectRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStatisticsCollectRepo) EXPECT() *MockStatisticsCollectRepoMockRecorder {
+	return m.recorder
+}
+
+// BatchInsertCollect mocks base method.
+func (m *MockStatisticsCollectRepo) BatchInsertCollect(ctx context.Context, list []*statistics_entity.StatisticsCollect) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BatchInsertCollect", ctx, list)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BatchInsertCollect indicates an expected call of BatchInsertCollect.
+func (mr *MockStatisticsCollectRepoMockRecorder) BatchInsertCollect(ctx, list any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchInsertCollect", reflect.TypeOf((*MockStatisticsCollectRepo)(nil).BatchInsertCollect), ctx, list)
+}
+
+// BatchInsertVisitor mocks base method.
+func (m *MockStatisticsCollectRepo) BatchInsertVisitor(ctx context.Context, list []*statistics_entity.StatisticsVisitor) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BatchInsertVisitor", ctx, list)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BatchInsertVisitor indicates an expected call of BatchInsertVisitor.
+func (mr *MockStatisticsCollectRepoMockRecorder) BatchInsertVisitor(ctx, list any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchInsertVisitor", reflect.TypeOf((*MockStatisticsCollectRepo)(nil).BatchInsertVisitor), ctx, list)
+}
+
+// DurationTrend mocks base method.
+func (m *MockStatisticsCollectRepo) DurationTrend(ctx context.Context, scriptId int64, start, end time.Time) ([]*statistics_repo.DurationTrend, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DurationTrend", ctx, scriptId, start, end)
+	ret0, _ := ret[0].([]*statistics_repo.DurationTrend)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DurationTrend indicates an expected call of DurationTrend.
+func (mr *MockStatisticsCollectRepoMockRecorder) DurationTrend(ctx, scriptId, start, end any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DurationTrend", reflect.TypeOf((*MockStatisticsCollectRepo)(nil).DurationTrend), ctx, scriptId, start, end)
+}
+
+// MarkVisitors mocks base method.
+func (m *MockStatisticsCollectRepo) MarkVisitors(ctx context.Context, list []*statistics_entity.StatisticsVisitor) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkVisitors", ctx, list)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkVisitors indicates an expected call of MarkVisitors.
+func (mr *MockStatisticsCollectRepoMockRecorder) MarkVisitors(ctx, list any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkVisitors", reflect.TypeOf((*MockStatisticsCollectRepo)(nil).MarkVisitors), ctx, list)
+}
+
+// NewVisitors mocks base method.
+func (m *MockStatisticsCollectRepo) NewVisitors(ctx context.Context, list []*statistics_entity.StatisticsVisitor) ([]bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewVisitors", ctx, list)
+	ret0, _ := ret[0].([]bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NewVisitors indicates an expected call of NewVisitors.
+func (mr *MockStatisticsCollectRepoMockRecorder) NewVisitors(ctx, list any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewVisitors", reflect.TypeOf((*MockStatisticsCollectRepo)(nil).NewVisitors), ctx, list)
+}
+
+// PopBuffer mocks base method.
+func (m *MockStatisticsCollectRepo) PopBuffer(ctx context.Context, limit int) ([]*statistics_repo.CollectBuffer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PopBuffer", ctx, limit)
+	ret0, _ := ret[0].([]*statistics_repo.CollectBuffer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PopBuffer indicates an expected call of PopBuffer.
+func (mr *MockStatisticsCollectRepoMockRecorder) PopBuffer(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PopBuffer", reflect.TypeOf((*MockStatisticsCollectRepo)(nil).PopBuffer), ctx, limit)
+}
+
+// PushBuffer mocks base method.
+func (m *MockStatisticsCollectRepo) PushBuffer(ctx context.Context, list []*statistics_repo.CollectBuffer) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PushBuffer", ctx, list)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PushBuffer indicates an expected call of PushBuffer.
+func (mr *MockStatisticsCollectRepoMockRecorder) PushBuffer(ctx, list any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PushBuffer", reflect.TypeOf((*MockStatisticsCollectRepo)(nil).PushBuffer), ctx, list)
+}
+
+// TopHost mocks base method.
+func (m *MockStatisticsCollectRepo) TopHost(ctx context.Context, scriptId int64, start, end time.Time, limit int) ([]*statistics_repo.CollectTop, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TopHost", ctx, scriptId, start, end, limit)
+	ret0, _ := ret[0].([]*statistics_repo.CollectTop)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TopHost indicates an expected call of TopHost.
+func (mr *MockStatisticsCollectRepoMockRecorder) TopHost(ctx, scriptId, start, end, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TopHost", reflect.TypeOf((*MockStatisticsCollectRepo)(nil).TopHost), ctx, scriptId, start, end, limit)
+}
+
+// TopPage mocks base method.
+func (m *MockStatisticsCollectRepo) TopPage(ctx context.Context, scriptId int64, start, end time.Time, limit int) ([]*statistics_repo.CollectTop, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TopPage", ctx, scriptId, start, end, limit)
+	ret0, _ := ret[0].([]*statistics_repo.CollectTop)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TopPage indicates an expected call of TopPage.
+func (mr *MockStatisticsCollectRepoMockRecorder) TopPage(ctx, scriptId, start, end, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TopPage", reflect.TypeOf((*MockStatisticsCollectRepo)(nil).TopPage), ctx, scriptId, start, end, limit)
+}
+
+// VisitorTrend mocks base method.
+func (m *MockStatisticsCollectRepo) VisitorTrend(ctx context.Context, scriptId int64, start, end time.Time) ([]*statistics_repo.VisitorTrend, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VisitorTrend", ctx, scriptId, start, end)
+	ret0, _ := ret[0].([]*statistics_repo.VisitorTrend)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VisitorTrend indicates an expected call of VisitorTrend.
+func (mr *MockStatisticsCollectRepoMockRecorder) VisitorTrend(ctx, scriptId, start, end any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VisitorTrend", reflect.TypeOf((*MockStatisticsCollectRepo)(nil).VisitorTrend), ctx, scriptId, start, end)
+}
@@ -0,0 +1,205 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: daily.go
+//
+// Generated by this command:
+//
+//	mockgen -source=daily.go -destination=mock/daily.go
+//
+
+// Package mock_statistics_repo is a generated GoMock package.
+package mock_statistics_repo
+
+import (
+	context "context"
+	reflect "reflect"
+
+	statistics_entity "github.com/scriptscat/scriptlist/internal/model/entity/statistics_entity"
+	statistics_repo "github.com/scriptscat/scriptlist/internal/repository/statistics_repo"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockStatisticsDailyRepo is a mock of StatisticsDailyRepo interface.
+type MockStatisticsDailyRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockStatisticsDailyRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockStatisticsDailyRepoMockRecorder is the mock recorder for MockStatisticsDailyRepo.
+type MockStatisticsDailyRepoMockRecorder struct {
+	mock *MockStatisticsDailyRepo
+}
+
+// NewMockStatisticsDailyRepo creates a new mock instance.
+func NewMockStatisticsDailyRepo(ctrl *gomock.Controller) *MockStatisticsDailyRepo {
+	mock := &MockStatisticsDailyRepo{ctrl: ctrl}
+	mock.recorder = &MockStatisticsDailyRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStatisticsDailyRepo) EXPECT() *MockStatisticsDailyRepoMockRecorder {
+	return m.recorder
+}
+
+// FindRange mocks base method.
+func (m *MockStatisticsDailyRepo) FindRange(ctx context.Context, scriptId int64, op statistics_repo.ScriptStatisticsType, start, end string) ([]*statistics_entity.StatisticsDaily, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindRange", ctx, scriptId, op, start, end)
+	ret0, _ := ret[0].([]*statistics_entity.StatisticsDaily)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindRange indicates an expected call of FindRange.
+func (mr *MockStatisticsDailyRepoMockRecorder) FindRange(ctx, scriptId, op, start, end any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindRange", reflect.TypeOf((*MockStatisticsDailyRepo)(nil).FindRange), ctx, scriptId, op, start, end)
+}
+
+// FindSourceRange mocks base method.
+func (m *MockStatisticsDailyRepo) FindSourceRange(ctx context.Context, scriptId int64, start, end string) ([]*statistics_entity.StatisticsSourceDaily, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindSourceRange", ctx, scriptId, start, end)
+	ret0, _ := ret[0].([]*statistics_entity.StatisticsSourceDaily)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindSourceRange indicates an expected call of FindSourceRange.
+func (mr *MockStatisticsDailyRepoMockRecorder) FindSourceRange(ctx, scriptId, start, end any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindSourceRange", reflect.TypeOf((*MockStatisticsDailyRepo)(nil).FindSourceRange), ctx, scriptId, start, end)
+}
+
+// FindUADimension mocks base method.
+func (m *MockStatisticsDailyRepo) FindUADimension(ctx context.Context, scriptId int64, op statistics_repo.ScriptStatisticsType, dimension, start, end string) ([]*statistics_entity.StatisticsUaDaily, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindUADimension", ctx, scriptId, op, dimension, start, end)
+	ret0, _ := ret[0].([]*statistics_entity.StatisticsUaDaily)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindUADimension indicates an expected call of FindUADimension.
+func (mr *MockStatisticsDailyRepoMockRecorder) FindUADimension(ctx, scriptId, op, dimension, start, end any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindUADimension", reflect.TypeOf((*MockStatisticsDailyRepo)(nil).FindUADimension), ctx, scriptId, op, dimension, start, end)
+}
+
+// FindUARange mocks base method.
+func (m *MockStatisticsDailyRepo) FindUARange(ctx context.Context, scriptId int64, start, end string) ([]*statistics_entity.StatisticsUaDaily, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindUARange", ctx, scriptId, start, end)
+	ret0, _ := ret[0].([]*statistics_entity.StatisticsUaDaily)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindUARange indicates an expected call of FindUARange.
+func (mr *MockStatisticsDailyRepoMockRecorder) FindUARange(ctx, scriptId, start, end any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindUARange", reflect.TypeOf((*MockStatisticsDailyRepo)(nil).FindUARange), ctx, scriptId, start, end)
+}
+
+// Save mocks base method.
+func (m *MockStatisticsDailyRepo) Save(ctx context.Context, list []*statistics_entity.StatisticsDaily) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, list)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockStatisticsDailyRepoMockRecorder) Save(ctx, list any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockStatisticsDailyRepo)(nil).Save), ctx, list)
+}
+
+// SaveSource mocks base method.
+func (m *MockStatisticsDailyRepo) SaveSource(ctx context.Context, list []*statistics_entity.StatisticsSourceDaily) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveSource", ctx, list)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveSource indicates an expected call of SaveSource.
+func (mr *MockStatisticsDailyRepoMockRecorder) SaveSource(ctx, list any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveSource", reflect.TypeOf((*MockStatisticsDailyRepo)(nil).SaveSource), ctx, list)
+}
+
+// SaveUA mocks base method.
+func (m *MockStatisticsDailyRepo) SaveUA(ctx context.Context, list []*statistics_entity.StatisticsUaDaily) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveUA", ctx, list)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveUA indicates an expected call of SaveUA.
+func (mr *MockStatisticsDailyRepoMockRecorder) SaveUA(ctx, list any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveUA", reflect.TypeOf((*MockStatisticsDailyRepo)(nil).SaveUA), ctx, list)
+}
+
+// SumByDate mocks base method.
+func (m *MockStatisticsDailyRepo) SumByDate(ctx context.Context, scriptIds []int64, op statistics_repo.ScriptStatisticsType, start, end string) ([]*statistics_entity.StatisticsDaily, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SumByDate", ctx, scriptIds, op, start, end)
+	ret0, _ := ret[0].([]*statistics_entity.StatisticsDaily)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SumByDate indicates an expected call of SumByDate.
+func (mr *MockStatisticsDailyRepoMockRecorder) SumByDate(ctx, scriptIds, op, start, end any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumByDate", reflect.TypeOf((*MockStatisticsDailyRepo)(nil).SumByDate), ctx, scriptIds, op, start, end)
+}
+
+// SumByScript mocks base method.
+func (m *MockStatisticsDailyRepo) SumByScript(ctx context.Context, scriptIds []int64, op statistics_repo.ScriptStatisticsType, start, end string) ([]*statistics_entity.StatisticsDaily, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SumByScript", ctx, scriptIds, op, start, end)
+	ret0, _ := ret[0].([]*statistics_entity.StatisticsDaily)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SumByScript indicates an expected call of SumByScript.
+func (mr *MockStatisticsDailyRepoMockRecorder) SumByScript(ctx, scriptIds, op, start, end any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumByScript", reflect.TypeOf((*MockStatisticsDailyRepo)(nil).SumByScript), ctx, scriptIds, op, start, end)
+}
+
+// SumSourceRange mocks base method.
+func (m *MockStatisticsDailyRepo) SumSourceRange(ctx context.Context, scriptId int64, start, end string) ([]*statistics_entity.StatisticsSourceDaily, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SumSourceRange", ctx, scriptId, start, end)
+	ret0, _ := ret[0].([]*statistics_entity.StatisticsSourceDaily)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SumSourceRange indicates an expected call of SumSourceRange.
+func (mr *MockStatisticsDailyRepoMockRecorder) SumSourceRange(ctx, scriptId, start, end any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumSourceRange", reflect.TypeOf((*MockStatisticsDailyRepo)(nil).SumSourceRange), ctx, scriptId, start, end)
+}
+
+// SumUARange mocks base method.
+func (m *MockStatisticsDailyRepo) SumUARange(ctx context.Context, scriptId int64, ops []statistics_repo.ScriptStatisticsType, start, end string) ([]*statistics_entity.StatisticsUaDaily, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SumUARange", ctx, scriptId, ops, start, end)
+	ret0, _ := ret[0].([]*statistics_entity.StatisticsUaDaily)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SumUARange indicates an expected call of SumUARange.
+func (mr *MockStatisticsDailyRepoMockRecorder) SumUARange(ctx, scriptId, ops, start, end any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumUARange", reflect.TypeOf((*MockStatisticsDailyRepo)(nil).SumUARange), ctx, scriptId, ops, start, end)
+}
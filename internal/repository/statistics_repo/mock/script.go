@@ -0,0 +1,220 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./script.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./script.go -destination=./mock/script.go
+//
+
+// Package mock_statistics_repo is a generated GoMock package.
+package mock_statistics_repo
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	statistics_repo "github.com/scriptscat/scriptlist/internal/repository/statistics_repo"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockScriptStatisticsRepo is a mock of ScriptStatisticsRepo interface.
+type MockScriptStatisticsRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockScriptStatisticsRepoMockRecorder
+}
+
+// MockScriptStatisticsRepoMockRecorder is the mock recorder for MockScriptStatisticsRepo.
+type MockScriptStatisticsRepoMockRecorder struct {
+	mock *MockScriptStatisticsRepo
+}
+
+// NewMockScriptStatisticsRepo creates a new mock instance.
+func NewMockScriptStatisticsRepo(ctrl *gomock.Controller) *MockScriptStatisticsRepo {
+	mock := &MockScriptStatisticsRepo{ctrl: ctrl}
+	mock.recorder = &MockScriptStatisticsRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockScriptStatisticsRepo) EXPECT() *MockScriptStatisticsRepoMockRecorder {
+	return m.recorder
+}
+
+// DayBatch mocks base method.
+func (m *MockScriptStatisticsRepo) DayBatch(ctx context.Context, scriptIds []int64, ops []statistics_repo.ScriptStatisticsType, t time.Time) ([]*statistics_repo.DayStatistics, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DayBatch", ctx, scriptIds, ops, t)
+	ret0, _ := ret[0].([]*statistics_repo.DayStatistics)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DayBatch indicates an expected call of DayBatch.
+func (mr *MockScriptStatisticsRepoMockRecorder) DayBatch(ctx, scriptIds, ops, t any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DayBatch", reflect.TypeOf((*MockScriptStatisticsRepo)(nil).DayBatch), ctx, scriptIds, ops, t)
+}
+
+// DaySource mocks base method.
+func (m *MockScriptStatisticsRepo) DaySource(ctx context.Context, scriptId int64, op statistics_repo.ScriptStatisticsType, t time.Time) (map[string]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DaySource", ctx, scriptId, op, t)
+	ret0, _ := ret[0].(map[string]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DaySource indicates an expected call of DaySource.
+func (mr *MockScriptStatisticsRepoMockRecorder) DaySource(ctx, scriptId, op, t any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DaySource", reflect.TypeOf((*MockScriptStatisticsRepo)(nil).DaySource), ctx, scriptId, op, t)
+}
+
+// DayUA mocks base method.
+func (m *MockScriptStatisticsRepo) DayUA(ctx context.Context, scriptId int64, op statistics_repo.ScriptStatisticsType, t time.Time) (map[string]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DayUA", ctx, scriptId, op, t)
+	ret0, _ := ret[0].(map[string]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DayUA indicates an expected call of DayUA.
+func (mr *MockScriptStatisticsRepoMockRecorder) DayUA(ctx, scriptId, op, t any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DayUA", reflect.TypeOf((*MockScriptStatisticsRepo)(nil).DayUA), ctx, scriptId, op, t)
+}
+
+// DaysPvNum mocks base method.
+func (m *MockScriptStatisticsRepo) DaysPvNum(ctx context.Context, scriptId int64, op statistics_repo.ScriptStatisticsType, days int, t time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DaysPvNum", ctx, scriptId, op, days, t)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DaysPvNum indicates an expected call of DaysPvNum.
+func (mr *MockScriptStatisticsRepoMockRecorder) DaysPvNum(ctx, scriptId, op, days, t any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DaysPvNum", reflect.TypeOf((*MockScriptStatisticsRepo)(nil).DaysPvNum), ctx, scriptId, op, days, t)
+}
+
+// DaysUvNum mocks base method.
+func (m *MockScriptStatisticsRepo) DaysUvNum(ctx context.Context, scriptId int64, op statistics_repo.ScriptStatisticsType, days int, t time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DaysUvNum", ctx, scriptId, op, days, t)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DaysUvNum indicates an expected call of DaysUvNum.
+func (mr *MockScriptStatisticsRepoMockRecorder) DaysUvNum(ctx, scriptId, op, days, t any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DaysUvNum", reflect.TypeOf((*MockScriptStatisticsRepo)(nil).DaysUvNum), ctx, scriptId, op, days, t)
+}
+
+// IncrDownload mocks base method.
+func (m *MockScriptStatisticsRepo) IncrDownload(ctx context.Context, scriptId int64, ip, statisticsToken string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrDownload", ctx, scriptId, ip, statisticsToken)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IncrDownload indicates an expected call of IncrDownload.
+func (mr *MockScriptStatisticsRepoMockRecorder) IncrDownload(ctx, scriptId, ip, statisticsToken any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrDownload", reflect.TypeOf((*MockScriptStatisticsRepo)(nil).IncrDownload), ctx, scriptId, ip, statisticsToken)
+}
+
+// IncrPageView mocks base method.
+func (m *MockScriptStatisticsRepo) IncrPageView(ctx context.Context, scriptId int64, ip, statisticsToken string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrPageView", ctx, scriptId, ip, statisticsToken)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IncrPageView indicates an expected call of IncrPageView.
+func (mr *MockScriptStatisticsRepoMockRecorder) IncrPageView(ctx, scriptId, ip, statisticsToken any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrPageView", reflect.TypeOf((*MockScriptStatisticsRepo)(nil).IncrPageView), ctx, scriptId, ip, statisticsToken)
+}
+
+// IncrSource mocks base method.
+func (m *MockScriptStatisticsRepo) IncrSource(ctx context.Context, scriptId int64, op statistics_repo.ScriptStatisticsType, t time.Time, dimensions map[string]string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrSource", ctx, scriptId, op, t, dimensions)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IncrSource indicates an expected call of IncrSource.
+func (mr *MockScriptStatisticsRepoMockRecorder) IncrSource(ctx, scriptId, op, t, dimensions any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrSource", reflect.TypeOf((*MockScriptStatisticsRepo)(nil).IncrSource), ctx, scriptId, op, t, dimensions)
+}
+
+// IncrUA mocks base method.
+func (m *MockScriptStatisticsRepo) IncrUA(ctx context.Context, scriptId int64, op statistics_repo.ScriptStatisticsType, t time.Time, dimensions map[string]string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrUA", ctx, scriptId, op, t, dimensions)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IncrUA indicates an expected call of IncrUA.
+func (mr *MockScriptStatisticsRepoMockRecorder) IncrUA(ctx, scriptId, op, t, dimensions any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrUA", reflect.TypeOf((*MockScriptStatisticsRepo)(nil).IncrUA), ctx, scriptId, op, t, dimensions)
+}
+
+// IncrUpdate mocks base method.
+func (m *MockScriptStatisticsRepo) IncrUpdate(ctx context.Context, scriptId int64, ip, statisticsToken string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrUpdate", ctx, scriptId, ip, statisticsToken)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IncrUpdate indicates an expected call of IncrUpdate.
+func (mr *MockScriptStatisticsRepoMockRecorder) IncrUpdate(ctx, scriptId, ip, statisticsToken any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrUpdate", reflect.TypeOf((*MockScriptStatisticsRepo)(nil).IncrUpdate), ctx, scriptId, ip, statisticsToken)
+}
+
+// Realtime mocks base method.
+func (m *MockScriptStatisticsRepo) Realtime(ctx context.Context, scriptId int64, op statistics_repo.ScriptStatisticsType) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Realtime", ctx, scriptId, op)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Realtime indicates an expected call of Realtime.
+func (mr *MockScriptStatisticsRepoMockRecorder) Realtime(ctx, scriptId, op any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Realtime", reflect.TypeOf((*MockScriptStatisticsRepo)(nil).Realtime), ctx, scriptId, op)
+}
+
+// TotalPv mocks base method.
+func (m *MockScriptStatisticsRepo) TotalPv(ctx context.Context, scriptId int64, op statistics_repo.ScriptStatisticsType) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TotalPv", ctx, scriptId, op)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TotalPv indicates an expected call of TotalPv.
+func (mr *MockScriptStatisticsRepoMockRecorder) TotalPv(ctx, scriptId, op any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TotalPv", reflect.TypeOf((*MockScriptStatisticsRepo)(nil).TotalPv), ctx, scriptId, op)
+}
@@ -0,0 +1,191 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./script_error.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./script_error.go -destination=./mock/script_error.go
+//
+
+// Package mock_statistics_repo is a generated GoMock package.
+package mock_statistics_repo
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	httputils "github.com/cago-frame/cago/pkg/utils/httputils"
+	statistics_entity "github.com/scriptscat/scriptlist/internal/model/entity/statistics_entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockScriptErrorRepo is a mock of ScriptErrorRepo interface.
+type MockScriptErrorRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockScriptErrorRepoMockRecorder
+}
+
+// MockScriptErrorRepoMockRecorder is the mock recorder for MockScriptErrorRepo.
+type MockScriptErrorRepoMockRecorder struct {
+	mock *MockScriptErrorRepo
+}
+
+// NewMockScriptErrorRepo creates a new mock instance.
+func NewMockScriptErrorRepo(ctrl *gomock.Controller) *MockScriptErrorRepo {
+	mock := &MockScriptErrorRepo{ctrl: ctrl}
+	mock.recorder = &MockScriptErrorRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockScriptErrorRepo) EXPECT() *MockScriptErrorRepoMockRecorder {
+	return m.recorder
+}
+
+// BindIssue mocks base method.
+func (m *MockScriptErrorRepo) BindIssue(ctx context.Context, id, issueId int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BindIssue", ctx, id, issueId)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BindIssue indicates an expected call of BindIssue.
+func (mr *MockScriptErrorRepoMockRecorder) BindIssue(ctx, id, issueId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BindIssue", reflect.TypeOf((*MockScriptErrorRepo)(nil).BindIssue), ctx, id, issueId)
+}
+
+// CountGroup mocks base method.
+func (m *MockScriptErrorRepo) CountGroup(ctx context.Context, scriptId int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountGroup", ctx, scriptId)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountGroup indicates an expected call of CountGroup.
+func (mr *MockScriptErrorRepoMockRecorder) CountGroup(ctx, scriptId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountGroup", reflect.TypeOf((*MockScriptErrorRepo)(nil).CountGroup), ctx, scriptId)
+}
+
+// CreateGroup mocks base method.
+func (m *MockScriptErrorRepo) CreateGroup(ctx context.Context, group *statistics_entity.ScriptErrorGroup) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateGroup", ctx, group)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateGroup indicates an expected call of CreateGroup.
+func (mr *MockScriptErrorRepoMockRecorder) CreateGroup(ctx, group any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateGroup", reflect.TypeOf((*MockScriptErrorRepo)(nil).CreateGroup), ctx, group)
+}
+
+// FindGroup mocks base method.
+func (m *MockScriptErrorRepo) FindGroup(ctx context.Context, scriptId, id int64) (*statistics_entity.ScriptErrorGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindGroup", ctx, scriptId, id)
+	ret0, _ := ret[0].(*statistics_entity.ScriptErrorGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindGroup indicates an expected call of FindGroup.
+func (mr *MockScriptErrorRepoMockRecorder) FindGroup(ctx, scriptId, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindGroup", reflect.TypeOf((*MockScriptErrorRepo)(nil).FindGroup), ctx, scriptId, id)
+}
+
+// FindGroupByFingerprint mocks base method.
+func (m *MockScriptErrorRepo) FindGroupByFingerprint(ctx context.Context, scriptId int64, fingerprint string) (*statistics_entity.ScriptErrorGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindGroupByFingerprint", ctx, scriptId, fingerprint)
+	ret0, _ := ret[0].(*statistics_entity.ScriptErrorGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindGroupByFingerprint indicates an expected call of FindGroupByFingerprint.
+func (mr *MockScriptErrorRepoMockRecorder) FindGroupByFingerprint(ctx, scriptId, fingerprint any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindGroupByFingerprint", reflect.TypeOf((*MockScriptErrorRepo)(nil).FindGroupByFingerprint), ctx, scriptId, fingerprint)
+}
+
+// FindPage mocks base method.
+func (m *MockScriptErrorRepo) FindPage(ctx context.Context, scriptId int64, page httputils.PageRequest) ([]*statistics_entity.ScriptErrorGroup, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPage", ctx, scriptId, page)
+	ret0, _ := ret[0].([]*statistics_entity.ScriptErrorGroup)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FindPage indicates an expected call of FindPage.
+func (mr *MockScriptErrorRepoMockRecorder) FindPage(ctx, scriptId, page any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPage", reflect.TypeOf((*MockScriptErrorRepo)(nil).FindPage), ctx, scriptId, page)
+}
+
+// FindVersions mocks base method.
+func (m *MockScriptErrorRepo) FindVersions(ctx context.Context, groupId int64) ([]*statistics_entity.ScriptErrorVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindVersions", ctx, groupId)
+	ret0, _ := ret[0].([]*statistics_entity.ScriptErrorVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindVersions indicates an expected call of FindVersions.
+func (mr *MockScriptErrorRepoMockRecorder) FindVersions(ctx, groupId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindVersions", reflect.TypeOf((*MockScriptErrorRepo)(nil).FindVersions), ctx, groupId)
+}
+
+// IncrGroup mocks base method.
+func (m *MockScriptErrorRepo) IncrGroup(ctx context.Context, id, num int64, host, version string, t int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrGroup", ctx, id, num, host, version, t)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IncrGroup indicates an expected call of IncrGroup.
+func (mr *MockScriptErrorRepoMockRecorder) IncrGroup(ctx, id, num, host, version, t any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrGroup", reflect.TypeOf((*MockScriptErrorRepo)(nil).IncrGroup), ctx, id, num, host, version, t)
+}
+
+// IncrMinute mocks base method.
+func (m *MockScriptErrorRepo) IncrMinute(ctx context.Context, scriptId int64, t time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrMinute", ctx, scriptId, t)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IncrMinute indicates an expected call of IncrMinute.
+func (mr *MockScriptErrorRepoMockRecorder) IncrMinute(ctx, scriptId, t any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrMinute", reflect.TypeOf((*MockScriptErrorRepo)(nil).IncrMinute), ctx, scriptId, t)
+}
+
+// IncrVersion mocks base method.
+func (m *MockScriptErrorRepo) IncrVersion(ctx context.Context, groupId int64, version string, num, t int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrVersion", ctx, groupId, version, num, t)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IncrVersion indicates an expected call of IncrVersion.
+func (mr *MockScriptErrorRepoMockRecorder) IncrVersion(ctx, groupId, version, num, t any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrVersion", reflect.TypeOf((*MockScriptErrorRepo)(nil).IncrVersion), ctx, groupId, version, num, t)
+}
@@ -0,0 +1,93 @@
+package statistics_repo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/cago-frame/cago/database/redis"
+	"github.com/cago-frame/cago/pkg/logger"
+	"github.com/scriptscat/scriptlist/internal/pkg/pubsub"
+	"go.uber.org/zap"
+)
+
+// pubsub statistics:script:realtime:channel:@id 脚本实时统计事件
+
+//go:generate mockgen -source=./realtime.go -destination=./mock/realtime.go
+
+// RealtimeEvent 实时统计事件, 脚本某一分钟内某操作的数量
+type RealtimeEvent struct {
+	Op     ScriptStatisticsType `json:"op"`
+	Minute int64                `json:"minute"` // unix时间/60
+	Num    int64                `json:"num"`
+}
+
+// RealtimeRepo 通过redis发布订阅分发脚本的实时统计, 使多个实例都能收到, 每个实例只使用一个模式订阅
+type RealtimeRepo interface {
+	// Publish 发布脚本当前分钟某操作的数量
+	Publish(ctx context.Context, scriptId int64, op ScriptStatisticsType) error
+	// Subscribe 订阅脚本的实时统计事件, ctx结束后关闭通道
+	Subscribe(ctx context.Context, scriptId int64) (<-chan *RealtimeEvent, error)
+}
+
+var defaultRealtime RealtimeRepo
+
+func Realtime() RealtimeRepo {
+	return defaultRealtime
+}
+
+func RegisterRealtime(i RealtimeRepo) {
+	defaultRealtime = i
+}
+
+type realtimeRepo struct {
+	hub *pubsub.Hub
+}
+
+func NewRealtime() RealtimeRepo {
+	return &realtimeRepo{
+		hub: pubsub.NewHub("statistics:script:realtime:channel:*"),
+	}
+}
+
+func (r *realtimeRepo) channel(scriptId int64) string {
+	return fmt.Sprintf("statistics:script:realtime:channel:%d", scriptId)
+}
+
+func (r *realtimeRepo) Publish(ctx context.Context, scriptId int64, op ScriptStatisticsType) error {
+	minute := time.Now().Unix() / 60
+	num, err := redis.Ctx(ctx).Get(realtimeKey(scriptId, op, minute)).Int64()
+	if err != nil && !redis.Nil(err) {
+		return err
+	}
+	data, err := json.Marshal(&RealtimeEvent{Op: op, Minute: minute, Num: num})
+	if err != nil {
+		return err
+	}
+	return redis.Ctx(ctx).Publish(ctx, r.channel(scriptId), data).Err()
+}
+
+func (r *realtimeRepo) Subscribe(ctx context.Context, scriptId int64) (<-chan *RealtimeEvent, error) {
+	ch, err := r.hub.Subscribe(ctx, r.channel(scriptId))
+	if err != nil {
+		return nil, err
+	}
+	ret := make(chan *RealtimeEvent)
+	go func() {
+		defer close(ret)
+		for payload := range ch {
+			event := &RealtimeEvent{}
+			if err := json.Unmarshal([]byte(payload), event); err != nil {
+				logger.Ctx(ctx).Error("解析实时统计事件失败", zap.Error(err), zap.String("payload", payload))
+				continue
+			}
+			select {
+			case ret <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ret, nil
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/cago-frame/cago/database/redis"
 	"github.com/cago-frame/cago/pkg/logger"
+	redis2 "github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 )
 
@@ -19,13 +20,18 @@ const (
 	UpdateScriptStatistics   ScriptStatisticsType = "update"
 )
 
-// pf   statistics:script:@op:@id:day:uv:@date 30天过期
+// pf   statistics:script:@op:@id:day:uv:@date 60天过期
 
 // hash statistics:script:@op:@id:day:pv @date @num 永不过期
 // hash statistics:script:@op:@id:total:pv 永不过期
 
 // set statistics:script:@op:@id:realtime:@time @num 一小时过期
 
+// hash statistics:script:@op:@id:day:ua:@date @dimension:@value @num 60天过期
+// hash statistics:script:@op:@id:day:source:@date @dimension:@value @num 60天过期
+
+//go:generate mockgen -source=./script.go -destination=./mock/script.go
+
 // ScriptStatisticsRepo 统计平台数据库操作,与脚本统计不同,此处的纬度更丰富,且大多记录在redis中
 type ScriptStatisticsRepo interface {
 	// Save 数据落库
@@ -45,6 +51,27 @@ type ScriptStatisticsRepo interface {
 	IncrDownload(ctx context.Context, scriptId int64, ip string, statisticsToken string) (bool, error)
 	IncrUpdate(ctx context.Context, scriptId int64, ip string, statisticsToken string) (bool, error)
 	IncrPageView(ctx context.Context, scriptId int64, ip string, statisticsToken string) (bool, error)
+	// IncrUA 记录ua各维度的数量, dimensions为维度与值
+	IncrUA(ctx context.Context, scriptId int64, op ScriptStatisticsType, t time.Time, dimensions map[string]string) error
+	// DayUA 获取某一天ua各维度的数量, key为 维度:值
+	DayUA(ctx context.Context, scriptId int64, op ScriptStatisticsType, t time.Time) (map[string]int64, error)
+	// IncrSource 记录安装来源各维度的数量, dimensions为维度与值
+	IncrSource(ctx context.Context, scriptId int64, op ScriptStatisticsType, t time.Time, dimensions map[string]string) error
+	// DaySource 获取某一天安装来源各维度的数量, key为 维度:值
+	DaySource(ctx context.Context, scriptId int64, op ScriptStatisticsType, t time.Time) (map[string]int64, error)
+	// DayBatch 通过pipeline批量获取多个脚本某一天各操作的数据, 浏览不读取ua, 只有下载读取安装来源
+	DayBatch(ctx context.Context, scriptIds []int64, ops []ScriptStatisticsType, t time.Time) ([]*DayStatistics, error)
+}
+
+// DayStatistics 脚本某一天某操作的数据, Err不为空时表示该脚本读取失败
+type DayStatistics struct {
+	ScriptID int64
+	Op       ScriptStatisticsType
+	Pv       int64
+	Uv       int64
+	UA       map[string]int64
+	Source   map[string]int64
+	Err      error
 }
 
 var defaultScriptStatistics ScriptStatisticsRepo
@@ -64,11 +91,16 @@ func NewScriptStatistics() ScriptStatisticsRepo {
 	return &scriptStatisticsRepo{}
 }
 
+// realtimeKey 实时统计的key, minute为unix时间/60
+func realtimeKey(scriptId int64, op ScriptStatisticsType, minute int64) string {
+	return fmt.Sprintf("statistics:script:%s:%d:realtime:%d", op, scriptId, minute)
+}
+
 func (s *scriptStatisticsRepo) Realtime(ctx context.Context, scriptId int64, op ScriptStatisticsType) ([]int64, error) {
 	var ret []int64
 	t := time.Now().Unix() / 60
 	for i := int64(0); i < 15; i++ {
-		num, _ := redis.Ctx(ctx).Get(fmt.Sprintf("statistics:script:%s:%d:realtime:%d", op, scriptId, t-i)).Int64()
+		num, _ := redis.Ctx(ctx).Get(realtimeKey(scriptId, op, t-i)).Int64()
 		ret = append(ret, num)
 	}
 	return ret, nil
@@ -164,9 +196,9 @@ func (s *scriptStatisticsRepo) save(ctx context.Context, scriptId int64, ip, sta
 	// 总pv
 	redis.Ctx(ctx).Incr(key + ":total:pv")
 	// 实时统计
-	t := strconv.FormatInt(time.Now().Unix()/60, 10)
-	redis.Ctx(ctx).Incr(key + ":realtime:" + t)
-	redis.Ctx(ctx).Expire(key+":realtime:"+t, time.Hour)
+	t := time.Now().Unix() / 60
+	redis.Ctx(ctx).Incr(realtimeKey(scriptId, op, t))
+	redis.Ctx(ctx).Expire(realtimeKey(scriptId, op, t), time.Hour)
 	// 判断ip是否操作过了
 	result, err := redis.Ctx(ctx).SetNX(key+":ip:exist:day:"+date+":"+ip, "1", time.Hour*16).Result()
 	if err != nil {
@@ -174,3 +206,106 @@ func (s *scriptStatisticsRepo) save(ctx context.Context, scriptId int64, ip, sta
 	}
 	return ok && result, nil
 }
+
+func (s *scriptStatisticsRepo) dimensionKey(scriptId int64, op ScriptStatisticsType, kind string, t time.Time) string {
+	return fmt.Sprintf("statistics:script:%s:%d:day:%s:%s", op, scriptId, kind, t.Format("2006/01/02"))
+}
+
+func (s *scriptStatisticsRepo) incrDimension(ctx context.Context, key string, dimensions map[string]string) error {
+	for dimension, value := range dimensions {
+		if err := redis.Ctx(ctx).HIncrBy(key, dimension+":"+value, 1).Err(); err != nil {
+			return err
+		}
+	}
+	return redis.Ctx(ctx).Expire(key, time.Hour*24*60).Err()
+}
+
+func (s *scriptStatisticsRepo) dayDimension(ctx context.Context, key string) (map[string]int64, error) {
+	return dimensionResult(redis.Ctx(ctx).HGetAll(key))
+}
+
+func (s *scriptStatisticsRepo) IncrUA(ctx context.Context, scriptId int64, op ScriptStatisticsType, t time.Time, dimensions map[string]string) error {
+	return s.incrDimension(ctx, s.dimensionKey(scriptId, op, "ua", t), dimensions)
+}
+
+func (s *scriptStatisticsRepo) DayUA(ctx context.Context, scriptId int64, op ScriptStatisticsType, t time.Time) (map[string]int64, error) {
+	return s.dayDimension(ctx, s.dimensionKey(scriptId, op, "ua", t))
+}
+
+func (s *scriptStatisticsRepo) IncrSource(ctx context.Context, scriptId int64, op ScriptStatisticsType, t time.Time, dimensions map[string]string) error {
+	return s.incrDimension(ctx, s.dimensionKey(scriptId, op, "source", t), dimensions)
+}
+
+func (s *scriptStatisticsRepo) DaySource(ctx context.Context, scriptId int64, op ScriptStatisticsType, t time.Time) (map[string]int64, error) {
+	return s.dayDimension(ctx, s.dimensionKey(scriptId, op, "source", t))
+}
+
+func (s *scriptStatisticsRepo) DayBatch(ctx context.Context, scriptIds []int64, ops []ScriptStatisticsType, t time.Time) ([]*DayStatistics, error) {
+	type dayCmd struct {
+		pv     *redis2.StringCmd
+		uv     *redis2.IntCmd
+		ua     *redis2.MapStringStringCmd
+		source *redis2.MapStringStringCmd
+	}
+	date := t.Format("2006/01/02")
+	pipe := redis.Default().Pipeline()
+	ret := make([]*DayStatistics, 0, len(scriptIds)*len(ops))
+	cmds := make([]*dayCmd, 0, len(scriptIds)*len(ops))
+	for _, id := range scriptIds {
+		for _, op := range ops {
+			cmd := &dayCmd{
+				pv: pipe.HGet(ctx, fmt.Sprintf("statistics:script:%s:%d:day:pv", op, id), date),
+				uv: pipe.PFCount(ctx, fmt.Sprintf("statistics:script:%s:%d:day:uv:%s", op, id, date)),
+			}
+			if op != ViewScriptStatistics {
+				cmd.ua = pipe.HGetAll(ctx, s.dimensionKey(id, op, "ua", t))
+			}
+			if op == DownloadScriptStatistics {
+				cmd.source = pipe.HGetAll(ctx, s.dimensionKey(id, op, "source", t))
+			}
+			ret = append(ret, &DayStatistics{ScriptID: id, Op: op})
+			cmds = append(cmds, cmd)
+		}
+	}
+	// 错误记录在各命令中, 由调用方按脚本处理
+	_, _ = pipe.Exec(ctx)
+	for i, cmd := range cmds {
+		v := ret[i]
+		pv, err := cmd.pv.Int64()
+		if err != nil && !redis.Nil(err) {
+			v.Err = err
+			continue
+		}
+		v.Pv = pv
+		if v.Uv, v.Err = cmd.uv.Result(); v.Err != nil {
+			continue
+		}
+		if cmd.ua != nil {
+			if v.UA, v.Err = dimensionResult(cmd.ua); v.Err != nil {
+				continue
+			}
+		}
+		if cmd.source != nil {
+			if v.Source, v.Err = dimensionResult(cmd.source); v.Err != nil {
+				continue
+			}
+		}
+	}
+	return ret, nil
+}
+
+func dimensionResult(cmd *redis2.MapStringStringCmd) (map[string]int64, error) {
+	result, err := cmd.Result()
+	if err != nil {
+		return nil, err
+	}
+	ret := make(map[string]int64, len(result))
+	for k, v := range result {
+		num, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			continue
+		}
+		ret[k] = num
+	}
+	return ret, nil
+}
@@ -0,0 +1,165 @@
+package statistics_repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cago-frame/cago/database/db"
+	"github.com/cago-frame/cago/database/redis"
+	"github.com/cago-frame/cago/pkg/utils/httputils"
+	"github.com/scriptscat/scriptlist/internal/model/entity/statistics_entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// string statistics:error:script:@id:@minute 脚本每分钟上报的错误数量 两分钟过期
+
+//go:generate mockgen -source=./script_error.go -destination=./mock/script_error.go
+
+// ScriptErrorRepo 脚本运行错误
+type ScriptErrorRepo interface {
+	// FindGroup 获取脚本的错误组
+	FindGroup(ctx context.Context, scriptId, id int64) (*statistics_entity.ScriptErrorGroup, error)
+	// FindGroupByFingerprint 根据指纹获取脚本的错误组, 没有时返回nil
+	FindGroupByFingerprint(ctx context.Context, scriptId int64, fingerprint string) (*statistics_entity.ScriptErrorGroup, error)
+	// CreateGroup 创建错误组, 已存在时不做处理
+	CreateGroup(ctx context.Context, group *statistics_entity.ScriptErrorGroup) error
+	// CountGroup 统计脚本的错误组数量
+	CountGroup(ctx context.Context, scriptId int64) (int64, error)
+	// BindIssue 记录错误组转为的反馈, 只更新未转为反馈的错误组, 已经转为反馈时返回false
+	BindIssue(ctx context.Context, id, issueId int64) (bool, error)
+	// IncrGroup 增加错误组的数量, 并记录最近一次发生的时间, 域名与版本
+	IncrGroup(ctx context.Context, id, num int64, host, version string, t int64) error
+	// IncrVersion 增加错误在某版本中的数量
+	IncrVersion(ctx context.Context, groupId int64, version string, num, t int64) error
+	// FindVersions 获取错误在各版本中的数量, 按数量排序
+	FindVersions(ctx context.Context, groupId int64) ([]*statistics_entity.ScriptErrorVersion, error)
+	// FindPage 按最近发生时间分页获取脚本的错误组
+	FindPage(ctx context.Context, scriptId int64, page httputils.PageRequest) ([]*statistics_entity.ScriptErrorGroup, int64, error)
+	// IncrMinute 记录脚本当前分钟上报的错误, 返回当前分钟内的数量
+	IncrMinute(ctx context.Context, scriptId int64, t time.Time) (int64, error)
+}
+
+var defaultScriptError ScriptErrorRepo
+
+func ScriptError() ScriptErrorRepo {
+	return defaultScriptError
+}
+
+func RegisterScriptError(i ScriptErrorRepo) {
+	defaultScriptError = i
+}
+
+type scriptErrorRepo struct {
+}
+
+func NewScriptError() ScriptErrorRepo {
+	return &scriptErrorRepo{}
+}
+
+func (s *scriptErrorRepo) FindGroup(ctx context.Context, scriptId, id int64) (*statistics_entity.ScriptErrorGroup, error) {
+	ret := &statistics_entity.ScriptErrorGroup{}
+	if err := db.Ctx(ctx).First(ret, "id=? and script_id=?", id, scriptId).Error; err != nil {
+		if db.RecordNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return ret, nil
+}
+
+func (s *scriptErrorRepo) FindGroupByFingerprint(ctx context.Context, scriptId int64, fingerprint string) (*statistics_entity.ScriptErrorGroup, error) {
+	ret := &statistics_entity.ScriptErrorGroup{}
+	if err := db.Ctx(ctx).First(ret, "script_id=? and fingerprint=?", scriptId, fingerprint).Error; err != nil {
+		if db.RecordNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return ret, nil
+}
+
+func (s *scriptErrorRepo) CreateGroup(ctx context.Context, group *statistics_entity.ScriptErrorGroup) error {
+	return db.Ctx(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(group).Error
+}
+
+func (s *scriptErrorRepo) CountGroup(ctx context.Context, scriptId int64) (int64, error) {
+	var num int64
+	if err := db.Ctx(ctx).Model(&statistics_entity.ScriptErrorGroup{}).
+		Where("script_id=?", scriptId).Count(&num).Error; err != nil {
+		return 0, err
+	}
+	return num, nil
+}
+
+func (s *scriptErrorRepo) BindIssue(ctx context.Context, id, issueId int64) (bool, error) {
+	result := db.Ctx(ctx).Model(&statistics_entity.ScriptErrorGroup{}).
+		Where("id=? and issue_id=0", id).Updates(map[string]interface{}{
+		"issue_id":   issueId,
+		"updatetime": time.Now().Unix(),
+	})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (s *scriptErrorRepo) IncrGroup(ctx context.Context, id, num int64, host, version string, t int64) error {
+	return db.Ctx(ctx).Model(&statistics_entity.ScriptErrorGroup{}).Where("id=?", id).Updates(map[string]interface{}{
+		"count":      gorm.Expr("count+?", num),
+		"host":       host,
+		"version":    version,
+		"last_time":  t,
+		"updatetime": time.Now().Unix(),
+	}).Error
+}
+
+func (s *scriptErrorRepo) IncrVersion(ctx context.Context, groupId int64, version string, num, t int64) error {
+	return db.Ctx(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "group_id"}, {Name: "version"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"count":     gorm.Expr("count+?", num),
+			"last_time": t,
+		}),
+	}).Create(&statistics_entity.ScriptErrorVersion{
+		GroupID:    groupId,
+		Version:    version,
+		Count:      num,
+		LastTime:   t,
+		Createtime: time.Now().Unix(),
+	}).Error
+}
+
+func (s *scriptErrorRepo) FindVersions(ctx context.Context, groupId int64) ([]*statistics_entity.ScriptErrorVersion, error) {
+	var list []*statistics_entity.ScriptErrorVersion
+	if err := db.Ctx(ctx).Where("group_id=?", groupId).Order("count desc").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (s *scriptErrorRepo) FindPage(ctx context.Context, scriptId int64, page httputils.PageRequest) ([]*statistics_entity.ScriptErrorGroup, int64, error) {
+	var list []*statistics_entity.ScriptErrorGroup
+	var count int64
+	find := db.Ctx(ctx).Model(&statistics_entity.ScriptErrorGroup{}).Where("script_id=?", scriptId)
+	if err := find.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := find.Order("last_time desc").Offset(page.GetOffset()).Limit(page.GetLimit()).Find(&list).Error; err != nil {
+		return nil, 0, err
+	}
+	return list, count, nil
+}
+
+func (s *scriptErrorRepo) IncrMinute(ctx context.Context, scriptId int64, t time.Time) (int64, error) {
+	key := fmt.Sprintf("statistics:error:script:%d:%d", scriptId, t.Unix()/60)
+	num, err := redis.Ctx(ctx).Incr(key).Result()
+	if err != nil {
+		return 0, err
+	}
+	if num == 1 {
+		redis.Ctx(ctx).Expire(key, time.Minute*2)
+	}
+	return num, nil
+}
@@ -85,3 +85,18 @@ func (mr *MockUserRepoMockRecorder) FindByPrefix(ctx, query any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByPrefix", reflect.TypeOf((*MockUserRepo)(nil).FindByPrefix), ctx, query)
 }
+
+// FindByUsernames mocks base method.
+func (m *MockUserRepo) FindByUsernames(ctx context.Context, usernames []string) ([]*user_entity.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByUsernames", ctx, usernames)
+	ret0, _ := ret[0].([]*user_entity.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByUsernames indicates an expected call of FindByUsernames.
+func (mr *MockUserRepoMockRecorder) FindByUsernames(ctx, usernames any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUsernames", reflect.TypeOf((*MockUserRepo)(nil).FindByUsernames), ctx, usernames)
+}
@@ -18,6 +18,8 @@ type UserRepo interface {
 	Find(ctx context.Context, id int64) (*user_entity.User, error)
 	FindByPrefix(ctx context.Context, query string) ([]*user_entity.User, error)
 	FindAdmins(ctx context.Context) ([]*user_entity.User, error)
+	// FindByUsernames 根据用户名精确查找用户
+	FindByUsernames(ctx context.Context, usernames []string) ([]*user_entity.User, error)
 }
 
 var defaultUser UserRepo
@@ -105,3 +107,14 @@ func (u *user) FindByPrefix(ctx context.Context, query string) ([]*user_entity.U
 	}
 	return ret, nil
 }
+
+func (u *user) FindByUsernames(ctx context.Context, usernames []string) ([]*user_entity.User, error) {
+	var ret []*user_entity.User
+	if len(usernames) == 0 {
+		return ret, nil
+	}
+	if err := db.Ctx(ctx).Where("username IN (?)", usernames).Find(&ret).Error; err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
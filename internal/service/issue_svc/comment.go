@@ -10,9 +10,11 @@ import (
 	"github.com/cago-frame/cago/pkg/utils/httputils"
 	api "github.com/scriptscat/scriptlist/internal/api/issue"
 	"github.com/scriptscat/scriptlist/internal/model/entity/issue_entity"
+	"github.com/scriptscat/scriptlist/internal/model/entity/notification_entity"
 	"github.com/scriptscat/scriptlist/internal/repository/issue_repo"
 	"github.com/scriptscat/scriptlist/internal/repository/user_repo"
 	"github.com/scriptscat/scriptlist/internal/service/auth_svc"
+	"github.com/scriptscat/scriptlist/internal/service/notification_svc"
 	"github.com/scriptscat/scriptlist/internal/task/producer"
 )
 
@@ -69,6 +71,12 @@ func (c *commentSvc) ToComment(ctx context.Context, comment *issue_entity.Script
 		return nil, err
 	}
 	ret.UserInfo = user.UserInfo()
+	if comment.Type == issue_entity.CommentTypeComment {
+		ret.Mentions, err = notification_svc.Mention().Links(ctx, notification_entity.MentionTargetIssueComment, comment.ID, comment.Content)
+		if err != nil {
+			return nil, err
+		}
+	}
 	return ret, nil
 }
 
@@ -16,10 +16,12 @@ import (
 	"github.com/gin-gonic/gin"
 	api "github.com/scriptscat/scriptlist/internal/api/issue"
 	"github.com/scriptscat/scriptlist/internal/model/entity/issue_entity"
+	"github.com/scriptscat/scriptlist/internal/model/entity/notification_entity"
 	"github.com/scriptscat/scriptlist/internal/pkg/code"
 	"github.com/scriptscat/scriptlist/internal/repository/issue_repo"
 	"github.com/scriptscat/scriptlist/internal/repository/user_repo"
 	"github.com/scriptscat/scriptlist/internal/service/auth_svc"
+	"github.com/scriptscat/scriptlist/internal/service/notification_svc"
 	"github.com/scriptscat/scriptlist/internal/task/producer"
 )
 
@@ -125,9 +127,14 @@ func (i *issueSvc) CreateIssue(ctx context.Context, req *api.CreateIssueRequest)
 func (i *issueSvc) GetIssue(ctx context.Context, req *api.GetIssueRequest) (*api.GetIssueResponse, error) {
 	issue := i.CtxIssue(ctx)
 	ret, _ := i.ToIssue(ctx, issue)
+	mentions, err := notification_svc.Mention().Links(ctx, notification_entity.MentionTargetIssue, issue.ID, issue.Content)
+	if err != nil {
+		return nil, err
+	}
 	return &api.GetIssueResponse{
-		Issue:   ret,
-		Content: issue.Content,
+		Issue:    ret,
+		Content:  issue.Content,
+		Mentions: mentions,
 	}, nil
 }
 
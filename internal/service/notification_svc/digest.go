@@ -0,0 +1,174 @@
+package notification_svc
+
+import (
+	"context"
+	"encoding/json"
+	"html/template"
+	"math"
+	"time"
+
+	"github.com/cago-frame/cago/pkg/logger"
+	"github.com/scriptscat/scriptlist/configs"
+	"github.com/scriptscat/scriptlist/internal/model/entity/notification_entity"
+	"github.com/scriptscat/scriptlist/internal/model/entity/user_entity"
+	"github.com/scriptscat/scriptlist/internal/repository/notification_repo"
+	"github.com/scriptscat/scriptlist/internal/repository/user_repo"
+	"github.com/scriptscat/scriptlist/internal/service/notification_svc/sender"
+	template2 "github.com/scriptscat/scriptlist/internal/service/notification_svc/template"
+	"go.uber.org/zap"
+)
+
+const (
+	// DigestDailyHour 每日汇总邮件的发送时间
+	DigestDailyHour = 9
+	// digestLimit 单封汇总邮件最多包含的通知数量, 超出的在下次发送
+	digestLimit = 500
+)
+
+// isDigest 邮件通知是否需要汇总发送
+func (n *notificationSvc) isDigest(userConfig *user_entity.UserConfig, notificationType notification_entity.Type) bool {
+	if userConfig.Notify.DigestMode() == user_entity.DigestImmediate {
+		return false
+	}
+	_, ok := template2.DigestTypeName[notificationType]
+	return ok
+}
+
+// bufferMail 将邮件通知放入汇总队列
+func (n *notificationSvc) bufferMail(ctx context.Context, to, from *user_entity.User,
+	notificationType notification_entity.Type, title, link string, params interface{},
+) error {
+	scriptId, scriptName := digestScript(notificationType, params)
+	buffer := &notification_entity.NotificationMailBuffer{
+		UserID:     to.ID,
+		Type:       notificationType,
+		ScriptID:   scriptId,
+		ScriptName: scriptName,
+		Title:      title,
+		Link:       link,
+		Createtime: time.Now().Unix(),
+	}
+	if from != nil {
+		buffer.FromUserID = from.ID
+	}
+	return notification_repo.MailBuffer().Create(ctx, buffer)
+}
+
+// digestScript 从通知参数中取出脚本信息
+func digestScript(notificationType notification_entity.Type, params interface{}) (int64, string) {
+	b, err := json.Marshal(params)
+	if err != nil {
+		return 0, ""
+	}
+	v := &struct {
+		ID       int64  `json:"id"`
+		ScriptID int64  `json:"script_id"`
+		Name     string `json:"name"`
+	}{}
+	if err := json.Unmarshal(b, v); err != nil {
+		return 0, ""
+	}
+	if notificationType == notification_entity.ScriptUpdateTemplate {
+		return v.ID, v.Name
+	}
+	return v.ScriptID, v.Name
+}
+
+// SendDigest 发送汇总邮件, 每小时执行一次, 每日汇总在DigestDailyHour发送
+func (n *notificationSvc) SendDigest(ctx context.Context, now time.Time) error {
+	userIds, err := notification_repo.MailBuffer().FindUserIDs(ctx)
+	if err != nil {
+		return err
+	}
+	for _, uid := range userIds {
+		if err := n.sendUserDigest(ctx, uid, now); err != nil {
+			logger.Ctx(ctx).Error("发送汇总邮件失败", zap.Error(err), zap.Int64("user_id", uid))
+		}
+	}
+	return nil
+}
+
+func (n *notificationSvc) sendUserDigest(ctx context.Context, uid int64, now time.Time) error {
+	user, err := user_repo.User().Find(ctx, uid)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return notification_repo.MailBuffer().DeleteByUser(ctx, uid, math.MaxInt64)
+	}
+	userConfig, err := user_repo.UserConfig().FindByUserID(ctx, uid)
+	if err != nil {
+		return err
+	}
+	// 修改为立即发送后, 剩余的通知在下次执行时发出
+	if userConfig != nil && userConfig.Notify != nil &&
+		userConfig.Notify.DigestMode() == user_entity.DigestDaily && now.Hour() != DigestDailyHour {
+		return nil
+	}
+	list, err := notification_repo.MailBuffer().FindByUser(ctx, uid, digestLimit)
+	if err != nil {
+		return err
+	}
+	if len(list) == 0 {
+		return nil
+	}
+	data := map[string]interface{}{
+		"Config": map[string]interface{}{
+			"Url": configs.Url(),
+		},
+		"Value": buildDigest(list),
+	}
+	title, err := n.parseTpl(template2.DigestTitle, data)
+	if err != nil {
+		return err
+	}
+	content, err := n.parseTpl(template2.DigestContent, data)
+	if err != nil {
+		return err
+	}
+	if err := n.senderMap[sender.MailSender].Send(ctx, user, content, &sender.SendOptions{
+		Title: title,
+	}); err != nil {
+		return err
+	}
+	return notification_repo.MailBuffer().DeleteByUser(ctx, uid, list[len(list)-1].ID)
+}
+
+// buildDigest 按脚本和通知类型分组, 保持通知的先后顺序
+func buildDigest(list []*notification_entity.NotificationMailBuffer) *template2.Digest {
+	ret := &template2.Digest{
+		Total:   len(list),
+		Scripts: make([]*template2.DigestScript, 0),
+	}
+	scripts := make(map[int64]*template2.DigestScript)
+	groups := make(map[int64]map[notification_entity.Type]*template2.DigestGroup)
+	for _, v := range list {
+		script, ok := scripts[v.ScriptID]
+		if !ok {
+			script = &template2.DigestScript{
+				ID:     v.ScriptID,
+				Name:   v.ScriptName,
+				Groups: make([]*template2.DigestGroup, 0),
+			}
+			scripts[v.ScriptID] = script
+			groups[v.ScriptID] = make(map[notification_entity.Type]*template2.DigestGroup)
+			ret.Scripts = append(ret.Scripts, script)
+		}
+		group, ok := groups[v.ScriptID][v.Type]
+		if !ok {
+			group = &template2.DigestGroup{
+				Type:  v.Type,
+				Name:  template2.DigestTypeName[v.Type],
+				Items: make([]*template2.DigestItem, 0),
+			}
+			groups[v.ScriptID][v.Type] = group
+			script.Groups = append(script.Groups, group)
+		}
+		group.Items = append(group.Items, &template2.DigestItem{
+			Title:      template.HTML(v.Title), //nolint:gosec
+			Link:       v.Link,
+			Createtime: v.Createtime,
+		})
+	}
+	return ret
+}
@@ -0,0 +1,68 @@
+package notification_svc
+
+import (
+	"testing"
+
+	"github.com/scriptscat/scriptlist/internal/model/entity/notification_entity"
+	"github.com/scriptscat/scriptlist/internal/model/entity/user_entity"
+	template2 "github.com/scriptscat/scriptlist/internal/service/notification_svc/template"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildDigest(t *testing.T) {
+	digest := buildDigest([]*notification_entity.NotificationMailBuffer{
+		{ScriptID: 1, ScriptName: "a", Type: notification_entity.IssueCreateTemplate, Title: "issue1", Link: "/1"},
+		{ScriptID: 2, ScriptName: "b", Type: notification_entity.ScriptScoreTemplate, Title: "score"},
+		{ScriptID: 1, ScriptName: "a", Type: notification_entity.CommentCreateTemplate, Title: "comment"},
+		{ScriptID: 1, ScriptName: "a", Type: notification_entity.IssueCreateTemplate, Title: "issue2"},
+	})
+	assert.Equal(t, 4, digest.Total)
+	assert.Len(t, digest.Scripts, 2)
+	assert.Equal(t, "a", digest.Scripts[0].Name)
+	assert.Len(t, digest.Scripts[0].Groups, 2)
+	assert.Equal(t, "新的反馈", digest.Scripts[0].Groups[0].Name)
+	assert.Equal(t, []*template2.DigestItem{{Title: "issue1", Link: "/1"}, {Title: "issue2"}}, digest.Scripts[0].Groups[0].Items)
+	assert.Equal(t, notification_entity.ScriptScoreTemplate, digest.Scripts[1].Groups[0].Type)
+
+	n := &notificationSvc{}
+	content, err := n.parseTpl(template2.DigestContent, map[string]interface{}{
+		"Config": map[string]interface{}{"Url": "https://scriptcat.org"},
+		"Value":  digest,
+	})
+	assert.NoError(t, err)
+	assert.Contains(t, content, `<a href="/1">issue1</a>`)
+	assert.Contains(t, content, "<li>score</li>")
+
+	// 缓存的标题已经转义过, 不会重复转义
+	digest = buildDigest([]*notification_entity.NotificationMailBuffer{
+		{ScriptID: 1, ScriptName: "a&b", Type: notification_entity.IssueCreateTemplate, Title: "a&amp;b &lt;issue&gt;"},
+	})
+	content, err = n.parseTpl(template2.DigestContent, map[string]interface{}{
+		"Config": map[string]interface{}{"Url": "https://scriptcat.org"},
+		"Value":  digest,
+	})
+	assert.NoError(t, err)
+	assert.Contains(t, content, "<h3>a&amp;b</h3>")
+	assert.Contains(t, content, "<li>a&amp;b &lt;issue&gt;</li>")
+}
+
+func TestDigestScript(t *testing.T) {
+	id, name := digestScript(notification_entity.ScriptUpdateTemplate, &template2.ScriptUpdate{ID: 1, Name: "a"})
+	assert.Equal(t, int64(1), id)
+	assert.Equal(t, "a", name)
+	id, name = digestScript(notification_entity.IssueCreateTemplate, &template2.IssueCreate{ScriptID: 2, IssueID: 3, Name: "b"})
+	assert.Equal(t, int64(2), id)
+	assert.Equal(t, "b", name)
+}
+
+func TestIsDigest(t *testing.T) {
+	n := &notificationSvc{}
+	cfg := &user_entity.UserConfig{Notify: &user_entity.Notify{}}
+	assert.False(t, n.isDigest(cfg, notification_entity.IssueCreateTemplate))
+	cfg.Notify.Digest = user_entity.DigestDaily
+	assert.True(t, n.isDigest(cfg, notification_entity.IssueCreateTemplate))
+	// 邀请和删除通知不汇总
+	assert.False(t, n.isDigest(cfg, notification_entity.AccessInviteTemplate))
+	cfg.Notify.Digest = 9
+	assert.False(t, n.isDigest(cfg, notification_entity.IssueCreateTemplate))
+}
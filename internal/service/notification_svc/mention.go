@@ -0,0 +1,121 @@
+package notification_svc
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/scriptscat/scriptlist/internal/model/entity/notification_entity"
+	"github.com/scriptscat/scriptlist/internal/repository/notification_repo"
+	"github.com/scriptscat/scriptlist/internal/repository/user_repo"
+	template2 "github.com/scriptscat/scriptlist/internal/service/notification_svc/template"
+)
+
+// MentionOptions 包含@的内容
+type MentionOptions struct {
+	Target     notification_entity.MentionTarget
+	TargetID   int64
+	ScriptID   int64
+	ScriptName string
+	Title      string // 反馈或举报的标题
+	From       int64
+	Content    string
+	OldContent string // 编辑前的内容
+	Path       string // 内容所在的页面
+}
+
+type MentionSvc interface {
+	// Mention 保存内容中@的用户, 并通知新提及的用户
+	Mention(ctx context.Context, opts *MentionOptions) error
+	// Links 获取内容中@的用户链接
+	Links(ctx context.Context, target notification_entity.MentionTarget, targetId int64, content string) ([]*notification_entity.MentionLink, error)
+}
+
+type mentionSvc struct {
+}
+
+var defaultMention = &mentionSvc{}
+
+func Mention() MentionSvc {
+	return defaultMention
+}
+
+// Mention 保存内容中@的用户, 编辑时只通知新提及的用户
+// 不通知自己和被封禁的用户, 被封禁的用户@他人时也不发送通知
+func (m *mentionSvc) Mention(ctx context.Context, opts *MentionOptions) error {
+	names := notification_entity.ParseMentions(opts.Content)
+	if len(names) == 0 && len(notification_entity.ParseMentions(opts.OldContent)) == 0 {
+		return nil
+	}
+	from, err := user_repo.User().Find(ctx, opts.From)
+	if err != nil {
+		return err
+	}
+	users, err := user_repo.User().FindByUsernames(ctx, names)
+	if err != nil {
+		return err
+	}
+	old, err := notification_repo.Mention().FindByTarget(ctx, opts.Target, opts.TargetID)
+	if err != nil {
+		return err
+	}
+	mentioned := make(map[int64]struct{}, len(old))
+	for _, v := range old {
+		mentioned[v.UserID] = struct{}{}
+	}
+	now := time.Now().Unix()
+	list := make([]*notification_entity.Mention, 0, len(users))
+	uids := make([]int64, 0, len(users))
+	for _, user := range users {
+		if user.ID == opts.From {
+			continue
+		}
+		list = append(list, &notification_entity.Mention{
+			TargetType: opts.Target,
+			TargetID:   opts.TargetID,
+			ScriptID:   opts.ScriptID,
+			UserID:     user.ID,
+			Username:   user.Username,
+			FromUserID: opts.From,
+			Createtime: now,
+		})
+		if _, ok := mentioned[user.ID]; ok {
+			continue
+		}
+		if user.IsBanned(ctx) != nil {
+			continue
+		}
+		uids = append(uids, user.ID)
+	}
+	if err := notification_repo.Mention().ReplaceByTarget(ctx, opts.Target, opts.TargetID, list); err != nil {
+		return err
+	}
+	if len(uids) == 0 || from.IsBanned(ctx) != nil {
+		return nil
+	}
+	return Notification().MultipleSend(ctx, uids, notification_entity.MentionTemplate,
+		WithFrom(opts.From), WithParams(&template2.Mention{
+			ScriptID: opts.ScriptID,
+			Name:     opts.ScriptName,
+			Username: from.Username,
+			Title:    opts.Title,
+			Content:  opts.Content,
+			Path:     opts.Path,
+		}))
+}
+
+// Links 获取内容中@的用户链接, 内容中没有@时不查询
+func (m *mentionSvc) Links(ctx context.Context, target notification_entity.MentionTarget, targetId int64, content string) ([]*notification_entity.MentionLink, error) {
+	if !strings.Contains(content, "@") {
+		return nil, nil
+	}
+	list, err := notification_repo.Mention().FindByTarget(ctx, target, targetId)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]*notification_entity.MentionLink, 0, len(list))
+	for _, v := range list {
+		ret = append(ret, v.Link())
+	}
+	return ret, nil
+}
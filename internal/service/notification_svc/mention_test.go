@@ -0,0 +1,106 @@
+package notification_svc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/scriptscat/scriptlist/internal/model/entity/notification_entity"
+	"github.com/scriptscat/scriptlist/internal/model/entity/user_entity"
+	"github.com/scriptscat/scriptlist/internal/repository/notification_repo"
+	mock_notification_repo "github.com/scriptscat/scriptlist/internal/repository/notification_repo/mock"
+	"github.com/scriptscat/scriptlist/internal/repository/user_repo"
+	mock_user_repo "github.com/scriptscat/scriptlist/internal/repository/user_repo/mock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestMentionSvc_Mention(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockUserRepo := mock_user_repo.NewMockUserRepo(mockCtrl)
+	user_repo.RegisterUser(mockUserRepo)
+	mockMentionRepo := mock_notification_repo.NewMockMentionRepo(mockCtrl)
+	notification_repo.RegisterMention(mockMentionRepo)
+	ctx := context.Background()
+
+	t.Run("没有提及时不查询", func(t *testing.T) {
+		err := Mention().Mention(ctx, &MentionOptions{Content: "没有提及", OldContent: "test@example.com"})
+		assert.NoError(t, err)
+	})
+
+	t.Run("不通知自己和被封禁的用户", func(t *testing.T) {
+		mockUserRepo.EXPECT().Find(gomock.Any(), int64(1)).Return(&user_entity.User{ID: 1, Username: "from"}, nil)
+		mockUserRepo.EXPECT().FindByUsernames(gomock.Any(), []string{"from", "banned"}).Return([]*user_entity.User{
+			{ID: 1, Username: "from"},
+			{ID: 2, Username: "banned", Freeze: 1},
+		}, nil)
+		mockMentionRepo.EXPECT().FindByTarget(gomock.Any(), notification_entity.MentionTargetIssue, int64(10)).Return(nil, nil)
+		mockMentionRepo.EXPECT().ReplaceByTarget(gomock.Any(), notification_entity.MentionTargetIssue, int64(10), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, target notification_entity.MentionTarget, targetId int64, list []*notification_entity.Mention) error {
+				assert.Len(t, list, 1)
+				assert.Equal(t, int64(2), list[0].UserID)
+				assert.Equal(t, "/users/2", list[0].Link().Link)
+				return nil
+			})
+		err := Mention().Mention(ctx, &MentionOptions{
+			Target:   notification_entity.MentionTargetIssue,
+			TargetID: 10,
+			From:     1,
+			Content:  "@from @banned",
+		})
+		assert.NoError(t, err)
+	})
+
+	t.Run("编辑时只通知新提及的用户", func(t *testing.T) {
+		mockUserRepo.EXPECT().Find(gomock.Any(), int64(1)).Return(&user_entity.User{ID: 1, Username: "from"}, nil)
+		mockUserRepo.EXPECT().FindByUsernames(gomock.Any(), []string{"user3"}).Return([]*user_entity.User{
+			{ID: 3, Username: "user3"},
+		}, nil)
+		mockMentionRepo.EXPECT().FindByTarget(gomock.Any(), notification_entity.MentionTargetScoreReply, int64(20)).Return([]*notification_entity.Mention{
+			{UserID: 3, Username: "user3"},
+		}, nil)
+		mockMentionRepo.EXPECT().ReplaceByTarget(gomock.Any(), notification_entity.MentionTargetScoreReply, int64(20), gomock.Len(1)).Return(nil)
+		err := Mention().Mention(ctx, &MentionOptions{
+			Target:     notification_entity.MentionTargetScoreReply,
+			TargetID:   20,
+			From:       1,
+			Content:    "@user3 已修复",
+			OldContent: "@user3",
+		})
+		assert.NoError(t, err)
+	})
+
+	t.Run("删除全部提及", func(t *testing.T) {
+		mockUserRepo.EXPECT().Find(gomock.Any(), int64(1)).Return(&user_entity.User{ID: 1, Username: "from"}, nil)
+		mockUserRepo.EXPECT().FindByUsernames(gomock.Any(), []string{}).Return(nil, nil)
+		mockMentionRepo.EXPECT().FindByTarget(gomock.Any(), notification_entity.MentionTargetScoreReply, int64(20)).Return(nil, nil)
+		mockMentionRepo.EXPECT().ReplaceByTarget(gomock.Any(), notification_entity.MentionTargetScoreReply, int64(20), gomock.Len(0)).Return(nil)
+		err := Mention().Mention(ctx, &MentionOptions{
+			Target:     notification_entity.MentionTargetScoreReply,
+			TargetID:   20,
+			From:       1,
+			Content:    "已修复",
+			OldContent: "@user3",
+		})
+		assert.NoError(t, err)
+	})
+}
+
+func TestMentionSvc_Links(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockMentionRepo := mock_notification_repo.NewMockMentionRepo(mockCtrl)
+	notification_repo.RegisterMention(mockMentionRepo)
+	ctx := context.Background()
+
+	links, err := Mention().Links(ctx, notification_entity.MentionTargetIssueComment, 1, "没有提及")
+	assert.NoError(t, err)
+	assert.Nil(t, links)
+
+	mockMentionRepo.EXPECT().FindByTarget(gomock.Any(), notification_entity.MentionTargetIssueComment, int64(1)).Return([]*notification_entity.Mention{
+		{UserID: 2, Username: "user2"},
+	}, nil)
+	links, err = Mention().Links(ctx, notification_entity.MentionTargetIssueComment, 1, "@user2")
+	assert.NoError(t, err)
+	assert.Equal(t, []*notification_entity.MentionLink{{UserID: 2, Username: "user2", Link: "/users/2"}}, links)
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/cago-frame/cago/pkg/gogo"
 	"github.com/cago-frame/cago/pkg/logger"
 	"github.com/cago-frame/cago/pkg/utils/httputils"
+	"github.com/gin-gonic/gin"
 	"github.com/scriptscat/scriptlist/configs"
 	api "github.com/scriptscat/scriptlist/internal/api/notification"
 	"github.com/scriptscat/scriptlist/internal/model/entity/notification_entity"
@@ -28,11 +29,15 @@ type NotificationSvc interface {
 	GetUnreadCount(ctx context.Context, req *api.GetUnreadCountRequest) (*api.GetUnreadCountResponse, error)
 	MarkRead(ctx context.Context, req *api.MarkReadRequest) error
 	BatchMarkRead(ctx context.Context, req *api.BatchMarkReadRequest) error
+	// Stream 通过SSE推送新通知和未读数
+	Stream(ctx *gin.Context, req *api.StreamRequest) error
 
 	// Send 发送通知
 	Send(ctx context.Context, toUser int64, notificationType notification_entity.Type, options ...Option) error
 	// MultipleSend 发送通知给多个用户
 	MultipleSend(ctx context.Context, toUsers []int64, notificationType notification_entity.Type, options ...Option) error
+	// SendDigest 发送汇总邮件
+	SendDigest(ctx context.Context, now time.Time) error
 }
 
 type notificationSvc struct {
@@ -41,8 +46,9 @@ type notificationSvc struct {
 
 var defaultNotification = &notificationSvc{
 	senderMap: map[sender.Type]sender.Sender{
-		sender.InAppSender: sender.NewApp(),
-		sender.MailSender:  sender.NewMail(),
+		sender.InAppSender:   sender.NewApp(),
+		sender.MailSender:    sender.NewMail(),
+		sender.WebhookSender: sender.NewWebhook(),
 	},
 }
 
@@ -123,12 +129,27 @@ func (n *notificationSvc) MarkRead(ctx context.Context, req *api.MarkReadRequest
 	if err := notification_repo.Notification().Update(ctx, notification); err != nil {
 		return err
 	}
+	n.publishUnread(ctx, notification.UserID)
 	return nil
 }
 
 // BatchMarkRead 批量标记已读
 func (n *notificationSvc) BatchMarkRead(ctx context.Context, req *api.BatchMarkReadRequest) error {
-	return notification_repo.Notification().BatchMarkRead(ctx, auth_svc.Auth().Get(ctx).UID, req.IDs)
+	uid := auth_svc.Auth().Get(ctx).UID
+	if err := notification_repo.Notification().BatchMarkRead(ctx, uid, req.IDs); err != nil {
+		return err
+	}
+	n.publishUnread(ctx, uid)
+	return nil
+}
+
+// publishUnread 通知用户的其它连接更新未读数
+func (n *notificationSvc) publishUnread(ctx context.Context, userId int64) {
+	if err := notification_repo.Realtime().Publish(ctx, userId, &notification_repo.RealtimeEvent{
+		Event: notification_repo.RealtimeEventUnread,
+	}); err != nil {
+		logger.Ctx(ctx).Error("推送未读数失败", zap.Error(err), zap.Int64("user_id", userId))
+	}
 }
 
 // Send 根据模板id发送通知给指定用户
@@ -227,6 +248,13 @@ func (n *notificationSvc) MultipleSend(ctx context.Context, toUsers []int64, not
 				} else if !ok {
 					continue
 				}
+				// 开启了汇总的邮件通知先缓存, 由定时任务统一发送
+				if senderType == sender.MailSender && n.isDigest(userConfig, notificationType) {
+					if err := n.bufferMail(ctx, to, from, notificationType, content.Title, content.Link, opts.params); err != nil {
+						logger.Ctx(ctx).Error("buffer mail notification error", zap.Error(err), zap.Int64("user_id", toUser))
+					}
+					continue
+				}
 				if err := s.Send(ctx, to, content.Content, &sender.SendOptions{
 					From:   from,
 					Title:  content.Title,
@@ -271,6 +299,8 @@ func (n *notificationSvc) IsNotify(ctx context.Context, userConfig *user_entity.
 		return userConfig.Notify.IsEnabled(userConfig.Notify.ScriptReport), nil
 	case notification_entity.ReportCommentTemplate:
 		return userConfig.Notify.IsEnabled(userConfig.Notify.ScriptReportComment), nil
+	case notification_entity.MentionTemplate:
+		return userConfig.Notify.IsEnabled(userConfig.Notify.At), nil
 	default:
 		return true, nil
 	}
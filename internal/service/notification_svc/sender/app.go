@@ -52,5 +52,13 @@ func (s *app) Send(ctx context.Context, user *user_entity.User, content string,
 		return err
 	}
 
+	// 推送给在线的用户, 失败时用户重连后仍能获取
+	if err := notification_repo.Realtime().Publish(ctx, user.ID, &notification_repo.RealtimeEvent{
+		Event: notification_repo.RealtimeEventNotification,
+		ID:    m.ID,
+	}); err != nil {
+		logger.Ctx(ctx).Error("推送应用内通知失败", zap.Error(err), zap.Int64("user_id", user.ID))
+	}
+
 	return nil
 }
@@ -9,8 +9,9 @@ import (
 type Type int
 
 const (
-	MailSender  Type = iota + 1
-	InAppSender      // 应用内通知
+	MailSender    Type = iota + 1
+	InAppSender        // 应用内通知
+	WebhookSender      // webhook通知
 )
 
 type Sender interface {
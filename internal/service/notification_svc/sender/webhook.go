@@ -0,0 +1,199 @@
+package sender
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/cago-frame/cago/pkg/utils"
+	"github.com/scriptscat/scriptlist/internal/model/entity/notification_entity"
+	"github.com/scriptscat/scriptlist/internal/model/entity/user_entity"
+	"github.com/scriptscat/scriptlist/internal/repository/notification_repo"
+	"github.com/scriptscat/scriptlist/internal/task/producer"
+)
+
+const (
+	// WebhookMaxAttempt 最大投递次数, 与nsq默认的MaxAttempts一致, 超过后消息会被nsq丢弃
+	WebhookMaxAttempt = 5
+	// webhookRetryBase 首次重试间隔, 之后每次乘3
+	webhookRetryBase = 10 * time.Second
+	// webhookRetryMax 最大重试间隔
+	webhookRetryMax = 15 * time.Minute
+	// webhookResponseLimit 记录的响应内容长度
+	webhookResponseLimit = 1024
+)
+
+// WebhookPayload webhook请求体
+type WebhookPayload struct {
+	Event   string                   `json:"event"`
+	Type    notification_entity.Type `json:"type,omitempty"`
+	Title   string                   `json:"title,omitempty"`
+	Content string                   `json:"content,omitempty"` // 通知内容标识, 与应用内通知一致
+	Link    string                   `json:"link,omitempty"`
+	Params  interface{}              `json:"params,omitempty"`
+	User    user_entity.UserInfo     `json:"user"`
+	From    *user_entity.UserInfo    `json:"from,omitempty"`
+	Time    int64                    `json:"time"`
+}
+
+var webhookClient = &http.Client{
+	Timeout: 10 * time.Second,
+	Transport: &http.Transport{
+		DialContext: (&net.Dialer{
+			Timeout: 5 * time.Second,
+			Control: webhookDialControl,
+		}).DialContext,
+	},
+	// 不跟随跳转, 避免跳转到内网地址
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
+
+// webhookDialControl 拒绝连接内网地址
+func webhookDialControl(network, address string, c syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !notification_entity.IsPublicIP(ip) {
+		return errors.New("webhook address is not allowed")
+	}
+	return nil
+}
+
+type webhook struct {
+}
+
+func NewWebhook() Sender {
+	return &webhook{}
+}
+
+// Send 将通知放入投递队列, 由消费者签名后投递
+func (w *webhook) Send(ctx context.Context, user *user_entity.User, content string, options *SendOptions) error {
+	hook, err := notification_repo.Webhook().FindByUser(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	if hook == nil || !hook.IsActive() || !hook.Subscribed(options.Type) {
+		return nil
+	}
+	payload := &WebhookPayload{
+		Event:   notification_entity.WebhookEventNotification,
+		Type:    options.Type,
+		Title:   options.Title,
+		Content: content,
+		Link:    options.Link,
+		Params:  options.Params,
+		User:    user.UserInfo(),
+		Time:    time.Now().Unix(),
+	}
+	if options.From != nil {
+		from := options.From.UserInfo()
+		payload.From = &from
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	return producer.PublishNotificationWebhook(ctx, &producer.NotificationWebhookMsg{
+		WebhookID: hook.ID,
+		UserID:    user.ID,
+		Delivery:  NewWebhookDelivery(),
+		Event:     payload.Event,
+		Type:      payload.Type,
+		Payload:   body,
+	})
+}
+
+// NewWebhookDelivery 生成投递id
+func NewWebhookDelivery() string {
+	return utils.RandString(32, utils.Mix)
+}
+
+// WebhookSignature 使用密钥对请求体进行HMAC-SHA256签名
+func WebhookSignature(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// WebhookRetryDelay 第attempt次投递失败后的重试间隔, 达到最大次数时返回0
+func WebhookRetryDelay(attempt int) time.Duration {
+	if attempt >= WebhookMaxAttempt {
+		return 0
+	}
+	delay := webhookRetryBase
+	for i := 1; i < attempt; i++ {
+		delay *= 3
+		if delay >= webhookRetryMax {
+			return webhookRetryMax
+		}
+	}
+	return delay
+}
+
+// DeliverWebhook 投递webhook, 返回本次投递的记录
+func DeliverWebhook(ctx context.Context, hook *notification_entity.NotificationWebhook,
+	msg *producer.NotificationWebhookMsg, attempt int,
+) *notification_entity.NotificationWebhookLog {
+	log := &notification_entity.NotificationWebhookLog{
+		WebhookID:  hook.ID,
+		UserID:     hook.UserID,
+		Delivery:   msg.Delivery,
+		Event:      msg.Event,
+		Type:       msg.Type,
+		URL:        hook.URL,
+		Payload:    string(msg.Payload),
+		Attempt:    attempt,
+		Status:     notification_entity.WebhookDeliveryFailed,
+		Createtime: time.Now().Unix(),
+	}
+	start := time.Now()
+	defer func() {
+		log.Duration = time.Since(start).Milliseconds()
+	}()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(msg.Payload))
+	if err != nil {
+		log.Error = truncate(err.Error(), webhookResponseLimit)
+		return log
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "ScriptCat-Webhook")
+	req.Header.Set("X-ScriptCat-Event", msg.Event)
+	req.Header.Set("X-ScriptCat-Delivery", msg.Delivery)
+	req.Header.Set("X-ScriptCat-Signature-256", WebhookSignature(hook.Secret, msg.Payload))
+	resp, err := webhookClient.Do(req)
+	if err != nil {
+		log.Error = truncate(err.Error(), webhookResponseLimit)
+		return log
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	log.StatusCode = resp.StatusCode
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, webhookResponseLimit))
+	log.Response = truncate(string(body), webhookResponseLimit)
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		log.Status = notification_entity.WebhookDeliverySuccess
+	}
+	return log
+}
+
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) > n {
+		return string(r[:n])
+	}
+	return s
+}
@@ -0,0 +1,78 @@
+package sender
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/scriptscat/scriptlist/internal/model/entity/notification_entity"
+	"github.com/scriptscat/scriptlist/internal/task/producer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookSignature(t *testing.T) {
+	// echo -n '{"event":"ping"}' | openssl dgst -sha256 -hmac secret
+	assert.Equal(t, "sha256=4f4bb3a54e99c4a20e243485229f9b08c66e09104ba6f79c23ce647242a4ce84",
+		WebhookSignature("secret", []byte(`{"event":"ping"}`)))
+}
+
+func TestWebhookRetryDelay(t *testing.T) {
+	assert.Equal(t, 10*time.Second, WebhookRetryDelay(1))
+	assert.Equal(t, 30*time.Second, WebhookRetryDelay(2))
+	assert.Equal(t, 90*time.Second, WebhookRetryDelay(3))
+	assert.Equal(t, 270*time.Second, WebhookRetryDelay(4))
+	assert.Equal(t, time.Duration(0), WebhookRetryDelay(WebhookMaxAttempt))
+}
+
+func TestDeliverWebhook(t *testing.T) {
+	msg := &producer.NotificationWebhookMsg{
+		WebhookID: 1,
+		UserID:    2,
+		Delivery:  "delivery",
+		Event:     notification_entity.WebhookEventPing,
+		Payload:   []byte(`{"event":"ping"}`),
+	}
+	t.Run("不允许投递到内网地址", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer srv.Close()
+		log := DeliverWebhook(context.Background(), &notification_entity.NotificationWebhook{ID: 1, UserID: 2, URL: srv.URL}, msg, 1)
+		assert.Equal(t, notification_entity.WebhookDeliveryFailed, log.Status)
+		assert.Contains(t, log.Error, "not allowed")
+	})
+
+	client := webhookClient
+	webhookClient = &http.Client{Timeout: time.Second}
+	defer func() {
+		webhookClient = client
+	}()
+	t.Run("投递成功", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			assert.Equal(t, WebhookSignature("secret", body), r.Header.Get("X-ScriptCat-Signature-256"))
+			assert.Equal(t, "ping", r.Header.Get("X-ScriptCat-Event"))
+			assert.Equal(t, "delivery", r.Header.Get("X-ScriptCat-Delivery"))
+			_, _ = w.Write([]byte("ok"))
+		}))
+		defer srv.Close()
+		log := DeliverWebhook(context.Background(), &notification_entity.NotificationWebhook{ID: 1, UserID: 2, URL: srv.URL, Secret: "secret"}, msg, 2)
+		assert.Equal(t, notification_entity.WebhookDeliverySuccess, log.Status)
+		assert.Equal(t, http.StatusOK, log.StatusCode)
+		assert.Equal(t, "ok", log.Response)
+		assert.Equal(t, 2, log.Attempt)
+		assert.Equal(t, `{"event":"ping"}`, log.Payload)
+	})
+	t.Run("响应非2xx", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer srv.Close()
+		log := DeliverWebhook(context.Background(), &notification_entity.NotificationWebhook{ID: 1, UserID: 2, URL: srv.URL}, msg, 1)
+		assert.Equal(t, notification_entity.WebhookDeliveryFailed, log.Status)
+		assert.Equal(t, http.StatusInternalServerError, log.StatusCode)
+	})
+}
@@ -0,0 +1,113 @@
+package notification_svc
+
+import (
+	"strconv"
+
+	"github.com/cago-frame/cago/pkg/i18n"
+	"github.com/cago-frame/cago/pkg/logger"
+	"github.com/gin-gonic/gin"
+	api "github.com/scriptscat/scriptlist/internal/api/notification"
+	"github.com/scriptscat/scriptlist/internal/model/entity/user_entity"
+	"github.com/scriptscat/scriptlist/internal/pkg/code"
+	"github.com/scriptscat/scriptlist/internal/pkg/pubsub"
+	"github.com/scriptscat/scriptlist/internal/repository/notification_repo"
+	"github.com/scriptscat/scriptlist/internal/repository/user_repo"
+	"github.com/scriptscat/scriptlist/internal/service/auth_svc"
+	"go.uber.org/zap"
+)
+
+const (
+	// streamLimit 单次最多推送的通知数量, 更早的通知通过列表接口获取
+	streamLimit = 50
+	// streamMaxConn 每个用户在一个实例上最多同时打开的通知连接
+	streamMaxConn = 5
+)
+
+var streamLimiter = pubsub.NewLimiter(streamMaxConn)
+
+// Stream 通过SSE推送新通知和未读数, 重连时补发last id之后的通知
+func (n *notificationSvc) Stream(ctx *gin.Context, req *api.StreamRequest) error {
+	uid := auth_svc.Auth().Get(ctx).UID
+	key := strconv.FormatInt(uid, 10)
+	if !streamLimiter.Acquire(key) {
+		return i18n.NewError(ctx, code.NotificationStreamLimit)
+	}
+	defer streamLimiter.Release(key)
+	lastId := req.LastID
+	if id, err := strconv.ParseInt(ctx.GetHeader("Last-Event-ID"), 10, 64); err == nil {
+		lastId = id
+	}
+	events, err := notification_repo.Realtime().Subscribe(ctx.Request.Context(), uid)
+	if err != nil {
+		return err
+	}
+	sse := pubsub.NewSSE(ctx)
+	// 已经开始推送, 响应头已经写出, 错误无法再返回给客户端, 只记录日志
+	if err := n.stream(ctx, sse, uid, lastId, events); err != nil {
+		logger.Ctx(ctx).Error("推送实时通知失败", zap.Error(err), zap.Int64("user_id", uid))
+	}
+	return nil
+}
+
+func (n *notificationSvc) stream(ctx *gin.Context, sse *pubsub.SSE, uid, lastId int64,
+	events <-chan *notification_repo.RealtimeEvent) error {
+	var err error
+	if lastId > 0 {
+		if lastId, err = n.streamNotifications(ctx, sse, uid, lastId); err != nil {
+			return err
+		}
+	}
+	if err := n.streamUnread(ctx, sse, uid); err != nil {
+		return err
+	}
+	sse.Flush()
+	return pubsub.Serve(sse, events, func(event *notification_repo.RealtimeEvent) error {
+		if event.Event == notification_repo.RealtimeEventNotification {
+			// 首次连接时从收到的第一条通知开始推送
+			if lastId == 0 {
+				lastId = event.ID - 1
+			}
+			if lastId, err = n.streamNotifications(ctx, sse, uid, lastId); err != nil {
+				return err
+			}
+		}
+		return n.streamUnread(ctx, sse, uid)
+	})
+}
+
+// streamNotifications 推送lastId之后的通知, 返回推送的最后一条通知id
+func (n *notificationSvc) streamNotifications(ctx *gin.Context, sse *pubsub.SSE, uid, lastId int64) (int64, error) {
+	list, err := notification_repo.Notification().FindAfter(ctx, uid, lastId, streamLimit)
+	if err != nil {
+		return lastId, err
+	}
+	userMap := make(map[int64]*user_entity.User)
+	for _, item := range list {
+		if _, ok := userMap[item.FromUserID]; item.FromUserID == 0 || ok {
+			continue
+		}
+		user, err := user_repo.User().Find(ctx, item.FromUserID)
+		if err != nil {
+			logger.Ctx(ctx).Error("find user error", zap.Error(err), zap.Int64("user_id", item.FromUserID))
+			continue
+		}
+		if user != nil {
+			userMap[item.FromUserID] = user
+		}
+	}
+	for _, item := range list {
+		if err := sse.Event(item.ID, "notification", n.toAPINotification(ctx, item, userMap)); err != nil {
+			return lastId, err
+		}
+		lastId = item.ID
+	}
+	return lastId, nil
+}
+
+func (n *notificationSvc) streamUnread(ctx *gin.Context, sse *pubsub.SSE, uid int64) error {
+	total, err := notification_repo.Notification().CountUnread(ctx, uid, 0)
+	if err != nil {
+		return err
+	}
+	return sse.Event(0, "unread", &api.GetUnreadCountResponse{Total: total})
+}
@@ -0,0 +1,76 @@
+package notification_svc
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	api "github.com/scriptscat/scriptlist/internal/api/notification"
+	"github.com/scriptscat/scriptlist/internal/model/entity/notification_entity"
+	"github.com/scriptscat/scriptlist/internal/repository/notification_repo"
+	mock_notification_repo "github.com/scriptscat/scriptlist/internal/repository/notification_repo/mock"
+	"github.com/scriptscat/scriptlist/internal/service/auth_svc"
+	mock_auth_svc "github.com/scriptscat/scriptlist/internal/service/auth_svc/mock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestNotificationSvc_Stream(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockAuth := mock_auth_svc.NewMockAuthSvc(mockCtrl)
+	auth_svc.RegisterAuth(mockAuth)
+	mockNotificationRepo := mock_notification_repo.NewMockNotificationRepo(mockCtrl)
+	notification_repo.RegisterNotification(mockNotificationRepo)
+	mockRealtimeRepo := mock_notification_repo.NewMockRealtimeRepo(mockCtrl)
+	notification_repo.RegisterRealtime(mockRealtimeRepo)
+
+	mockAuth.U().Get(1)
+	reqCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := make(chan *notification_repo.RealtimeEvent)
+	mockRealtimeRepo.EXPECT().Subscribe(gomock.Any(), int64(1)).Return(events, nil)
+	// 重连时补发last id之后的通知
+	mockNotificationRepo.EXPECT().FindAfter(gomock.Any(), int64(1), int64(10), streamLimit).Return([]*notification_entity.Notification{
+		{ID: 11, UserID: 1, Type: notification_entity.IssueCreateTemplate, Params: "{}"},
+	}, nil)
+	mockNotificationRepo.EXPECT().CountUnread(gomock.Any(), int64(1), int32(0)).Return(int64(3), nil)
+	// 收到新通知时推送上次之后的通知和未读数
+	mockNotificationRepo.EXPECT().FindAfter(gomock.Any(), int64(1), int64(11), streamLimit).Return([]*notification_entity.Notification{
+		{ID: 12, UserID: 1, Type: notification_entity.MentionTemplate, Params: "{}"},
+	}, nil)
+	mockNotificationRepo.EXPECT().CountUnread(gomock.Any(), int64(1), int32(0)).Return(int64(4), nil)
+	// 标记已读后只推送未读数
+	mockNotificationRepo.EXPECT().CountUnread(gomock.Any(), int64(1), int32(0)).DoAndReturn(func(ctx context.Context, userId int64, templateType int32) (int64, error) {
+		cancel()
+		return 0, nil
+	})
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = httptest.NewRequest("GET", "/notifications/stream", nil).WithContext(reqCtx)
+	ctx.Request.Header.Set("Last-Event-ID", "10")
+	go func() {
+		events <- &notification_repo.RealtimeEvent{Event: notification_repo.RealtimeEventNotification, ID: 12}
+		events <- &notification_repo.RealtimeEvent{Event: notification_repo.RealtimeEventUnread}
+	}()
+	done := make(chan error)
+	go func() {
+		done <- Notification().Stream(ctx, &api.StreamRequest{LastID: 1})
+	}()
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("stream not finished")
+	}
+	body := w.Body.String()
+	assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+	assert.Contains(t, body, "id: 11\nevent: notification\n")
+	assert.True(t, strings.Index(body, "id: 11\nevent: notification\n") < strings.Index(body, "id: 12\nevent: notification\n"))
+	assert.Contains(t, body, "event: unread\ndata: {\"total\":3}\n\n")
+	assert.Contains(t, body, "event: unread\ndata: {\"total\":4}\n\n")
+}
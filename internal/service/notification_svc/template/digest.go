@@ -0,0 +1,62 @@
+package template
+
+import (
+	"html/template"
+
+	"github.com/scriptscat/scriptlist/internal/model/entity/notification_entity"
+)
+
+const (
+	DigestTitle   = "ScriptCat: 您有{{.Value.Total}}条新通知"
+	DigestContent = `
+{{- range .Value.Scripts}}
+<h3>{{if .Name}}{{.Name}}{{else}}其它{{end}}</h3>
+{{- range .Groups}}
+<p><b>{{.Name}}</b>({{len .Items}})</p>
+<ul>
+{{- range .Items}}
+  <li>{{if .Link}}<a href="{{.Link}}">{{.Title}}</a>{{else}}{{.Title}}{{end}}</li>
+{{- end}}
+</ul>
+{{- end}}
+{{- end}}
+<hr/>您可以在<a href="{{.Config.Url}}/users/notify">个人设置页面</a>中修改邮件的发送方式
+`
+)
+
+// DigestTypeName 汇总邮件中通知类型的名称
+var DigestTypeName = map[notification_entity.Type]string{
+	notification_entity.ScriptUpdateTemplate:     "脚本更新",
+	notification_entity.IssueCreateTemplate:      "新的反馈",
+	notification_entity.CommentCreateTemplate:    "反馈动态",
+	notification_entity.ScriptScoreTemplate:      "新的评分",
+	notification_entity.ScriptScoreReplyTemplate: "评分回复",
+	notification_entity.ReportCreateTemplate:     "新的举报",
+	notification_entity.ReportCommentTemplate:    "举报评论",
+	notification_entity.MentionTemplate:          "提到了您",
+}
+
+// Digest 汇总邮件, 按脚本和通知类型分组
+type Digest struct {
+	Total   int             `json:"total"`
+	Scripts []*DigestScript `json:"scripts"`
+}
+
+type DigestScript struct {
+	ID     int64          `json:"id"`
+	Name   string         `json:"name"`
+	Groups []*DigestGroup `json:"groups"`
+}
+
+type DigestGroup struct {
+	Type  notification_entity.Type `json:"type"`
+	Name  string                   `json:"name"`
+	Items []*DigestItem            `json:"items"`
+}
+
+type DigestItem struct {
+	// Title 通知标题, 缓存时已经由模板渲染转义, 不能再次转义
+	Title      template.HTML `json:"title"`
+	Link       string        `json:"link"`
+	Createtime int64         `json:"createtime"`
+}
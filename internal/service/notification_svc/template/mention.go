@@ -0,0 +1,23 @@
+package template
+
+const (
+	MentionTitle   = "[{{.Value.Name}}]{{.Value.Username}}提到了您"
+	MentionContent = `
+  {{.Value.Content}}
+  <hr/>
+  <a href="{{.Config.Url}}{{.Value.Path}}">点击查看原文</a><hr/>您可以在<a href="{{.Config.Url}}/users/notify">个人设置页面</a>中取消本邮件的通知
+`
+)
+
+type Mention struct {
+	ScriptID int64  `json:"script_id"`
+	Name     string `json:"name"`     // 脚本名
+	Username string `json:"username"` // 提及人
+	Title    string `json:"title"`    // 反馈或举报的标题
+	Content  string `json:"content"`
+	Path     string `json:"path"` // 提及所在的页面
+}
+
+func (m *Mention) Link() string {
+	return m.Path
+}
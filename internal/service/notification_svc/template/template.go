@@ -105,4 +105,23 @@ var TplMap = map[notification_entity.Type]map[sender.Type]Template{
 			Content: ScriptDeleteContent,
 		},
 	},
+	notification_entity.MentionTemplate: {
+		sender.InAppSender: {
+			Content: "mention.content",
+		},
+		sender.MailSender: {
+			Title:   MentionTitle,
+			Content: MentionContent,
+		},
+	},
+}
+
+func init() {
+	// webhook使用邮件的标题和应用内通知的内容标识
+	for _, tpl := range TplMap {
+		tpl[sender.WebhookSender] = Template{
+			Title:   tpl[sender.MailSender].Title,
+			Content: tpl[sender.InAppSender].Content,
+		}
+	}
 }
@@ -0,0 +1,212 @@
+package notification_svc
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/cago-frame/cago/pkg/consts"
+	"github.com/cago-frame/cago/pkg/utils/httputils"
+	api "github.com/scriptscat/scriptlist/internal/api/notification"
+	"github.com/scriptscat/scriptlist/internal/model/entity/notification_entity"
+	"github.com/scriptscat/scriptlist/internal/repository/notification_repo"
+	"github.com/scriptscat/scriptlist/internal/repository/user_repo"
+	"github.com/scriptscat/scriptlist/internal/service/auth_svc"
+	"github.com/scriptscat/scriptlist/internal/service/notification_svc/sender"
+	template2 "github.com/scriptscat/scriptlist/internal/service/notification_svc/template"
+	"github.com/scriptscat/scriptlist/internal/task/producer"
+)
+
+type WebhookSvc interface {
+	// GetWebhook 获取webhook配置
+	GetWebhook(ctx context.Context, req *api.GetWebhookRequest) (*api.GetWebhookResponse, error)
+	// UpdateWebhook 更新webhook配置
+	UpdateWebhook(ctx context.Context, req *api.UpdateWebhookRequest) (*api.UpdateWebhookResponse, error)
+	// ListWebhookLog webhook投递记录
+	ListWebhookLog(ctx context.Context, req *api.ListWebhookLogRequest) (*api.ListWebhookLogResponse, error)
+	// PingWebhook 同步发送一次测试请求
+	PingWebhook(ctx context.Context, req *api.PingWebhookRequest) (*api.PingWebhookResponse, error)
+	// CleanWebhookLog 清理过期的投递记录
+	CleanWebhookLog(ctx context.Context, now time.Time) error
+}
+
+const (
+	// webhookLogRetention 投递记录保留的天数
+	webhookLogRetention = 30
+	// webhookLogCleanBatch 每次删除的投递记录数量, 避免长时间锁表
+	webhookLogCleanBatch = 1000
+)
+
+type webhookSvc struct {
+}
+
+var defaultWebhook = &webhookSvc{}
+
+func Webhook() WebhookSvc {
+	return defaultWebhook
+}
+
+// GetWebhook 获取webhook配置
+func (w *webhookSvc) GetWebhook(ctx context.Context, req *api.GetWebhookRequest) (*api.GetWebhookResponse, error) {
+	hook, err := notification_repo.Webhook().FindByUser(ctx, auth_svc.Auth().Get(ctx).UID)
+	if err != nil {
+		return nil, err
+	}
+	if hook == nil {
+		return &api.GetWebhookResponse{Webhook: &api.Webhook{
+			Types:  []notification_entity.Type{},
+			Status: consts.DELETE,
+		}}, nil
+	}
+	return &api.GetWebhookResponse{Webhook: w.toWebhook(hook, false)}, nil
+}
+
+// UpdateWebhook 更新webhook配置, 不存在时创建
+func (w *webhookSvc) UpdateWebhook(ctx context.Context, req *api.UpdateWebhookRequest) (*api.UpdateWebhookResponse, error) {
+	if err := notification_entity.CheckURL(ctx, req.URL); err != nil {
+		return nil, err
+	}
+	uid := auth_svc.Auth().Get(ctx).UID
+	hook, err := notification_repo.Webhook().FindByUser(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	// 忽略不存在的通知类型
+	types := make([]notification_entity.Type, 0, len(req.Types))
+	for _, t := range req.Types {
+		if _, ok := template2.TplMap[t]; ok {
+			types = append(types, t)
+		}
+	}
+	now := time.Now().Unix()
+	if hook == nil {
+		hook = &notification_entity.NotificationWebhook{
+			UserID:     uid,
+			URL:        req.URL,
+			Status:     req.Status,
+			Createtime: now,
+			Updatetime: now,
+		}
+		hook.ResetSecret()
+		hook.SetTypes(types)
+		if err := notification_repo.Webhook().Create(ctx, hook); err != nil {
+			return nil, err
+		}
+		return &api.UpdateWebhookResponse{Webhook: w.toWebhook(hook, true)}, nil
+	}
+	hook.URL = req.URL
+	hook.Status = req.Status
+	hook.SetTypes(types)
+	if req.ResetSecret {
+		hook.ResetSecret()
+	}
+	hook.Updatetime = now
+	if err := notification_repo.Webhook().Update(ctx, hook); err != nil {
+		return nil, err
+	}
+	return &api.UpdateWebhookResponse{Webhook: w.toWebhook(hook, req.ResetSecret)}, nil
+}
+
+// ListWebhookLog webhook投递记录
+func (w *webhookSvc) ListWebhookLog(ctx context.Context, req *api.ListWebhookLogRequest) (*api.ListWebhookLogResponse, error) {
+	list, total, err := notification_repo.WebhookLog().FindPage(ctx, auth_svc.Auth().Get(ctx).UID, req.PageRequest)
+	if err != nil {
+		return nil, err
+	}
+	ret := &api.ListWebhookLogResponse{
+		PageResponse: httputils.PageResponse[*api.WebhookLog]{
+			List:  make([]*api.WebhookLog, len(list)),
+			Total: total,
+		},
+	}
+	for n, v := range list {
+		ret.List[n] = w.toWebhookLog(v)
+	}
+	return ret, nil
+}
+
+// PingWebhook 同步发送一次测试请求, 停用时也可以测试
+func (w *webhookSvc) PingWebhook(ctx context.Context, req *api.PingWebhookRequest) (*api.PingWebhookResponse, error) {
+	uid := auth_svc.Auth().Get(ctx).UID
+	hook, err := notification_repo.Webhook().FindByUser(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	if err := hook.CheckOperate(ctx); err != nil {
+		return nil, err
+	}
+	user, err := user_repo.User().Find(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	payload := &sender.WebhookPayload{
+		Event: notification_entity.WebhookEventPing,
+		Time:  time.Now().Unix(),
+	}
+	if user != nil {
+		payload.User = user.UserInfo()
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	log := sender.DeliverWebhook(ctx, hook, &producer.NotificationWebhookMsg{
+		WebhookID: hook.ID,
+		UserID:    uid,
+		Delivery:  sender.NewWebhookDelivery(),
+		Event:     payload.Event,
+		Payload:   body,
+	}, 1)
+	if err := notification_repo.WebhookLog().Create(ctx, log); err != nil {
+		return nil, err
+	}
+	return &api.PingWebhookResponse{WebhookLog: w.toWebhookLog(log)}, nil
+}
+
+// CleanWebhookLog 分批删除超过保留天数的投递记录
+func (w *webhookSvc) CleanWebhookLog(ctx context.Context, now time.Time) error {
+	t := now.AddDate(0, 0, -webhookLogRetention).Unix()
+	for {
+		num, err := notification_repo.WebhookLog().DeleteBefore(ctx, t, webhookLogCleanBatch)
+		if err != nil {
+			return err
+		}
+		if num < webhookLogCleanBatch {
+			return nil
+		}
+	}
+}
+
+// toWebhook 签名密钥只在创建和重新生成时返回
+func (w *webhookSvc) toWebhook(hook *notification_entity.NotificationWebhook, secret bool) *api.Webhook {
+	ret := &api.Webhook{
+		ID:         hook.ID,
+		URL:        hook.URL,
+		Types:      hook.GetTypes(),
+		Status:     hook.Status,
+		Createtime: hook.Createtime,
+		Updatetime: hook.Updatetime,
+	}
+	if secret {
+		ret.Secret = hook.Secret
+	}
+	return ret
+}
+
+func (w *webhookSvc) toWebhookLog(log *notification_entity.NotificationWebhookLog) *api.WebhookLog {
+	return &api.WebhookLog{
+		ID:         log.ID,
+		Delivery:   log.Delivery,
+		Event:      log.Event,
+		Type:       log.Type,
+		URL:        log.URL,
+		Payload:    log.Payload,
+		Attempt:    log.Attempt,
+		StatusCode: log.StatusCode,
+		Response:   log.Response,
+		Error:      log.Error,
+		Duration:   log.Duration,
+		Status:     log.Status,
+		Createtime: log.Createtime,
+	}
+}
@@ -0,0 +1,35 @@
+package notification_svc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/scriptscat/scriptlist/internal/model/entity/notification_entity"
+	"github.com/scriptscat/scriptlist/internal/repository/notification_repo"
+	mock_notification_repo "github.com/scriptscat/scriptlist/internal/repository/notification_repo/mock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestWebhookSvc_CleanWebhookLog(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockLogRepo := mock_notification_repo.NewMockWebhookLogRepo(mockCtrl)
+	notification_repo.RegisterWebhookLog(mockLogRepo)
+
+	now := time.Date(2026, 3, 31, 4, 30, 0, 0, time.Local)
+	before := now.AddDate(0, 0, -webhookLogRetention).Unix()
+	// 删除数量等于批量大小时继续删除
+	gomock.InOrder(
+		mockLogRepo.EXPECT().DeleteBefore(gomock.Any(), before, webhookLogCleanBatch).Return(int64(webhookLogCleanBatch), nil),
+		mockLogRepo.EXPECT().DeleteBefore(gomock.Any(), before, webhookLogCleanBatch).Return(int64(10), nil),
+	)
+	assert.NoError(t, (&webhookSvc{}).CleanWebhookLog(context.Background(), now))
+}
+
+func TestWebhookSvc_toWebhook(t *testing.T) {
+	hook := &notification_entity.NotificationWebhook{ID: 1, URL: "https://example.com", Secret: "secret"}
+	assert.Empty(t, (&webhookSvc{}).toWebhook(hook, false).Secret)
+	assert.Equal(t, "secret", (&webhookSvc{}).toWebhook(hook, true).Secret)
+}
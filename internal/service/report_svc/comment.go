@@ -7,10 +7,12 @@ import (
 	"github.com/cago-frame/cago/pkg/consts"
 	"github.com/cago-frame/cago/pkg/utils/httputils"
 	api "github.com/scriptscat/scriptlist/internal/api/report"
+	"github.com/scriptscat/scriptlist/internal/model/entity/notification_entity"
 	"github.com/scriptscat/scriptlist/internal/model/entity/report_entity"
 	"github.com/scriptscat/scriptlist/internal/repository/report_repo"
 	"github.com/scriptscat/scriptlist/internal/repository/user_repo"
 	"github.com/scriptscat/scriptlist/internal/service/auth_svc"
+	"github.com/scriptscat/scriptlist/internal/service/notification_svc"
 	"github.com/scriptscat/scriptlist/internal/service/script_svc"
 	"github.com/scriptscat/scriptlist/internal/task/producer"
 )
@@ -71,6 +73,12 @@ func (c *reportCommentSvc) toComment(ctx context.Context, comment *report_entity
 		return nil, err
 	}
 	ret.UserInfo = user.UserInfo()
+	if comment.Type == report_entity.CommentTypeComment {
+		ret.Mentions, err = notification_svc.Mention().Links(ctx, notification_entity.MentionTargetReportComment, comment.ID, comment.Content)
+		if err != nil {
+			return nil, err
+		}
+	}
 	return ret, nil
 }
 
@@ -0,0 +1,133 @@
+package script_svc
+
+import (
+	"context"
+
+	"github.com/cago-frame/cago/pkg/consts"
+	"github.com/cago-frame/cago/pkg/logger"
+	"github.com/scriptscat/scriptlist/internal/model/entity/script_entity"
+	"github.com/scriptscat/scriptlist/internal/repository/script_repo"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.uber.org/zap"
+)
+
+// ReconcileResult 对账结果
+type ReconcileResult struct {
+	Checked  int64 // 检查的脚本数量
+	Missing  int64 // 索引中缺失的脚本
+	Mismatch int64 // 状态/公开/不适内容/更新时间不一致的脚本
+	Orphan   int64 // 索引中存在但是mysql中不存在的脚本
+	Repaired int64
+	Failed   int64
+}
+
+// reconcileBatch 每次对账的脚本数量
+const reconcileBatch = 500
+
+var reconcileCounter, _ = otel.Meter("github.com/scriptscat/scriptlist/internal/service/script_svc").
+	Int64Counter("script_search_reconcile_total", metric.WithDescription("搜索索引对账数量"))
+
+func (r *ReconcileResult) report(ctx context.Context) {
+	if reconcileCounter == nil {
+		return
+	}
+	for k, v := range map[string]int64{
+		"checked":  r.Checked,
+		"missing":  r.Missing,
+		"mismatch": r.Mismatch,
+		"orphan":   r.Orphan,
+		"repaired": r.Repaired,
+		"failed":   r.Failed,
+	} {
+		reconcileCounter.Add(ctx, v, metric.WithAttributes(attribute.String("type", k)))
+	}
+}
+
+// ReconcileSearch 按id分批对比mysql与搜索索引中的id,status,public,unwell,updatetime, 修复不一致的部分,
+// mysql中最大id之后的索引也会检查, 直到索引中的最大id
+func (s *scriptSvc) ReconcileSearch(ctx context.Context) (*ReconcileResult, error) {
+	ret := &ReconcileResult{}
+	defer ret.report(ctx)
+	lastId := int64(0)
+	for {
+		list, err := script_repo.Migrate().List(ctx, lastId, reconcileBatch)
+		if err != nil {
+			return ret, err
+		}
+		if len(list) == 0 {
+			break
+		}
+		startId, endId := lastId+1, list[len(list)-1].ID
+		docs, err := script_repo.SearchEngine().FindByIDRange(ctx, startId, endId)
+		if err != nil {
+			return ret, err
+		}
+		indexed := make(map[int64]*script_entity.ScriptSearch, len(docs))
+		for _, v := range docs {
+			indexed[v.ID] = v
+		}
+		for _, v := range list {
+			ret.Checked++
+			doc, ok := indexed[v.ID]
+			delete(indexed, v.ID)
+			if !ok {
+				ret.Missing++
+			} else if doc.Status == v.Status && doc.Public == v.Public &&
+				doc.Unwell == v.Unwell && doc.Updatetime == v.Updatetime {
+				continue
+			} else {
+				ret.Mismatch++
+			}
+			logger := logger.Ctx(ctx).With(zap.Int64("script_id", v.ID), zap.Bool("missing", !ok))
+			search, err := script_repo.Migrate().Convert(ctx, v)
+			if err == nil {
+				err = script_repo.Migrate().Save(ctx, search)
+			}
+			if err != nil {
+				logger.Error("修复搜索索引失败", zap.Error(err))
+				ret.Failed++
+				continue
+			}
+			logger.Info("修复搜索索引成功")
+			ret.Repaired++
+		}
+		// 剩下的是mysql中已经不存在的脚本
+		orphans := make([]*script_entity.ScriptSearch, 0, len(indexed))
+		for _, v := range indexed {
+			orphans = append(orphans, v)
+		}
+		s.deleteOrphans(ctx, ret, orphans)
+		lastId = endId
+	}
+	// mysql中最大id之后的索引都是多余的
+	maxId, err := script_repo.SearchEngine().MaxID(ctx)
+	if err != nil {
+		return ret, err
+	}
+	for startId := lastId + 1; startId <= maxId; startId += reconcileBatch {
+		docs, err := script_repo.SearchEngine().FindByIDRange(ctx, startId, startId+reconcileBatch-1)
+		if err != nil {
+			return ret, err
+		}
+		s.deleteOrphans(ctx, ret, docs)
+	}
+	return ret, nil
+}
+
+// deleteOrphans 删除mysql中已经不存在的脚本的索引
+func (s *scriptSvc) deleteOrphans(ctx context.Context, ret *ReconcileResult, docs []*script_entity.ScriptSearch) {
+	for _, doc := range docs {
+		if doc.Status == consts.DELETE {
+			continue
+		}
+		ret.Orphan++
+		if err := script_repo.Migrate().Delete(ctx, doc.ID); err != nil {
+			logger.Ctx(ctx).Error("删除多余的搜索索引失败", zap.Int64("script_id", doc.ID), zap.Error(err))
+			ret.Failed++
+			continue
+		}
+		ret.Repaired++
+	}
+}
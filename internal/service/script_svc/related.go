@@ -0,0 +1,73 @@
+package script_svc
+
+import (
+	"context"
+
+	"github.com/cago-frame/cago/pkg/consts"
+	"github.com/cago-frame/cago/pkg/logger"
+	api "github.com/scriptscat/scriptlist/internal/api/script"
+	"github.com/scriptscat/scriptlist/internal/model/entity/script_entity"
+	"github.com/scriptscat/scriptlist/internal/repository/script_repo"
+	"github.com/scriptscat/scriptlist/internal/repository/statistics_repo"
+	"go.uber.org/zap"
+)
+
+// Related 相关脚本推荐, 只返回公开,非不适内容且未归档的脚本
+func (s *scriptSvc) Related(ctx context.Context, req *api.RelatedRequest) (*api.RelatedResponse, error) {
+	script := s.CtxScript(ctx)
+	resp := &api.RelatedResponse{
+		Installed: make([]*api.Script, 0),
+		Similar:   make([]*api.Script, 0),
+	}
+	// 多取一些, 过滤后再截取
+	installed, err := statistics_repo.CoInstall().Related(ctx, script.ID, req.Size*3)
+	if err != nil {
+		return nil, err
+	}
+	resp.Installed, err = s.relatedScripts(ctx, installed, req)
+	if err != nil {
+		return nil, err
+	}
+	search, err := script_repo.Migrate().Convert(ctx, script)
+	if err != nil {
+		return nil, err
+	}
+	similar, err := script_repo.SearchEngine().Similar(ctx, search, req.Size)
+	if err != nil {
+		// 搜索后端异常时只返回共同安装
+		logger.Ctx(ctx).Error("查找相似脚本失败", zap.Int64("script_id", script.ID), zap.Error(err))
+		return resp, nil
+	}
+	resp.Similar, err = s.relatedScripts(ctx, similar, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (s *scriptSvc) relatedScripts(ctx context.Context, ids []int64, req *api.RelatedRequest) ([]*api.Script, error) {
+	ret := make([]*api.Script, 0, req.Size)
+	for _, id := range ids {
+		if len(ret) >= req.Size {
+			break
+		}
+		if id == req.ID {
+			continue
+		}
+		script, err := script_repo.Script().Find(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if script == nil || script.Status != consts.ACTIVE || script.Public != script_entity.PublicScript ||
+			script.Unwell != script_entity.Well || script.Archive != script_entity.IsActive {
+			continue
+		}
+		data, err := s.ToScript(ctx, script, false, "")
+		if err != nil {
+			return nil, err
+		}
+		s.localize(ctx, data, req.Languages())
+		ret = append(ret, data)
+	}
+	return ret, nil
+}
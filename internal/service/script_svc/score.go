@@ -2,6 +2,7 @@ package script_svc
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"time"
 
@@ -64,13 +65,14 @@ func (s *scoreSvc) ReplyScore(ctx context.Context, req *api.ReplyScoreRequest) (
 	}
 	if reply == nil {
 		//不存在记录，创建一条记录
-		err := script_repo.ScriptScore().CreateReplayByComment(ctx, &script_entity.ScriptScoreReply{
+		reply = &script_entity.ScriptScoreReply{
 			CommentID:  commentID,
 			ScriptID:   scriptId,
 			Message:    req.Message,
 			Createtime: time.Now().Unix(),
 			Updatetime: time.Now().Unix(),
-		})
+		}
+		err := script_repo.ScriptScore().CreateReplayByComment(ctx, reply)
 		if err != nil {
 			return nil, err
 		}
@@ -84,17 +86,36 @@ func (s *scoreSvc) ReplyScore(ctx context.Context, req *api.ReplyScoreRequest) (
 			// 发送失败不影响主要流程, 只记录错误
 			logger.Ctx(ctx).Error("作者回复通知失败", zap.Int64("script", scriptId), zap.Int64("commentID", commentID), zap.Error(err))
 		}
+		s.mentionReply(ctx, script, reply, "")
 		return &api.ReplyScoreResponse{}, nil
 	}
+	oldMessage := reply.Message
 	reply.Message = req.Message
 	reply.Updatetime = time.Now().Unix()
 	err = script_repo.ScriptScore().UpdateReplayByComment(ctx, reply)
 	if err != nil {
 		return nil, err
 	}
+	s.mentionReply(ctx, script, reply, oldMessage)
 	return &api.ReplyScoreResponse{}, nil
 }
 
+// mentionReply 通知评分回复中@的用户
+func (s *scoreSvc) mentionReply(ctx context.Context, script *script_entity.Script, reply *script_entity.ScriptScoreReply, oldMessage string) {
+	if err := notification_svc.Mention().Mention(ctx, &notification_svc.MentionOptions{
+		Target:     notification_entity.MentionTargetScoreReply,
+		TargetID:   reply.ID,
+		ScriptID:   script.ID,
+		ScriptName: script.Name,
+		From:       script.UserID,
+		Content:    reply.Message,
+		OldContent: oldMessage,
+		Path:       fmt.Sprintf("/script-show-page/%d/comment", script.ID),
+	}); err != nil {
+		logger.Ctx(ctx).Error("评分回复提及通知失败", zap.Int64("script", script.ID), zap.Int64("reply", reply.ID), zap.Error(err))
+	}
+}
+
 var defaultScore = &scoreSvc{}
 
 func Score() ScoreSvc {
@@ -245,6 +266,10 @@ func (s *scoreSvc) ToScore(ctx context.Context, score *script_entity.ScriptScore
 	if comment != nil {
 		resp.AuthorMessage = comment.Message
 		resp.AuthorMessageCreatetime = comment.Createtime
+		resp.AuthorMessageMentions, err = notification_svc.Mention().Links(ctx, notification_entity.MentionTargetScoreReply, comment.ID, comment.Message)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	return resp, nil
@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
@@ -20,9 +21,11 @@ import (
 	"github.com/cago-frame/cago/database/db"
 	"github.com/cago-frame/cago/database/redis"
 	"github.com/cago-frame/cago/pkg/consts"
+	"github.com/cago-frame/cago/pkg/gogo"
 	"github.com/cago-frame/cago/pkg/i18n"
 	"github.com/cago-frame/cago/pkg/logger"
 	"github.com/cago-frame/cago/pkg/opentelemetry/trace"
+	"github.com/cago-frame/cago/pkg/utils"
 	"github.com/cago-frame/cago/pkg/utils/httputils"
 	"github.com/gin-gonic/gin"
 	api "github.com/scriptscat/scriptlist/internal/api/script"
@@ -54,16 +57,36 @@ const (
 type ScriptSvc interface {
 	// List 获取脚本列表
 	List(ctx context.Context, req *api.ListRequest) (*api.ListResponse, error)
+	// Match 根据网址查找会在该页面运行的脚本
+	Match(ctx context.Context, req *api.MatchRequest) (*api.MatchResponse, error)
+	// SearchAnalytics 搜索词统计
+	SearchAnalytics(ctx context.Context, req *api.SearchAnalyticsRequest) (*api.SearchAnalyticsResponse, error)
+	// CleanSearchLog 清理过期的搜索日志
+	CleanSearchLog(ctx context.Context, now time.Time) error
+	// Suggest 搜索建议
+	Suggest(ctx context.Context, req *api.SuggestRequest) (*api.SuggestResponse, error)
 	// Create 创建脚本/库
 	Create(ctx context.Context, req *api.CreateRequest) (*api.CreateResponse, error)
 	// UpdateCode 更新脚本/库代码
 	UpdateCode(ctx context.Context, req *api.UpdateCodeRequest) (*api.UpdateCodeResponse, error)
-	// MigrateEs 全量迁移数据到es
-	MigrateEs()
+	// MigrateEs 全量迁移数据到es, 存在未完成的任务时从检查点继续
+	MigrateEs(ctx context.Context, req *api.MigrateEsRequest) (*api.MigrateEsResponse, error)
+	// MigrateEsProgress 获取迁移数据到es的进度
+	MigrateEsProgress(ctx context.Context, req *api.MigrateEsProgressRequest) (*api.MigrateEsProgressResponse, error)
+	// CancelMigrateEs 取消迁移数据到es
+	CancelMigrateEs(ctx context.Context, req *api.CancelMigrateEsRequest) (*api.CancelMigrateEsResponse, error)
+	// ResumeMigrateEs 恢复因进程退出而中断的迁移任务, 索引映射过旧时记录告警
+	ResumeMigrateEs(ctx context.Context) error
+	// UpdateTrending 重新计算所有脚本的热度, 并同步到搜索索引
+	UpdateTrending(ctx context.Context) error
+	// ReconcileSearch 对比mysql与搜索索引中的数据, 修复不一致的部分
+	ReconcileSearch(ctx context.Context) (*ReconcileResult, error)
 	// GetCode 获取脚本代码,version为latest时获取最新版本
 	GetCode(ctx context.Context, id int64, version string) (*script_entity.Code, error)
 	// Info 获取脚本信息
 	Info(ctx context.Context, req *api.InfoRequest) (*api.InfoResponse, error)
+	// Related 相关脚本推荐, 包括共同安装与内容相似的脚本
+	Related(ctx context.Context, req *api.RelatedRequest) (*api.RelatedResponse, error)
 	// Code 获取脚本代码
 	Code(ctx context.Context, req *api.CodeRequest) (*api.CodeResponse, error)
 	// VersionList 获取版本列表
@@ -163,24 +186,142 @@ func (s *scriptSvc) List(ctx context.Context, req *api.ListRequest) (*api.ListRe
 	if req.Category > 0 {
 		searchOptions.Category = []int64{req.Category}
 	}
-	resp, total, err := script_repo.Script().Search(ctx, searchOptions, req.PageRequest)
-	if err != nil {
-		return nil, err
+	var (
+		resp   []*script_entity.Script
+		total  int64
+		result *script_repo.SearchResult
+		err    error
+	)
+	if req.Keyword != "" {
+		// 关键字搜索额外返回高亮与分面统计
+		result, err = script_repo.SearchEngine().Search(ctx, searchOptions, req.PageRequest)
+		if err != nil {
+			return nil, err
+		}
+		resp, total = result.List, result.Total
+	} else {
+		resp, total, err = script_repo.Script().Search(ctx, searchOptions, req.PageRequest)
+		if err != nil {
+			return nil, err
+		}
 	}
 	list := make([]*api.Script, 0)
+	languages := req.Languages()
 	for _, item := range resp {
 		data, err := s.ToScript(ctx, item, false, "")
 		if err != nil {
 			return nil, err
 		}
+		s.localize(ctx, data, languages)
+		if result != nil {
+			data.Highlight = result.Highlight[item.ID]
+		}
 		list = append(list, data)
 	}
-	return &api.ListResponse{
+	ret := &api.ListResponse{
 		PageResponse: httputils.PageResponse[*api.Script]{
 			List:  list,
 			Total: total,
 		},
-	}, nil
+	}
+	if result != nil {
+		ret.Facets = &api.SearchFacets{
+			Category: s.toFacetItems(ctx, result.Facets["category"], true),
+			Tag:      s.toFacetItems(ctx, result.Facets["tag"], true),
+			Type:     s.toFacetItems(ctx, result.Facets["type"], false),
+			Domain:   s.toFacetItems(ctx, result.Facets["domain"], false),
+		}
+		// 只记录第一页, 翻页时前端继续使用第一页的搜索id
+		if req.GetPage() <= 1 {
+			ret.SearchID = s.logSearch(ctx, req, total)
+		}
+	}
+	return ret, nil
+}
+
+// toFacetItems 转换分面统计, category为true时key为分类id, 需要补充分类名称
+func (s *scriptSvc) toFacetItems(ctx context.Context, facets []*script_repo.SearchFacet, category bool) []*api.FacetItem {
+	ret := make([]*api.FacetItem, 0, len(facets))
+	for _, v := range facets {
+		item := &api.FacetItem{
+			Key:   v.Key,
+			Count: v.Count,
+		}
+		if category {
+			id, _ := strconv.ParseInt(v.Key, 10, 64)
+			c, err := script_repo.ScriptCategoryList().Find(ctx, id)
+			if err != nil {
+				logger.Ctx(ctx).Error("获取脚本分类失败", zap.Error(err), zap.Int64("category_id", id))
+			} else if c != nil {
+				item.Name = c.Name
+			}
+		}
+		ret = append(ret, item)
+	}
+	return ret
+}
+
+// matchCandidateBatch 根据网址匹配脚本时每批检查的候选脚本数量, 匹配所有网址的脚本很多, 分批加载规则
+const matchCandidateBatch = 1000
+
+// Match 根据网址查找会在该页面运行的脚本
+func (s *scriptSvc) Match(ctx context.Context, req *api.MatchRequest) (*api.MatchResponse, error) {
+	u, err := url.Parse(req.URL)
+	if err != nil || u.Scheme == "" || u.Hostname() == "" {
+		return nil, i18n.NewError(ctx, code.ScriptMatchUrlInvalid)
+	}
+	// 先通过主机缩小范围, 再分批逐个脚本精确匹配, 所有候选脚本都会检查, 保证总数准确
+	hosts := script_entity.MatchCandidateHosts(u.Hostname())
+	matched := make([]int64, 0)
+	for lastId := int64(0); ; {
+		ids, err := script_repo.ScriptMatch().FindCandidates(ctx, hosts, lastId, matchCandidateBatch)
+		if err != nil {
+			return nil, err
+		}
+		if len(ids) == 0 {
+			break
+		}
+		rules, err := script_repo.ScriptMatch().FindByScriptIDs(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		ruleMap := make(map[int64][]*script_entity.ScriptMatch)
+		for _, v := range rules {
+			ruleMap[v.ScriptID] = append(ruleMap[v.ScriptID], v)
+		}
+		for _, id := range ids {
+			if script_entity.MatchScriptURL(ruleMap[id], u) {
+				matched = append(matched, id)
+			}
+		}
+		if len(ids) < matchCandidateBatch {
+			break
+		}
+		lastId = ids[len(ids)-1]
+	}
+	resp := &api.MatchResponse{
+		PageResponse: httputils.PageResponse[*api.Script]{
+			List:  make([]*api.Script, 0),
+			Total: int64(len(matched)),
+		},
+	}
+	if req.GetOffset() >= len(matched) {
+		return resp, nil
+	}
+	list, err := script_repo.ScriptMatch().FindByDownload(ctx, matched, req.GetOffset(), req.GetLimit())
+	if err != nil {
+		return nil, err
+	}
+	languages := req.Languages()
+	for _, v := range list {
+		data, err := s.ToScript(ctx, v, false, "")
+		if err != nil {
+			return nil, err
+		}
+		s.localize(ctx, data, languages)
+		resp.List = append(resp.List, data)
+	}
+	return resp, nil
 }
 
 func (s *scriptSvc) ToScript(ctx context.Context, item *script_entity.Script, withcode bool, version string) (*api.Script, error) {
@@ -280,6 +421,28 @@ func (s *scriptSvc) ToScript(ctx context.Context, item *script_entity.Script, wi
 	return data, nil
 }
 
+// localize 根据语言偏好替换脚本的名称与描述
+func (s *scriptSvc) localize(ctx context.Context, data *api.Script, languages []string) {
+	if len(languages) == 0 {
+		return
+	}
+	locales, err := script_repo.ScriptLocale().FindByScriptID(ctx, data.ID)
+	if err != nil {
+		logger.Ctx(ctx).Error("获取脚本多语言信息失败", zap.Error(err), zap.Int64("script_id", data.ID))
+		return
+	}
+	locale := script_entity.MatchScriptLocale(locales, languages)
+	if locale == nil {
+		return
+	}
+	if locale.Name != "" {
+		data.Name = locale.Name
+	}
+	if locale.Description != "" {
+		data.Description = locale.Description
+	}
+}
+
 func (s *scriptSvc) scriptCode(ctx context.Context, script *script_entity.Script, code *script_entity.Code) *api.Code {
 	ret := &api.Code{
 		ID:           code.ID,
@@ -302,6 +465,54 @@ func (s *scriptSvc) scriptCode(ctx context.Context, script *script_entity.Script
 	return ret
 }
 
+// Suggest 搜索建议, 返回脚本名称, 标签, 作者与域名
+func (s *scriptSvc) Suggest(ctx context.Context, req *api.SuggestRequest) (*api.SuggestResponse, error) {
+	q := strings.TrimSpace(req.Q)
+	ret := &api.SuggestResponse{
+		Scripts: make([]*api.SuggestItem, 0),
+		Tags:    make([]*api.SuggestItem, 0),
+		Authors: make([]*api.SuggestItem, 0),
+		Domains: make([]*api.SuggestItem, 0),
+	}
+	if q == "" {
+		return ret, nil
+	}
+	scripts, err := script_repo.SearchEngine().Suggest(ctx, q, req.Size)
+	if err != nil {
+		logger.Ctx(ctx).Error("搜索建议失败", zap.String("q", q), zap.Error(err))
+		return nil, err
+	}
+	for _, v := range scripts {
+		ret.Scripts = append(ret.Scripts, &api.SuggestItem{
+			ID:    v.ID,
+			Name:  v.Name,
+			Count: v.TotalDownload,
+		})
+	}
+	for _, v := range []struct {
+		list *[]*api.SuggestItem
+		find func(ctx context.Context, prefix string, size int) ([]*script_repo.SuggestItem, error)
+	}{
+		{&ret.Tags, script_repo.ScriptSuggest().Tag},
+		{&ret.Authors, script_repo.ScriptSuggest().Author},
+		{&ret.Domains, script_repo.ScriptSuggest().Domain},
+	} {
+		list, err := v.find(ctx, q, req.Size)
+		if err != nil {
+			logger.Ctx(ctx).Error("搜索建议失败", zap.String("q", q), zap.Error(err))
+			return nil, err
+		}
+		for _, item := range list {
+			*v.list = append(*v.list, &api.SuggestItem{
+				ID:    item.ID,
+				Name:  item.Name,
+				Count: item.Count,
+			})
+		}
+	}
+	return ret, nil
+}
+
 // Create 创建脚本
 func (s *scriptSvc) Create(ctx context.Context, req *api.CreateRequest) (*api.CreateResponse, error) {
 	script := &script_entity.Script{
@@ -329,6 +540,7 @@ func (s *scriptSvc) Create(ctx context.Context, req *api.CreateRequest) (*api.Cr
 	err := db.Ctx(ctx).Transaction(func(tx *gorm.DB) error {
 		ctx = db.WithContextDB(ctx, tx)
 		var tags []string
+		var locales []*script_entity.ScriptLocale
 		if req.Type == script_entity.LibraryType {
 			// 脚本引用库
 			script.Name = req.Name
@@ -351,6 +563,7 @@ func (s *scriptSvc) Create(ctx context.Context, req *api.CreateRequest) (*api.Cr
 			}
 			script.Name = metaJson["name"][0]
 			script.Description = metaJson["description"][0]
+			locales = script_entity.ParseScriptLocale(metaJson)
 			// 处理tag关联
 			tags = metaJson["tags"]
 			if len(metaJson["background"]) > 0 || len(metaJson["crontab"]) > 0 {
@@ -378,6 +591,14 @@ func (s *scriptSvc) Create(ctx context.Context, req *api.CreateRequest) (*api.Cr
 				code.ScriptCreateFailed,
 			)
 		}
+		// 保存多语言信息
+		if err := script_repo.ScriptLocale().Save(ctx, script.ID, locales); err != nil {
+			logger.Ctx(ctx).Error("scriptSvc locale save failed", zap.Int64("script_id", script.ID), zap.Error(err))
+			return i18n.NewInternalError(
+				ctx,
+				code.ScriptCreateFailed,
+			)
+		}
 		// 处理分类关联
 		if err := Category().LinkScriptCategory(ctx, script.ID, req.CategoryID); err != nil {
 			return err
@@ -501,6 +722,14 @@ func (s *scriptSvc) UpdateCode(ctx context.Context, req *api.UpdateCodeRequest)
 		// 更新名字和描述
 		script.Name = metaJson["name"][0]
 		script.Description = metaJson["description"][0]
+		// 更新多语言信息, 需要在更新脚本之前保存, 以便同步到es中
+		if err := script_repo.ScriptLocale().Save(ctx, script.ID, script_entity.ParseScriptLocale(metaJson)); err != nil {
+			logger.Ctx(ctx).Error("scriptSvc locale save failed", zap.Int64("script_id", script.ID), zap.Error(err))
+			return nil, i18n.NewInternalError(
+				ctx,
+				code.ScriptUpdateFailed,
+			)
+		}
 		tags = req.Tags
 		if len(metaJson["background"]) > 0 || len(metaJson["crontab"]) > 0 {
 			tags = append(tags, "后台脚本")
@@ -603,38 +832,226 @@ func (s *scriptSvc) UpdateCode(ctx context.Context, req *api.UpdateCodeRequest)
 	return &api.UpdateCodeResponse{}, nil
 }
 
-// MigrateEs 全量迁移数据到es
-func (s *scriptSvc) MigrateEs() {
-	ctx, cancel := context.WithCancel(context.Background())
+// MigrateEs 全量迁移数据到es, 存在未完成的任务时从检查点继续
+func (s *scriptSvc) MigrateEs(ctx context.Context, req *api.MigrateEsRequest) (*api.MigrateEsResponse, error) {
+	owner := utils.RandString(16, utils.Mix)
+	if ok, err := script_repo.Migrate().Lock(ctx, owner, false); err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, i18n.NewError(ctx, code.ScriptMigrateRunning)
+	}
+	progress, err := s.startMigrate(ctx, owner, req.Mode, req.Restart)
+	if err != nil {
+		_ = script_repo.Migrate().Unlock(ctx, owner)
+		return nil, err
+	}
+	return &api.MigrateEsResponse{MigrateProgress: progress}, nil
+}
+
+// ResumeMigrateEs 恢复因进程退出而中断的迁移任务, 任务状态为进行中但是锁已过期
+// 没有进行中的任务时检查索引的映射版本, 过旧时只记录告警, 重建索引需要管理员执行
+func (s *scriptSvc) ResumeMigrateEs(ctx context.Context) error {
+	progress, err := script_repo.Migrate().GetProgress(ctx)
+	if err != nil {
+		return err
+	}
+	if progress == nil || progress.Status != script_entity.MigrateStatusRunning {
+		return s.checkMapping(ctx)
+	}
+	owner := utils.RandString(16, utils.Mix)
+	if ok, err := script_repo.Migrate().Lock(ctx, owner, false); err != nil {
+		return err
+	} else if !ok {
+		return nil
+	}
+	logger.Ctx(ctx).Info("恢复中断的迁移任务", zap.Int64("last_id", progress.LastID))
+	if _, err := s.startMigrate(ctx, owner, progress.Mode, false); err != nil {
+		_ = script_repo.Migrate().Unlock(ctx, owner)
+		return err
+	}
+	return nil
+}
+
+// checkMapping 索引的映射版本过旧时每小时记录一次告警, 提醒管理员以alias模式重建索引
+func (s *scriptSvc) checkMapping(ctx context.Context) error {
+	reindexer, ok := script_repo.SearchEngine().(script_repo.ScriptSearchReindexer)
+	if !ok {
+		return nil
+	}
+	version, err := reindexer.MappingVersion(ctx)
+	if err != nil {
+		return err
+	}
+	if version >= script_entity.ScriptSearchMappingVersion {
+		return nil
+	}
+	if ok, err := redis.Ctx(ctx).SetNX("script:migrate:mapping:warn", "1", time.Hour).Result(); err != nil {
+		return err
+	} else if !ok {
+		return nil
+	}
+	logger.Ctx(ctx).Warn("索引映射版本过旧, 请以alias模式重建索引", zap.Int("version", version),
+		zap.Int("latest", script_entity.ScriptSearchMappingVersion))
+	return nil
+}
+
+// startMigrate 需要先获取锁, 从检查点恢复或者创建新的任务, 并在后台执行
+func (s *scriptSvc) startMigrate(ctx context.Context, owner string, mode script_entity.MigrateMode,
+	restart bool) (*script_entity.MigrateProgress, error) {
+	// 清除之前残留的取消标记
+	if _, err := script_repo.Migrate().IsCancelled(ctx); err != nil {
+		return nil, err
+	}
+	progress, err := script_repo.Migrate().GetProgress(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !restart && progress.Resumable() && progress.Mode != mode {
+		// 避免换了模式后静默丢弃未完成的任务, 需要明确指定重新开始
+		return nil, i18n.NewError(ctx, code.ScriptMigrateModeMismatch)
+	}
+	if restart || !progress.Resumable() {
+		if progress.Resumable() && progress.Index != "" {
+			// 放弃未完成的alias任务, 删除构建到一半的索引
+			if reindexer, ok := script_repo.SearchEngine().(script_repo.ScriptSearchReindexer); ok {
+				if err := reindexer.DeleteIndex(ctx, progress.Index); err != nil {
+					logger.Ctx(ctx).Error("删除放弃的索引失败", zap.String("index", progress.Index), zap.Error(err))
+				}
+			}
+		}
+		total, err := script_repo.Migrate().Count(ctx, 0)
+		if err != nil {
+			return nil, err
+		}
+		progress = &script_entity.MigrateProgress{
+			Mode:      mode,
+			Total:     total,
+			Starttime: time.Now().Unix(),
+		}
+		if mode == script_entity.MigrateModeAlias {
+			reindexer, ok := script_repo.SearchEngine().(script_repo.ScriptSearchReindexer)
+			if !ok {
+				return nil, i18n.NewError(ctx, code.ScriptMigrateNotSupport)
+			}
+			progress.Index, err = reindexer.NewIndex(ctx)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	progress.Status = script_entity.MigrateStatusRunning
+	progress.LastError = ""
+	progress.Updatetime = time.Now().Unix()
+	if err := script_repo.Migrate().SaveProgress(ctx, progress); err != nil {
+		return nil, err
+	}
+	ret := *progress
+	if err := gogo.Go(context.Background(), func(ctx context.Context) error {
+		s.migrate(ctx, owner, progress)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+// migrate 按id顺序分批迁移, 每批结束后保存检查点
+func (s *scriptSvc) migrate(ctx context.Context, owner string, progress *script_entity.MigrateProgress) {
+	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
-	start := 0
+	defer func() {
+		if err := script_repo.Migrate().Unlock(ctx, owner); err != nil {
+			logger.Ctx(ctx).Error("释放迁移锁失败", zap.Error(err))
+		}
+	}()
+	save := func(ctx context.Context, search *script_entity.ScriptSearch) error {
+		if progress.Mode == script_entity.MigrateModeAlias {
+			reindexer, ok := script_repo.SearchEngine().(script_repo.ScriptSearchReindexer)
+			if !ok {
+				return i18n.NewError(ctx, code.ScriptMigrateNotSupport)
+			}
+			// 不覆盖重建期间双写的数据, 迁移的快照可能比双写的旧
+			return reindexer.CopyToIndex(ctx, progress.Index, search)
+		}
+		return script_repo.Migrate().Save(ctx, search)
+	}
+	fail := func(ctx context.Context, err error) {
+		logger.Ctx(ctx).Error("迁移失败", zap.Int64("last_id", progress.LastID), zap.Error(err))
+		progress.Status = script_entity.MigrateStatusFailed
+		progress.LastError = err.Error()
+		progress.Updatetime = time.Now().Unix()
+		if err := script_repo.Migrate().SaveProgress(ctx, progress); err != nil {
+			logger.Ctx(ctx).Error("保存迁移进度失败", zap.Error(err))
+		}
+	}
 	for {
 		if ok := func(ctx context.Context) bool {
 			ctx, span := trace.Default().Tracer("MigrateEs").Start(ctx, "MigrateEs")
-			defer func() {
-				span.End()
-				start += 20
-			}()
+			defer span.End()
 			ctx = logger.WithContextLogger(ctx, logger.Ctx(ctx).With(trace.LoggerLabel(ctx)...))
-			list, err := script_repo.Migrate().List(ctx, start, 20)
+			// 续期锁并检查是否被取消
+			if ok, err := script_repo.Migrate().Lock(ctx, owner, true); err != nil {
+				fail(ctx, err)
+				return true
+			} else if !ok {
+				// 锁已过期并被其它任务获取, 由持有锁的任务继续执行
+				logger.Ctx(ctx).Warn("迁移锁已丢失, 停止迁移", zap.Int64("last_id", progress.LastID))
+				return true
+			}
+			if cancelled, err := script_repo.Migrate().IsCancelled(ctx); err != nil {
+				fail(ctx, err)
+				return true
+			} else if cancelled {
+				logger.Ctx(ctx).Info("迁移已取消", zap.Int64("last_id", progress.LastID))
+				progress.Status = script_entity.MigrateStatusCancelled
+				progress.Updatetime = time.Now().Unix()
+				if err := script_repo.Migrate().SaveProgress(ctx, progress); err != nil {
+					logger.Ctx(ctx).Error("保存迁移进度失败", zap.Error(err))
+				}
+				return true
+			}
+			list, err := script_repo.Migrate().List(ctx, progress.LastID, 100)
 			if err != nil {
-				logger.Ctx(ctx).Error("获取迁移数据失败", zap.Error(err))
-				return false
+				fail(ctx, err)
+				return true
 			}
 			if len(list) == 0 {
-				logger.Ctx(ctx).Info("迁移完成")
+				if progress.Mode == script_entity.MigrateModeAlias {
+					reindexer, ok := script_repo.SearchEngine().(script_repo.ScriptSearchReindexer)
+					if !ok {
+						fail(ctx, i18n.NewError(ctx, code.ScriptMigrateNotSupport))
+						return true
+					}
+					if err := reindexer.SwitchIndex(ctx, progress.Index); err != nil {
+						fail(ctx, err)
+						return true
+					}
+				}
+				logger.Ctx(ctx).Info("迁移完成", zap.Int64("processed", progress.Processed),
+					zap.Int64("failed", progress.Failed))
+				progress.Status = script_entity.MigrateStatusFinished
+				progress.Updatetime = time.Now().Unix()
+				if err := script_repo.Migrate().SaveProgress(ctx, progress); err != nil {
+					logger.Ctx(ctx).Error("保存迁移进度失败", zap.Error(err))
+				}
 				return true
 			}
 			for _, item := range list {
 				search, err := script_repo.Migrate().Convert(ctx, item)
-				if err != nil {
-					logger.Ctx(ctx).Error("转换数据失败", zap.Error(err))
-					continue
+				if err == nil {
+					err = save(ctx, search)
 				}
-				if err := script_repo.Migrate().Save(ctx, search); err != nil {
-					logger.Ctx(ctx).Error("保存数据失败", zap.Error(err))
-					continue
+				if err != nil {
+					logger.Ctx(ctx).Error("迁移数据失败", zap.Int64("script_id", item.ID), zap.Error(err))
+					progress.Failed++
+					progress.LastError = err.Error()
 				}
+				progress.Processed++
+				progress.LastID = item.ID
+			}
+			progress.Updatetime = time.Now().Unix()
+			if err := script_repo.Migrate().SaveProgress(ctx, progress); err != nil {
+				logger.Ctx(ctx).Error("保存迁移进度失败", zap.Error(err))
 			}
 			return false
 		}(ctx); ok {
@@ -643,6 +1060,50 @@ func (s *scriptSvc) MigrateEs() {
 	}
 }
 
+// MigrateEsProgress 获取迁移数据到es的进度
+func (s *scriptSvc) MigrateEsProgress(ctx context.Context, req *api.MigrateEsProgressRequest) (*api.MigrateEsProgressResponse, error) {
+	progress, err := script_repo.Migrate().GetProgress(ctx)
+	if err != nil {
+		return nil, err
+	}
+	locked, err := script_repo.Migrate().IsLocked(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &api.MigrateEsProgressResponse{
+		MigrateProgress: progress,
+		Locked:          locked,
+	}, nil
+}
+
+// CancelMigrateEs 取消迁移数据到es, 执行中的任务会在当前批次结束后停止
+func (s *scriptSvc) CancelMigrateEs(ctx context.Context, req *api.CancelMigrateEsRequest) (*api.CancelMigrateEsResponse, error) {
+	progress, err := script_repo.Migrate().GetProgress(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if progress == nil || progress.Status != script_entity.MigrateStatusRunning {
+		return &api.CancelMigrateEsResponse{}, nil
+	}
+	locked, err := script_repo.Migrate().IsLocked(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !locked {
+		// 没有机器在执行, 直接标记为取消, 避免被定时任务恢复
+		progress.Status = script_entity.MigrateStatusCancelled
+		progress.Updatetime = time.Now().Unix()
+		if err := script_repo.Migrate().SaveProgress(ctx, progress); err != nil {
+			return nil, err
+		}
+		return &api.CancelMigrateEsResponse{}, nil
+	}
+	if err := script_repo.Migrate().Cancel(ctx); err != nil {
+		return nil, err
+	}
+	return &api.CancelMigrateEsResponse{}, nil
+}
+
 // GetCode 获取脚本代码,version为latest时获取最新版本
 func (s *scriptSvc) GetCode(ctx context.Context, id int64, version string) (*script_entity.Code, error) {
 	if version == "latest" || version == "" {
@@ -664,6 +1125,7 @@ func (s *scriptSvc) Info(ctx context.Context, req *api.InfoRequest) (*api.InfoRe
 	if err != nil {
 		return nil, err
 	}
+	s.localize(ctx, script, req.Languages())
 	resp := &api.InfoResponse{
 		Script:  script,
 		Content: m.Content,
@@ -1435,6 +1897,7 @@ func (s *scriptSvc) RecordVisit(ctx *gin.Context, req *api.RecordVisitRequest) (
 		IP:              ctx.ClientIP(),
 		UA:              ua,
 		StatisticsToken: statistics_svc.Statistics().GetStatisticsToken(ctx),
+		NoCookie:        !statistics_svc.Statistics().HasStatisticsToken(ctx),
 		Download:        statistics_repo.ViewScriptStatistics,
 		Time:            time.Now(),
 	}
@@ -1448,6 +1911,15 @@ func (s *scriptSvc) RecordVisit(ctx *gin.Context, req *api.RecordVisitRequest) (
 	if err != nil {
 		logger.Ctx(ctx).Error("脚本访问统计记录失败", zap.Any("record", record), zap.Error(err))
 	}
+	if req.SearchID != "" {
+		if err := producer.PublishScriptSearchClick(ctx, &producer.ScriptSearchClickMsg{
+			SearchID: req.SearchID,
+			ScriptID: req.ID,
+			Time:     record.Time,
+		}); err != nil {
+			logger.Ctx(ctx).Error("搜索点击记录失败", zap.String("search_id", req.SearchID), zap.Error(err))
+		}
+	}
 
 	return &api.RecordVisitResponse{}, nil
 }
@@ -0,0 +1,111 @@
+package script_svc
+
+import (
+	"context"
+	"time"
+
+	"github.com/cago-frame/cago/pkg/logger"
+	"github.com/cago-frame/cago/pkg/utils"
+	api "github.com/scriptscat/scriptlist/internal/api/script"
+	"github.com/scriptscat/scriptlist/internal/repository/script_repo"
+	"github.com/scriptscat/scriptlist/internal/service/auth_svc"
+	"github.com/scriptscat/scriptlist/internal/task/producer"
+	"go.uber.org/zap"
+)
+
+const (
+	// searchLogRetention 搜索日志保留的天数, 与搜索词统计的最大范围一致
+	searchLogRetention = 90
+	// searchLogCleanBatch 每次删除的搜索日志数量, 避免长时间锁表
+	searchLogCleanBatch = 1000
+)
+
+// logSearch 异步记录关键字搜索, 返回搜索id, 记录失败时返回空
+func (s *scriptSvc) logSearch(ctx context.Context, req *api.ListRequest, total int64) string {
+	msg := &producer.ScriptSearchMsg{
+		SearchID: utils.RandString(32, utils.Mix),
+		Query:    req.Keyword,
+		Filters:  make(map[string]interface{}),
+		Sort:     req.Sort,
+		Total:    total,
+		Time:     time.Now(),
+	}
+	if req.Domain != "" {
+		msg.Filters["domain"] = req.Domain
+	}
+	if req.UserID != 0 {
+		msg.Filters["user_id"] = req.UserID
+	}
+	if req.Category != 0 {
+		msg.Filters["category"] = req.Category
+	}
+	if req.ScriptType != 0 {
+		msg.Filters["script_type"] = req.ScriptType
+	}
+	if user := auth_svc.Auth().Get(ctx); user != nil {
+		msg.UserID = user.UID
+	}
+	if err := producer.PublishScriptSearch(ctx, msg); err != nil {
+		logger.Ctx(ctx).Error("记录搜索日志失败", zap.String("keyword", req.Keyword), zap.Error(err))
+		return ""
+	}
+	return msg.SearchID
+}
+
+// SearchAnalytics 搜索词统计
+func (s *scriptSvc) SearchAnalytics(ctx context.Context, req *api.SearchAnalyticsRequest) (*api.SearchAnalyticsResponse, error) {
+	end := time.Now()
+	start := end.AddDate(0, 0, -req.Days)
+	summary, err := script_repo.ScriptSearchLog().Summary(ctx, start.Unix(), end.Unix())
+	if err != nil {
+		return nil, err
+	}
+	top, err := script_repo.ScriptSearchLog().TopQueries(ctx, start.Unix(), end.Unix(), false, req.Size)
+	if err != nil {
+		return nil, err
+	}
+	zero, err := script_repo.ScriptSearchLog().TopQueries(ctx, start.Unix(), end.Unix(), true, req.Size)
+	if err != nil {
+		return nil, err
+	}
+	return &api.SearchAnalyticsResponse{
+		Summary:    toSearchQueryStat(summary),
+		TopQueries: toSearchQueryStats(top),
+		ZeroResult: toSearchQueryStats(zero),
+	}, nil
+}
+
+func toSearchQueryStat(stat *script_repo.SearchQueryStat) *api.SearchQueryStat {
+	ret := &api.SearchQueryStat{
+		Query:       stat.Query,
+		Searches:    stat.Searches,
+		ZeroResults: stat.ZeroResults,
+		Clicked:     stat.Clicked,
+	}
+	if stat.Searches > 0 {
+		ret.CTR = float64(stat.Clicked) / float64(stat.Searches)
+	}
+	return ret
+}
+
+func toSearchQueryStats(list []*script_repo.SearchQueryStat) []*api.SearchQueryStat {
+	ret := make([]*api.SearchQueryStat, 0, len(list))
+	for _, v := range list {
+		ret = append(ret, toSearchQueryStat(v))
+	}
+	return ret
+}
+
+// CleanSearchLog 分批删除超过保留天数的搜索日志
+func (s *scriptSvc) CleanSearchLog(ctx context.Context, now time.Time) error {
+	t := now.AddDate(0, 0, -searchLogRetention).Unix()
+	for {
+		num, err := script_repo.ScriptSearchLog().DeleteBefore(ctx, t, searchLogCleanBatch)
+		if err != nil {
+			return err
+		}
+		if num < searchLogCleanBatch {
+			return nil
+		}
+	}
+}
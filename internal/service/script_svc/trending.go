@@ -0,0 +1,73 @@
+package script_svc
+
+import (
+	"context"
+	"time"
+
+	"github.com/cago-frame/cago/pkg/consts"
+	"github.com/cago-frame/cago/pkg/logger"
+	"github.com/scriptscat/scriptlist/internal/model/entity"
+	"github.com/scriptscat/scriptlist/internal/repository/script_repo"
+	"go.uber.org/zap"
+)
+
+// UpdateTrending 按id分批重新计算脚本的热度, 只有热度明显变化的脚本才会更新并同步到搜索索引
+func (s *scriptSvc) UpdateTrending(ctx context.Context) error {
+	now := time.Now()
+	start := now.AddDate(0, 0, -entity.TrendingDays+1)
+	lastId := int64(0)
+	updated := 0
+	for {
+		list, err := script_repo.Migrate().List(ctx, lastId, 200)
+		if err != nil {
+			return err
+		}
+		if len(list) == 0 {
+			break
+		}
+		lastId = list[len(list)-1].ID
+		ids := make([]int64, 0, len(list))
+		for _, v := range list {
+			ids = append(ids, v.ID)
+		}
+		dates, err := script_repo.ScriptDateStatistics().FindByScriptIDs(ctx, ids, start)
+		if err != nil {
+			return err
+		}
+		dateMap := make(map[int64][]*entity.ScriptDateStatistics)
+		for _, v := range dates {
+			dateMap[v.ScriptID] = append(dateMap[v.ScriptID], v)
+		}
+		for _, v := range list {
+			if v.Status != consts.ACTIVE {
+				continue
+			}
+			logger := logger.Ctx(ctx).With(zap.Int64("script_id", v.ID))
+			statistics, err := script_repo.ScriptStatistics().FindByScriptID(ctx, v.ID)
+			if err != nil {
+				logger.Error("获取脚本统计失败", zap.Error(err))
+				continue
+			}
+			trending := entity.Trending(now, dateMap[v.ID], statistics, v.Updatetime)
+			if statistics != nil && !entity.TrendingChanged(statistics.Trending, trending) {
+				continue
+			}
+			if err := script_repo.ScriptStatistics().UpdateTrending(ctx, v.ID, trending); err != nil {
+				logger.Error("更新脚本热度失败", zap.Error(err))
+				continue
+			}
+			search, err := script_repo.Migrate().Convert(ctx, v)
+			if err != nil {
+				logger.Error("转换搜索数据失败", zap.Error(err))
+				continue
+			}
+			if err := script_repo.Migrate().Update(ctx, search); err != nil {
+				logger.Error("更新搜索索引热度失败", zap.Error(err))
+				continue
+			}
+			updated++
+		}
+	}
+	logger.Ctx(ctx).Info("更新脚本热度完成", zap.Int("updated", updated))
+	return nil
+}
@@ -0,0 +1,46 @@
+package statistics_svc
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	api "github.com/scriptscat/scriptlist/internal/api/statistics"
+	"github.com/scriptscat/scriptlist/internal/repository/statistics_repo"
+)
+
+// Filtered 被识别为机器人而没有计入统计的请求
+func (s *statisticsSvc) Filtered(ctx context.Context, req *api.FilteredRequest) (*api.FilteredResponse, error) {
+	charts := map[statistics_repo.ScriptStatisticsType]*api.Chart{}
+	for _, op := range dailyOps {
+		charts[op] = &api.Chart{X: make([]string, 0, req.Days), Y: make([]int64, 0, req.Days)}
+	}
+	reason := map[string]int64{}
+	now := time.Now()
+	for i := req.Days - 1; i >= 0; i-- {
+		t := now.AddDate(0, 0, -i)
+		m, err := statistics_repo.Bot().DayFiltered(ctx, req.ScriptID, t)
+		if err != nil {
+			return nil, err
+		}
+		day := map[statistics_repo.ScriptStatisticsType]int64{}
+		for k, v := range m {
+			op, r, ok := strings.Cut(k, ":")
+			if !ok {
+				continue
+			}
+			day[statistics_repo.ScriptStatisticsType(op)] += v
+			reason[r] += v
+		}
+		for op, chart := range charts {
+			chart.X = append(chart.X, t.Format("2006/01/02"))
+			chart.Y = append(chart.Y, day[op])
+		}
+	}
+	return &api.FilteredResponse{
+		View:     charts[statistics_repo.ViewScriptStatistics],
+		Download: charts[statistics_repo.DownloadScriptStatistics],
+		Update:   charts[statistics_repo.UpdateScriptStatistics],
+		Reason:   pieChart(reason, len(reason)),
+	}, nil
+}
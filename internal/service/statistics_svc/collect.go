@@ -0,0 +1,204 @@
+package statistics_svc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/cago-frame/cago/pkg/i18n"
+	"github.com/gin-gonic/gin"
+	api "github.com/scriptscat/scriptlist/internal/api/statistics"
+	"github.com/scriptscat/scriptlist/internal/pkg/clickhouse"
+	"github.com/scriptscat/scriptlist/internal/pkg/code"
+	"github.com/scriptscat/scriptlist/internal/repository/statistics_repo"
+	"github.com/scriptscat/scriptlist/internal/task/producer"
+)
+
+const (
+	// 统计查询的默认范围与最大范围
+	collectDefaultRange = time.Hour * 24 * 7
+	collectMaxRange     = time.Hour * 24 * 90
+	// 单次会话最长停留时长
+	collectMaxDuration = 86400
+)
+
+// Collect 统计数据上报
+func (s *statisticsSvc) Collect(ctx *gin.Context, req *api.CollectRequest) (*api.CollectResponse, error) {
+	if clickhouse.Default() == nil {
+		return nil, i18n.NewError(ctx, code.StatisticsCollectDisabled)
+	}
+	info, err := statistics_repo.StatisticsInfo().FindByStatisticsKey(ctx, req.Key)
+	if err != nil {
+		return nil, err
+	}
+	if info == nil || info.ID == 0 {
+		return nil, i18n.NewNotFoundError(ctx, code.StatisticsInfoUninitialized)
+	}
+	// operation_page由客户端填写, 不可信, 使用浏览器发送的Origin或Referer判断白名单
+	host := requestHost(ctx)
+	if host == "" {
+		return nil, i18n.NewError(ctx, code.StatisticsWhitelistNotFound)
+	}
+	if !info.Whitelist.Allow(host) {
+		return nil, i18n.NewForbiddenError(ctx, code.StatisticsWhitelistNotFound)
+	}
+	// 时间由客户端上报, 不可信的时间使用服务器时间
+	now := time.Now().Unix()
+	if req.VisitTime <= 0 || req.VisitTime > now+60 || req.VisitTime < now-86400 {
+		req.VisitTime = now
+	}
+	if req.Duration > collectMaxDuration {
+		req.Duration = collectMaxDuration
+	}
+	if req.ExitTime < req.VisitTime || req.ExitTime > req.VisitTime+collectMaxDuration {
+		req.ExitTime = req.VisitTime + int64(req.Duration)
+	}
+	if err := producer.PublishStatisticsCollect(ctx, &producer.StatisticsCollectMsg{
+		SessionID:     req.SessionID,
+		ScriptID:      info.ScriptID,
+		VisitorID:     req.VisitorID,
+		OperationHost: host,
+		OperationPage: req.OperationPage,
+		InstallPage:   req.InstallPage,
+		Duration:      req.Duration,
+		UA:            ctx.Request.UserAgent(),
+		IP:            ctx.ClientIP(),
+		VisitTime:     req.VisitTime,
+		ExitTime:      req.ExitTime,
+		Version:       req.Version,
+	}); err != nil {
+		return nil, err
+	}
+	return &api.CollectResponse{}, nil
+}
+
+// collectRange 统计时间范围
+func collectRange(r api.CollectRange) (time.Time, time.Time) {
+	end := time.Now()
+	if r.End > 0 && r.End < end.Unix() {
+		end = time.Unix(r.End, 0)
+	}
+	start := end.Add(-collectDefaultRange)
+	if r.Start > 0 && r.Start < end.Unix() {
+		start = time.Unix(r.Start, 0)
+	}
+	if end.Sub(start) > collectMaxRange {
+		start = end.Add(-collectMaxRange)
+	}
+	return start, end
+}
+
+// collectDays 时间范围内的每一天, 用于补全没有数据的日期
+func collectDays(start, end time.Time) []time.Time {
+	var days []time.Time
+	day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, start.Location())
+	for ; !day.After(end); day = day.AddDate(0, 0, 1) {
+		days = append(days, day)
+	}
+	return days
+}
+
+func (s *statisticsSvc) collectError(ctx context.Context, err error) error {
+	if errors.Is(err, clickhouse.ErrNotConfigured) {
+		return i18n.NewError(ctx, code.StatisticsCollectDisabled)
+	}
+	return err
+}
+
+func (s *statisticsSvc) toCollectTop(list []*statistics_repo.CollectTop) []*api.CollectTop {
+	ret := make([]*api.CollectTop, 0, len(list))
+	for _, v := range list {
+		ret = append(ret, &api.CollectTop{
+			Key: v.Key,
+			PV:  v.PV,
+			UV:  v.UV,
+		})
+	}
+	return ret
+}
+
+// CollectHost 脚本运行域名统计
+func (s *statisticsSvc) CollectHost(ctx context.Context, req *api.CollectHostRequest) (*api.CollectHostResponse, error) {
+	start, end := collectRange(req.CollectRange)
+	list, err := statistics_repo.StatisticsCollect().TopHost(ctx, req.ID, start, end, req.Limit)
+	if err != nil {
+		return nil, s.collectError(ctx, err)
+	}
+	return &api.CollectHostResponse{
+		List: s.toCollectTop(list),
+	}, nil
+}
+
+// CollectPage 脚本运行页面统计
+func (s *statisticsSvc) CollectPage(ctx context.Context, req *api.CollectPageRequest) (*api.CollectPageResponse, error) {
+	start, end := collectRange(req.CollectRange)
+	list, err := statistics_repo.StatisticsCollect().TopPage(ctx, req.ID, start, end, req.Limit)
+	if err != nil {
+		return nil, s.collectError(ctx, err)
+	}
+	return &api.CollectPageResponse{
+		List: s.toCollectTop(list),
+	}, nil
+}
+
+// CollectDuration 停留时长趋势
+func (s *statisticsSvc) CollectDuration(ctx context.Context, req *api.CollectDurationRequest) (*api.CollectDurationResponse, error) {
+	start, end := collectRange(req.CollectRange)
+	list, err := statistics_repo.StatisticsCollect().DurationTrend(ctx, req.ID, start, end)
+	if err != nil {
+		return nil, s.collectError(ctx, err)
+	}
+	m := make(map[string]*statistics_repo.DurationTrend, len(list))
+	for _, v := range list {
+		m[v.Date] = v
+	}
+	resp := &api.CollectDurationResponse{
+		Sessions:    &api.Chart{X: []string{}, Y: []int64{}},
+		AvgDuration: &api.FChart{X: []string{}, Y: []float64{}},
+	}
+	for _, day := range collectDays(start, end) {
+		x := day.Format("2006/01/02")
+		resp.Sessions.X = append(resp.Sessions.X, x)
+		resp.AvgDuration.X = append(resp.AvgDuration.X, x)
+		if v, ok := m[day.Format("2006-01-02")]; ok {
+			resp.Sessions.Y = append(resp.Sessions.Y, v.Sessions)
+			resp.AvgDuration.Y = append(resp.AvgDuration.Y, v.AvgDuration)
+		} else {
+			resp.Sessions.Y = append(resp.Sessions.Y, 0)
+			resp.AvgDuration.Y = append(resp.AvgDuration.Y, 0)
+		}
+	}
+	return resp, nil
+}
+
+// CollectVisitor 访客趋势
+func (s *statisticsSvc) CollectVisitor(ctx context.Context, req *api.CollectVisitorRequest) (*api.CollectVisitorResponse, error) {
+	start, end := collectRange(req.CollectRange)
+	list, err := statistics_repo.StatisticsCollect().VisitorTrend(ctx, req.ID, start, end)
+	if err != nil {
+		return nil, s.collectError(ctx, err)
+	}
+	m := make(map[string]*statistics_repo.VisitorTrend, len(list))
+	for _, v := range list {
+		m[v.Date] = v
+	}
+	resp := &api.CollectVisitorResponse{
+		Visitors:    &api.Chart{X: []string{}, Y: []int64{}},
+		NewVisitors: &api.Chart{X: []string{}, Y: []int64{}},
+		PV:          &api.Chart{X: []string{}, Y: []int64{}},
+	}
+	for _, day := range collectDays(start, end) {
+		x := day.Format("2006/01/02")
+		v, ok := m[day.Format("2006-01-02")]
+		if !ok {
+			v = &statistics_repo.VisitorTrend{}
+		}
+		resp.Visitors.X = append(resp.Visitors.X, x)
+		resp.Visitors.Y = append(resp.Visitors.Y, v.Visitors)
+		resp.NewVisitors.X = append(resp.NewVisitors.X, x)
+		resp.NewVisitors.Y = append(resp.NewVisitors.Y, v.NewVisitors)
+		resp.PV.X = append(resp.PV.X, x)
+		resp.PV.Y = append(resp.PV.Y, v.PV)
+	}
+	return resp, nil
+}
@@ -0,0 +1,439 @@
+package statistics_svc
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cago-frame/cago/pkg/i18n"
+	"github.com/cago-frame/cago/pkg/logger"
+	api "github.com/scriptscat/scriptlist/internal/api/statistics"
+	"github.com/scriptscat/scriptlist/internal/model/entity/statistics_entity"
+	"github.com/scriptscat/scriptlist/internal/pkg/code"
+	"github.com/scriptscat/scriptlist/internal/repository/script_repo"
+	"github.com/scriptscat/scriptlist/internal/repository/statistics_repo"
+	"go.uber.org/zap"
+)
+
+const (
+	// 图表默认显示的天数
+	dailyDefaultDays = 30
+	// 按天聚合时最多查询的天数, 按周/月聚合时最多查询的天数
+	dailyMaxDays       = 366
+	dailyMaxBucketDays = 366 * 5
+	// redis中uv数据保留的天数, 超过的只能从数据库读取
+	dailyRedisDays = 60
+)
+
+var dailyOps = []statistics_repo.ScriptStatisticsType{
+	statistics_repo.ViewScriptStatistics,
+	statistics_repo.DownloadScriptStatistics,
+	statistics_repo.UpdateScriptStatistics,
+}
+
+// dailyRows 一个脚本某一天需要落库的数据
+type dailyRows struct {
+	daily  []*statistics_entity.StatisticsDaily
+	ua     []*statistics_entity.StatisticsUaDaily
+	source []*statistics_entity.StatisticsSourceDaily
+}
+
+func (d *dailyRows) append(rows *dailyRows) {
+	d.daily = append(d.daily, rows.daily...)
+	d.ua = append(d.ua, rows.ua...)
+	d.source = append(d.source, rows.source...)
+}
+
+// SaveDaily 将某一天的统计数据从redis落库, 单个脚本失败时记录日志并跳过, 不影响其它脚本
+func (s *statisticsSvc) SaveDaily(ctx context.Context, date time.Time) error {
+	day := date.Format("2006-01-02")
+	lastId := int64(0)
+	saved, failed := 0, 0
+	for {
+		list, err := script_repo.Migrate().List(ctx, lastId, 200)
+		if err != nil {
+			return err
+		}
+		if len(list) == 0 {
+			break
+		}
+		lastId = list[len(list)-1].ID
+		ids := make([]int64, 0, len(list))
+		for _, v := range list {
+			ids = append(ids, v.ID)
+		}
+		// 通过pipeline一次读取整批脚本的数据
+		days, err := statistics_repo.ScriptStatistics().DayBatch(ctx, ids, dailyOps, date)
+		if err != nil {
+			return err
+		}
+		// 读取失败的脚本跳过全部数据
+		readFailed := make(map[int64]struct{})
+		for _, v := range days {
+			if v.Err == nil {
+				continue
+			}
+			if _, ok := readFailed[v.ScriptID]; !ok {
+				logger.Ctx(ctx).Error("读取每日统计数据失败", zap.Error(v.Err),
+					zap.Int64("script_id", v.ScriptID), zap.String("date", day))
+				readFailed[v.ScriptID] = struct{}{}
+				failed++
+			}
+		}
+		scripts := make(map[int64]*dailyRows, len(list))
+		for _, v := range days {
+			if _, ok := readFailed[v.ScriptID]; ok {
+				continue
+			}
+			rows, ok := scripts[v.ScriptID]
+			if !ok {
+				rows = &dailyRows{}
+				scripts[v.ScriptID] = rows
+			}
+			rows.add(day, v)
+		}
+		batch := &dailyRows{}
+		for _, v := range list {
+			if rows := scripts[v.ID]; rows != nil {
+				batch.append(rows)
+			}
+		}
+		err = s.saveDailyRows(ctx, batch)
+		if err == nil {
+			saved += len(batch.daily)
+			continue
+		}
+		// 批量保存失败时逐个脚本保存, 跳过失败的脚本
+		logger.Ctx(ctx).Warn("批量保存每日统计数据失败, 逐个脚本重试", zap.Error(err), zap.String("date", day))
+		for _, v := range list {
+			rows := scripts[v.ID]
+			if rows == nil {
+				continue
+			}
+			if err := s.saveDailyRows(ctx, rows); err != nil {
+				logger.Ctx(ctx).Error("保存每日统计数据失败", zap.Error(err),
+					zap.Int64("script_id", v.ID), zap.String("date", day))
+				failed++
+				continue
+			}
+			saved += len(rows.daily)
+		}
+	}
+	logger.Ctx(ctx).Info("每日统计数据落库完成", zap.String("date", day),
+		zap.Int("saved", saved), zap.Int("failed", failed))
+	return nil
+}
+
+// add 添加脚本某一天某操作的数据, 没有数据的操作不落库
+func (d *dailyRows) add(day string, v *statistics_repo.DayStatistics) {
+	if v.Pv == 0 && v.Uv == 0 {
+		return
+	}
+	now := time.Now().Unix()
+	d.daily = append(d.daily, &statistics_entity.StatisticsDaily{
+		ScriptID:   v.ScriptID,
+		Op:         string(v.Op),
+		Date:       day,
+		Pv:         v.Pv,
+		Uv:         v.Uv,
+		Createtime: now,
+	})
+	for key, num := range v.UA {
+		dimension, value, ok := strings.Cut(key, ":")
+		if !ok {
+			continue
+		}
+		d.ua = append(d.ua, &statistics_entity.StatisticsUaDaily{
+			ScriptID:   v.ScriptID,
+			Op:         string(v.Op),
+			Date:       day,
+			Dimension:  dimension,
+			Value:      value,
+			Num:        num,
+			Createtime: now,
+		})
+	}
+	for key, num := range v.Source {
+		dimension, value, ok := strings.Cut(key, ":")
+		if !ok {
+			continue
+		}
+		d.source = append(d.source, &statistics_entity.StatisticsSourceDaily{
+			ScriptID:   v.ScriptID,
+			Date:       day,
+			Dimension:  dimension,
+			Value:      value,
+			Num:        num,
+			Createtime: now,
+		})
+	}
+}
+
+func (s *statisticsSvc) saveDailyRows(ctx context.Context, rows *dailyRows) error {
+	if err := statistics_repo.StatisticsDaily().Save(ctx, rows.daily); err != nil {
+		return err
+	}
+	if err := statistics_repo.StatisticsDaily().SaveUA(ctx, rows.ua); err != nil {
+		return err
+	}
+	return statistics_repo.StatisticsDaily().SaveSource(ctx, rows.source)
+}
+
+// dateRange 解析图表的时间范围
+func (s *statisticsSvc) dateRange(ctx context.Context, startStr, endStr, bucket string) (time.Time, time.Time, error) {
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	end := today
+	if endStr != "" {
+		t, err := time.ParseInLocation("2006-01-02", endStr, now.Location())
+		if err != nil {
+			return time.Time{}, time.Time{}, i18n.NewError(ctx, code.StatisticsDateRangeInvalid)
+		}
+		if t.Before(today) {
+			end = t
+		}
+	}
+	start := end.AddDate(0, 0, -dailyDefaultDays+1)
+	if startStr != "" {
+		t, err := time.ParseInLocation("2006-01-02", startStr, now.Location())
+		if err != nil {
+			return time.Time{}, time.Time{}, i18n.NewError(ctx, code.StatisticsDateRangeInvalid)
+		}
+		start = t
+	}
+	if start.After(end) {
+		return time.Time{}, time.Time{}, i18n.NewError(ctx, code.StatisticsDateRangeInvalid)
+	}
+	maxDays := dailyMaxBucketDays
+	if bucket == "day" || bucket == "" {
+		maxDays = dailyMaxDays
+	}
+	if end.Sub(start) >= time.Hour*24*time.Duration(maxDays) {
+		return time.Time{}, time.Time{}, i18n.NewError(ctx, code.StatisticsDateRangeInvalid)
+	}
+	return start, end, nil
+}
+
+// dailyData 获取时间范围内每天的pv和uv, 已落库的从数据库读取, 未落库的近期数据从redis读取
+func (s *statisticsSvc) dailyData(ctx context.Context, scriptId int64, op statistics_repo.ScriptStatisticsType,
+	start, end time.Time) ([]*statistics_entity.StatisticsDaily, error) {
+	list, err := statistics_repo.StatisticsDaily().FindRange(ctx, scriptId, op,
+		start.Format("2006-01-02"), end.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	m := make(map[string]*statistics_entity.StatisticsDaily, len(list))
+	for _, v := range list {
+		m[v.Date] = v
+	}
+	redisStart := time.Now().AddDate(0, 0, -dailyRedisDays)
+	ret := make([]*statistics_entity.StatisticsDaily, 0)
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		date := day.Format("2006-01-02")
+		v, ok := m[date]
+		if !ok {
+			v = &statistics_entity.StatisticsDaily{ScriptID: scriptId, Op: string(op), Date: date}
+			if day.After(redisStart) {
+				// pv和uv通过一次pipeline读取
+				if days := DayBatchIgnoreError(ctx, []int64{scriptId}, op, day); len(days) > 0 {
+					v.Pv, v.Uv = days[0].Pv, days[0].Uv
+				}
+			}
+		}
+		ret = append(ret, v)
+	}
+	return ret, nil
+}
+
+// bucketKey 日期所属的聚合区间
+func bucketKey(t time.Time, bucket string) string {
+	switch bucket {
+	case "week":
+		// 以周一作为一周的开始
+		offset := (int(t.Weekday()) + 6) % 7
+		return t.AddDate(0, 0, -offset).Format("2006/01/02")
+	case "month":
+		return t.Format("2006/01")
+	}
+	return t.Format("2006/01/02")
+}
+
+// bucketChart 按天/周/月聚合每日数据, 周和月的uv为每日uv之和
+func bucketChart(list []*statistics_entity.StatisticsDaily, bucket string,
+	value func(*statistics_entity.StatisticsDaily) int64) *api.Chart {
+	chart := &api.Chart{X: []string{}, Y: []int64{}}
+	for _, v := range list {
+		t, err := time.Parse("2006-01-02", v.Date)
+		if err != nil {
+			continue
+		}
+		key := bucketKey(t, bucket)
+		if n := len(chart.X); n > 0 && chart.X[n-1] == key {
+			chart.Y[n-1] += value(v)
+			continue
+		}
+		chart.X = append(chart.X, key)
+		chart.Y = append(chart.Y, value(v))
+	}
+	return chart
+}
+
+func dailyPv(v *statistics_entity.StatisticsDaily) int64 {
+	return v.Pv
+}
+
+func dailyUv(v *statistics_entity.StatisticsDaily) int64 {
+	return v.Uv
+}
+
+// visitorPieTop 饼图最多显示的项, 其余的合并为other
+const visitorPieTop = 10
+
+// refererTop 来源网站最多显示的项
+const refererTop = 20
+
+// recentDaily 是否为没有落库且有数据的近期数据, ID为0的是dailyData从redis读取的数据
+func recentDaily(v *statistics_entity.StatisticsDaily) bool {
+	return v.ID == 0 && (v.Pv != 0 || v.Uv != 0)
+}
+
+// dimensionSum 汇总时间范围内各维度的数据, rows为已落库的数据, 未落库的近期数据通过day从redis读取
+func dimensionSum(ctx context.Context, daily map[statistics_repo.ScriptStatisticsType][]*statistics_entity.StatisticsDaily,
+	rows map[string]map[string]int64,
+	day func(ctx context.Context, op statistics_repo.ScriptStatisticsType, t time.Time) (map[string]int64, error),
+) (map[string]map[string]int64, error) {
+	sum := rows
+	if sum == nil {
+		sum = make(map[string]map[string]int64)
+	}
+	for op, days := range daily {
+		for _, v := range days {
+			if !recentDaily(v) {
+				continue
+			}
+			t, err := time.ParseInLocation("2006-01-02", v.Date, time.Local)
+			if err != nil {
+				continue
+			}
+			m, err := day(ctx, op, t)
+			if err != nil {
+				return nil, err
+			}
+			for key, num := range m {
+				if dimension, value, ok := strings.Cut(key, ":"); ok {
+					if _, ok := sum[dimension]; !ok {
+						sum[dimension] = make(map[string]int64)
+					}
+					sum[dimension][value] += num
+				}
+			}
+		}
+	}
+	return sum, nil
+}
+
+// visitorPie 汇总时间范围内的ua分布
+func (s *statisticsSvc) visitorPie(ctx context.Context, scriptId int64, start, end time.Time,
+	daily map[statistics_repo.ScriptStatisticsType][]*statistics_entity.StatisticsDaily) (*api.VisitorPie, error) {
+	ops := make([]statistics_repo.ScriptStatisticsType, 0, len(daily))
+	for op := range daily {
+		ops = append(ops, op)
+	}
+	list, err := statistics_repo.StatisticsDaily().SumUARange(ctx, scriptId, ops,
+		start.Format("2006-01-02"), end.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	rows := make(map[string]map[string]int64)
+	for _, v := range list {
+		if _, ok := rows[v.Dimension]; !ok {
+			rows[v.Dimension] = make(map[string]int64)
+		}
+		rows[v.Dimension][v.Value] += v.Num
+	}
+	sum, err := dimensionSum(ctx, daily, rows, func(ctx context.Context, op statistics_repo.ScriptStatisticsType, t time.Time) (map[string]int64, error) {
+		return statistics_repo.ScriptStatistics().DayUA(ctx, scriptId, op, t)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &api.VisitorPie{
+		Browser:        pieChart(sum[statistics_entity.UADimensionBrowser], visitorPieTop),
+		OS:             pieChart(sum[statistics_entity.UADimensionOS], visitorPieTop),
+		Device:         pieChart(sum[statistics_entity.UADimensionDevice], visitorPieTop),
+		Manager:        pieChart(sum[statistics_entity.UADimensionManager], visitorPieTop),
+		ManagerVersion: pieChart(sum[statistics_entity.UADimensionManagerVersion], visitorPieTop),
+	}, nil
+}
+
+// installSource 汇总时间范围内的安装来源
+func (s *statisticsSvc) installSource(ctx context.Context, scriptId int64, start, end time.Time,
+	download []*statistics_entity.StatisticsDaily) (*api.InstallSource, error) {
+	list, err := statistics_repo.StatisticsDaily().SumSourceRange(ctx, scriptId,
+		start.Format("2006-01-02"), end.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	rows := make(map[string]map[string]int64)
+	for _, v := range list {
+		if _, ok := rows[v.Dimension]; !ok {
+			rows[v.Dimension] = make(map[string]int64)
+		}
+		rows[v.Dimension][v.Value] += v.Num
+	}
+	sum, err := dimensionSum(ctx, map[statistics_repo.ScriptStatisticsType][]*statistics_entity.StatisticsDaily{
+		statistics_repo.DownloadScriptStatistics: download,
+	}, rows, func(ctx context.Context, op statistics_repo.ScriptStatisticsType, t time.Time) (map[string]int64, error) {
+		return statistics_repo.ScriptStatistics().DaySource(ctx, scriptId, op, t)
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp := &api.InstallSource{
+		Source:  pieChart(sum[statistics_entity.SourceDimensionSource], visitorPieTop),
+		Referer: pieChart(sum[statistics_entity.SourceDimensionReferer], refererTop),
+		Folder:  make([]*api.FolderSource, 0),
+	}
+	for _, v := range pieChart(sum[statistics_entity.SourceDimensionFolder], visitorPieTop) {
+		item := &api.FolderSource{Value: v.Value}
+		if id, err := strconv.ParseInt(v.Key, 10, 64); err == nil {
+			item.ID = id
+			// 私密收藏夹不显示名称
+			folder, err := script_repo.ScriptFavoriteFolder().Find(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+			if folder != nil && folder.Private == 2 {
+				item.Name = folder.Name
+			}
+		} else {
+			item.Name = v.Key
+		}
+		resp.Folder = append(resp.Folder, item)
+	}
+	return resp, nil
+}
+
+// pieChart 按数量从大到小排序, 超过top的合并为other
+func pieChart(m map[string]int64, top int) []*api.PieChart {
+	ret := make([]*api.PieChart, 0, len(m))
+	for k, v := range m {
+		ret = append(ret, &api.PieChart{Key: k, Value: v})
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].Value == ret[j].Value {
+			return ret[i].Key < ret[j].Key
+		}
+		return ret[i].Value > ret[j].Value
+	})
+	if len(ret) > top {
+		other := &api.PieChart{Key: "other"}
+		for _, v := range ret[top:] {
+			other.Value += v.Value
+		}
+		ret = append(ret[:top], other)
+	}
+	return ret
+}
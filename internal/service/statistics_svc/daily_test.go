@@ -0,0 +1,214 @@
+package statistics_svc
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	api "github.com/scriptscat/scriptlist/internal/api/statistics"
+	"github.com/scriptscat/scriptlist/internal/model/entity/script_entity"
+	"github.com/scriptscat/scriptlist/internal/model/entity/statistics_entity"
+	"github.com/scriptscat/scriptlist/internal/repository/script_repo"
+	mock_script_repo "github.com/scriptscat/scriptlist/internal/repository/script_repo/mock"
+	"github.com/scriptscat/scriptlist/internal/repository/statistics_repo"
+	mock_statistics_repo "github.com/scriptscat/scriptlist/internal/repository/statistics_repo/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestBucketChart(t *testing.T) {
+	var list []*statistics_entity.StatisticsDaily
+	// 2024-01-29为周一
+	for i, date := range []string{"2024-01-28", "2024-01-29", "2024-01-30", "2024-02-04", "2024-02-05"} {
+		list = append(list, &statistics_entity.StatisticsDaily{Date: date, Pv: int64(i + 1), Uv: 1})
+	}
+	assert.Equal(t, &api.Chart{
+		X: []string{"2024/01/28", "2024/01/29", "2024/01/30", "2024/02/04", "2024/02/05"},
+		Y: []int64{1, 2, 3, 4, 5},
+	}, bucketChart(list, "day", dailyPv))
+	assert.Equal(t, &api.Chart{
+		X: []string{"2024/01/22", "2024/01/29", "2024/02/05"},
+		Y: []int64{1, 9, 5},
+	}, bucketChart(list, "week", dailyPv))
+	assert.Equal(t, &api.Chart{
+		X: []string{"2024/01", "2024/02"},
+		Y: []int64{3, 2},
+	}, bucketChart(list, "month", dailyUv))
+}
+
+func TestStatisticsSvc_SaveDaily(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockMigrate := mock_script_repo.NewMockScriptMigrateRepo(mockCtrl)
+	script_repo.RegisterMigrate(mockMigrate)
+	mockDaily := mock_statistics_repo.NewMockStatisticsDailyRepo(mockCtrl)
+	statistics_repo.RegisterStatisticsDaily(mockDaily)
+	mockStatistics := mock_statistics_repo.NewMockScriptStatisticsRepo(mockCtrl)
+	statistics_repo.RegisterScriptStatistics(mockStatistics)
+
+	ctx := context.Background()
+	s := &statisticsSvc{}
+	date := time.Date(2023, 1, 1, 0, 0, 0, 0, time.Local)
+
+	mockMigrate.EXPECT().List(gomock.Any(), int64(0), 200).
+		Return([]*script_entity.Script{{ID: 1}, {ID: 2}, {ID: 3}}, nil)
+	mockMigrate.EXPECT().List(gomock.Any(), int64(3), 200).Return(nil, nil)
+	// 脚本1读取redis失败, 跳过该脚本的全部数据
+	days := []*statistics_repo.DayStatistics{
+		{ScriptID: 1, Op: statistics_repo.ViewScriptStatistics, Pv: 1, Uv: 1},
+		{ScriptID: 1, Op: statistics_repo.DownloadScriptStatistics, Err: errors.New("redis error")},
+	}
+	for _, id := range []int64{2, 3} {
+		days = append(days,
+			&statistics_repo.DayStatistics{ScriptID: id, Op: statistics_repo.ViewScriptStatistics, Pv: 1, Uv: 1},
+			&statistics_repo.DayStatistics{ScriptID: id, Op: statistics_repo.DownloadScriptStatistics},
+			&statistics_repo.DayStatistics{ScriptID: id, Op: statistics_repo.UpdateScriptStatistics},
+		)
+	}
+	mockStatistics.EXPECT().DayBatch(gomock.Any(), []int64{1, 2, 3}, dailyOps, date).Return(days, nil)
+	// 批量保存失败后逐个脚本保存, 脚本2失败不影响脚本3
+	mockDaily.EXPECT().Save(gomock.Any(), gomock.Len(2)).Return(errors.New("db error"))
+	mockDaily.EXPECT().Save(gomock.Any(), gomock.Len(1)).DoAndReturn(
+		func(ctx context.Context, list []*statistics_entity.StatisticsDaily) error {
+			if list[0].ScriptID == 2 {
+				return errors.New("db error")
+			}
+			return nil
+		}).Times(2)
+	mockDaily.EXPECT().SaveUA(gomock.Any(), gomock.Len(0)).Return(nil)
+	mockDaily.EXPECT().SaveSource(gomock.Any(), gomock.Len(0)).Return(nil)
+
+	require.NoError(t, s.SaveDaily(ctx, date))
+}
+
+func TestStatisticsSvc_dateRange(t *testing.T) {
+	ctx := context.Background()
+	s := &statisticsSvc{}
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	start, end, err := s.dateRange(ctx, "", "", "day")
+	require.NoError(t, err)
+	assert.Equal(t, today, end)
+	assert.Equal(t, today.AddDate(0, 0, -29), start)
+
+	start, end, err = s.dateRange(ctx, "2023-01-01", "2023-12-31", "day")
+	require.NoError(t, err)
+	assert.Equal(t, "2023-01-01", start.Format("2006-01-02"))
+	assert.Equal(t, "2023-12-31", end.Format("2006-01-02"))
+
+	_, _, err = s.dateRange(ctx, "2020-01-01", "2023-12-31", "day")
+	assert.Error(t, err)
+	_, _, err = s.dateRange(ctx, "2020-01-01", "2023-12-31", "month")
+	assert.NoError(t, err)
+	_, _, err = s.dateRange(ctx, "2023-12-31", "2023-01-01", "day")
+	assert.Error(t, err)
+}
+
+func TestStatisticsSvc_dailyData(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockRepo := mock_statistics_repo.NewMockStatisticsDailyRepo(mockCtrl)
+	statistics_repo.RegisterStatisticsDaily(mockRepo)
+
+	ctx := context.Background()
+	s := &statisticsSvc{}
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.Local)
+	end := time.Date(2023, 1, 3, 0, 0, 0, 0, time.Local)
+
+	// 超过redis保留时间的缺失日期补0
+	mockRepo.EXPECT().FindRange(gomock.Any(), int64(1), statistics_repo.DownloadScriptStatistics,
+		"2023-01-01", "2023-01-03").Return([]*statistics_entity.StatisticsDaily{
+		{ScriptID: 1, Op: "download", Date: "2023-01-02", Pv: 10, Uv: 5},
+	}, nil)
+	list, err := s.dailyData(ctx, 1, statistics_repo.DownloadScriptStatistics, start, end)
+	require.NoError(t, err)
+	require.Len(t, list, 3)
+	assert.Equal(t, "2023-01-01", list[0].Date)
+	assert.Equal(t, int64(0), list[0].Pv)
+	assert.Equal(t, int64(10), list[1].Pv)
+	assert.Equal(t, int64(5), list[1].Uv)
+	assert.Equal(t, "2023-01-03", list[2].Date)
+}
+
+func TestStatisticsSvc_visitorPie(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockDaily := mock_statistics_repo.NewMockStatisticsDailyRepo(mockCtrl)
+	statistics_repo.RegisterStatisticsDaily(mockDaily)
+	mockStatistics := mock_statistics_repo.NewMockScriptStatisticsRepo(mockCtrl)
+	statistics_repo.RegisterScriptStatistics(mockStatistics)
+
+	ctx := context.Background()
+	s := &statisticsSvc{}
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.Local)
+	end := time.Date(2023, 1, 2, 0, 0, 0, 0, time.Local)
+
+	mockDaily.EXPECT().SumUARange(gomock.Any(), int64(1), gomock.Len(1), "2023-01-01", "2023-01-02").
+		Return([]*statistics_entity.StatisticsUaDaily{
+			{Dimension: "browser", Value: "chrome", Num: 3},
+			{Dimension: "manager", Value: "Tampermonkey", Num: 3},
+		}, nil)
+	// 只有没有落库且有数据的日期从redis读取
+	mockStatistics.EXPECT().DayUA(gomock.Any(), int64(1), statistics_repo.DownloadScriptStatistics, end).
+		Return(map[string]int64{"browser:firefox": 4, "browser:chrome": 1, "manager:ScriptCat": 5}, nil)
+	pie, err := s.visitorPie(ctx, 1, start, end, map[statistics_repo.ScriptStatisticsType][]*statistics_entity.StatisticsDaily{
+		statistics_repo.DownloadScriptStatistics: {
+			{ID: 1, Date: "2023-01-01", Pv: 3, Uv: 3},
+			{Date: "2023-01-02", Pv: 5, Uv: 5},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []*api.PieChart{{Key: "chrome", Value: 4}, {Key: "firefox", Value: 4}}, pie.Browser)
+	assert.Equal(t, []*api.PieChart{{Key: "ScriptCat", Value: 5}, {Key: "Tampermonkey", Value: 3}}, pie.Manager)
+	assert.Empty(t, pie.OS)
+}
+
+func TestPieChart(t *testing.T) {
+	m := map[string]int64{}
+	for i := 0; i < visitorPieTop+2; i++ {
+		m["v"+strconv.Itoa(i)] = int64(i + 1)
+	}
+	pie := pieChart(m, visitorPieTop)
+	assert.Len(t, pie, visitorPieTop+1)
+	assert.Equal(t, &api.PieChart{Key: "v11", Value: 12}, pie[0])
+	assert.Equal(t, &api.PieChart{Key: "other", Value: 3}, pie[visitorPieTop])
+}
+
+func TestStatisticsSvc_installSource(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockDaily := mock_statistics_repo.NewMockStatisticsDailyRepo(mockCtrl)
+	statistics_repo.RegisterStatisticsDaily(mockDaily)
+	mockStatistics := mock_statistics_repo.NewMockScriptStatisticsRepo(mockCtrl)
+	statistics_repo.RegisterScriptStatistics(mockStatistics)
+
+	ctx := context.Background()
+	s := &statisticsSvc{}
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.Local)
+	end := time.Date(2023, 1, 2, 0, 0, 0, 0, time.Local)
+
+	mockDaily.EXPECT().SumSourceRange(gomock.Any(), int64(1), "2023-01-01", "2023-01-02").
+		Return([]*statistics_entity.StatisticsSourceDaily{
+			{Dimension: "source", Value: "site", Num: 6},
+			{Dimension: "source", Value: "external", Num: 2},
+			{Dimension: "referer", Value: "github.com", Num: 2},
+		}, nil)
+	mockStatistics.EXPECT().DaySource(gomock.Any(), int64(1), statistics_repo.DownloadScriptStatistics, end).
+		Return(map[string]int64{"source:external": 3, "referer:greasyfork.org": 3}, nil)
+	source, err := s.installSource(ctx, 1, start, end, []*statistics_entity.StatisticsDaily{
+		{ID: 1, Date: "2023-01-01", Pv: 8, Uv: 8},
+		{Date: "2023-01-02", Pv: 3, Uv: 3},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []*api.PieChart{{Key: "site", Value: 6}, {Key: "external", Value: 5}}, source.Source)
+	assert.Equal(t, []*api.PieChart{{Key: "greasyfork.org", Value: 3}, {Key: "github.com", Value: 2}}, source.Referer)
+	assert.Empty(t, source.Folder)
+}
@@ -0,0 +1,234 @@
+package statistics_svc
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/cago-frame/cago/database/db"
+	"github.com/cago-frame/cago/pkg/consts"
+	"github.com/cago-frame/cago/pkg/i18n"
+	"github.com/cago-frame/cago/pkg/utils/httputils"
+	"github.com/gin-gonic/gin"
+	api "github.com/scriptscat/scriptlist/internal/api/statistics"
+	"github.com/scriptscat/scriptlist/internal/model/entity/issue_entity"
+	"github.com/scriptscat/scriptlist/internal/model/entity/statistics_entity"
+	"github.com/scriptscat/scriptlist/internal/pkg/code"
+	"github.com/scriptscat/scriptlist/internal/repository/issue_repo"
+	"github.com/scriptscat/scriptlist/internal/repository/script_repo"
+	"github.com/scriptscat/scriptlist/internal/repository/statistics_repo"
+	"github.com/scriptscat/scriptlist/internal/service/auth_svc"
+	"github.com/scriptscat/scriptlist/internal/task/producer"
+	"gorm.io/gorm"
+)
+
+const (
+	// 脚本每分钟全部记录的错误数量, 超过后每errorSampleRate条记录一条
+	errorSampleThreshold = 60
+	errorSampleRate      = 10
+	// 脚本每分钟最多接收的错误数量, 超过的丢弃
+	errorMinuteLimit = 1200
+	// 转为反馈时标题的最大长度
+	errorIssueTitleLen = 100
+)
+
+// errorSample 根据脚本当前分钟内的错误数量采样, 返回记录的权重, 为0时不记录
+func errorSample(num int64) int64 {
+	if num <= errorSampleThreshold {
+		return 1
+	}
+	if num%errorSampleRate != 0 {
+		return 0
+	}
+	return errorSampleRate
+}
+
+// ErrorReport 脚本运行错误上报
+func (s *statisticsSvc) ErrorReport(ctx *gin.Context, req *api.ErrorReportRequest) (*api.ErrorReportResponse, error) {
+	info, err := statistics_repo.StatisticsInfo().FindByStatisticsKey(ctx, req.Key)
+	if err != nil {
+		return nil, err
+	}
+	if info == nil || info.ID == 0 {
+		return nil, i18n.NewNotFoundError(ctx, code.StatisticsInfoUninitialized)
+	}
+	// page由客户端填写, 不可信, 使用浏览器发送的Origin或Referer判断白名单
+	host := requestHost(ctx)
+	if host == "" {
+		return nil, i18n.NewError(ctx, code.StatisticsWhitelistNotFound)
+	}
+	if !info.Whitelist.Allow(host) {
+		return nil, i18n.NewForbiddenError(ctx, code.StatisticsWhitelistNotFound)
+	}
+	now := time.Now()
+	num, err := statistics_repo.ScriptError().IncrMinute(ctx, info.ScriptID, now)
+	if err != nil {
+		return nil, err
+	}
+	if num > errorMinuteLimit {
+		return nil, i18n.NewError(ctx, code.StatisticsLimitExceeded)
+	}
+	weight := errorSample(num)
+	// 未被采样的直接返回成功
+	if weight == 0 {
+		return &api.ErrorReportResponse{}, nil
+	}
+	if err := producer.PublishStatisticsError(ctx, &producer.StatisticsErrorMsg{
+		ScriptID: info.ScriptID,
+		Message:  req.Message,
+		Stack:    req.Stack,
+		Host:     host,
+		Version:  req.Version,
+		Weight:   weight,
+		Time:     now.Unix(),
+	}); err != nil {
+		return nil, err
+	}
+	return &api.ErrorReportResponse{}, nil
+}
+
+// requestHost 请求来源页面的域名, 优先使用Origin, 没有时使用Referer
+func requestHost(ctx *gin.Context) string {
+	for _, v := range []string{ctx.GetHeader("Origin"), ctx.GetHeader("Referer")} {
+		if u, err := url.Parse(v); err == nil && u.Hostname() != "" {
+			return strings.ToLower(u.Hostname())
+		}
+	}
+	return ""
+}
+
+func (s *statisticsSvc) toErrorGroup(group *statistics_entity.ScriptErrorGroup) *api.ErrorGroup {
+	return &api.ErrorGroup{
+		ID:        group.ID,
+		Message:   group.Message,
+		Host:      group.Host,
+		Version:   group.Version,
+		Count:     group.Count,
+		IssueID:   group.IssueID,
+		FirstTime: group.FirstTime,
+		LastTime:  group.LastTime,
+	}
+}
+
+// ErrorGroupList 脚本运行错误列表
+func (s *statisticsSvc) ErrorGroupList(ctx context.Context, req *api.ErrorGroupListRequest) (*api.ErrorGroupListResponse, error) {
+	list, total, err := statistics_repo.ScriptError().FindPage(ctx, req.ID, req.PageRequest)
+	if err != nil {
+		return nil, err
+	}
+	resp := &api.ErrorGroupListResponse{
+		PageResponse: httputils.PageResponse[*api.ErrorGroup]{
+			List:  make([]*api.ErrorGroup, 0, len(list)),
+			Total: total,
+		},
+	}
+	for _, v := range list {
+		resp.List = append(resp.List, s.toErrorGroup(v))
+	}
+	return resp, nil
+}
+
+// ErrorGroup 脚本运行错误详情
+func (s *statisticsSvc) ErrorGroup(ctx context.Context, req *api.ErrorGroupRequest) (*api.ErrorGroupResponse, error) {
+	group, err := statistics_repo.ScriptError().FindGroup(ctx, req.ID, req.GroupID)
+	if err != nil {
+		return nil, err
+	}
+	if group == nil {
+		return nil, i18n.NewNotFoundError(ctx, code.StatisticsErrorGroupNotFound)
+	}
+	versions, err := statistics_repo.ScriptError().FindVersions(ctx, group.ID)
+	if err != nil {
+		return nil, err
+	}
+	resp := &api.ErrorGroupResponse{
+		ErrorGroup: s.toErrorGroup(group),
+		Stack:      group.Stack,
+		Versions:   make([]*api.PieChart, 0, len(versions)),
+	}
+	for _, v := range versions {
+		resp.Versions = append(resp.Versions, &api.PieChart{Key: v.Version, Value: v.Count})
+	}
+	return resp, nil
+}
+
+// ErrorGroupIssue 将错误转为脚本反馈, 反馈由当前用户发布
+func (s *statisticsSvc) ErrorGroupIssue(ctx context.Context, req *api.ErrorGroupIssueRequest) (*api.ErrorGroupIssueResponse, error) {
+	group, err := statistics_repo.ScriptError().FindGroup(ctx, req.ID, req.GroupID)
+	if err != nil {
+		return nil, err
+	}
+	if group == nil {
+		return nil, i18n.NewNotFoundError(ctx, code.StatisticsErrorGroupNotFound)
+	}
+	if group.IssueID != 0 {
+		return nil, i18n.NewError(ctx, code.StatisticsErrorGroupConverted)
+	}
+	script, err := script_repo.Script().Find(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	versions, err := statistics_repo.ScriptError().FindVersions(ctx, group.ID)
+	if err != nil {
+		return nil, err
+	}
+	issue := &issue_entity.ScriptIssue{
+		ScriptID:   req.ID,
+		UserID:     auth_svc.Auth().Get(ctx).UID,
+		Title:      errorIssueTitle(group.Message),
+		Content:    errorIssueContent(group, versions),
+		Labels:     "bug",
+		Status:     consts.ACTIVE,
+		Createtime: time.Now().Unix(),
+	}
+	// 同时转为反馈时只有一个请求能成功, 失败的请求回滚创建的反馈
+	if err := db.Ctx(ctx).Transaction(func(tx *gorm.DB) error {
+		ctx := db.WithContextDB(ctx, tx)
+		if err := issue_repo.Issue().Create(ctx, issue); err != nil {
+			return err
+		}
+		ok, err := statistics_repo.ScriptError().BindIssue(ctx, group.ID, issue.ID)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return i18n.NewError(ctx, code.StatisticsErrorGroupConverted)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return &api.ErrorGroupIssueResponse{IssueID: issue.ID}, producer.PublishIssueCreate(ctx, script, issue)
+}
+
+// errorIssueTitle 使用错误信息的第一行作为反馈标题
+func errorIssueTitle(message string) string {
+	message, _, _ = strings.Cut(strings.TrimSpace(message), "\n")
+	if r := []rune(message); len(r) > errorIssueTitleLen {
+		message = string(r[:errorIssueTitleLen]) + "..."
+	}
+	return "[运行错误] " + message
+}
+
+// errorIssueContent 反馈的内容, 包含错误的发生情况, 版本分布与堆栈
+func errorIssueContent(group *statistics_entity.ScriptErrorGroup, versions []*statistics_entity.ScriptErrorVersion) string {
+	b := &strings.Builder{}
+	b.WriteString("该反馈由脚本运行错误上报自动生成\n\n")
+	fmt.Fprintf(b, "- 错误信息: %s\n", group.Message)
+	fmt.Fprintf(b, "- 发生次数: %d\n", group.Count)
+	fmt.Fprintf(b, "- 首次发生: %s\n", time.Unix(group.FirstTime, 0).Format("2006-01-02 15:04:05"))
+	fmt.Fprintf(b, "- 最近发生: %s (%s)\n", time.Unix(group.LastTime, 0).Format("2006-01-02 15:04:05"), group.Host)
+	if len(versions) > 0 {
+		list := make([]string, 0, len(versions))
+		for _, v := range versions {
+			list = append(list, fmt.Sprintf("%s (%d)", v.Version, v.Count))
+		}
+		fmt.Fprintf(b, "- 版本分布: %s\n", strings.Join(list, ", "))
+	}
+	if group.Stack != "" {
+		fmt.Fprintf(b, "\n```\n%s\n```\n", group.Stack)
+	}
+	return b.String()
+}
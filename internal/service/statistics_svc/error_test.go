@@ -0,0 +1,72 @@
+package statistics_svc
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	api "github.com/scriptscat/scriptlist/internal/api/statistics"
+	"github.com/scriptscat/scriptlist/internal/model/entity/statistics_entity"
+	"github.com/scriptscat/scriptlist/internal/repository/statistics_repo"
+	mock_statistics_repo "github.com/scriptscat/scriptlist/internal/repository/statistics_repo/mock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestErrorSample(t *testing.T) {
+	assert.Equal(t, int64(1), errorSample(1))
+	assert.Equal(t, int64(1), errorSample(errorSampleThreshold))
+	assert.Equal(t, int64(0), errorSample(errorSampleThreshold+1))
+	assert.Equal(t, int64(errorSampleRate), errorSample(errorSampleThreshold*2))
+}
+
+func TestRequestHost(t *testing.T) {
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ctx.Request = httptest.NewRequest("POST", "/statistics/key/error", nil)
+	assert.Equal(t, "", requestHost(ctx))
+	ctx.Request.Header.Set("Referer", "https://www.Example.com/page")
+	assert.Equal(t, "www.example.com", requestHost(ctx))
+	// 优先使用Origin
+	ctx.Request.Header.Set("Origin", "https://scriptcat.org")
+	assert.Equal(t, "scriptcat.org", requestHost(ctx))
+}
+
+func TestErrorIssue(t *testing.T) {
+	assert.Equal(t, "[运行错误] a is null", errorIssueTitle("a is null\n    at main"))
+	content := errorIssueContent(&statistics_entity.ScriptErrorGroup{
+		Message: "a is null",
+		Stack:   "at main (userscript.html:1:1)",
+		Host:    "example.com",
+		Count:   12,
+	}, []*statistics_entity.ScriptErrorVersion{{Version: "1.0.0", Count: 10}, {Version: "1.0.1", Count: 2}})
+	assert.Contains(t, content, "- 发生次数: 12")
+	assert.Contains(t, content, "- 版本分布: 1.0.0 (10), 1.0.1 (2)")
+	assert.Contains(t, content, "```\nat main (userscript.html:1:1)\n```")
+}
+
+func TestStatisticsSvc_ErrorGroupIssue(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockRepo := mock_statistics_repo.NewMockScriptErrorRepo(mockCtrl)
+	statistics_repo.RegisterScriptError(mockRepo)
+
+	ctx := context.Background()
+	s := &statisticsSvc{}
+
+	t.Run("错误不存在", func(t *testing.T) {
+		mockRepo.EXPECT().FindGroup(gomock.Any(), int64(1), int64(2)).Return(nil, nil)
+		_, err := s.ErrorGroupIssue(ctx, &api.ErrorGroupIssueRequest{ID: 1, GroupID: 2})
+		assert.Error(t, err)
+	})
+
+	t.Run("已经转为反馈", func(t *testing.T) {
+		mockRepo.EXPECT().FindGroup(gomock.Any(), int64(1), int64(2)).Return(&statistics_entity.ScriptErrorGroup{
+			ID: 2, ScriptID: 1, IssueID: 3,
+		}, nil)
+		_, err := s.ErrorGroupIssue(ctx, &api.ErrorGroupIssueRequest{ID: 1, GroupID: 2})
+		assert.Error(t, err)
+	})
+}
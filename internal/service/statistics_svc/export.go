@@ -0,0 +1,227 @@
+package statistics_svc
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/cago-frame/cago/pkg/logger"
+	"github.com/gin-gonic/gin"
+	api "github.com/scriptscat/scriptlist/internal/api/statistics"
+	"github.com/scriptscat/scriptlist/internal/model/entity/statistics_entity"
+	"github.com/scriptscat/scriptlist/internal/repository/statistics_repo"
+	"go.uber.org/zap"
+)
+
+// 每次从数据库读取的天数, 避免一次性加载整个时间范围的数据
+const exportChunkDays = 31
+
+// exportDimensionTotal 当天的pv和uv
+const exportDimensionTotal = "total"
+
+// exportWriter 按格式写入导出的数据
+type exportWriter interface {
+	Write(row *api.ExportRow) error
+	Flush() error
+}
+
+type csvExportWriter struct {
+	w      *csv.Writer
+	header bool
+}
+
+func newCSVExportWriter(w io.Writer) *csvExportWriter {
+	return &csvExportWriter{w: csv.NewWriter(w)}
+}
+
+func (c *csvExportWriter) Write(row *api.ExportRow) error {
+	if !c.header {
+		c.header = true
+		if err := c.w.Write([]string{"date", "op", "dimension", "value", "pv", "uv"}); err != nil {
+			return err
+		}
+	}
+	return c.w.Write([]string{row.Date, row.Op, row.Dimension, row.Value,
+		strconv.FormatInt(row.Pv, 10), strconv.FormatInt(row.Uv, 10)})
+}
+
+func (c *csvExportWriter) Flush() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+type ndjsonExportWriter struct {
+	enc *json.Encoder
+}
+
+func newNDJSONExportWriter(w io.Writer) *ndjsonExportWriter {
+	return &ndjsonExportWriter{enc: json.NewEncoder(w)}
+}
+
+func (n *ndjsonExportWriter) Write(row *api.ExportRow) error {
+	return n.enc.Encode(row)
+}
+
+func (n *ndjsonExportWriter) Flush() error {
+	return nil
+}
+
+// ScriptExport 以csv或者ndjson的格式流式导出脚本每日统计数据
+func (s *statisticsSvc) ScriptExport(ctx *gin.Context, req *api.ScriptExportRequest) error {
+	start, end, err := s.dateRange(ctx, req.Start, req.End, "day")
+	if err != nil {
+		return err
+	}
+	var w exportWriter
+	header := ctx.Writer.Header()
+	if req.Format == "ndjson" {
+		w = newNDJSONExportWriter(ctx.Writer)
+		header.Set("Content-Type", "application/x-ndjson; charset=utf-8")
+	} else {
+		w = newCSVExportWriter(ctx.Writer)
+		header.Set("Content-Type", "text/csv; charset=utf-8")
+	}
+	header.Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"script-%d-statistics-%s-%s.%s\"",
+		req.ID, start.Format("20060102"), end.Format("20060102"), req.Format))
+	ctx.Status(http.StatusOK)
+	// 已经开始输出, 出错时只能中断
+	if err := s.exportRows(ctx, req.ID, start, end, func(rows []*api.ExportRow) error {
+		for _, row := range rows {
+			if err := w.Write(row); err != nil {
+				return err
+			}
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		ctx.Writer.Flush()
+		return nil
+	}); err != nil {
+		logger.Ctx(ctx).Error("导出统计数据失败", zap.Error(err), zap.Int64("script_id", req.ID))
+	}
+	return nil
+}
+
+// exportRows 分段读取时间范围内每天的数据, 每段数据读取完成后调用write
+func (s *statisticsSvc) exportRows(ctx context.Context, scriptId int64, start, end time.Time,
+	write func(rows []*api.ExportRow) error) error {
+	for chunkStart := start; !chunkStart.After(end); chunkStart = chunkStart.AddDate(0, 0, exportChunkDays) {
+		chunkEnd := chunkStart.AddDate(0, 0, exportChunkDays-1)
+		if chunkEnd.After(end) {
+			chunkEnd = end
+		}
+		rows, err := s.exportChunk(ctx, scriptId, chunkStart, chunkEnd)
+		if err != nil {
+			return err
+		}
+		if err := write(rows); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *statisticsSvc) exportChunk(ctx context.Context, scriptId int64, start, end time.Time) ([]*api.ExportRow, error) {
+	daily := make(map[statistics_repo.ScriptStatisticsType][]*statistics_entity.StatisticsDaily, len(dailyOps))
+	for _, op := range dailyOps {
+		list, err := s.dailyData(ctx, scriptId, op, start, end)
+		if err != nil {
+			return nil, err
+		}
+		daily[op] = list
+	}
+	startStr, endStr := start.Format("2006-01-02"), end.Format("2006-01-02")
+	uaList, err := statistics_repo.StatisticsDaily().FindUARange(ctx, scriptId, startStr, endStr)
+	if err != nil {
+		return nil, err
+	}
+	// 按 日期:操作 分组
+	ua := make(map[string][]*api.ExportRow)
+	for _, v := range uaList {
+		key := v.Date + ":" + v.Op
+		ua[key] = append(ua[key], &api.ExportRow{
+			Date: v.Date, Op: v.Op, Dimension: v.Dimension, Value: v.Value, Pv: v.Num,
+		})
+	}
+	sourceList, err := statistics_repo.StatisticsDaily().FindSourceRange(ctx, scriptId, startStr, endStr)
+	if err != nil {
+		return nil, err
+	}
+	source := make(map[string][]*api.ExportRow)
+	for _, v := range sourceList {
+		source[v.Date] = append(source[v.Date], &api.ExportRow{
+			Date: v.Date, Op: string(statistics_repo.DownloadScriptStatistics),
+			Dimension: v.Dimension, Value: v.Value, Pv: v.Num,
+		})
+	}
+
+	ret := make([]*api.ExportRow, 0)
+	for i, day := range daily[statistics_repo.ViewScriptStatistics] {
+		date := day.Date
+		for _, op := range dailyOps {
+			v := daily[op][i]
+			ret = append(ret, &api.ExportRow{
+				Date: date, Op: string(op), Dimension: exportDimensionTotal, Pv: v.Pv, Uv: v.Uv,
+			})
+		}
+		for _, op := range dailyOps {
+			// 没有落库的近期数据从redis读取
+			if !recentDaily(daily[op][i]) {
+				ret = append(ret, ua[date+":"+string(op)]...)
+				continue
+			}
+			sum, err := dimensionSum(ctx, map[statistics_repo.ScriptStatisticsType][]*statistics_entity.StatisticsDaily{
+				op: {daily[op][i]},
+			}, nil, func(ctx context.Context, op statistics_repo.ScriptStatisticsType, t time.Time) (map[string]int64, error) {
+				return statistics_repo.ScriptStatistics().DayUA(ctx, scriptId, op, t)
+			})
+			if err != nil {
+				return nil, err
+			}
+			ret = append(ret, exportDimensionRows(date, op, sum)...)
+		}
+		download := daily[statistics_repo.DownloadScriptStatistics][i]
+		if !recentDaily(download) {
+			ret = append(ret, source[date]...)
+			continue
+		}
+		sum, err := dimensionSum(ctx, map[statistics_repo.ScriptStatisticsType][]*statistics_entity.StatisticsDaily{
+			statistics_repo.DownloadScriptStatistics: {download},
+		}, nil, func(ctx context.Context, op statistics_repo.ScriptStatisticsType, t time.Time) (map[string]int64, error) {
+			return statistics_repo.ScriptStatistics().DaySource(ctx, scriptId, op, t)
+		})
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, exportDimensionRows(date, statistics_repo.DownloadScriptStatistics, sum)...)
+	}
+	return ret, nil
+}
+
+// exportDimensionRows 将各维度的数量转换为导出的数据, 按维度和数量排序
+func exportDimensionRows(date string, op statistics_repo.ScriptStatisticsType, sum map[string]map[string]int64) []*api.ExportRow {
+	ret := make([]*api.ExportRow, 0)
+	for dimension, m := range sum {
+		for value, num := range m {
+			ret = append(ret, &api.ExportRow{
+				Date: date, Op: string(op), Dimension: dimension, Value: value, Pv: num,
+			})
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].Dimension != ret[j].Dimension {
+			return ret[i].Dimension < ret[j].Dimension
+		}
+		if ret[i].Pv != ret[j].Pv {
+			return ret[i].Pv > ret[j].Pv
+		}
+		return ret[i].Value < ret[j].Value
+	})
+	return ret
+}
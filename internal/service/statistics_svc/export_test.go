@@ -0,0 +1,80 @@
+package statistics_svc
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	api "github.com/scriptscat/scriptlist/internal/api/statistics"
+	"github.com/scriptscat/scriptlist/internal/model/entity/statistics_entity"
+	"github.com/scriptscat/scriptlist/internal/repository/statistics_repo"
+	mock_statistics_repo "github.com/scriptscat/scriptlist/internal/repository/statistics_repo/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestExportWriter(t *testing.T) {
+	row := &api.ExportRow{Date: "2023-01-01", Op: "download", Dimension: "browser", Value: "chrome, edge", Pv: 10}
+
+	buf := &bytes.Buffer{}
+	w := newCSVExportWriter(buf)
+	require.NoError(t, w.Write(row))
+	require.NoError(t, w.Flush())
+	assert.Equal(t, "date,op,dimension,value,pv,uv\n2023-01-01,download,browser,\"chrome, edge\",10,0\n", buf.String())
+
+	buf = &bytes.Buffer{}
+	nw := newNDJSONExportWriter(buf)
+	require.NoError(t, nw.Write(row))
+	require.NoError(t, nw.Write(row))
+	assert.Equal(t, 2, bytes.Count(buf.Bytes(), []byte("\n")))
+}
+
+func TestStatisticsSvc_exportRows(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockRepo := mock_statistics_repo.NewMockStatisticsDailyRepo(mockCtrl)
+	statistics_repo.RegisterStatisticsDaily(mockRepo)
+
+	ctx := context.Background()
+	s := &statisticsSvc{}
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.Local)
+	end := start.AddDate(0, 0, exportChunkDays)
+
+	// 超过一段的天数分两次读取
+	for _, r := range [][2]string{{"2023-01-01", "2023-01-31"}, {"2023-02-01", "2023-02-01"}} {
+		for _, op := range dailyOps {
+			list := []*statistics_entity.StatisticsDaily{}
+			if op == statistics_repo.DownloadScriptStatistics && r[0] == "2023-01-01" {
+				list = append(list, &statistics_entity.StatisticsDaily{ID: 1, ScriptID: 1, Op: "download", Date: "2023-01-01", Pv: 10, Uv: 5})
+			}
+			mockRepo.EXPECT().FindRange(gomock.Any(), int64(1), op, r[0], r[1]).Return(list, nil)
+		}
+		var ua []*statistics_entity.StatisticsUaDaily
+		var source []*statistics_entity.StatisticsSourceDaily
+		if r[0] == "2023-01-01" {
+			ua = append(ua, &statistics_entity.StatisticsUaDaily{Op: "download", Date: "2023-01-01", Dimension: "browser", Value: "chrome", Num: 10})
+			source = append(source, &statistics_entity.StatisticsSourceDaily{Date: "2023-01-01", Dimension: "source", Value: "direct", Num: 10})
+		}
+		mockRepo.EXPECT().FindUARange(gomock.Any(), int64(1), r[0], r[1]).Return(ua, nil)
+		mockRepo.EXPECT().FindSourceRange(gomock.Any(), int64(1), r[0], r[1]).Return(source, nil)
+	}
+
+	var rows []*api.ExportRow
+	chunks := 0
+	err := s.exportRows(ctx, 1, start, end, func(list []*api.ExportRow) error {
+		chunks++
+		rows = append(rows, list...)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, chunks)
+	// 每天3行汇总数据, 第一天另有ua和来源各1行
+	assert.Len(t, rows, (exportChunkDays+1)*3+2)
+	assert.Equal(t, &api.ExportRow{Date: "2023-01-01", Op: "download", Dimension: "total", Pv: 10, Uv: 5}, rows[1])
+	assert.Equal(t, "browser", rows[3].Dimension)
+	assert.Equal(t, "source", rows[4].Dimension)
+	assert.Equal(t, "2023-02-01", rows[len(rows)-1].Date)
+}
@@ -0,0 +1,324 @@
+package statistics_svc
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/cago-frame/cago/pkg/i18n"
+	"github.com/cago-frame/cago/pkg/logger"
+	"github.com/cago-frame/cago/pkg/utils/httputils"
+	api "github.com/scriptscat/scriptlist/internal/api/statistics"
+	"github.com/scriptscat/scriptlist/internal/model/entity/script_entity"
+	"github.com/scriptscat/scriptlist/internal/model/entity/statistics_entity"
+	"github.com/scriptscat/scriptlist/internal/pkg/code"
+	"github.com/scriptscat/scriptlist/internal/repository/script_repo"
+	"github.com/scriptscat/scriptlist/internal/repository/statistics_repo"
+	"github.com/scriptscat/scriptlist/internal/service/auth_svc"
+	"github.com/scriptscat/scriptlist/internal/task/producer"
+	"go.uber.org/zap"
+)
+
+const (
+	// 当日下载量达到该数量才进行检测
+	fraudMinDownload = 200
+	// 当日下载量超过日均下载量的倍数视为突增
+	fraudSpikeRatio = 5
+	// 同一ip段的下载占比
+	fraudIPNetRatio = 0.5
+	// 同一统计token的下载次数, 下载按ip去重, 同一token出现多次说明在不同的ip下使用
+	fraudTokenRepeat = 20
+	// 不可能的ua组合占比
+	fraudUARatio = 0.3
+)
+
+// ipNet ipv4取/24, ipv6取/48
+func ipNet(ip string) string {
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return ""
+	}
+	if v4 := addr.To4(); v4 != nil {
+		return v4.Mask(net.CIDRMask(24, 32)).String() + "/24"
+	}
+	return addr.Mask(net.CIDRMask(48, 128)).String() + "/48"
+}
+
+// fraudReasons 只有下载量突增且存在异常特征时才标记
+func fraudReasons(stat *statistics_repo.FraudStat, baseline float64) []script_entity.FraudReason {
+	if stat.Total < fraudMinDownload || float64(stat.Total) < baseline*fraudSpikeRatio {
+		return nil
+	}
+	reasons := make([]script_entity.FraudReason, 0)
+	if float64(stat.IPNetNum) >= float64(stat.Total)*fraudIPNetRatio {
+		reasons = append(reasons, script_entity.FraudReasonIPRange)
+	}
+	if stat.TokenNum >= fraudTokenRepeat {
+		reasons = append(reasons, script_entity.FraudReasonToken)
+	}
+	if float64(stat.Impossible) >= float64(stat.Total)*fraudUARatio {
+		reasons = append(reasons, script_entity.FraudReasonUA)
+	}
+	if len(reasons) == 0 {
+		return nil
+	}
+	return append([]script_entity.FraudReason{script_entity.FraudReasonSpike}, reasons...)
+}
+
+// FraudDetect 检测计入统计的下载是否存在刷量
+func (s *statisticsSvc) FraudDetect(ctx context.Context, msg *producer.ScriptStatisticsMsg) error {
+	stat, err := statistics_repo.Fraud().Record(ctx, msg.ScriptID, msg.Time, ipNet(msg.IP), msg.StatisticsToken,
+		statistics_entity.ParseUserAgent(msg.UA).Impossible())
+	if err != nil {
+		return err
+	}
+	if stat.Total < fraudMinDownload {
+		return nil
+	}
+	baseline, err := statistics_repo.Fraud().Baseline(ctx, msg.ScriptID)
+	if err != nil {
+		return err
+	}
+	reasons := fraudReasons(stat, baseline)
+	if len(reasons) == 0 {
+		return nil
+	}
+	// 使用当日的完整数据作为审核依据
+	day, err := statistics_repo.Fraud().DayStat(ctx, msg.ScriptID, msg.Time)
+	if err != nil {
+		return err
+	}
+	return s.flagFraud(ctx, msg.ScriptID, msg.Time, reasons, day, baseline)
+}
+
+// FraudDaily 根据每日下载量计算日均下载量, 并检测某一天是否存在刷量
+func (s *statisticsSvc) FraudDaily(ctx context.Context, date time.Time) error {
+	day := date.Format("2006-01-02")
+	start := date.AddDate(0, 0, -7)
+	lastId := int64(0)
+	flagged := 0
+	for {
+		list, err := script_repo.Migrate().List(ctx, lastId, 200)
+		if err != nil {
+			return err
+		}
+		if len(list) == 0 {
+			break
+		}
+		lastId = list[len(list)-1].ID
+		ids := make([]int64, 0, len(list))
+		for _, v := range list {
+			ids = append(ids, v.ID)
+		}
+		rows, err := script_repo.ScriptDateStatistics().FindByScriptIDs(ctx, ids, start)
+		if err != nil {
+			return err
+		}
+		downloads := make(map[int64]map[string]int64)
+		for _, v := range rows {
+			if _, ok := downloads[v.ScriptID]; !ok {
+				downloads[v.ScriptID] = make(map[string]int64)
+			}
+			downloads[v.ScriptID][v.Date] = v.Download
+		}
+		for _, id := range ids {
+			m := downloads[id]
+			// 前7天的日均下载量作为检测当天的基线
+			sum := int64(0)
+			for i := 1; i <= 7; i++ {
+				sum += m[date.AddDate(0, 0, -i).Format("2006-01-02")]
+			}
+			baseline := float64(sum) / 7
+			total := m[day]
+			// 包含当天的近7天日均下载量作为实时检测的基线
+			if err := statistics_repo.Fraud().SetBaseline(ctx, id,
+				float64(sum-m[start.Format("2006-01-02")]+total)/7); err != nil {
+				return err
+			}
+			if total < fraudMinDownload {
+				continue
+			}
+			stat, err := statistics_repo.Fraud().DayStat(ctx, id, date)
+			if err != nil {
+				return err
+			}
+			stat.Total = total
+			reasons := fraudReasons(stat, baseline)
+			if len(reasons) == 0 {
+				continue
+			}
+			if err := s.flagFraud(ctx, id, date, reasons, stat, baseline); err != nil {
+				return err
+			}
+			flagged++
+		}
+	}
+	logger.Ctx(ctx).Info("刷量检测完成", zap.String("date", day), zap.Int("flagged", flagged))
+	return nil
+}
+
+// flagFraud 将脚本加入审核队列, 并冻结排行数据
+func (s *statisticsSvc) flagFraud(ctx context.Context, scriptId int64, t time.Time, reasons []script_entity.FraudReason,
+	stat *statistics_repo.FraudStat, baseline float64) error {
+	if ok, err := statistics_repo.Fraud().TryFlag(ctx, scriptId, t); err != nil {
+		return err
+	} else if !ok {
+		return nil
+	}
+	if pending, err := script_repo.ScriptFraudReview().FindPending(ctx, scriptId); err != nil {
+		return err
+	} else if pending != nil {
+		return nil
+	}
+	review := &script_entity.ScriptFraudReview{
+		ScriptID:   scriptId,
+		Status:     script_entity.FraudReviewPending,
+		Date:       t.Format("2006-01-02"),
+		Total:      stat.Total,
+		Baseline:   baseline,
+		IPNet:      stat.IPNet,
+		IPNetNum:   stat.IPNetNum,
+		TokenNum:   stat.TokenNum,
+		Impossible: stat.Impossible,
+		Createtime: time.Now().Unix(),
+	}
+	review.SetReasons(reasons)
+	statistics, err := script_repo.ScriptStatistics().FindByScriptID(ctx, scriptId)
+	if err != nil {
+		return err
+	}
+	if statistics != nil {
+		review.Download = statistics.Download
+		review.Trending = statistics.Trending
+	}
+	if err := script_repo.ScriptFraudReview().Create(ctx, review); err != nil {
+		return err
+	}
+	logger.Ctx(ctx).Warn("脚本疑似刷量", zap.Int64("script_id", scriptId), zap.String("reason", review.Reason),
+		zap.Int64("total", stat.Total), zap.Float64("baseline", baseline))
+	s.syncSearch(ctx, scriptId)
+	return nil
+}
+
+// syncSearch 同步脚本的排行数据到搜索索引
+func (s *statisticsSvc) syncSearch(ctx context.Context, scriptId int64) {
+	logger := logger.Ctx(ctx).With(zap.Int64("script_id", scriptId))
+	script, err := script_repo.Script().Find(ctx, scriptId)
+	if err != nil {
+		logger.Error("同步搜索索引失败", zap.Error(err))
+		return
+	}
+	if script == nil {
+		return
+	}
+	search, err := script_repo.Migrate().Convert(ctx, script)
+	if err != nil {
+		logger.Error("同步搜索索引失败", zap.Error(err))
+		return
+	}
+	if err := script_repo.Migrate().Update(ctx, search); err != nil {
+		logger.Error("同步搜索索引失败", zap.Error(err))
+	}
+}
+
+// FraudReviewList 疑似刷量的脚本审核队列
+func (s *statisticsSvc) FraudReviewList(ctx context.Context, req *api.FraudReviewListRequest) (*api.FraudReviewListResponse, error) {
+	list, total, err := script_repo.ScriptFraudReview().FindPage(ctx, req.Status, req.PageRequest)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]*api.FraudReview, 0, len(list))
+	for _, v := range list {
+		item := &api.FraudReview{
+			ID:         v.ID,
+			ScriptID:   v.ScriptID,
+			Status:     v.Status,
+			Reason:     v.Reasons(),
+			Date:       v.Date,
+			Total:      v.Total,
+			Baseline:   v.Baseline,
+			IPNet:      v.IPNet,
+			IPNetNum:   v.IPNetNum,
+			TokenNum:   v.TokenNum,
+			Impossible: v.Impossible,
+			Download:   v.Download,
+			Trending:   v.Trending,
+			ReviewUID:  v.ReviewUID,
+			ReviewNote: v.ReviewNote,
+			ReviewTime: v.ReviewTime,
+			Createtime: v.Createtime,
+		}
+		script, err := script_repo.Script().Find(ctx, v.ScriptID)
+		if err != nil {
+			return nil, err
+		}
+		if script != nil {
+			item.ScriptName = script.Name
+		}
+		ret = append(ret, item)
+	}
+	return &api.FraudReviewListResponse{
+		PageResponse: httputils.PageResponse[*api.FraudReview]{
+			List:  ret,
+			Total: total,
+		},
+	}, nil
+}
+
+// FraudReview 审核疑似刷量的脚本, 确认刷量时从总下载量中扣除异常当日的下载量并清零当日下载量
+func (s *statisticsSvc) FraudReview(ctx context.Context, req *api.FraudReviewRequest) (*api.FraudReviewResponse, error) {
+	review, err := script_repo.ScriptFraudReview().Find(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	if review == nil {
+		return nil, i18n.NewNotFoundError(ctx, code.StatisticsFraudReviewNotFound)
+	}
+	if !review.IsPending() {
+		return nil, i18n.NewError(ctx, code.StatisticsFraudReviewed)
+	}
+	if req.Status == script_entity.FraudReviewConfirmed {
+		if err := s.revokeFraudDownload(ctx, review); err != nil {
+			return nil, err
+		}
+	}
+	review.Status = req.Status
+	review.ReviewUID = auth_svc.Auth().Get(ctx).UID
+	review.ReviewNote = req.Note
+	review.ReviewTime = time.Now().Unix()
+	review.Updatetime = time.Now().Unix()
+	if err := script_repo.ScriptFraudReview().Update(ctx, review); err != nil {
+		return nil, err
+	}
+	s.syncSearch(ctx, review.ScriptID)
+	return &api.FraudReviewResponse{}, nil
+}
+
+// revokeFraudDownload 扣除异常当日的下载量, 使用增量扣除避免覆盖其它日期以及缓冲中尚未落库的下载量
+func (s *statisticsSvc) revokeFraudDownload(ctx context.Context, review *script_entity.ScriptFraudReview) error {
+	date, err := time.ParseInLocation("2006-01-02", review.Date, time.Local)
+	if err != nil {
+		return err
+	}
+	day, err := script_repo.ScriptDateStatistics().FindByScriptID(ctx, review.ScriptID, date)
+	if err != nil {
+		return err
+	}
+	if day == nil || day.Download <= 0 {
+		return nil
+	}
+	num := day.Download
+	statistics, err := script_repo.ScriptStatistics().FindByScriptID(ctx, review.ScriptID)
+	if err != nil {
+		return err
+	}
+	if statistics != nil && statistics.Download < num {
+		num = statistics.Download
+	}
+	if num > 0 {
+		if err := script_repo.ScriptStatistics().IncrDownload(ctx, review.ScriptID, -num); err != nil {
+			return err
+		}
+	}
+	return script_repo.ScriptDateStatistics().IncrDownload(ctx, review.ScriptID, date, -day.Download)
+}
@@ -0,0 +1,162 @@
+package statistics_svc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	api "github.com/scriptscat/scriptlist/internal/api/statistics"
+	"github.com/scriptscat/scriptlist/internal/model/entity"
+	"github.com/scriptscat/scriptlist/internal/model/entity/script_entity"
+	"github.com/scriptscat/scriptlist/internal/repository/script_repo"
+	mock_script_repo "github.com/scriptscat/scriptlist/internal/repository/script_repo/mock"
+	"github.com/scriptscat/scriptlist/internal/repository/statistics_repo"
+	mock_statistics_repo "github.com/scriptscat/scriptlist/internal/repository/statistics_repo/mock"
+	"github.com/scriptscat/scriptlist/internal/service/auth_svc"
+	mock_auth_svc "github.com/scriptscat/scriptlist/internal/service/auth_svc/mock"
+	"github.com/scriptscat/scriptlist/internal/task/producer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestIPNet(t *testing.T) {
+	assert.Equal(t, "1.2.3.0/24", ipNet("1.2.3.4"))
+	assert.Equal(t, "2001:db8:1::/48", ipNet("2001:db8:1:2::1"))
+	assert.Equal(t, "", ipNet("unknown"))
+}
+
+func TestFraudReasons(t *testing.T) {
+	t.Run("下载量未达到检测数量", func(t *testing.T) {
+		assert.Nil(t, fraudReasons(&statistics_repo.FraudStat{Total: 100, IPNetNum: 100}, 0))
+	})
+	t.Run("没有突增", func(t *testing.T) {
+		assert.Nil(t, fraudReasons(&statistics_repo.FraudStat{Total: 1000, IPNetNum: 1000}, 500))
+	})
+	t.Run("突增但没有异常特征", func(t *testing.T) {
+		assert.Nil(t, fraudReasons(&statistics_repo.FraudStat{Total: 1000, IPNetNum: 10, TokenNum: 1}, 100))
+	})
+	t.Run("突增且集中在同一ip段", func(t *testing.T) {
+		assert.Equal(t, []script_entity.FraudReason{script_entity.FraudReasonSpike, script_entity.FraudReasonIPRange},
+			fraudReasons(&statistics_repo.FraudStat{Total: 1000, IPNetNum: 600}, 100))
+	})
+	t.Run("重复token与不可能的ua", func(t *testing.T) {
+		assert.Equal(t, []script_entity.FraudReason{script_entity.FraudReasonSpike,
+			script_entity.FraudReasonToken, script_entity.FraudReasonUA},
+			fraudReasons(&statistics_repo.FraudStat{Total: 1000, TokenNum: 50, Impossible: 400}, 100))
+	})
+}
+
+func TestStatisticsSvc_FraudDetect(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockFraud := mock_statistics_repo.NewMockFraudRepo(mockCtrl)
+	statistics_repo.RegisterFraud(mockFraud)
+	mockReview := mock_script_repo.NewMockScriptFraudReviewRepo(mockCtrl)
+	script_repo.RegisterScriptFraudReview(mockReview)
+	mockStatistics := mock_script_repo.NewMockScriptStatisticsRepo(mockCtrl)
+	script_repo.RegisterScriptStatistics(mockStatistics)
+	mockScript := mock_script_repo.NewMockScriptRepo(mockCtrl)
+	script_repo.RegisterScript(mockScript)
+
+	ctx := context.Background()
+	s := &statisticsSvc{}
+	msg := &producer.ScriptStatisticsMsg{
+		ScriptID:        1,
+		IP:              "1.2.3.4",
+		UA:              "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+		StatisticsToken: "token",
+		Download:        statistics_repo.DownloadScriptStatistics,
+		Time:            time.Now(),
+	}
+
+	t.Run("下载量较少时不检测", func(t *testing.T) {
+		mockFraud.EXPECT().Record(gomock.Any(), int64(1), msg.Time, "1.2.3.0/24", "token", false).
+			Return(&statistics_repo.FraudStat{Total: 10, IPNetNum: 10, TokenNum: 1}, nil)
+		assert.NoError(t, s.FraudDetect(ctx, msg))
+	})
+
+	t.Run("标记并冻结排行数据", func(t *testing.T) {
+		mockFraud.EXPECT().Record(gomock.Any(), int64(1), msg.Time, "1.2.3.0/24", "token", false).
+			Return(&statistics_repo.FraudStat{Total: 300, IPNetNum: 200, TokenNum: 1}, nil)
+		mockFraud.EXPECT().Baseline(gomock.Any(), int64(1)).Return(float64(20), nil)
+		mockFraud.EXPECT().DayStat(gomock.Any(), int64(1), msg.Time).
+			Return(&statistics_repo.FraudStat{Total: 300, IPNet: "1.2.3.0/24", IPNetNum: 200, TokenNum: 2}, nil)
+		mockFraud.EXPECT().TryFlag(gomock.Any(), int64(1), msg.Time).Return(true, nil)
+		mockReview.EXPECT().FindPending(gomock.Any(), int64(1)).Return(nil, nil)
+		mockStatistics.EXPECT().FindByScriptID(gomock.Any(), int64(1)).
+			Return(&entity.ScriptStatistics{ScriptID: 1, Download: 5000, Trending: 12.5}, nil)
+		mockReview.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, review *script_entity.ScriptFraudReview) error {
+				assert.Equal(t, script_entity.FraudReviewPending, review.Status)
+				assert.Equal(t, "spike,ip_range", review.Reason)
+				assert.Equal(t, "1.2.3.0/24", review.IPNet)
+				assert.Equal(t, int64(5000), review.Download)
+				assert.Equal(t, 12.5, review.Trending)
+				return nil
+			})
+		mockScript.EXPECT().Find(gomock.Any(), int64(1)).Return(nil, nil)
+		require.NoError(t, s.FraudDetect(ctx, msg))
+	})
+
+	t.Run("当天已标记过", func(t *testing.T) {
+		mockFraud.EXPECT().Record(gomock.Any(), int64(1), msg.Time, "1.2.3.0/24", "token", false).
+			Return(&statistics_repo.FraudStat{Total: 301, IPNetNum: 201, TokenNum: 1}, nil)
+		mockFraud.EXPECT().Baseline(gomock.Any(), int64(1)).Return(float64(20), nil)
+		mockFraud.EXPECT().DayStat(gomock.Any(), int64(1), msg.Time).
+			Return(&statistics_repo.FraudStat{Total: 301, IPNet: "1.2.3.0/24", IPNetNum: 201}, nil)
+		mockFraud.EXPECT().TryFlag(gomock.Any(), int64(1), msg.Time).Return(false, nil)
+		assert.NoError(t, s.FraudDetect(ctx, msg))
+	})
+}
+
+func TestStatisticsSvc_FraudReview(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockAuth := mock_auth_svc.NewMockAuthSvc(mockCtrl)
+	auth_svc.RegisterAuth(mockAuth)
+	mockReview := mock_script_repo.NewMockScriptFraudReviewRepo(mockCtrl)
+	script_repo.RegisterScriptFraudReview(mockReview)
+	mockStatistics := mock_script_repo.NewMockScriptStatisticsRepo(mockCtrl)
+	script_repo.RegisterScriptStatistics(mockStatistics)
+	mockDate := mock_script_repo.NewMockScriptDateStatisticsRepo(mockCtrl)
+	script_repo.RegisterScriptDateStatistics(mockDate)
+	mockScript := mock_script_repo.NewMockScriptRepo(mockCtrl)
+	script_repo.RegisterScript(mockScript)
+
+	ctx := context.Background()
+	s := &statisticsSvc{}
+	mockAuth.U().Get(1)
+
+	t.Run("确认前一天的刷量", func(t *testing.T) {
+		yesterday := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+		mockReview.EXPECT().Find(gomock.Any(), int64(1)).Return(&script_entity.ScriptFraudReview{
+			ID: 1, ScriptID: 2, Status: script_entity.FraudReviewPending, Date: yesterday, Download: 5000,
+		}, nil)
+		// 扣除的是异常当日的下载量, 而不是回滚到标记时的总下载量快照
+		mockDate.EXPECT().FindByScriptID(gomock.Any(), int64(2), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, scriptId int64, date time.Time) (*entity.ScriptDateStatistics, error) {
+				assert.Equal(t, yesterday, date.Format("2006-01-02"))
+				return &entity.ScriptDateStatistics{ScriptID: 2, Date: yesterday, Download: 800}, nil
+			})
+		mockStatistics.EXPECT().FindByScriptID(gomock.Any(), int64(2)).
+			Return(&entity.ScriptStatistics{ScriptID: 2, Download: 5100}, nil)
+		mockStatistics.EXPECT().IncrDownload(gomock.Any(), int64(2), int64(-800)).Return(nil)
+		mockDate.EXPECT().IncrDownload(gomock.Any(), int64(2), gomock.Any(), int64(-800)).DoAndReturn(
+			func(ctx context.Context, scriptId int64, date time.Time, num int64) error {
+				assert.Equal(t, yesterday, date.Format("2006-01-02"))
+				return nil
+			})
+		mockReview.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, review *script_entity.ScriptFraudReview) error {
+				assert.Equal(t, script_entity.FraudReviewConfirmed, review.Status)
+				assert.Equal(t, int64(1), review.ReviewUID)
+				return nil
+			})
+		mockScript.EXPECT().Find(gomock.Any(), int64(2)).Return(nil, nil)
+		_, err := s.FraudReview(ctx, &api.FraudReviewRequest{ID: 1, Status: script_entity.FraudReviewConfirmed})
+		require.NoError(t, err)
+	})
+}
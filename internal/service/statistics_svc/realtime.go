@@ -0,0 +1,54 @@
+package statistics_svc
+
+import (
+	"strconv"
+
+	"github.com/cago-frame/cago/pkg/i18n"
+	"github.com/cago-frame/cago/pkg/logger"
+	"github.com/gin-gonic/gin"
+	api "github.com/scriptscat/scriptlist/internal/api/statistics"
+	"github.com/scriptscat/scriptlist/internal/pkg/code"
+	"github.com/scriptscat/scriptlist/internal/pkg/pubsub"
+	"github.com/scriptscat/scriptlist/internal/repository/statistics_repo"
+	"github.com/scriptscat/scriptlist/internal/service/auth_svc"
+	"go.uber.org/zap"
+)
+
+const (
+	// 每个用户在一个实例上最多同时打开的实时统计连接
+	realtimeStreamMaxConn = 5
+)
+
+var realtimeStreamLimiter = pubsub.NewLimiter(realtimeStreamMaxConn)
+
+// ScriptRealtimeStream 通过SSE推送脚本实时统计数据, 连接时先推送最近15分钟的数据, 之后推送每分钟的变化
+func (s *statisticsSvc) ScriptRealtimeStream(ctx *gin.Context, req *api.ScriptRealtimeStreamRequest) error {
+	key := strconv.FormatInt(auth_svc.Auth().Get(ctx).UID, 10)
+	if !realtimeStreamLimiter.Acquire(key) {
+		return i18n.NewError(ctx, code.StatisticsRealtimeStreamLimit)
+	}
+	defer realtimeStreamLimiter.Release(key)
+	events, err := statistics_repo.Realtime().Subscribe(ctx.Request.Context(), req.ID)
+	if err != nil {
+		return err
+	}
+	sse := pubsub.NewSSE(ctx)
+	if err := s.realtimeStream(ctx, sse, req.ID, events); err != nil {
+		logger.Ctx(ctx).Error("推送实时统计失败", zap.Error(err), zap.Int64("script_id", req.ID))
+	}
+	return nil
+}
+
+func (s *statisticsSvc) realtimeStream(ctx *gin.Context, sse *pubsub.SSE, scriptId int64,
+	events <-chan *statistics_repo.RealtimeEvent) error {
+	if err := sse.Event(0, "realtime", &api.ScriptRealtimeResponse{
+		Download: s.realtime(ctx, scriptId, statistics_repo.DownloadScriptStatistics),
+		Update:   s.realtime(ctx, scriptId, statistics_repo.UpdateScriptStatistics),
+	}); err != nil {
+		return err
+	}
+	sse.Flush()
+	return pubsub.Serve(sse, events, func(event *statistics_repo.RealtimeEvent) error {
+		return sse.Event(0, "minute", event)
+	})
+}
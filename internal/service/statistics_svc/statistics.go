@@ -11,6 +11,7 @@ import (
 	"github.com/gin-gonic/gin"
 	api "github.com/scriptscat/scriptlist/internal/api/statistics"
 	"github.com/scriptscat/scriptlist/internal/model"
+	"github.com/scriptscat/scriptlist/internal/model/entity/statistics_entity"
 	"github.com/scriptscat/scriptlist/internal/repository/script_repo"
 	"github.com/scriptscat/scriptlist/internal/repository/statistics_repo"
 	"github.com/scriptscat/scriptlist/internal/task/producer"
@@ -23,12 +24,54 @@ type StatisticsSvc interface {
 	ScriptRecord(ctx context.Context, data *producer.ScriptStatisticsMsg) error
 	// GetStatisticsToken 获取统计token
 	GetStatisticsToken(ctx *gin.Context) string
+	// HasStatisticsToken 请求是否携带了统计token
+	HasStatisticsToken(ctx *gin.Context) bool
+	// ClientVersion 更新检查时客户端当前安装的脚本版本
+	ClientVersion(ctx *gin.Context) string
 	// Script 脚本统计数据
 	Script(ctx context.Context, req *api.ScriptRequest) (*api.ScriptResponse, error)
 	// ScriptRealtime 脚本实时统计数据
 	ScriptRealtime(ctx context.Context, req *api.ScriptRealtimeRequest) (*api.ScriptRealtimeResponse, error)
+	// ScriptRealtimeStream 通过SSE推送脚本实时统计数据
+	ScriptRealtimeStream(ctx *gin.Context, req *api.ScriptRealtimeStreamRequest) error
+	// ScriptExport 导出脚本每日统计数据
+	ScriptExport(ctx *gin.Context, req *api.ScriptExportRequest) error
+	// ScriptVersion 脚本各版本的每日活跃安装数
+	ScriptVersion(ctx context.Context, req *api.ScriptVersionRequest) (*api.ScriptVersionResponse, error)
+	// ErrorReport 脚本运行错误上报
+	ErrorReport(ctx *gin.Context, req *api.ErrorReportRequest) (*api.ErrorReportResponse, error)
+	// ErrorGroupList 脚本运行错误列表
+	ErrorGroupList(ctx context.Context, req *api.ErrorGroupListRequest) (*api.ErrorGroupListResponse, error)
+	// ErrorGroup 脚本运行错误详情
+	ErrorGroup(ctx context.Context, req *api.ErrorGroupRequest) (*api.ErrorGroupResponse, error)
+	// ErrorGroupIssue 将错误转为脚本反馈
+	ErrorGroupIssue(ctx context.Context, req *api.ErrorGroupIssueRequest) (*api.ErrorGroupIssueResponse, error)
+	// Filtered 被识别为机器人而没有计入统计的请求
+	Filtered(ctx context.Context, req *api.FilteredRequest) (*api.FilteredResponse, error)
+	// FraudDetect 检测计入统计的下载是否存在刷量
+	FraudDetect(ctx context.Context, msg *producer.ScriptStatisticsMsg) error
+	// FraudDaily 根据每日下载量计算日均下载量, 并检测某一天是否存在刷量
+	FraudDaily(ctx context.Context, date time.Time) error
+	// FraudReviewList 疑似刷量的脚本审核队列
+	FraudReviewList(ctx context.Context, req *api.FraudReviewListRequest) (*api.FraudReviewListResponse, error)
+	// FraudReview 审核疑似刷量的脚本
+	FraudReview(ctx context.Context, req *api.FraudReviewRequest) (*api.FraudReviewResponse, error)
+	// UserStatistics 用户拥有或者管理的所有脚本的统计数据
+	UserStatistics(ctx context.Context, req *api.UserStatisticsRequest) (*api.UserStatisticsResponse, error)
 	// Middleware 中间件
 	Middleware() gin.HandlerFunc
+	// SaveDaily 将某一天的统计数据从redis落库
+	SaveDaily(ctx context.Context, date time.Time) error
+	// Collect 统计数据上报
+	Collect(ctx *gin.Context, req *api.CollectRequest) (*api.CollectResponse, error)
+	// CollectHost 脚本运行域名统计
+	CollectHost(ctx context.Context, req *api.CollectHostRequest) (*api.CollectHostResponse, error)
+	// CollectPage 脚本运行页面统计
+	CollectPage(ctx context.Context, req *api.CollectPageRequest) (*api.CollectPageResponse, error)
+	// CollectDuration 停留时长趋势
+	CollectDuration(ctx context.Context, req *api.CollectDurationRequest) (*api.CollectDurationResponse, error)
+	// CollectVisitor 访客趋势
+	CollectVisitor(ctx context.Context, req *api.CollectVisitorRequest) (*api.CollectVisitorResponse, error)
 }
 
 type statisticsSvc struct {
@@ -53,6 +96,11 @@ func (s *statisticsSvc) GetStatisticsToken(ctx *gin.Context) string {
 	return stk
 }
 
+func (s *statisticsSvc) HasStatisticsToken(ctx *gin.Context) bool {
+	stk, _ := ctx.Cookie("_statistics")
+	return stk != ""
+}
+
 func (s *statisticsSvc) Middleware() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		sid := ctx.Param("id")
@@ -76,6 +124,29 @@ func (s *statisticsSvc) Middleware() gin.HandlerFunc {
 
 // Script 脚本统计数据
 func (s *statisticsSvc) Script(ctx context.Context, req *api.ScriptRequest) (*api.ScriptResponse, error) {
+	start, end, err := s.dateRange(ctx, req.Start, req.End, req.Bucket)
+	if err != nil {
+		return nil, err
+	}
+	download, err := s.dailyData(ctx, req.ID, statistics_repo.DownloadScriptStatistics, start, end)
+	if err != nil {
+		return nil, err
+	}
+	update, err := s.dailyData(ctx, req.ID, statistics_repo.UpdateScriptStatistics, start, end)
+	if err != nil {
+		return nil, err
+	}
+	visitor, err := s.visitorPie(ctx, req.ID, start, end, map[statistics_repo.ScriptStatisticsType][]*statistics_entity.StatisticsDaily{
+		statistics_repo.DownloadScriptStatistics: download,
+		statistics_repo.UpdateScriptStatistics:   update,
+	})
+	if err != nil {
+		return nil, err
+	}
+	source, err := s.installSource(ctx, req.ID, start, end, download)
+	if err != nil {
+		return nil, err
+	}
 	return &api.ScriptResponse{
 		PagePv: &api.Overview{
 			Today:     DaysPvNumIgnoreError(ctx, req.ID, statistics_repo.ViewScriptStatistics, 1, time.Now()),
@@ -98,13 +169,15 @@ func (s *statisticsSvc) Script(ctx context.Context, req *api.ScriptRequest) (*ap
 			Week:      DaysUvNumIgnoreError(ctx, req.ID, statistics_repo.UpdateScriptStatistics, 7, time.Now()),
 		},
 		UvChart: &api.DUChart{
-			Download: s.daysData(ctx, req.ID, 30, time.Now(), statistics_repo.DownloadScriptStatistics, "uv"),
-			Update:   s.daysData(ctx, req.ID, 30, time.Now(), statistics_repo.UpdateScriptStatistics, "uv"),
+			Download: bucketChart(download, req.Bucket, dailyUv),
+			Update:   bucketChart(update, req.Bucket, dailyUv),
 		},
 		PvChart: &api.DUChart{
-			Download: s.daysData(ctx, req.ID, 30, time.Now(), statistics_repo.DownloadScriptStatistics, "pv"),
-			Update:   s.daysData(ctx, req.ID, 30, time.Now(), statistics_repo.UpdateScriptStatistics, "pv"),
+			Download: bucketChart(download, req.Bucket, dailyPv),
+			Update:   bucketChart(update, req.Bucket, dailyPv),
 		},
+		Visitor: visitor,
+		Source:  source,
 	}, nil
 }
 
@@ -126,28 +199,21 @@ func DaysUvNumIgnoreError(ctx context.Context, scriptId int64, op statistics_rep
 	return resp
 }
 
-func (s *statisticsSvc) daysData(ctx context.Context, scriptId, days int64, date time.Time,
-	op statistics_repo.ScriptStatisticsType, data string) *api.Chart {
-	t := date.Add(-time.Hour * 24 * time.Duration(days))
-	var x []string
-	var y []int64
-	for i := int64(0); i < days; i++ {
-		t = t.Add(time.Hour * 24)
-		day := t.Format("2006/01/02")
-		var num int64
-		switch data {
-		case "uv":
-			num, _ = statistics_repo.ScriptStatistics().DaysUvNum(ctx, scriptId, op, 1, t)
-		case "pv":
-			num, _ = statistics_repo.ScriptStatistics().DaysPvNum(ctx, scriptId, op, 1, t)
-		}
-		x = append(x, day)
-		y = append(y, num)
+// DayBatchIgnoreError 通过pipeline读取多个脚本某一天的数据, 读取失败的脚本记录日志并按0计算
+func DayBatchIgnoreError(ctx context.Context, scriptIds []int64, op statistics_repo.ScriptStatisticsType, t time.Time) []*statistics_repo.DayStatistics {
+	list, err := statistics_repo.ScriptStatistics().DayBatch(ctx, scriptIds,
+		[]statistics_repo.ScriptStatisticsType{op}, t)
+	if err != nil {
+		logger.Ctx(ctx).Error("DayBatchIgnoreError", zap.Error(err), zap.Int64s("scriptIds", scriptIds), zap.Time("t", t))
+		return nil
 	}
-	return &api.Chart{
-		X: x,
-		Y: y,
+	for _, v := range list {
+		if v.Err != nil {
+			logger.Ctx(ctx).Error("DayBatchIgnoreError", zap.Error(v.Err), zap.Int64("scriptId", v.ScriptID), zap.Time("t", t))
+			v.Pv, v.Uv = 0, 0
+		}
 	}
+	return list
 }
 
 // ScriptRealtime 脚本实时统计数据
@@ -0,0 +1,262 @@
+package statistics_svc
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/cago-frame/cago/pkg/consts"
+	api "github.com/scriptscat/scriptlist/internal/api/statistics"
+	"github.com/scriptscat/scriptlist/internal/model/entity/script_entity"
+	"github.com/scriptscat/scriptlist/internal/model/entity/statistics_entity"
+	"github.com/scriptscat/scriptlist/internal/repository/issue_repo"
+	"github.com/scriptscat/scriptlist/internal/repository/report_repo"
+	"github.com/scriptscat/scriptlist/internal/repository/script_repo"
+	"github.com/scriptscat/scriptlist/internal/repository/statistics_repo"
+	"github.com/scriptscat/scriptlist/internal/service/auth_svc"
+)
+
+const (
+	// 下载量增长排行的数量
+	userTopScripts = 10
+	// 每日任务会落库前两天的数据, 更近的日期没有落库时从redis读取
+	userRedisDays = 2
+)
+
+// UserStatistics 用户拥有或者通过访问权限管理的所有脚本的统计数据
+func (s *statisticsSvc) UserStatistics(ctx context.Context, req *api.UserStatisticsRequest) (*api.UserStatisticsResponse, error) {
+	start, end, err := s.dateRange(ctx, req.Start, req.End, req.Bucket)
+	if err != nil {
+		return nil, err
+	}
+	days := 0
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		days++
+	}
+	prevStart, prevEnd := start.AddDate(0, 0, -days), start.AddDate(0, 0, -1)
+
+	scripts, err := s.userScripts(ctx, auth_svc.Auth().Get(ctx).UID)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int64, 0, len(scripts))
+	summary := make(map[int64]*api.ScriptSummary, len(scripts))
+	for _, v := range scripts {
+		ids = append(ids, v.ID)
+		summary[v.ID] = &api.ScriptSummary{ScriptID: v.ID, Name: v.Name}
+	}
+	resp := &api.UserStatisticsResponse{
+		ScriptNum: len(scripts),
+		PvChart:   &api.UserChart{},
+	}
+
+	for _, op := range dailyOps {
+		list, current, err := s.userDaily(ctx, ids, op, start, end)
+		if err != nil {
+			return nil, err
+		}
+		_, previous, err := s.userDaily(ctx, ids, op, prevStart, prevEnd)
+		if err != nil {
+			return nil, err
+		}
+		compare := &api.Compare{}
+		for id, v := range current {
+			compare.Current += v
+			if op == statistics_repo.DownloadScriptStatistics {
+				summary[id].Download = v
+			}
+		}
+		for id, v := range previous {
+			compare.Previous += v
+			if op == statistics_repo.DownloadScriptStatistics {
+				summary[id].Previous = v
+			}
+		}
+		chart := bucketChart(list, req.Bucket, dailyPv)
+		switch op {
+		case statistics_repo.ViewScriptStatistics:
+			resp.View, resp.PvChart.View = compare, chart
+		case statistics_repo.DownloadScriptStatistics:
+			resp.Download, resp.PvChart.Download = compare, chart
+		case statistics_repo.UpdateScriptStatistics:
+			resp.Update, resp.PvChart.Update = compare, chart
+		}
+	}
+
+	// 反馈与举报按创建时间统计, 结束日期包含当天
+	startTime, endTime := start.Unix(), end.AddDate(0, 0, 1).Unix()
+	prevStartTime := prevStart.Unix()
+	if resp.Issue, err = s.userCompare(ctx, ids, startTime, endTime, prevStartTime,
+		issue_repo.Issue().CountNewByScripts, func(id, num int64) {
+			summary[id].Issue = num
+		}); err != nil {
+		return nil, err
+	}
+	if resp.Report, err = s.userCompare(ctx, ids, startTime, endTime, prevStartTime,
+		report_repo.Report().CountNewByScripts, func(id, num int64) {
+			summary[id].Report = num
+		}); err != nil {
+		return nil, err
+	}
+
+	resp.Rating = &api.RatingCompare{}
+	scores, err := script_repo.ScriptScore().StatByScripts(ctx, ids, startTime, endTime)
+	if err != nil {
+		return nil, err
+	}
+	sum := int64(0)
+	for _, v := range scores {
+		resp.Rating.Count += v.Count
+		sum += v.Score
+		summary[v.ScriptID].RatingCount = v.Count
+		summary[v.ScriptID].RatingAverage = float64(v.Score) / float64(v.Count)
+	}
+	if resp.Rating.Count > 0 {
+		resp.Rating.Average = float64(sum) / float64(resp.Rating.Count)
+	}
+	scores, err = script_repo.ScriptScore().StatByScripts(ctx, ids, prevStartTime, startTime)
+	if err != nil {
+		return nil, err
+	}
+	sum = 0
+	for _, v := range scores {
+		resp.Rating.PreviousCount += v.Count
+		sum += v.Score
+	}
+	if resp.Rating.PreviousCount > 0 {
+		resp.Rating.PreviousAverage = float64(sum) / float64(resp.Rating.PreviousCount)
+	}
+
+	resp.Top = make([]*api.ScriptSummary, 0, len(summary))
+	for _, v := range summary {
+		v.Growth = v.Download - v.Previous
+		resp.Top = append(resp.Top, v)
+	}
+	sort.Slice(resp.Top, func(i, j int) bool {
+		if resp.Top[i].Growth != resp.Top[j].Growth {
+			return resp.Top[i].Growth > resp.Top[j].Growth
+		}
+		if resp.Top[i].Download != resp.Top[j].Download {
+			return resp.Top[i].Download > resp.Top[j].Download
+		}
+		return resp.Top[i].ScriptID < resp.Top[j].ScriptID
+	})
+	if len(resp.Top) > userTopScripts {
+		resp.Top = resp.Top[:userTopScripts]
+	}
+	return resp, nil
+}
+
+// userScripts 用户拥有的脚本, 以及通过用户或用户组的访问权限可以管理统计的脚本
+func (s *statisticsSvc) userScripts(ctx context.Context, uid int64) ([]*script_entity.Script, error) {
+	ret, err := script_repo.Script().FindByUser(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	exist := make(map[int64]struct{}, len(ret))
+	for _, v := range ret {
+		exist[v.ID] = struct{}{}
+	}
+	access, err := script_repo.ScriptAccess().FindAllByLinkID(ctx, []int64{uid}, script_entity.AccessTypeUser)
+	if err != nil {
+		return nil, err
+	}
+	members, err := script_repo.ScriptGroupMember().FindAllByUserId(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	groupIds := make([]int64, 0, len(members))
+	for _, v := range members {
+		if v.IsValid(ctx) {
+			groupIds = append(groupIds, v.GroupID)
+		}
+	}
+	groupAccess, err := script_repo.ScriptAccess().FindAllByLinkID(ctx, groupIds, script_entity.AccessTypeGroup)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range append(access, groupAccess...) {
+		// 与脚本的访问权限一致, manager与owner拥有统计的管理权限
+		if !v.IsValid(ctx) || (v.Role != script_entity.AccessRoleManager && v.Role != script_entity.AccessRoleOwner) {
+			continue
+		}
+		if _, ok := exist[v.ScriptID]; ok {
+			continue
+		}
+		exist[v.ScriptID] = struct{}{}
+		script, err := script_repo.Script().Find(ctx, v.ScriptID)
+		if err != nil {
+			return nil, err
+		}
+		if script == nil || script.Status != consts.ACTIVE {
+			continue
+		}
+		ret = append(ret, script)
+	}
+	return ret, nil
+}
+
+// userDaily 汇总多个脚本每天的数据, 返回每天的数据与每个脚本的pv总和
+func (s *statisticsSvc) userDaily(ctx context.Context, ids []int64, op statistics_repo.ScriptStatisticsType,
+	start, end time.Time) ([]*statistics_entity.StatisticsDaily, map[int64]int64, error) {
+	startStr, endStr := start.Format("2006-01-02"), end.Format("2006-01-02")
+	list, err := statistics_repo.StatisticsDaily().SumByDate(ctx, ids, op, startStr, endStr)
+	if err != nil {
+		return nil, nil, err
+	}
+	m := make(map[string]*statistics_entity.StatisticsDaily, len(list))
+	for _, v := range list {
+		m[v.Date] = v
+	}
+	scripts, err := statistics_repo.StatisticsDaily().SumByScript(ctx, ids, op, startStr, endStr)
+	if err != nil {
+		return nil, nil, err
+	}
+	total := make(map[int64]int64, len(ids))
+	for _, v := range scripts {
+		total[v.ScriptID] = v.Pv
+	}
+	now := time.Now()
+	redisStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()).AddDate(0, 0, -userRedisDays)
+	ret := make([]*statistics_entity.StatisticsDaily, 0)
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		date := day.Format("2006-01-02")
+		v, ok := m[date]
+		if !ok {
+			v = &statistics_entity.StatisticsDaily{Op: string(op), Date: date}
+			if !day.Before(redisStart) {
+				// 通过pipeline一次读取所有脚本当天的数据
+				for _, d := range DayBatchIgnoreError(ctx, ids, op, day) {
+					v.Pv += d.Pv
+					v.Uv += d.Uv
+					total[d.ScriptID] += d.Pv
+				}
+			}
+		}
+		ret = append(ret, v)
+	}
+	return ret, total, nil
+}
+
+// userCompare 统计时间范围与上一个时间范围内新增的数量
+func (s *statisticsSvc) userCompare(ctx context.Context, ids []int64, start, end, prevStart int64,
+	count func(ctx context.Context, scriptIds []int64, start, end int64) (map[int64]int64, error),
+	each func(id, num int64)) (*api.Compare, error) {
+	ret := &api.Compare{}
+	current, err := count(ctx, ids, start, end)
+	if err != nil {
+		return nil, err
+	}
+	for id, num := range current {
+		ret.Current += num
+		each(id, num)
+	}
+	previous, err := count(ctx, ids, prevStart, start)
+	if err != nil {
+		return nil, err
+	}
+	for _, num := range previous {
+		ret.Previous += num
+	}
+	return ret, nil
+}
@@ -0,0 +1,141 @@
+package statistics_svc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cago-frame/cago/pkg/consts"
+	"github.com/scriptscat/scriptlist/internal/model/entity/script_entity"
+	"github.com/scriptscat/scriptlist/internal/model/entity/statistics_entity"
+	"github.com/scriptscat/scriptlist/internal/repository/script_repo"
+	mock_script_repo "github.com/scriptscat/scriptlist/internal/repository/script_repo/mock"
+	"github.com/scriptscat/scriptlist/internal/repository/statistics_repo"
+	mock_statistics_repo "github.com/scriptscat/scriptlist/internal/repository/statistics_repo/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestStatisticsSvc_userScripts(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockScript := mock_script_repo.NewMockScriptRepo(mockCtrl)
+	script_repo.RegisterScript(mockScript)
+	mockAccess := mock_script_repo.NewMockScriptAccessRepo(mockCtrl)
+	script_repo.RegisterScriptAccess(mockAccess)
+	mockMember := mock_script_repo.NewMockScriptGroupMemberRepo(mockCtrl)
+	script_repo.RegisterScriptGroupMember(mockMember)
+
+	ctx := context.Background()
+	s := &statisticsSvc{}
+	access := func(scriptId int64, role script_entity.AccessRole) *script_entity.ScriptAccess {
+		return &script_entity.ScriptAccess{
+			ScriptID:     scriptId,
+			Role:         role,
+			InviteStatus: script_entity.AccessInviteStatusAccept,
+			Status:       consts.ACTIVE,
+		}
+	}
+
+	mockScript.EXPECT().FindByUser(gomock.Any(), int64(1)).Return([]*script_entity.Script{
+		{ID: 1, UserID: 1, Status: consts.ACTIVE},
+	}, nil)
+	mockAccess.EXPECT().FindAllByLinkID(gomock.Any(), []int64{1}, script_entity.AccessTypeUser).
+		Return([]*script_entity.ScriptAccess{
+			// 自己的脚本不重复计算
+			access(1, script_entity.AccessRoleOwner),
+			access(2, script_entity.AccessRoleManager),
+			// 访客没有统计权限
+			access(3, script_entity.AccessRoleGuest),
+		}, nil)
+	mockMember.EXPECT().FindAllByUserId(gomock.Any(), int64(1)).Return([]*script_entity.ScriptGroupMember{
+		{GroupID: 10, InviteStatus: script_entity.AccessInviteStatusAccept, Status: consts.ACTIVE},
+		// 未接受邀请的用户组不计算
+		{GroupID: 11, InviteStatus: script_entity.AccessInviteStatusPending, Status: consts.ACTIVE},
+	}, nil)
+	mockAccess.EXPECT().FindAllByLinkID(gomock.Any(), []int64{10}, script_entity.AccessTypeGroup).
+		Return([]*script_entity.ScriptAccess{
+			access(2, script_entity.AccessRoleOwner),
+			access(4, script_entity.AccessRoleManager),
+			access(5, script_entity.AccessRoleManager),
+		}, nil)
+	mockScript.EXPECT().Find(gomock.Any(), int64(2)).Return(&script_entity.Script{ID: 2, Status: consts.ACTIVE}, nil)
+	mockScript.EXPECT().Find(gomock.Any(), int64(4)).Return(&script_entity.Script{ID: 4, Status: consts.ACTIVE}, nil)
+	// 已删除的脚本不计算
+	mockScript.EXPECT().Find(gomock.Any(), int64(5)).Return(&script_entity.Script{ID: 5, Status: consts.DELETE}, nil)
+
+	list, err := s.userScripts(ctx, 1)
+	require.NoError(t, err)
+	ids := make([]int64, 0, len(list))
+	for _, v := range list {
+		ids = append(ids, v.ID)
+	}
+	assert.Equal(t, []int64{1, 2, 4}, ids)
+}
+
+func TestStatisticsSvc_userDaily(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockRepo := mock_statistics_repo.NewMockStatisticsDailyRepo(mockCtrl)
+	statistics_repo.RegisterStatisticsDaily(mockRepo)
+
+	ctx := context.Background()
+	s := &statisticsSvc{}
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.Local)
+	end := time.Date(2023, 1, 3, 0, 0, 0, 0, time.Local)
+
+	mockRepo.EXPECT().SumByDate(gomock.Any(), []int64{1, 2}, statistics_repo.DownloadScriptStatistics,
+		"2023-01-01", "2023-01-03").Return([]*statistics_entity.StatisticsDaily{
+		{Op: "download", Date: "2023-01-02", Pv: 15, Uv: 8},
+	}, nil)
+	mockRepo.EXPECT().SumByScript(gomock.Any(), []int64{1, 2}, statistics_repo.DownloadScriptStatistics,
+		"2023-01-01", "2023-01-03").Return([]*statistics_entity.StatisticsDaily{
+		{ScriptID: 1, Op: "download", Pv: 10, Uv: 5},
+		{ScriptID: 2, Op: "download", Pv: 5, Uv: 3},
+	}, nil)
+	list, total, err := s.userDaily(ctx, []int64{1, 2}, statistics_repo.DownloadScriptStatistics, start, end)
+	require.NoError(t, err)
+	require.Len(t, list, 3)
+	assert.Equal(t, "2023-01-01", list[0].Date)
+	assert.Equal(t, int64(0), list[0].Pv)
+	assert.Equal(t, int64(15), list[1].Pv)
+	assert.Equal(t, map[int64]int64{1: 10, 2: 5}, total)
+}
+
+func TestStatisticsSvc_userDailyRedis(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockRepo := mock_statistics_repo.NewMockStatisticsDailyRepo(mockCtrl)
+	statistics_repo.RegisterStatisticsDaily(mockRepo)
+	mockStatistics := mock_statistics_repo.NewMockScriptStatisticsRepo(mockCtrl)
+	statistics_repo.RegisterScriptStatistics(mockStatistics)
+
+	ctx := context.Background()
+	s := &statisticsSvc{}
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	date := today.Format("2006-01-02")
+
+	mockRepo.EXPECT().SumByDate(gomock.Any(), []int64{1, 2}, statistics_repo.DownloadScriptStatistics,
+		date, date).Return(nil, nil)
+	mockRepo.EXPECT().SumByScript(gomock.Any(), []int64{1, 2}, statistics_repo.DownloadScriptStatistics,
+		date, date).Return(nil, nil)
+	// 未落库的数据一次读取所有脚本, 读取失败的脚本按0计算
+	mockStatistics.EXPECT().DayBatch(gomock.Any(), []int64{1, 2},
+		[]statistics_repo.ScriptStatisticsType{statistics_repo.DownloadScriptStatistics}, today).
+		Return([]*statistics_repo.DayStatistics{
+			{ScriptID: 1, Op: statistics_repo.DownloadScriptStatistics, Pv: 3, Uv: 2},
+			{ScriptID: 2, Op: statistics_repo.DownloadScriptStatistics, Pv: 1, Err: errors.New("redis error")},
+		}, nil)
+	list, total, err := s.userDaily(ctx, []int64{1, 2}, statistics_repo.DownloadScriptStatistics, today, today)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, int64(3), list[0].Pv)
+	assert.Equal(t, int64(2), list[0].Uv)
+	assert.Equal(t, map[int64]int64{1: 3, 2: 0}, total)
+}
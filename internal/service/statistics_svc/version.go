@@ -0,0 +1,129 @@
+package statistics_svc
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	api "github.com/scriptscat/scriptlist/internal/api/statistics"
+	"github.com/scriptscat/scriptlist/internal/model/entity/statistics_entity"
+	"github.com/scriptscat/scriptlist/internal/repository/statistics_repo"
+)
+
+// 版本趋势图最多显示的版本数, 其余的合并为other
+const versionTop = 10
+
+// clientVersionHeader 检查更新时携带当前安装版本的请求头, 目前的脚本管理器请求meta.js时不会携带安装的版本,
+// 这是新增的客户端约定, 脚本管理器可以在检查更新时通过该请求头上报当前安装的版本, 见README
+const clientVersionHeader = "X-Script-Version"
+
+// 版本号只允许常见的字符, 长度与ua维度值的字段一致
+var clientVersionRegex = regexp.MustCompile(`^[0-9A-Za-z][0-9A-Za-z.+_\-]{0,63}$`)
+
+// ClientVersion 更新检查时客户端当前安装的脚本版本, 只从X-Script-Version请求头读取,
+// meta.js的version参数用于选择返回哪个版本的元数据, 不作为上报的版本
+func (s *statisticsSvc) ClientVersion(ctx *gin.Context) string {
+	version := strings.TrimSpace(ctx.GetHeader(clientVersionHeader))
+	if !clientVersionRegex.MatchString(version) {
+		return ""
+	}
+	return version
+}
+
+// ScriptVersion 脚本各版本的每日活跃安装数
+func (s *statisticsSvc) ScriptVersion(ctx context.Context, req *api.ScriptVersionRequest) (*api.ScriptVersionResponse, error) {
+	start, end, err := s.dateRange(ctx, req.Start, req.End, "day")
+	if err != nil {
+		return nil, err
+	}
+	update, err := s.dailyData(ctx, req.ID, statistics_repo.UpdateScriptStatistics, start, end)
+	if err != nil {
+		return nil, err
+	}
+	list, err := statistics_repo.StatisticsDaily().FindUADimension(ctx, req.ID, statistics_repo.UpdateScriptStatistics,
+		statistics_entity.UADimensionVersion, start.Format("2006-01-02"), end.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	days := make(map[string]map[string]int64)
+	for _, v := range list {
+		if _, ok := days[v.Date]; !ok {
+			days[v.Date] = make(map[string]int64)
+		}
+		days[v.Date][v.Value] += v.Num
+	}
+	// 没有落库的近期数据从redis读取, 每天单独汇总
+	for _, v := range update {
+		if !recentDaily(v) {
+			continue
+		}
+		sum, err := dimensionSum(ctx, map[statistics_repo.ScriptStatisticsType][]*statistics_entity.StatisticsDaily{
+			statistics_repo.UpdateScriptStatistics: {v},
+		}, nil, func(ctx context.Context, op statistics_repo.ScriptStatisticsType, t time.Time) (map[string]int64, error) {
+			return statistics_repo.ScriptStatistics().DayUA(ctx, req.ID, op, t)
+		})
+		if err != nil {
+			return nil, err
+		}
+		days[v.Date] = sum[statistics_entity.UADimensionVersion]
+	}
+	return versionChart(update, days), nil
+}
+
+// versionChart 按时间范围内的总数选出最多的版本, 生成每天的趋势与最后一天的分布
+func versionChart(update []*statistics_entity.StatisticsDaily, days map[string]map[string]int64) *api.ScriptVersionResponse {
+	total := make(map[string]int64)
+	for _, m := range days {
+		for version, num := range m {
+			total[version] += num
+		}
+	}
+	versions := make([]string, 0, len(total))
+	for version := range total {
+		versions = append(versions, version)
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		if total[versions[i]] != total[versions[j]] {
+			return total[versions[i]] > total[versions[j]]
+		}
+		return versions[i] > versions[j]
+	})
+	other := len(versions) > versionTop
+	if other {
+		versions = versions[:versionTop]
+	}
+	resp := &api.ScriptVersionResponse{
+		X:      make([]string, 0, len(update)),
+		Series: make([]*api.VersionSeries, 0, len(versions)+1),
+		Latest: make([]*api.PieChart, 0),
+	}
+	index := make(map[string]int, len(versions))
+	for n, version := range versions {
+		index[version] = n
+		resp.Series = append(resp.Series, &api.VersionSeries{Version: version, Y: make([]int64, len(update))})
+	}
+	if other {
+		resp.Series = append(resp.Series, &api.VersionSeries{Version: "other", Y: make([]int64, len(update))})
+	}
+	for i, v := range update {
+		if t, err := time.Parse("2006-01-02", v.Date); err == nil {
+			resp.X = append(resp.X, bucketKey(t, "day"))
+		} else {
+			resp.X = append(resp.X, v.Date)
+		}
+		for version, num := range days[v.Date] {
+			if n, ok := index[version]; ok {
+				resp.Series[n].Y[i] += num
+			} else {
+				resp.Series[len(resp.Series)-1].Y[i] += num
+			}
+		}
+	}
+	if len(update) > 0 {
+		resp.Latest = pieChart(days[update[len(update)-1].Date], versionTop)
+	}
+	return resp
+}
@@ -0,0 +1,96 @@
+package statistics_svc
+
+import (
+	"context"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	api "github.com/scriptscat/scriptlist/internal/api/statistics"
+	"github.com/scriptscat/scriptlist/internal/model/entity/statistics_entity"
+	"github.com/scriptscat/scriptlist/internal/repository/statistics_repo"
+	mock_statistics_repo "github.com/scriptscat/scriptlist/internal/repository/statistics_repo/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestStatisticsSvc_ClientVersion(t *testing.T) {
+	s := &statisticsSvc{}
+	version := func(url string, header map[string]string) string {
+		ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+		ctx.Request = httptest.NewRequest("GET", url, nil)
+		for k, v := range header {
+			ctx.Request.Header.Set(k, v)
+		}
+		return s.ClientVersion(ctx)
+	}
+	// version参数用于选择元数据的版本, 不作为上报的版本
+	assert.Equal(t, "", version("/scripts/code/1/a.meta.js?version=1.2.0", nil))
+	assert.Equal(t, "1.1.0", version("/scripts/code/1/a.meta.js?version=1.2.0",
+		map[string]string{"X-Script-Version": "1.1.0"}))
+	assert.Equal(t, "1.1.0-beta", version("/scripts/code/1/a.meta.js",
+		map[string]string{"X-Script-Version": "1.1.0-beta"}))
+	assert.Equal(t, "", version("/scripts/code/1/a.meta.js", nil))
+	// 非法的版本号不记录
+	assert.Equal(t, "", version("/scripts/code/1/a.meta.js",
+		map[string]string{"X-Script-Version": "<script>"}))
+}
+
+func TestVersionChart(t *testing.T) {
+	update := []*statistics_entity.StatisticsDaily{{Date: "2024-01-01"}, {Date: "2024-01-02"}}
+	days := map[string]map[string]int64{
+		"2024-01-01": {"1.0.0": 10, "1.1.0": 2},
+		"2024-01-02": {"1.0.0": 6, "1.1.0": 8},
+	}
+	for i := 0; i < versionTop; i++ {
+		days["2024-01-02"]["0."+strconv.Itoa(i)] = 1
+	}
+	resp := versionChart(update, days)
+	assert.Equal(t, []string{"2024/01/01", "2024/01/02"}, resp.X)
+	assert.Len(t, resp.Series, versionTop+1)
+	assert.Equal(t, &api.VersionSeries{Version: "1.0.0", Y: []int64{10, 6}}, resp.Series[0])
+	assert.Equal(t, &api.VersionSeries{Version: "1.1.0", Y: []int64{2, 8}}, resp.Series[1])
+	// 超出数量的版本合并为other
+	assert.Equal(t, &api.VersionSeries{Version: "other", Y: []int64{0, 2}}, resp.Series[versionTop])
+	assert.Equal(t, &api.PieChart{Key: "1.1.0", Value: 8}, resp.Latest[0])
+}
+
+func TestStatisticsSvc_ScriptVersion(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockDaily := mock_statistics_repo.NewMockStatisticsDailyRepo(mockCtrl)
+	statistics_repo.RegisterStatisticsDaily(mockDaily)
+	mockStatistics := mock_statistics_repo.NewMockScriptStatisticsRepo(mockCtrl)
+	statistics_repo.RegisterScriptStatistics(mockStatistics)
+
+	ctx := context.Background()
+	s := &statisticsSvc{}
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	yesterday := today.AddDate(0, 0, -1)
+	start, end := yesterday.Format("2006-01-02"), today.Format("2006-01-02")
+
+	// 昨天已经落库, 今天从redis读取
+	mockDaily.EXPECT().FindRange(gomock.Any(), int64(1), statistics_repo.UpdateScriptStatistics, start, end).
+		Return([]*statistics_entity.StatisticsDaily{{ID: 1, Date: start, Pv: 3, Uv: 3}}, nil)
+	mockStatistics.EXPECT().DayBatch(gomock.Any(), []int64{1},
+		[]statistics_repo.ScriptStatisticsType{statistics_repo.UpdateScriptStatistics}, today).
+		Return([]*statistics_repo.DayStatistics{{ScriptID: 1, Op: statistics_repo.UpdateScriptStatistics, Pv: 5, Uv: 4}}, nil)
+	mockDaily.EXPECT().FindUADimension(gomock.Any(), int64(1), statistics_repo.UpdateScriptStatistics,
+		statistics_entity.UADimensionVersion, start, end).
+		Return([]*statistics_entity.StatisticsUaDaily{{Date: start, Value: "1.0.0", Num: 3}}, nil)
+	mockStatistics.EXPECT().DayUA(gomock.Any(), int64(1), statistics_repo.UpdateScriptStatistics, today).
+		Return(map[string]int64{"version:1.1.0": 4, "browser:chrome": 4}, nil)
+
+	resp, err := s.ScriptVersion(ctx, &api.ScriptVersionRequest{ID: 1, Start: start, End: end})
+	require.NoError(t, err)
+	assert.Len(t, resp.Series, 2)
+	assert.Equal(t, "1.1.0", resp.Series[0].Version)
+	assert.Equal(t, []int64{0, 4}, resp.Series[0].Y)
+	assert.Equal(t, "1.0.0", resp.Series[1].Version)
+	assert.Equal(t, []int64{3, 0}, resp.Series[1].Y)
+}
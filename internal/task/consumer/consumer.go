@@ -21,6 +21,10 @@ func Consumer(ctx context.Context, cfg *configs.Config) error {
 		&subscribe.Access{},
 		&subscribe.Report{},
 		&subscribe.AuditLog{},
+		&subscribe.SearchLog{},
+		&subscribe.StatisticsCollect{},
+		&subscribe.StatisticsError{},
+		&subscribe.NotificationWebhook{},
 	}
 	for _, v := range subscribers {
 		if err := v.Subscribe(ctx); err != nil {
@@ -2,6 +2,7 @@ package subscribe
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/cago-frame/cago/pkg/logger"
 	issue2 "github.com/scriptscat/scriptlist/internal/api/issue"
@@ -58,6 +59,19 @@ func (s *Issue) issueCreate(ctx context.Context, script *script_entity.Script, i
 		}
 		uids = append(uids, v.UserID)
 	}
+	// 通知@的用户
+	if err := notification_svc.Mention().Mention(ctx, &notification_svc.MentionOptions{
+		Target:     notification_entity.MentionTargetIssue,
+		TargetID:   issue.ID,
+		ScriptID:   script.ID,
+		ScriptName: script.Name,
+		Title:      issue.Title,
+		From:       issue.UserID,
+		Content:    issue.Content,
+		Path:       fmt.Sprintf("/script-show-page/%d/issue/%d", script.ID, issue.ID),
+	}); err != nil {
+		logger.Ctx(ctx).Error("发送反馈提及通知错误", zap.Int64("issue", issue.ID), zap.Error(err))
+	}
 	// 通知关注人
 	return notification_svc.Notification().MultipleSend(ctx, uids, notification_entity.IssueCreateTemplate,
 		notification_svc.WithParams(&template.IssueCreate{
@@ -92,6 +106,21 @@ func (s *Issue) commentCreate(ctx context.Context, script *script_entity.Script,
 			logger.Ctx(ctx).Error("发送反馈评论通知错误", zap.Int64("issue", issue.ID), zap.Error(err))
 		}
 	}
+	// 通知@的用户
+	if comment.Type == issue_entity.CommentTypeComment {
+		if err := notification_svc.Mention().Mention(ctx, &notification_svc.MentionOptions{
+			Target:     notification_entity.MentionTargetIssueComment,
+			TargetID:   comment.ID,
+			ScriptID:   script.ID,
+			ScriptName: script.Name,
+			Title:      issue.Title,
+			From:       comment.UserID,
+			Content:    comment.Content,
+			Path:       fmt.Sprintf("/script-show-page/%d/issue/%d#comment-%d", script.ID, issue.ID, comment.ID),
+		}); err != nil {
+			logger.Ctx(ctx).Error("发送反馈评论提及通知错误", zap.Int64("comment", comment.ID), zap.Error(err))
+		}
+	}
 	// 更新issue更新时间
 	issue, err = issue_repo.Issue().Find(ctx, script.ID, issue.ID)
 	if err != nil {
@@ -0,0 +1,45 @@
+package subscribe
+
+import (
+	"context"
+	"time"
+
+	"github.com/cago-frame/cago/pkg/logger"
+	"github.com/scriptscat/scriptlist/internal/model/entity/notification_entity"
+	"github.com/scriptscat/scriptlist/internal/repository/notification_repo"
+	"github.com/scriptscat/scriptlist/internal/service/notification_svc/sender"
+	"github.com/scriptscat/scriptlist/internal/task/producer"
+	"go.uber.org/zap"
+)
+
+// NotificationWebhook 投递通知webhook, 失败时按退避间隔重新入队
+type NotificationWebhook struct {
+}
+
+func (n *NotificationWebhook) Subscribe(ctx context.Context) error {
+	return producer.SubscribeNotificationWebhook(ctx, n.deliver)
+}
+
+func (n *NotificationWebhook) deliver(ctx context.Context, msg *producer.NotificationWebhookMsg, attempt int) (time.Duration, error) {
+	hook, err := notification_repo.Webhook().Find(ctx, msg.WebhookID)
+	if err != nil {
+		return 0, err
+	}
+	// 已删除或停用后不再投递
+	if hook == nil || !hook.IsActive() {
+		return 0, nil
+	}
+	log := sender.DeliverWebhook(ctx, hook, msg, attempt)
+	if err := notification_repo.WebhookLog().Create(ctx, log); err != nil {
+		logger.Ctx(ctx).Error("记录webhook投递失败", zap.Error(err), zap.Int64("webhook_id", hook.ID))
+	}
+	if log.Status == notification_entity.WebhookDeliverySuccess {
+		return 0, nil
+	}
+	delay := sender.WebhookRetryDelay(attempt)
+	if delay == 0 {
+		logger.Ctx(ctx).Warn("webhook投递失败, 已达最大重试次数", zap.Int64("webhook_id", hook.ID),
+			zap.String("delivery", msg.Delivery), zap.Int("attempt", attempt))
+	}
+	return delay, nil
+}
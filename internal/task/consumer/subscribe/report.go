@@ -2,6 +2,7 @@ package subscribe
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/cago-frame/cago/pkg/logger"
 	"github.com/scriptscat/scriptlist/internal/model/entity/notification_entity"
@@ -90,5 +91,20 @@ func (s *Report) reportCommentCreate(ctx context.Context, script *script_entity.
 		logger.Ctx(ctx).Error("发送举报评论通知错误", zap.Int64("report", report.ID), zap.Error(err))
 	}
 
+	// 通知@的用户
+	if comment.Type == report_entity.CommentTypeComment {
+		if err := notification_svc.Mention().Mention(ctx, &notification_svc.MentionOptions{
+			Target:     notification_entity.MentionTargetReportComment,
+			TargetID:   comment.ID,
+			ScriptID:   script.ID,
+			ScriptName: script.Name,
+			From:       comment.UserID,
+			Content:    comment.Content,
+			Path:       fmt.Sprintf("/script-show-page/%d/report/%d", script.ID, report.ID),
+		}); err != nil {
+			logger.Ctx(ctx).Error("发送举报评论提及通知错误", zap.Int64("comment", comment.ID), zap.Error(err))
+		}
+	}
+
 	return nil
 }
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"regexp"
+	"strconv"
 	"time"
 
 	"github.com/cago-frame/cago/pkg/consts"
@@ -61,6 +62,11 @@ func (s *Script) scriptCreate(ctx context.Context, msg *producer.ScriptCreateMsg
 			logger.Error("saveDomain", zap.Error(err))
 			return err
 		}
+		// 处理完整的匹配规则
+		if err := s.saveMatch(ctx, script.ID, code.ID, metaJson); err != nil {
+			logger.Error("saveMatch", zap.Error(err))
+			return err
+		}
 	}
 
 	// 关注自己脚本
@@ -95,6 +101,11 @@ func (s *Script) scriptCodeUpdate(ctx context.Context, msg *producer.ScriptCodeU
 			logger.Error("saveDomain", zap.Error(err))
 			return err
 		}
+		// 处理完整的匹配规则
+		if err := s.saveMatch(ctx, script.ID, code.ID, metaJson); err != nil {
+			logger.Error("saveMatch", zap.Error(err))
+			return err
+		}
 	}
 	logger.Info("update script code")
 
@@ -197,6 +208,46 @@ func (s *Script) saveDomain(ctx context.Context, id, codeID int64, meta map[stri
 	return nil
 }
 
+// 保存脚本完整的匹配规则
+func (s *Script) saveMatch(ctx context.Context, id, codeID int64, meta map[string][]string) error {
+	list, err := script_repo.ScriptMatch().List(ctx, id)
+	if err != nil {
+		return err
+	}
+	matchMap := make(map[string]*script_entity.ScriptMatch)
+	for _, v := range list {
+		matchMap[strconv.Itoa(int(v.Type))+":"+v.Pattern] = v
+	}
+	for _, v := range script_entity.ParseScriptMatch(meta) {
+		key := strconv.Itoa(int(v.Type)) + ":" + v.Pattern
+		result, ok := matchMap[key]
+		if !ok {
+			v.ScriptID = id
+			v.ScriptCodeID = codeID
+			v.Status = consts.ACTIVE
+			v.Createtime = time.Now().Unix()
+			if err := script_repo.ScriptMatch().Create(ctx, v); err != nil {
+				logger.Ctx(ctx).Error("Create", zap.Error(err), zap.Int64("script_id", id), zap.String("pattern", v.Pattern))
+			}
+		} else if result.Status != consts.ACTIVE || result.ScriptCodeID != codeID {
+			result.Status = consts.ACTIVE
+			result.ScriptCodeID = codeID
+			if err := script_repo.ScriptMatch().Update(ctx, result); err != nil {
+				logger.Ctx(ctx).Error("Update", zap.Error(err), zap.Int64("script_id", id), zap.String("pattern", v.Pattern))
+			}
+		}
+		delete(matchMap, key)
+	}
+	for _, v := range matchMap {
+		if v.Status == consts.ACTIVE {
+			if err := script_repo.ScriptMatch().Delete(ctx, v.ID); err != nil {
+				logger.Ctx(ctx).Error("Delete", zap.Error(err), zap.Int64("script_id", id), zap.String("pattern", v.Pattern))
+			}
+		}
+	}
+	return nil
+}
+
 // 解析meta中的域名信息
 // 返回格式为: 顶级域名, 原始域名
 func (s *Script) parseMatchDomain(meta string) (string, string) {
@@ -0,0 +1,56 @@
+package subscribe
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/cago-frame/cago/pkg/broker/broker"
+	"github.com/cago-frame/cago/pkg/logger"
+	"github.com/scriptscat/scriptlist/internal/model/entity/script_entity"
+	"github.com/scriptscat/scriptlist/internal/repository/script_repo"
+	"github.com/scriptscat/scriptlist/internal/task/producer"
+	"go.uber.org/zap"
+)
+
+// SearchLog 搜索日志消费者
+type SearchLog struct{}
+
+func (s *SearchLog) Subscribe(ctx context.Context) error {
+	if err := producer.SubscribeScriptSearch(ctx, s.search); err != nil {
+		return err
+	}
+	// 点击可能比搜索日志先到达, 失败时重新投递
+	if err := producer.SubscribeScriptSearchClick(ctx, s.click, broker.Retry()); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *SearchLog) search(ctx context.Context, msg *producer.ScriptSearchMsg) error {
+	filters, err := json.Marshal(msg.Filters)
+	if err != nil {
+		return err
+	}
+	if err := script_repo.ScriptSearchLog().Create(ctx, &script_entity.ScriptSearchLog{
+		SearchID:   msg.SearchID,
+		Query:      script_entity.NormalizeSearchQuery(msg.Query),
+		Filters:    string(filters),
+		Sort:       msg.Sort,
+		Total:      msg.Total,
+		UserID:     msg.UserID,
+		Createtime: msg.Time.Unix(),
+	}); err != nil {
+		logger.Ctx(ctx).Error("保存搜索日志失败", zap.String("search_id", msg.SearchID), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (s *SearchLog) click(ctx context.Context, msg *producer.ScriptSearchClickMsg) error {
+	if err := script_repo.ScriptSearchLog().Click(ctx, msg.SearchID, msg.ScriptID, msg.Time.Unix()); err != nil {
+		logger.Ctx(ctx).Error("保存搜索点击失败", zap.String("search_id", msg.SearchID),
+			zap.Int64("script_id", msg.ScriptID), zap.Error(err))
+		return err
+	}
+	return nil
+}
@@ -3,12 +3,19 @@ package subscribe
 import (
 	"context"
 	"fmt"
+	"net/url"
+	"strconv"
 	"time"
 
 	"github.com/cago-frame/cago/database/redis"
+	"github.com/cago-frame/cago/pkg/consts"
 	"github.com/cago-frame/cago/pkg/logger"
+	"github.com/scriptscat/scriptlist/configs"
+	"github.com/scriptscat/scriptlist/internal/model/entity/statistics_entity"
+	"github.com/scriptscat/scriptlist/internal/pkg/botdetect"
 	"github.com/scriptscat/scriptlist/internal/repository/script_repo"
 	"github.com/scriptscat/scriptlist/internal/repository/statistics_repo"
+	"github.com/scriptscat/scriptlist/internal/service/statistics_svc"
 	"github.com/scriptscat/scriptlist/internal/task/producer"
 	"go.uber.org/zap"
 )
@@ -64,13 +71,105 @@ func SyncIncr(ctx context.Context, key, field string, update func(ctx context.Co
 	return nil
 }
 
+// recordUA 记录访客的浏览器, 系统, 设备和脚本管理器分布, 更新检查还会记录安装的版本
+func (s *Statistics) recordUA(ctx context.Context, msg *producer.ScriptStatisticsMsg) {
+	dimensions := statistics_entity.ParseUserAgent(msg.UA).Dimensions()
+	// 更新检查同时记录客户端安装的版本
+	if msg.Download == statistics_repo.UpdateScriptStatistics {
+		dimensions[statistics_entity.UADimensionVersion] = msg.Version
+		if msg.Version == "" {
+			dimensions[statistics_entity.UADimensionVersion] = "unknown"
+		}
+	}
+	if err := statistics_repo.ScriptStatistics().IncrUA(ctx, msg.ScriptID, msg.Download, msg.Time, dimensions); err != nil {
+		logger.Ctx(ctx).Error("统计ua分布失败", zap.Error(err))
+	}
+}
+
+// recordSource 记录安装来源
+func (s *Statistics) recordSource(ctx context.Context, msg *producer.ScriptStatisticsMsg) {
+	siteHost := ""
+	if u, err := url.Parse(configs.Url()); err == nil {
+		siteHost = u.Hostname()
+	}
+	attribution := statistics_entity.ParseAttribution(msg.Source, s.folderID(ctx, msg), msg.Referer, siteHost)
+	if err := statistics_repo.ScriptStatistics().IncrSource(ctx, msg.ScriptID, msg.Download, msg.Time,
+		attribution.Dimensions()); err != nil {
+		logger.Ctx(ctx).Error("统计安装来源失败", zap.Error(err))
+	}
+}
+
+// folderID 校验链接中的收藏夹参数, 收藏夹不存在或者不包含该脚本时返回0
+func (s *Statistics) folderID(ctx context.Context, msg *producer.ScriptStatisticsMsg) int64 {
+	if msg.Source != string(statistics_entity.InstallSourceFolder) || msg.FolderID <= 0 {
+		return 0
+	}
+	folder, err := script_repo.ScriptFavoriteFolder().Find(ctx, msg.FolderID)
+	if err != nil {
+		logger.Ctx(ctx).Error("查询收藏夹失败", zap.Error(err), zap.Int64("folder_id", msg.FolderID))
+		return 0
+	}
+	if folder == nil {
+		return 0
+	}
+	favorite, err := script_repo.ScriptFavorite().FindByFavoriteAndScriptID(ctx, folder.UserID, folder.ID, msg.ScriptID)
+	if err != nil {
+		logger.Ctx(ctx).Error("查询收藏记录失败", zap.Error(err), zap.Int64("folder_id", msg.FolderID))
+		return 0
+	}
+	if favorite == nil || favorite.Status != consts.ACTIVE {
+		return 0
+	}
+	return folder.ID
+}
+
+// publishRealtime 推送脚本当前分钟的下载与更新数量
+func (s *Statistics) publishRealtime(ctx context.Context, msg *producer.ScriptStatisticsMsg) {
+	if err := statistics_repo.Realtime().Publish(ctx, msg.ScriptID, msg.Download); err != nil {
+		logger.Ctx(ctx).Error("推送实时统计失败", zap.Error(err))
+	}
+}
+
+// 同一ip一小时内不带cookie的下载和访问超过该数量时, 后续的请求认为是机器人
+const noCookieHourLimit = 300
+
+// botReason 判断请求是否来自机器人, 更新检查由脚本管理器发起, 通常不带cookie, 不参与频率判断
+func (s *Statistics) botReason(ctx context.Context, msg *producer.ScriptStatisticsMsg) botdetect.Reason {
+	if reason := botdetect.Default().Classify(msg.UA, msg.IP); reason != botdetect.ReasonNone {
+		return reason
+	}
+	if !msg.NoCookie || msg.Download == statistics_repo.UpdateScriptStatistics {
+		return botdetect.ReasonNone
+	}
+	num, err := statistics_repo.Bot().IncrNoCookie(ctx, msg.IP, msg.Time)
+	if err != nil {
+		logger.Ctx(ctx).Error("记录无cookie请求失败", zap.Error(err))
+		return botdetect.ReasonNone
+	}
+	if num > noCookieHourLimit {
+		return botdetect.ReasonFrequency
+	}
+	return botdetect.ReasonNone
+}
+
 func (s *Statistics) scriptStatistics(ctx context.Context, msg *producer.ScriptStatisticsMsg) error {
+	// 机器人的请求只记录原始数量, 不计入统计
+	if reason := s.botReason(ctx, msg); reason != botdetect.ReasonNone {
+		if err := statistics_repo.Bot().IncrFiltered(ctx, msg.ScriptID, msg.Download, string(reason), msg.Time); err != nil {
+			logger.Ctx(ctx).Error("记录机器人请求失败", zap.Error(err))
+			return err
+		}
+		return nil
+	}
 	switch msg.Download {
 	case statistics_repo.DownloadScriptStatistics:
-		if ok, err := statistics_repo.ScriptStatistics().IncrDownload(ctx, msg.ScriptID, msg.IP, msg.StatisticsToken); err != nil {
+		ok, err := statistics_repo.ScriptStatistics().IncrDownload(ctx, msg.ScriptID, msg.IP, msg.StatisticsToken)
+		if err != nil {
 			logger.Ctx(ctx).Error("统计下载量失败", zap.Error(err))
 			return err
-		} else if ok {
+		}
+		s.publishRealtime(ctx, msg)
+		if ok {
 			// 统计总量
 			if err := SyncIncr(ctx, s.statisticSyncKey(msg.ScriptID, "download"), "total",
 				func(ctx context.Context, num int64) error {
@@ -85,12 +184,33 @@ func (s *Statistics) scriptStatistics(ctx context.Context, msg *producer.ScriptS
 				}); err != nil {
 				logger.Ctx(ctx).Error("统计总更新量失败", zap.Error(err))
 			}
+			s.recordUA(ctx, msg)
+			s.recordSource(ctx, msg)
+			if err := statistics_svc.Statistics().FraudDetect(ctx, msg); err != nil {
+				logger.Ctx(ctx).Error("刷量检测失败", zap.Error(err))
+			}
+			// 记录共同安装
+			actors := make([]string, 0, 2)
+			if msg.StatisticsToken != "" {
+				actors = append(actors, "token:"+msg.StatisticsToken)
+			}
+			if msg.UserID != 0 {
+				actors = append(actors, "user:"+strconv.FormatInt(msg.UserID, 10))
+			}
+			for _, actor := range actors {
+				if err := statistics_repo.CoInstall().Record(ctx, actor, msg.ScriptID); err != nil {
+					logger.Ctx(ctx).Error("记录共同安装失败", zap.Error(err))
+				}
+			}
 		}
 	case statistics_repo.UpdateScriptStatistics:
-		if ok, err := statistics_repo.ScriptStatistics().IncrUpdate(ctx, msg.ScriptID, msg.IP, msg.StatisticsToken); err != nil {
+		ok, err := statistics_repo.ScriptStatistics().IncrUpdate(ctx, msg.ScriptID, msg.IP, msg.StatisticsToken)
+		if err != nil {
 			logger.Ctx(ctx).Error("统计更新量失败", zap.Error(err))
 			return err
-		} else if ok {
+		}
+		s.publishRealtime(ctx, msg)
+		if ok {
 			// 统计总量
 			if err := SyncIncr(ctx, s.statisticSyncKey(msg.ScriptID, "update"), "total",
 				func(ctx context.Context, num int64) error {
@@ -105,6 +225,7 @@ func (s *Statistics) scriptStatistics(ctx context.Context, msg *producer.ScriptS
 				}); err != nil {
 				logger.Ctx(ctx).Error("统计当日更新量失败", zap.Error(err))
 			}
+			s.recordUA(ctx, msg)
 		}
 	case statistics_repo.ViewScriptStatistics:
 		if _, err := statistics_repo.ScriptStatistics().IncrPageView(ctx, msg.ScriptID, msg.IP, msg.StatisticsToken); err != nil {
@@ -0,0 +1,155 @@
+package subscribe
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"github.com/cago-frame/cago/pkg/broker/broker"
+	"github.com/cago-frame/cago/pkg/logger"
+	"github.com/scriptscat/scriptlist/internal/model/entity/statistics_entity"
+	"github.com/scriptscat/scriptlist/internal/pkg/clickhouse"
+	"github.com/scriptscat/scriptlist/internal/repository/statistics_repo"
+	"github.com/scriptscat/scriptlist/internal/task/producer"
+	"go.uber.org/zap"
+)
+
+const (
+	// 缓冲区积累到该数量时批量写入clickhouse
+	collectBatchSize = 500
+	// 缓冲区没有积满时定时写入的间隔
+	collectFlushInterval = time.Second * 10
+)
+
+// StatisticsCollect 统计平台收集的数据, 写入clickhouse
+// 消息先放入redis缓冲区, 积满一批或者定时批量写入, 写入失败时放回缓冲区等待下次写入
+type StatisticsCollect struct {
+}
+
+func (s *StatisticsCollect) Subscribe(ctx context.Context) error {
+	// 没有配置clickhouse不处理
+	if clickhouse.Default() == nil {
+		return nil
+	}
+	go func() {
+		ticker := time.NewTicker(collectFlushInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := s.flush(ctx); err != nil {
+					logger.Ctx(ctx).Error("定时写入统计数据失败", zap.Error(err))
+				}
+			}
+		}
+	}()
+	return producer.SubscribeStatisticsCollect(ctx, s.collect, broker.Retry())
+}
+
+func (s *StatisticsCollect) collect(ctx context.Context, msg *producer.StatisticsCollectMsg) error {
+	visitor := &statistics_entity.StatisticsVisitor{
+		ScriptID:    msg.ScriptID,
+		VisitorID:   msg.VisitorID,
+		UA:          msg.UA,
+		IP:          msg.IP,
+		Version:     msg.Version,
+		InstallPage: msg.InstallPage,
+		VisitTime:   msg.VisitTime,
+	}
+	if u, err := url.Parse(msg.InstallPage); err == nil {
+		visitor.InstallHost = u.Hostname()
+	}
+	visitor.ParseUA()
+	num, err := statistics_repo.StatisticsCollect().PushBuffer(ctx, []*statistics_repo.CollectBuffer{{
+		Collect: &statistics_entity.StatisticsCollect{
+			SessionID:     msg.SessionID,
+			ScriptID:      msg.ScriptID,
+			VisitorID:     msg.VisitorID,
+			OperationHost: msg.OperationHost,
+			OperationPage: msg.OperationPage,
+			Duration:      msg.Duration,
+			VisitTime:     msg.VisitTime,
+			ExitTime:      msg.ExitTime,
+		},
+		Visitor: visitor,
+	}})
+	if err != nil {
+		logger.Ctx(ctx).Error("写入统计缓冲区失败", zap.Error(err), zap.Int64("script_id", msg.ScriptID))
+		return err
+	}
+	if num >= collectBatchSize {
+		// 缓冲区中的数据不会丢失, 写入失败时不需要重新投递消息
+		if err := s.flush(ctx); err != nil {
+			logger.Ctx(ctx).Error("批量写入统计数据失败", zap.Error(err))
+		}
+	}
+	return nil
+}
+
+// flush 将缓冲区中的数据批量写入clickhouse, 直到缓冲区为空
+func (s *StatisticsCollect) flush(ctx context.Context) error {
+	for {
+		list, err := statistics_repo.StatisticsCollect().PopBuffer(ctx, collectBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(list) == 0 {
+			return nil
+		}
+		if err := s.write(ctx, list); err != nil {
+			if _, pushErr := statistics_repo.StatisticsCollect().PushBuffer(ctx, list); pushErr != nil {
+				logger.Ctx(ctx).Error("统计数据放回缓冲区失败", zap.Error(pushErr), zap.Int("num", len(list)))
+			}
+			return err
+		}
+		if len(list) < collectBatchSize {
+			return nil
+		}
+	}
+}
+
+// write 批量写入一批数据, 两张表都写入成功后才记录访客, 失败重试时仍然能识别出新访客
+func (s *StatisticsCollect) write(ctx context.Context, list []*statistics_repo.CollectBuffer) error {
+	collects := make([]*statistics_entity.StatisticsCollect, 0, len(list))
+	visitors := make([]*statistics_entity.StatisticsVisitor, 0, len(list))
+	for _, v := range list {
+		collects = append(collects, v.Collect)
+		// 放回缓冲区的数据可能已经判断过新访客, 重新判断
+		v.Visitor.FirstVisitTime = 0
+		visitors = append(visitors, v.Visitor)
+	}
+	isNew, err := statistics_repo.StatisticsCollect().NewVisitors(ctx, visitors)
+	if err != nil {
+		logger.Ctx(ctx).Error("判断新访客失败", zap.Error(err))
+		isNew = make([]bool, len(visitors))
+	}
+	// 同一批中同一访客只有第一次访问算作新访客
+	type visitorKey struct {
+		scriptId  int64
+		visitorId string
+	}
+	seen := make(map[visitorKey]struct{})
+	for i, v := range visitors {
+		key := visitorKey{scriptId: v.ScriptID, visitorId: v.VisitorID}
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		if isNew[i] {
+			v.FirstVisitTime = v.VisitTime
+		}
+	}
+	if err := statistics_repo.StatisticsCollect().BatchInsertCollect(ctx, collects); err != nil {
+		return err
+	}
+	// 会话数据使用session_id去重, 重新写入时不会重复计算
+	if err := statistics_repo.StatisticsCollect().BatchInsertVisitor(ctx, visitors); err != nil {
+		return err
+	}
+	if err := statistics_repo.StatisticsCollect().MarkVisitors(ctx, visitors); err != nil {
+		logger.Ctx(ctx).Error("记录访客失败", zap.Error(err))
+	}
+	return nil
+}
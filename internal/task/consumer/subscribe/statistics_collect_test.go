@@ -0,0 +1,117 @@
+package subscribe
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/scriptscat/scriptlist/internal/model/entity/statistics_entity"
+	"github.com/scriptscat/scriptlist/internal/repository/statistics_repo"
+	mock_statistics_repo "github.com/scriptscat/scriptlist/internal/repository/statistics_repo/mock"
+	"github.com/scriptscat/scriptlist/internal/task/producer"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestStatisticsCollect_collect(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockRepo := mock_statistics_repo.NewMockStatisticsCollectRepo(mockCtrl)
+	statistics_repo.RegisterStatisticsCollect(mockRepo)
+
+	ctx := context.Background()
+	s := &StatisticsCollect{}
+	msg := &producer.StatisticsCollectMsg{
+		SessionID:     "session",
+		ScriptID:      1,
+		VisitorID:     "visitor",
+		OperationHost: "example.com",
+		OperationPage: "https://example.com/a",
+		InstallPage:   "https://scriptcat.org/script-show-page/1",
+		Duration:      10,
+		UA:            "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+		VisitTime:     1700000000,
+		ExitTime:      1700000010,
+	}
+
+	t.Run("放入缓冲区", func(t *testing.T) {
+		mockRepo.EXPECT().PushBuffer(gomock.Any(), gomock.Len(1)).DoAndReturn(
+			func(ctx context.Context, list []*statistics_repo.CollectBuffer) (int64, error) {
+				assert.Equal(t, "session", list[0].Collect.SessionID)
+				v := list[0].Visitor
+				assert.Equal(t, "scriptcat.org", v.InstallHost)
+				assert.Equal(t, int64(statistics_entity.DeviceTypePC), v.DeviceType)
+				assert.Equal(t, string(statistics_entity.BrowserTypeChrome), v.BrowserType)
+				return 1, nil
+			})
+		assert.NoError(t, s.collect(ctx, msg))
+	})
+
+	t.Run("缓冲区积满后批量写入", func(t *testing.T) {
+		mockRepo.EXPECT().PushBuffer(gomock.Any(), gomock.Len(1)).Return(int64(collectBatchSize), nil)
+		mockRepo.EXPECT().PopBuffer(gomock.Any(), collectBatchSize).Return(nil, nil)
+		assert.NoError(t, s.collect(ctx, msg))
+	})
+
+	t.Run("写入缓冲区失败时返回错误重新投递", func(t *testing.T) {
+		mockRepo.EXPECT().PushBuffer(gomock.Any(), gomock.Len(1)).Return(int64(0), errors.New("down"))
+		assert.Error(t, s.collect(ctx, msg))
+	})
+}
+
+func TestStatisticsCollect_flush(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockRepo := mock_statistics_repo.NewMockStatisticsCollectRepo(mockCtrl)
+	statistics_repo.RegisterStatisticsCollect(mockRepo)
+
+	ctx := context.Background()
+	s := &StatisticsCollect{}
+	buffer := func() []*statistics_repo.CollectBuffer {
+		return []*statistics_repo.CollectBuffer{
+			{
+				Collect: &statistics_entity.StatisticsCollect{SessionID: "s1", ScriptID: 1, VisitorID: "v1"},
+				Visitor: &statistics_entity.StatisticsVisitor{ScriptID: 1, VisitorID: "v1", VisitTime: 100},
+			},
+			{
+				Collect: &statistics_entity.StatisticsCollect{SessionID: "s2", ScriptID: 1, VisitorID: "v1"},
+				Visitor: &statistics_entity.StatisticsVisitor{ScriptID: 1, VisitorID: "v1", VisitTime: 200},
+			},
+			{
+				Collect: &statistics_entity.StatisticsCollect{SessionID: "s3", ScriptID: 1, VisitorID: "v2"},
+				Visitor: &statistics_entity.StatisticsVisitor{ScriptID: 1, VisitorID: "v2", VisitTime: 300},
+			},
+		}
+	}
+
+	t.Run("写入成功后记录访客", func(t *testing.T) {
+		gomock.InOrder(
+			mockRepo.EXPECT().PopBuffer(gomock.Any(), collectBatchSize).Return(buffer(), nil),
+			mockRepo.EXPECT().NewVisitors(gomock.Any(), gomock.Len(3)).Return([]bool{true, true, false}, nil),
+			mockRepo.EXPECT().BatchInsertCollect(gomock.Any(), gomock.Len(3)).Return(nil),
+			mockRepo.EXPECT().BatchInsertVisitor(gomock.Any(), gomock.Len(3)).DoAndReturn(
+				func(ctx context.Context, list []*statistics_entity.StatisticsVisitor) error {
+					// 同一批中同一访客只有第一次算作新访客
+					assert.Equal(t, int64(100), list[0].FirstVisitTime)
+					assert.Equal(t, int64(0), list[1].FirstVisitTime)
+					assert.Equal(t, int64(0), list[2].FirstVisitTime)
+					return nil
+				}),
+			mockRepo.EXPECT().MarkVisitors(gomock.Any(), gomock.Len(3)).Return(nil),
+		)
+		assert.NoError(t, s.flush(ctx))
+	})
+
+	t.Run("写入失败时放回缓冲区且不记录访客", func(t *testing.T) {
+		gomock.InOrder(
+			mockRepo.EXPECT().PopBuffer(gomock.Any(), collectBatchSize).Return(buffer(), nil),
+			mockRepo.EXPECT().NewVisitors(gomock.Any(), gomock.Len(3)).Return([]bool{true, true, true}, nil),
+			mockRepo.EXPECT().BatchInsertCollect(gomock.Any(), gomock.Len(3)).Return(nil),
+			mockRepo.EXPECT().BatchInsertVisitor(gomock.Any(), gomock.Len(3)).Return(errors.New("down")),
+			mockRepo.EXPECT().PushBuffer(gomock.Any(), gomock.Len(3)).Return(int64(3), nil),
+		)
+		assert.Error(t, s.flush(ctx))
+	})
+}
@@ -0,0 +1,80 @@
+package subscribe
+
+import (
+	"context"
+	"time"
+
+	"github.com/cago-frame/cago/pkg/logger"
+	"github.com/scriptscat/scriptlist/internal/model/entity/statistics_entity"
+	"github.com/scriptscat/scriptlist/internal/repository/statistics_repo"
+	"github.com/scriptscat/scriptlist/internal/task/producer"
+	"go.uber.org/zap"
+)
+
+// errorGroupLimit 每个脚本最多记录的错误组数量, 超过后新的错误不再记录
+const errorGroupLimit = 1000
+
+// StatisticsError 脚本运行错误, 按指纹聚合并记录各版本的数量
+type StatisticsError struct {
+}
+
+func (s *StatisticsError) Subscribe(ctx context.Context) error {
+	return producer.SubscribeStatisticsError(ctx, s.record)
+}
+
+func (s *StatisticsError) record(ctx context.Context, msg *producer.StatisticsErrorMsg) error {
+	version := msg.Version
+	if version == "" {
+		version = "unknown"
+	}
+	fingerprint := statistics_entity.ErrorFingerprint(msg.Message, msg.Stack)
+	group, err := statistics_repo.ScriptError().FindGroupByFingerprint(ctx, msg.ScriptID, fingerprint)
+	if err != nil {
+		return err
+	}
+	if group == nil {
+		// 错误信息由客户端上报, 限制错误组的数量避免被刷爆
+		num, err := statistics_repo.ScriptError().CountGroup(ctx, msg.ScriptID)
+		if err != nil {
+			return err
+		}
+		if num >= errorGroupLimit {
+			logger.Ctx(ctx).Warn("脚本错误组数量超过限制", zap.Int64("script_id", msg.ScriptID), zap.Int64("num", num))
+			return nil
+		}
+		group = &statistics_entity.ScriptErrorGroup{
+			ScriptID:    msg.ScriptID,
+			Fingerprint: fingerprint,
+			Message:     msg.Message,
+			Stack:       msg.Stack,
+			Host:        msg.Host,
+			Version:     version,
+			FirstTime:   msg.Time,
+			LastTime:    msg.Time,
+			Createtime:  time.Now().Unix(),
+		}
+		if err := statistics_repo.ScriptError().CreateGroup(ctx, group); err != nil {
+			logger.Ctx(ctx).Error("创建错误组失败", zap.Error(err), zap.Int64("script_id", msg.ScriptID))
+			return err
+		}
+		// 同时有其它消息创建了错误组
+		if group.ID == 0 {
+			group, err = statistics_repo.ScriptError().FindGroupByFingerprint(ctx, msg.ScriptID, fingerprint)
+			if err != nil {
+				return err
+			}
+			if group == nil {
+				return nil
+			}
+		}
+	}
+	if err := statistics_repo.ScriptError().IncrGroup(ctx, group.ID, msg.Weight, msg.Host, version, msg.Time); err != nil {
+		logger.Ctx(ctx).Error("记录错误数量失败", zap.Error(err), zap.Int64("group_id", group.ID))
+		return err
+	}
+	if err := statistics_repo.ScriptError().IncrVersion(ctx, group.ID, version, msg.Weight, msg.Time); err != nil {
+		logger.Ctx(ctx).Error("记录错误版本失败", zap.Error(err), zap.Int64("group_id", group.ID))
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package subscribe
+
+import (
+	"context"
+	"testing"
+
+	"github.com/scriptscat/scriptlist/internal/model/entity/statistics_entity"
+	"github.com/scriptscat/scriptlist/internal/repository/statistics_repo"
+	mock_statistics_repo "github.com/scriptscat/scriptlist/internal/repository/statistics_repo/mock"
+	"github.com/scriptscat/scriptlist/internal/task/producer"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestStatisticsError_record(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockRepo := mock_statistics_repo.NewMockScriptErrorRepo(mockCtrl)
+	statistics_repo.RegisterScriptError(mockRepo)
+
+	ctx := context.Background()
+	s := &StatisticsError{}
+	msg := &producer.StatisticsErrorMsg{
+		ScriptID: 1,
+		Message:  "a is null",
+		Stack:    "at main (userscript.html:1:1)",
+		Host:     "example.com",
+		Weight:   10,
+		Time:     100,
+	}
+	fingerprint := statistics_entity.ErrorFingerprint(msg.Message, msg.Stack)
+
+	t.Run("新的错误", func(t *testing.T) {
+		mockRepo.EXPECT().FindGroupByFingerprint(gomock.Any(), int64(1), fingerprint).Return(nil, nil)
+		mockRepo.EXPECT().CountGroup(gomock.Any(), int64(1)).Return(int64(0), nil)
+		mockRepo.EXPECT().CreateGroup(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, group *statistics_entity.ScriptErrorGroup) error {
+				assert.Equal(t, "unknown", group.Version)
+				group.ID = 2
+				return nil
+			})
+		mockRepo.EXPECT().IncrGroup(gomock.Any(), int64(2), int64(10), "example.com", "unknown", int64(100)).Return(nil)
+		mockRepo.EXPECT().IncrVersion(gomock.Any(), int64(2), "unknown", int64(10), int64(100)).Return(nil)
+		assert.NoError(t, s.record(ctx, msg))
+	})
+
+	t.Run("已存在的错误", func(t *testing.T) {
+		msg.Version = "1.0.0"
+		mockRepo.EXPECT().FindGroupByFingerprint(gomock.Any(), int64(1), fingerprint).
+			Return(&statistics_entity.ScriptErrorGroup{ID: 2}, nil)
+		mockRepo.EXPECT().IncrGroup(gomock.Any(), int64(2), int64(10), "example.com", "1.0.0", int64(100)).Return(nil)
+		mockRepo.EXPECT().IncrVersion(gomock.Any(), int64(2), "1.0.0", int64(10), int64(100)).Return(nil)
+		assert.NoError(t, s.record(ctx, msg))
+	})
+
+	t.Run("错误组数量超过限制", func(t *testing.T) {
+		mockRepo.EXPECT().FindGroupByFingerprint(gomock.Any(), int64(1), fingerprint).Return(nil, nil)
+		mockRepo.EXPECT().CountGroup(gomock.Any(), int64(1)).Return(int64(errorGroupLimit), nil)
+		assert.NoError(t, s.record(ctx, msg))
+	})
+}
@@ -0,0 +1,115 @@
+package subscribe
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cago-frame/cago/pkg/consts"
+	"github.com/scriptscat/scriptlist/internal/model/entity/script_entity"
+	"github.com/scriptscat/scriptlist/internal/pkg/botdetect"
+	"github.com/scriptscat/scriptlist/internal/repository/script_repo"
+	mock_script_repo "github.com/scriptscat/scriptlist/internal/repository/script_repo/mock"
+	"github.com/scriptscat/scriptlist/internal/repository/statistics_repo"
+	mock_statistics_repo "github.com/scriptscat/scriptlist/internal/repository/statistics_repo/mock"
+	"github.com/scriptscat/scriptlist/internal/task/producer"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestStatistics_botReason(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockBot := mock_statistics_repo.NewMockBotRepo(mockCtrl)
+	statistics_repo.RegisterBot(mockBot)
+
+	ctx := context.Background()
+	s := &Statistics{}
+	chrome := "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+	msg := func(op statistics_repo.ScriptStatisticsType, ua string, noCookie bool) *producer.ScriptStatisticsMsg {
+		return &producer.ScriptStatisticsMsg{
+			ScriptID: 1,
+			IP:       "1.1.1.1",
+			UA:       ua,
+			Download: op,
+			Time:     time.Now(),
+			NoCookie: noCookie,
+		}
+	}
+
+	t.Run("爬虫ua", func(t *testing.T) {
+		assert.Equal(t, botdetect.ReasonUA, s.botReason(ctx, msg(statistics_repo.DownloadScriptStatistics, "Googlebot/2.1", true)))
+	})
+
+	t.Run("带cookie的请求不判断频率", func(t *testing.T) {
+		assert.Equal(t, botdetect.ReasonNone, s.botReason(ctx, msg(statistics_repo.DownloadScriptStatistics, chrome, false)))
+	})
+
+	t.Run("更新检查不判断频率", func(t *testing.T) {
+		assert.Equal(t, botdetect.ReasonNone, s.botReason(ctx, msg(statistics_repo.UpdateScriptStatistics, chrome, true)))
+	})
+
+	t.Run("无cookie高频请求", func(t *testing.T) {
+		mockBot.EXPECT().IncrNoCookie(gomock.Any(), "1.1.1.1", gomock.Any()).Return(int64(noCookieHourLimit), nil)
+		assert.Equal(t, botdetect.ReasonNone, s.botReason(ctx, msg(statistics_repo.DownloadScriptStatistics, chrome, true)))
+		mockBot.EXPECT().IncrNoCookie(gomock.Any(), "1.1.1.1", gomock.Any()).Return(int64(noCookieHourLimit+1), nil)
+		assert.Equal(t, botdetect.ReasonFrequency, s.botReason(ctx, msg(statistics_repo.DownloadScriptStatistics, chrome, true)))
+	})
+
+	t.Run("机器人请求不计入统计", func(t *testing.T) {
+		mockStatistics := mock_statistics_repo.NewMockScriptStatisticsRepo(mockCtrl)
+		statistics_repo.RegisterScriptStatistics(mockStatistics)
+		mockBot.EXPECT().IncrFiltered(gomock.Any(), int64(1), statistics_repo.DownloadScriptStatistics, "ua", gomock.Any()).Return(nil)
+		assert.NoError(t, s.scriptStatistics(ctx, msg(statistics_repo.DownloadScriptStatistics, "curl/8.4.0", false)))
+	})
+
+	t.Run("下载后推送实时统计", func(t *testing.T) {
+		mockStatistics := mock_statistics_repo.NewMockScriptStatisticsRepo(mockCtrl)
+		statistics_repo.RegisterScriptStatistics(mockStatistics)
+		mockRealtime := mock_statistics_repo.NewMockRealtimeRepo(mockCtrl)
+		statistics_repo.RegisterRealtime(mockRealtime)
+		// 重复的下载不计入总量, 但是计入实时统计
+		mockStatistics.EXPECT().IncrDownload(gomock.Any(), int64(1), "1.1.1.1", "").Return(false, nil)
+		mockRealtime.EXPECT().Publish(gomock.Any(), int64(1), statistics_repo.DownloadScriptStatistics).Return(nil)
+		assert.NoError(t, s.scriptStatistics(ctx, msg(statistics_repo.DownloadScriptStatistics, chrome, false)))
+	})
+}
+
+func TestStatistics_folderID(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockFolder := mock_script_repo.NewMockScriptFavoriteFolderRepo(mockCtrl)
+	script_repo.RegisterScriptFavoriteFolder(mockFolder)
+	mockFavorite := mock_script_repo.NewMockScriptFavoriteRepo(mockCtrl)
+	script_repo.RegisterScriptFavorite(mockFavorite)
+
+	ctx := context.Background()
+	s := &Statistics{}
+	msg := func(folderId int64) *producer.ScriptStatisticsMsg {
+		return &producer.ScriptStatisticsMsg{ScriptID: 1, Source: "folder", FolderID: folderId}
+	}
+
+	t.Run("收藏夹包含脚本", func(t *testing.T) {
+		mockFolder.EXPECT().Find(gomock.Any(), int64(10)).Return(&script_entity.ScriptFavoriteFolder{ID: 10, UserID: 2}, nil)
+		mockFavorite.EXPECT().FindByFavoriteAndScriptID(gomock.Any(), int64(2), int64(10), int64(1)).
+			Return(&script_entity.ScriptFavorite{Status: consts.ACTIVE}, nil)
+		assert.Equal(t, int64(10), s.folderID(ctx, msg(10)))
+	})
+
+	t.Run("收藏夹不存在", func(t *testing.T) {
+		mockFolder.EXPECT().Find(gomock.Any(), int64(11)).Return(nil, nil)
+		assert.Equal(t, int64(0), s.folderID(ctx, msg(11)))
+	})
+
+	t.Run("收藏夹不包含脚本", func(t *testing.T) {
+		mockFolder.EXPECT().Find(gomock.Any(), int64(12)).Return(&script_entity.ScriptFavoriteFolder{ID: 12, UserID: 2}, nil)
+		mockFavorite.EXPECT().FindByFavoriteAndScriptID(gomock.Any(), int64(2), int64(12), int64(1)).Return(nil, nil)
+		assert.Equal(t, int64(0), s.folderID(ctx, msg(12)))
+	})
+
+	t.Run("非收藏夹来源", func(t *testing.T) {
+		assert.Equal(t, int64(0), s.folderID(ctx, &producer.ScriptStatisticsMsg{ScriptID: 1, FolderID: 10}))
+	})
+}
@@ -18,7 +18,7 @@ func Crontab(ctx context.Context, cfg *configs.Config) error {
 	if configs.Default().Env == configs.PRE {
 		return nil
 	}
-	crontab := []Cron{&handler.Statistics{}, &handler.Script{}, &handler.Invite{}}
+	crontab := []Cron{&handler.Statistics{}, &handler.Script{}, &handler.Invite{}, &handler.Notification{}}
 	for _, v := range crontab {
 		if err := v.Crontab(cron.Default()); err != nil {
 			return err
@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"context"
+	"time"
+
+	"github.com/cago-frame/cago/database/redis"
+	"github.com/cago-frame/cago/pkg/logger"
+	"github.com/cago-frame/cago/server/cron"
+	"github.com/scriptscat/scriptlist/internal/service/notification_svc"
+	"go.uber.org/zap"
+)
+
+type Notification struct {
+}
+
+func (n *Notification) Crontab(c cron.Crontab) error {
+	_, err := c.AddFunc("0 * * * *", n.sendDigest)
+	if err != nil {
+		return err
+	}
+	_, err = c.AddFunc("30 4 * * *", n.cleanWebhookLog)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// 每小时发送汇总邮件
+func (n *Notification) sendDigest(ctx context.Context) error {
+	if ok, err := redis.Ctx(ctx).SetNX("sendNotificationDigest", "1", 50*time.Minute).Result(); err != nil {
+		logger.Ctx(ctx).Error("发送汇总邮件失败", zap.Error(err))
+		return err
+	} else if !ok {
+		logger.Ctx(ctx).Info("其他机器发送汇总邮件中")
+		return nil
+	}
+	if err := notification_svc.Notification().SendDigest(ctx, time.Now()); err != nil {
+		logger.Ctx(ctx).Error("发送汇总邮件失败", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// 每天清理过期的webhook投递记录
+func (n *Notification) cleanWebhookLog(ctx context.Context) error {
+	if ok, err := redis.Ctx(ctx).SetNX("cleanWebhookLog", "1", time.Hour).Result(); err != nil {
+		logger.Ctx(ctx).Error("清理webhook投递记录失败", zap.Error(err))
+		return err
+	} else if !ok {
+		logger.Ctx(ctx).Info("其他机器清理webhook投递记录中")
+		return nil
+	}
+	if err := notification_svc.Webhook().CleanWebhookLog(ctx, time.Now()); err != nil {
+		logger.Ctx(ctx).Error("清理webhook投递记录失败", zap.Error(err))
+		return err
+	}
+	return nil
+}
@@ -22,6 +22,92 @@ func (s *Script) Crontab(c cron.Crontab) error {
 	if err != nil {
 		return err
 	}
+	_, err = c.AddFunc("* * * * *", s.resumeMigrate)
+	if err != nil {
+		return err
+	}
+	_, err = c.AddFunc("30 */2 * * *", s.reconcileSearch)
+	if err != nil {
+		return err
+	}
+	_, err = c.AddFunc("10 * * * *", s.updateTrending)
+	if err != nil {
+		return err
+	}
+	_, err = c.AddFunc("40 4 * * *", s.cleanSearchLog)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// 每小时重新计算脚本热度
+func (s *Script) updateTrending(ctx context.Context) error {
+	if ok, err := redis.Ctx(ctx).SetNX("updateTrending", "1", time.Minute*30).Result(); err != nil {
+		logger.Ctx(ctx).Error("更新脚本热度失败", zap.Error(err))
+		return err
+	} else if !ok {
+		logger.Ctx(ctx).Info("其他机器更新脚本热度中")
+		return nil
+	}
+	if err := script_svc.Script().UpdateTrending(ctx); err != nil {
+		logger.Ctx(ctx).Error("更新脚本热度失败", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// 每天清理过期的搜索日志
+func (s *Script) cleanSearchLog(ctx context.Context) error {
+	if ok, err := redis.Ctx(ctx).SetNX("cleanSearchLog", "1", time.Hour).Result(); err != nil {
+		logger.Ctx(ctx).Error("清理搜索日志失败", zap.Error(err))
+		return err
+	} else if !ok {
+		logger.Ctx(ctx).Info("其他机器清理搜索日志中")
+		return nil
+	}
+	if err := script_svc.Script().CleanSearchLog(ctx, time.Now()); err != nil {
+		logger.Ctx(ctx).Error("清理搜索日志失败", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// 对比mysql与搜索索引, 修复不一致的数据
+func (s *Script) reconcileSearch(ctx context.Context) error {
+	if ok, err := redis.Ctx(ctx).SetNX("reconcileSearch", "1", time.Minute*30).Result(); err != nil {
+		logger.Ctx(ctx).Error("搜索索引对账失败", zap.Error(err))
+		return err
+	} else if !ok {
+		logger.Ctx(ctx).Info("其他机器搜索索引对账中")
+		return nil
+	}
+	// 重建索引时数据本就不一致, 跳过本次对账
+	if locked, err := script_repo.Migrate().IsLocked(ctx); err != nil {
+		logger.Ctx(ctx).Error("搜索索引对账失败", zap.Error(err))
+		return err
+	} else if locked {
+		logger.Ctx(ctx).Info("正在重建索引, 跳过搜索索引对账")
+		return nil
+	}
+	result, err := script_svc.Script().ReconcileSearch(ctx)
+	if err != nil {
+		logger.Ctx(ctx).Error("搜索索引对账失败", zap.Error(err))
+		return err
+	}
+	logger.Ctx(ctx).Info("搜索索引对账完成", zap.Int64("checked", result.Checked),
+		zap.Int64("missing", result.Missing), zap.Int64("mismatch", result.Mismatch),
+		zap.Int64("orphan", result.Orphan), zap.Int64("repaired", result.Repaired),
+		zap.Int64("failed", result.Failed))
+	return nil
+}
+
+// 恢复因进程退出而中断的重建索引任务, 索引映射过旧时记录告警
+func (s *Script) resumeMigrate(ctx context.Context) error {
+	if err := script_svc.Script().ResumeMigrateEs(ctx); err != nil {
+		logger.Ctx(ctx).Error("恢复重建索引失败", zap.Error(err))
+		return err
+	}
 	return nil
 }
 
@@ -1,21 +1,47 @@
 package handler
 
 import (
+	"context"
+	"time"
+
+	"github.com/cago-frame/cago/database/redis"
+	"github.com/cago-frame/cago/pkg/logger"
 	"github.com/cago-frame/cago/server/cron"
+	"github.com/scriptscat/scriptlist/internal/service/statistics_svc"
+	"go.uber.org/zap"
 )
 
 type Statistics struct {
 }
 
 func (s *Statistics) Crontab(c cron.Crontab) error {
-	//_, err := c.AddFunc("0 3 * * *", s.saveScriptStatistics)
-	//if err != nil {
-	//	return err
-	//}
+	_, err := c.AddFunc("0 3 * * *", s.saveScriptStatistics)
+	if err != nil {
+		return err
+	}
 	return nil
 }
 
-// 每日统计数据落库
-//func (s *Statistics) saveScriptStatistics(ctx context.Context) error {
-//	return nil
-//}
+// 每日统计数据落库, 同时重新落库前一天的数据, 避免任务失败时丢失
+func (s *Statistics) saveScriptStatistics(ctx context.Context) error {
+	if ok, err := redis.Ctx(ctx).SetNX("saveScriptStatistics", "1", time.Hour).Result(); err != nil {
+		logger.Ctx(ctx).Error("每日统计数据落库失败", zap.Error(err))
+		return err
+	} else if !ok {
+		logger.Ctx(ctx).Info("其他机器每日统计数据落库中")
+		return nil
+	}
+	now := time.Now()
+	for _, date := range []time.Time{now.AddDate(0, 0, -2), now.AddDate(0, 0, -1)} {
+		if err := statistics_svc.Statistics().SaveDaily(ctx, date); err != nil {
+			logger.Ctx(ctx).Error("每日统计数据落库失败", zap.Error(err), zap.Time("date", date))
+			return err
+		}
+	}
+	// 根据前一天的下载量检测刷量, 并更新实时检测使用的日均下载量
+	if err := statistics_svc.Statistics().FraudDaily(ctx, now.AddDate(0, 0, -1)); err != nil {
+		logger.Ctx(ctx).Error("刷量检测失败", zap.Error(err))
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package producer
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/cago-frame/cago/pkg/broker"
+	broker2 "github.com/cago-frame/cago/pkg/broker/broker"
+	"github.com/scriptscat/scriptlist/internal/model/entity/notification_entity"
+)
+
+// NotificationWebhookMsg webhook投递消息, Payload为签名的请求体
+type NotificationWebhookMsg struct {
+	WebhookID int64                    `json:"webhook_id"`
+	UserID    int64                    `json:"user_id"`
+	Delivery  string                   `json:"delivery"`
+	Event     string                   `json:"event"`
+	Type      notification_entity.Type `json:"type"`
+	Payload   json.RawMessage          `json:"payload"`
+}
+
+func PublishNotificationWebhook(ctx context.Context, msg *NotificationWebhookMsg) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return broker.Default().Publish(ctx, NotificationWebhookTopic, &broker2.Message{
+		Body: body,
+	})
+}
+
+func ParseNotificationWebhookMsg(msg *broker2.Message) (*NotificationWebhookMsg, error) {
+	ret := &NotificationWebhookMsg{}
+	if err := json.Unmarshal(msg.Body, ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// SubscribeNotificationWebhook 订阅webhook投递, fn返回大于0的重试间隔时消息重新入队
+// attempt为第几次投递, 不支持重试的消息代理上始终为1
+func SubscribeNotificationWebhook(ctx context.Context, fn func(ctx context.Context, msg *NotificationWebhookMsg, attempt int) (time.Duration, error), opts ...broker2.SubscribeOption) error {
+	_, err := broker.Default().Subscribe(ctx, NotificationWebhookTopic, func(ctx context.Context, ev broker2.Event) error {
+		m, err := ParseNotificationWebhookMsg(ev.Message())
+		if err != nil {
+			return err
+		}
+		attempt := ev.Attempted()
+		if attempt < 1 {
+			attempt = 1
+		}
+		retry, err := fn(ctx, m, attempt)
+		if err != nil {
+			return err
+		}
+		if retry > 0 {
+			return ev.Requeue(retry)
+		}
+		return nil
+	}, opts...)
+	return err
+}
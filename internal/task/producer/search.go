@@ -0,0 +1,86 @@
+package producer
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/cago-frame/cago/pkg/broker"
+	broker2 "github.com/cago-frame/cago/pkg/broker/broker"
+)
+
+// ScriptSearchMsg 关键字搜索记录
+type ScriptSearchMsg struct {
+	SearchID string
+	Query    string
+	Filters  map[string]interface{}
+	Sort     string
+	Total    int64
+	UserID   int64
+	Time     time.Time
+}
+
+func PublishScriptSearch(ctx context.Context, msg *ScriptSearchMsg) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return broker.Default().Publish(ctx, ScriptSearchTopic, &broker2.Message{
+		Body: body,
+	})
+}
+
+func ParseScriptSearchMsg(msg *broker2.Message) (*ScriptSearchMsg, error) {
+	ret := &ScriptSearchMsg{}
+	if err := json.Unmarshal(msg.Body, ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func SubscribeScriptSearch(ctx context.Context, fn func(ctx context.Context, msg *ScriptSearchMsg) error, opts ...broker2.SubscribeOption) error {
+	_, err := broker.Default().Subscribe(ctx, ScriptSearchTopic, func(ctx context.Context, ev broker2.Event) error {
+		m, err := ParseScriptSearchMsg(ev.Message())
+		if err != nil {
+			return err
+		}
+		return fn(ctx, m)
+	}, opts...)
+	return err
+}
+
+// ScriptSearchClickMsg 搜索结果点击记录
+type ScriptSearchClickMsg struct {
+	SearchID string
+	ScriptID int64
+	Time     time.Time
+}
+
+func PublishScriptSearchClick(ctx context.Context, msg *ScriptSearchClickMsg) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return broker.Default().Publish(ctx, ScriptSearchClickTopic, &broker2.Message{
+		Body: body,
+	})
+}
+
+func ParseScriptSearchClickMsg(msg *broker2.Message) (*ScriptSearchClickMsg, error) {
+	ret := &ScriptSearchClickMsg{}
+	if err := json.Unmarshal(msg.Body, ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func SubscribeScriptSearchClick(ctx context.Context, fn func(ctx context.Context, msg *ScriptSearchClickMsg) error, opts ...broker2.SubscribeOption) error {
+	_, err := broker.Default().Subscribe(ctx, ScriptSearchClickTopic, func(ctx context.Context, ev broker2.Event) error {
+		m, err := ParseScriptSearchClickMsg(ev.Message())
+		if err != nil {
+			return err
+		}
+		return fn(ctx, m)
+	}, opts...)
+	return err
+}
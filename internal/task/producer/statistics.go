@@ -15,6 +15,13 @@ type ScriptStatisticsMsg struct {
 	IP, UA, StatisticsToken        string
 	Download                       statistics_repo.ScriptStatisticsType
 	Time                           time.Time
+	// Referer 请求来源页面, Source 安装链接中的source参数, FolderID 通过收藏夹订阅安装时的收藏夹
+	Referer, Source string
+	FolderID        int64
+	// NoCookie 请求没有携带统计cookie
+	NoCookie bool
+	// Version 更新检查时客户端当前安装的脚本版本
+	Version string
 }
 
 func PublishScriptStatistics(ctx context.Context, msg *ScriptStatisticsMsg) error {
@@ -89,3 +96,43 @@ func SubscribeStatisticsCollect(ctx context.Context, fn func(ctx context.Context
 	}, opts...)
 	return err
 }
+
+// StatisticsErrorMsg 脚本运行错误, Weight为采样的权重
+type StatisticsErrorMsg struct {
+	ScriptID int64
+	Message  string
+	Stack    string
+	Host     string
+	Version  string
+	Weight   int64
+	Time     int64
+}
+
+func PublishStatisticsError(ctx context.Context, msg *StatisticsErrorMsg) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return broker.Default().Publish(ctx, StatisticErrorTopic, &broker2.Message{
+		Body: body,
+	})
+}
+
+func ParseStatisticsErrorMsg(msg *broker2.Message) (*StatisticsErrorMsg, error) {
+	ret := &StatisticsErrorMsg{}
+	if err := json.Unmarshal(msg.Body, ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func SubscribeStatisticsError(ctx context.Context, fn func(ctx context.Context, msg *StatisticsErrorMsg) error, opts ...broker2.SubscribeOption) error {
+	_, err := broker.Default().Subscribe(ctx, StatisticErrorTopic, func(ctx context.Context, ev broker2.Event) error {
+		m, err := ParseStatisticsErrorMsg(ev.Message())
+		if err != nil {
+			return err
+		}
+		return fn(ctx, m)
+	}, opts...)
+	return err
+}
@@ -6,13 +6,19 @@ const (
 	ScriptStatisticTopic  = "script.statistic"   // 统计脚本
 	ScriptDeleteTopic     = "script.delete"      // 删除脚本
 
+	ScriptSearchTopic      = "script.search"       // 关键字搜索
+	ScriptSearchClickTopic = "script.search.click" // 搜索结果点击
+
 	IssueCreateTopic   = "issue.create"   // 创建issue
 	CommentCreateTopic = "comment.create" // 创建评论
 
 	StatisticCollectTopic = "statistic.collect" // 统计数据收集
+	StatisticErrorTopic   = "statistic.error"   // 脚本运行错误上报
 
 	ScriptAccessInviteTopic = "script.access.invite" // 脚本访问邀请
 
 	ReportCreateTopic        = "report.create"         // 创建举报
 	ReportCommentCreateTopic = "report.comment.create" // 举报评论
+
+	NotificationWebhookTopic = "notification.webhook" // 通知webhook投递
 )
@@ -0,0 +1,20 @@
+package migrations
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"github.com/scriptscat/scriptlist/internal/model/entity/script_entity"
+	"gorm.io/gorm"
+)
+
+// T20260304 脚本多语言名称与描述
+func T20260304() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "T20260304",
+		Migrate: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&script_entity.ScriptLocale{})
+		},
+		Rollback: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&script_entity.ScriptLocale{})
+		},
+	}
+}
@@ -0,0 +1,20 @@
+package migrations
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"github.com/scriptscat/scriptlist/internal/model/entity/script_entity"
+	"gorm.io/gorm"
+)
+
+// T20260305 脚本完整的匹配规则
+func T20260305() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "T20260305",
+		Migrate: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&script_entity.ScriptMatch{})
+		},
+		Rollback: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&script_entity.ScriptMatch{})
+		},
+	}
+}
@@ -0,0 +1,20 @@
+package migrations
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"github.com/scriptscat/scriptlist/internal/model/entity/script_entity"
+	"gorm.io/gorm"
+)
+
+// T20260306 内嵌搜索后端使用的全文索引表
+func T20260306() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "T20260306",
+		Migrate: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&script_entity.ScriptFulltext{})
+		},
+		Rollback: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&script_entity.ScriptFulltext{})
+		},
+	}
+}
@@ -0,0 +1,20 @@
+package migrations
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"github.com/scriptscat/scriptlist/internal/model/entity/script_entity"
+	"gorm.io/gorm"
+)
+
+// T20260307 全文索引表增加脚本名称的前缀索引, 用于搜索建议
+func T20260307() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "T20260307",
+		Migrate: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&script_entity.ScriptFulltext{})
+		},
+		Rollback: func(tx *gorm.DB) error {
+			return tx.Migrator().DropIndex(&script_entity.ScriptFulltext{}, "name_prefix")
+		},
+	}
+}
@@ -0,0 +1,24 @@
+package migrations
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"github.com/scriptscat/scriptlist/internal/model/entity"
+	"github.com/scriptscat/scriptlist/internal/model/entity/script_entity"
+	"gorm.io/gorm"
+)
+
+// T20260308 脚本统计与全文索引表增加热度
+func T20260308() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "T20260308",
+		Migrate: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&entity.ScriptStatistics{}, &script_entity.ScriptFulltext{})
+		},
+		Rollback: func(tx *gorm.DB) error {
+			if err := tx.Migrator().DropColumn(&entity.ScriptStatistics{}, "trending"); err != nil {
+				return err
+			}
+			return tx.Migrator().DropColumn(&script_entity.ScriptFulltext{}, "trending")
+		},
+	}
+}
@@ -0,0 +1,20 @@
+package migrations
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"github.com/scriptscat/scriptlist/internal/model/entity/script_entity"
+	"gorm.io/gorm"
+)
+
+// T20260309 关键字搜索日志
+func T20260309() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "T20260309",
+		Migrate: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&script_entity.ScriptSearchLog{})
+		},
+		Rollback: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&script_entity.ScriptSearchLog{})
+		},
+	}
+}
@@ -0,0 +1,20 @@
+package migrations
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"github.com/scriptscat/scriptlist/internal/model/entity/statistics_entity"
+	"gorm.io/gorm"
+)
+
+// T20260310 脚本每日统计数据落库
+func T20260310() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "T20260310",
+		Migrate: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&statistics_entity.StatisticsDaily{})
+		},
+		Rollback: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&statistics_entity.StatisticsDaily{})
+		},
+	}
+}
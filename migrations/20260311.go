@@ -0,0 +1,20 @@
+package migrations
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"github.com/scriptscat/scriptlist/internal/model/entity/statistics_entity"
+	"gorm.io/gorm"
+)
+
+// T20260311 脚本每日访客ua分布
+func T20260311() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "T20260311",
+		Migrate: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&statistics_entity.StatisticsUaDaily{})
+		},
+		Rollback: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&statistics_entity.StatisticsUaDaily{})
+		},
+	}
+}
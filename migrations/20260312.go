@@ -0,0 +1,20 @@
+package migrations
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"github.com/scriptscat/scriptlist/internal/model/entity/statistics_entity"
+	"gorm.io/gorm"
+)
+
+// T20260312 脚本每日安装来源
+func T20260312() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "T20260312",
+		Migrate: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&statistics_entity.StatisticsSourceDaily{})
+		},
+		Rollback: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&statistics_entity.StatisticsSourceDaily{})
+		},
+	}
+}
@@ -0,0 +1,20 @@
+package migrations
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"github.com/scriptscat/scriptlist/internal/model/entity/script_entity"
+	"gorm.io/gorm"
+)
+
+// T20260313 疑似刷量脚本的审核队列
+func T20260313() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "T20260313",
+		Migrate: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&script_entity.ScriptFraudReview{})
+		},
+		Rollback: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&script_entity.ScriptFraudReview{})
+		},
+	}
+}
@@ -0,0 +1,20 @@
+package migrations
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"github.com/scriptscat/scriptlist/internal/model/entity/statistics_entity"
+	"gorm.io/gorm"
+)
+
+// T20260314 脚本运行错误上报
+func T20260314() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "T20260314",
+		Migrate: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&statistics_entity.ScriptErrorGroup{}, &statistics_entity.ScriptErrorVersion{})
+		},
+		Rollback: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&statistics_entity.ScriptErrorGroup{}, &statistics_entity.ScriptErrorVersion{})
+		},
+	}
+}
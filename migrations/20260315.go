@@ -0,0 +1,20 @@
+package migrations
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"github.com/scriptscat/scriptlist/internal/model/entity/notification_entity"
+	"gorm.io/gorm"
+)
+
+// T20260315 通知webhook
+func T20260315() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "T20260315",
+		Migrate: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&notification_entity.NotificationWebhook{}, &notification_entity.NotificationWebhookLog{})
+		},
+		Rollback: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&notification_entity.NotificationWebhook{}, &notification_entity.NotificationWebhookLog{})
+		},
+	}
+}
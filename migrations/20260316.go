@@ -0,0 +1,20 @@
+package migrations
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"github.com/scriptscat/scriptlist/internal/model/entity/notification_entity"
+	"gorm.io/gorm"
+)
+
+// T20260316 邮件通知汇总
+func T20260316() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "T20260316",
+		Migrate: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&notification_entity.NotificationMailBuffer{})
+		},
+		Rollback: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&notification_entity.NotificationMailBuffer{})
+		},
+	}
+}
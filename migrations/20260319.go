@@ -0,0 +1,20 @@
+package migrations
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"github.com/scriptscat/scriptlist/internal/model/entity/notification_entity"
+	"gorm.io/gorm"
+)
+
+// T20260319 汇总邮件的通知标题改为text, 包含脚本名称时可能超过255
+func T20260319() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "T20260319",
+		Migrate: func(tx *gorm.DB) error {
+			return tx.Migrator().AlterColumn(&notification_entity.NotificationMailBuffer{}, "Title")
+		},
+		Rollback: func(tx *gorm.DB) error {
+			return nil
+		},
+	}
+}
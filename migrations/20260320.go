@@ -0,0 +1,20 @@
+package migrations
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"github.com/scriptscat/scriptlist/internal/model/entity/notification_entity"
+	"gorm.io/gorm"
+)
+
+// T20260320 webhook投递记录按时间清理, 添加创建时间的索引
+func T20260320() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "T20260320",
+		Migrate: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateIndex(&notification_entity.NotificationWebhookLog{}, "idx_createtime")
+		},
+		Rollback: func(tx *gorm.DB) error {
+			return tx.Migrator().DropIndex(&notification_entity.NotificationWebhookLog{}, "idx_createtime")
+		},
+	}
+}
@@ -0,0 +1,78 @@
+package migrations
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/cago-frame/cago/pkg/consts"
+	"github.com/cago-frame/cago/pkg/logger"
+	"github.com/go-gormigrate/gormigrate/v2"
+	"github.com/scriptscat/scriptlist/internal/model/entity/script_entity"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// T20260321 根据最新版本的元数据回填已有脚本的多语言名称与描述, 之前只有重新保存的脚本才会有多语言信息
+// 搜索索引中的多语言字段需要重建索引后才会生效
+func T20260321() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "T20260321",
+		Migrate: func(tx *gorm.DB) error {
+			lastId := int64(0)
+			for {
+				list := make([]*script_entity.Script, 0)
+				if err := tx.Model(&script_entity.Script{}).Select("id").
+					Where("id>? and status=?", lastId, consts.ACTIVE).
+					Order("id").Limit(200).Find(&list).Error; err != nil {
+					return err
+				}
+				if len(list) == 0 {
+					return nil
+				}
+				lastId = list[len(list)-1].ID
+				for _, script := range list {
+					var num int64
+					if err := tx.Model(&script_entity.ScriptLocale{}).
+						Where("script_id=?", script.ID).Count(&num).Error; err != nil {
+						return err
+					}
+					if num > 0 {
+						continue
+					}
+					code := &script_entity.Code{}
+					if err := tx.Select("id, meta_json").Order("createtime desc").
+						Where("script_id=? and is_pre_release=? and status=?",
+							script.ID, script_entity.DisablePreReleaseScript, consts.ACTIVE).
+						Limit(1).Find(code).Error; err != nil {
+						return err
+					}
+					if code.ID == 0 {
+						continue
+					}
+					meta := make(map[string][]string)
+					if err := json.Unmarshal([]byte(code.MetaJson), &meta); err != nil {
+						logger.Ctx(context.Background()).Warn("回填多语言信息失败", zap.Int64("script_id", script.ID), zap.Error(err))
+						continue
+					}
+					locales := script_entity.ParseScriptLocale(meta)
+					if len(locales) == 0 {
+						continue
+					}
+					now := time.Now().Unix()
+					for _, v := range locales {
+						v.ScriptID = script.ID
+						v.Createtime = now
+						v.Updatetime = now
+					}
+					if err := tx.Create(locales).Error; err != nil {
+						return err
+					}
+				}
+			}
+		},
+		Rollback: func(tx *gorm.DB) error {
+			return nil
+		},
+	}
+}
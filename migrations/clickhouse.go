@@ -0,0 +1,58 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/cago-frame/cago/pkg/logger"
+	"github.com/scriptscat/scriptlist/internal/pkg/clickhouse"
+	"go.uber.org/zap"
+)
+
+// clickhouse的表结构, 语句需要保证可以重复执行
+var clickhouseMigrations = []string{
+	// 会话数据, 同一会话会多次上报, 保留退出时间最新的一条
+	`CREATE TABLE IF NOT EXISTS statistics_collect
+(
+    session_id     String,
+    script_id      Int64,
+    visitor_id     String,
+    operation_host String,
+    operation_page String,
+    duration       Int32,
+    visit_time     Int64,
+    exit_time      Int64
+) ENGINE = ReplacingMergeTree(exit_time)
+PARTITION BY toYYYYMM(toDateTime(visit_time))
+ORDER BY (script_id, session_id)`,
+	// 访客数据, 每次上报记录一条
+	`CREATE TABLE IF NOT EXISTS statistics_visitor
+(
+    script_id        Int64,
+    visitor_id       String,
+    ua               String,
+    ip               String,
+    version          String,
+    install_page     String,
+    install_host     String,
+    visit_time       Int64,
+    first_visit_time Int64,
+    device_type      Int64,
+    browser_type     LowCardinality(String)
+) ENGINE = MergeTree
+PARTITION BY toYYYYMM(toDateTime(visit_time))
+ORDER BY (script_id, visit_time, visitor_id)`,
+}
+
+// RunClickhouseMigrations clickhouse表结构迁移, 未配置clickhouse时跳过
+func RunClickhouseMigrations(ctx context.Context, client *clickhouse.Client) error {
+	if client == nil {
+		return nil
+	}
+	for _, v := range clickhouseMigrations {
+		if err := client.Exec(ctx, v, nil); err != nil {
+			logger.Ctx(ctx).Error("clickhouse迁移失败", zap.Error(err))
+			return err
+		}
+	}
+	return nil
+}
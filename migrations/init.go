@@ -47,6 +47,24 @@ func RunMigrations(db *gorm.DB) error {
 		T20260301,
 		T20260302,
 		T20260303,
+		T20260304,
+		T20260305,
+		T20260306,
+		T20260307,
+		T20260308,
+		T20260309,
+		T20260310,
+		T20260311,
+		T20260312,
+		T20260313,
+		T20260314,
+		T20260315,
+		T20260316,
+		T20260317,
+		T20260318,
+		T20260319,
+		T20260320,
+		T20260321,
 	)
 }
 